@@ -0,0 +1,280 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/tools"
+)
+
+// ApprovalDecision is the decision taken on an action awaiting approval.
+type ApprovalDecision string
+
+const (
+	// ApprovalApprove runs the action.
+	ApprovalApprove ApprovalDecision = "approve"
+	// ApprovalReject doesn't run the action, and gives the feedback of the
+	// approval to the agent as its observation.
+	ApprovalReject ApprovalDecision = "reject"
+	// ApprovalEdit runs the action with the tool input of the approval.
+	ApprovalEdit ApprovalDecision = "edit"
+	// ApprovalInterrupt suspends the run before the actions of the iteration,
+	// which fails with an InterruptError holding its state.
+	ApprovalInterrupt ApprovalDecision = "interrupt"
+)
+
+// Approval is the decision on an action awaiting approval.
+type Approval struct {
+	Decision ApprovalDecision `json:"decision"`
+	// Feedback is given to the agent when the action is rejected.
+	Feedback string `json:"feedback,omitempty"`
+	// ToolInput replaces the input of the action when it is edited.
+	ToolInput string `json:"tool_input,omitempty"`
+}
+
+// Approve returns an approval running the action.
+func Approve() Approval {
+	return Approval{Decision: ApprovalApprove}
+}
+
+// Reject returns an approval rejecting the action with feedback for the agent.
+func Reject(feedback string) Approval {
+	return Approval{Decision: ApprovalReject, Feedback: feedback}
+}
+
+// EditInput returns an approval running the action with another tool input.
+func EditInput(toolInput string) Approval {
+	return Approval{Decision: ApprovalEdit, ToolInput: toolInput}
+}
+
+// Interrupt returns an approval suspending the run, to decide later.
+func Interrupt() Approval {
+	return Approval{Decision: ApprovalInterrupt}
+}
+
+// ApprovalFunc decides on an action before the executor runs its tool, e.g. by
+// asking a human. Errors stop the run.
+type ApprovalFunc func(ctx context.Context, action schema.AgentAction) (Approval, error)
+
+// ExecutorState is the state of a run suspended before the actions of an
+// iteration. It can be marshaled to JSON to resume the run in another process
+// with Executor.Resume.
+type ExecutorState struct {
+	// Inputs are the inputs of the run, including the variables of the memory.
+	Inputs map[string]string
+	// Steps are the steps of the previous iterations.
+	Steps []schema.AgentStep
+	// Iteration is the index of the suspended iteration.
+	Iteration int
+	// Actions are the actions planned by the agent in the suspended iteration.
+	Actions []schema.AgentAction
+	// Decisions are the decisions already taken on the actions of the
+	// iteration awaiting approval, in order.
+	Decisions []Approval
+	// Pending are the actions still awaiting approval, in order.
+	Pending []schema.AgentAction
+}
+
+// executorStateJSON is the JSON form of an ExecutorState. It has its own
+// types for the actions and steps, so that the format of the states doesn't
+// depend on the types of the schema package.
+type executorStateJSON struct {
+	Inputs    map[string]string `json:"inputs"`
+	Steps     []agentStepJSON   `json:"steps"`
+	Iteration int               `json:"iteration"`
+	Actions   []agentActionJSON `json:"actions"`
+	Decisions []Approval        `json:"decisions,omitempty"`
+	Pending   []agentActionJSON `json:"pending"`
+}
+
+type agentActionJSON struct {
+	Tool      string `json:"tool"`
+	ToolInput string `json:"tool_input"`
+	Log       string `json:"log,omitempty"`
+	ToolID    string `json:"tool_id,omitempty"`
+}
+
+type agentStepJSON struct {
+	Action      agentActionJSON `json:"action"`
+	Observation string          `json:"observation"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s ExecutorState) MarshalJSON() ([]byte, error) {
+	var steps []agentStepJSON
+	if s.Steps != nil {
+		steps = make([]agentStepJSON, len(s.Steps))
+	}
+	for i, step := range s.Steps {
+		steps[i] = agentStepJSON{Action: agentActionJSON(step.Action), Observation: step.Observation}
+	}
+	return json.Marshal(executorStateJSON{
+		Inputs:    s.Inputs,
+		Steps:     steps,
+		Iteration: s.Iteration,
+		Actions:   actionsToJSON(s.Actions),
+		Decisions: s.Decisions,
+		Pending:   actionsToJSON(s.Pending),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *ExecutorState) UnmarshalJSON(data []byte) error {
+	var state executorStateJSON
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	var steps []schema.AgentStep
+	if state.Steps != nil {
+		steps = make([]schema.AgentStep, len(state.Steps))
+	}
+	for i, step := range state.Steps {
+		steps[i] = schema.AgentStep{Action: schema.AgentAction(step.Action), Observation: step.Observation}
+	}
+	*s = ExecutorState{
+		Inputs:    state.Inputs,
+		Steps:     steps,
+		Iteration: state.Iteration,
+		Actions:   actionsFromJSON(state.Actions),
+		Decisions: state.Decisions,
+		Pending:   actionsFromJSON(state.Pending),
+	}
+	return nil
+}
+
+// actionsToJSON and actionsFromJSON keep nil slices nil, since the executor
+// plans the actions of a resumed iteration again if it has none.
+func actionsToJSON(actions []schema.AgentAction) []agentActionJSON {
+	if actions == nil {
+		return nil
+	}
+	result := make([]agentActionJSON, len(actions))
+	for i, action := range actions {
+		result[i] = agentActionJSON(action)
+	}
+	return result
+}
+
+func actionsFromJSON(actions []agentActionJSON) []schema.AgentAction {
+	if actions == nil {
+		return nil
+	}
+	result := make([]schema.AgentAction, len(actions))
+	for i, action := range actions {
+		result[i] = schema.AgentAction(action)
+	}
+	return result
+}
+
+// InterruptError is returned by an executor whose run was suspended by an
+// ApprovalInterrupt decision. It holds the state needed to resume the run.
+type InterruptError struct {
+	State *ExecutorState
+}
+
+func (e *InterruptError) Error() string {
+	names := make([]string, len(e.State.Pending))
+	for i, action := range e.State.Pending {
+		names[i] = action.Tool
+	}
+	return fmt.Sprintf("%s: awaiting approval of %s", ErrInterrupted, strings.Join(names, ", "))
+}
+
+func (e *InterruptError) Unwrap() error {
+	return ErrInterrupted
+}
+
+// requiresApproval reports whether the actions using the tool await approval.
+func (e *Executor) requiresApproval(tool string) bool {
+	if len(e.ApprovalTools) == 0 {
+		return e.Approver != nil
+	}
+	for _, name := range e.ApprovalTools {
+		if strings.EqualFold(name, tool) {
+			return true
+		}
+	}
+	return false
+}
+
+// approveActions takes the decisions on the actions of an iteration awaiting
+// approval, before any of them runs. The given decisions are used first, then
+// the approval function is called, until an action is interrupted. It returns
+// the actions to run, with their edited inputs, and the steps of the rejected
+// ones by index.
+func (e *Executor) approveActions(
+	ctx context.Context,
+	steps []schema.AgentStep,
+	nameToTool map[string]tools.Tool,
+	actions []schema.AgentAction,
+	decisions []Approval,
+) ([]schema.AgentAction, map[int]schema.AgentStep, error) {
+	if e.Approver == nil && len(e.ApprovalTools) == 0 {
+		return actions, nil, nil
+	}
+
+	approved := slices.Clone(actions)
+	rejected := make(map[int]schema.AgentStep)
+	var taken []Approval
+	var pending []schema.AgentAction
+	for i, action := range actions {
+		// invalid and repeated actions don't run, so they don't need approval.
+		if _, ok := nameToTool[strings.ToUpper(action.Tool)]; !ok || !e.requiresApproval(action.Tool) {
+			continue
+		}
+		if _, repeated := e.checkRepeatedAction(steps, action); repeated {
+			continue
+		}
+		if len(pending) > 0 {
+			pending = append(pending, action)
+			continue
+		}
+
+		approval := Interrupt()
+		if len(decisions) > 0 {
+			approval, decisions = decisions[0], decisions[1:]
+		} else if e.Approver != nil {
+			var err error
+			if approval, err = e.Approver(ctx, action); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		switch approval.Decision {
+		case ApprovalApprove:
+		case ApprovalEdit:
+			approved[i].ToolInput = approval.ToolInput
+		case ApprovalReject:
+			rejected[i] = schema.AgentStep{Action: action, Observation: rejection(action, approval.Feedback)}
+		case ApprovalInterrupt:
+			pending = append(pending, action)
+			continue
+		default:
+			return nil, nil, fmt.Errorf("%w: %q", ErrUnknownApprovalDecision, approval.Decision)
+		}
+		taken = append(taken, approval)
+	}
+
+	if len(pending) > 0 {
+		return nil, nil, &InterruptError{State: &ExecutorState{
+			Steps:     steps,
+			Actions:   actions,
+			Decisions: taken,
+			Pending:   pending,
+		}}
+	}
+	return approved, rejected, nil
+}
+
+// rejection returns the observation of a rejected action.
+func rejection(action schema.AgentAction, feedback string) string {
+	if feedback == "" {
+		return fmt.Sprintf("The user rejected the call of %s.", action.Tool)
+	}
+	return fmt.Sprintf("The user rejected the call of %s: %s", action.Tool, feedback)
+}
@@ -0,0 +1,147 @@
+package agents_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/agents"
+	"github.com/IT-Tech-Company/langchaingo/chains"
+	"github.com/IT-Tech-Company/langchaingo/llms/fake"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sqlTool records the queries it runs.
+type sqlTool struct {
+	queries []string
+}
+
+func (t *sqlTool) Name() string        { return "sql" }
+func (t *sqlTool) Description() string { return "Runs SQL queries." }
+
+func (t *sqlTool) Call(_ context.Context, input string) (string, error) {
+	t.queries = append(t.queries, input)
+	return "1 row affected", nil
+}
+
+func newSQLExecutor(tool tools.Tool, opts ...agents.Option) (*agents.Executor, *fake.ScriptedLLM) {
+	llm := fake.NewScriptedLLM(
+		fake.ToolCallReply(
+			fake.ToolCall("call_1", "calculator", `{"expression":"2*21"}`),
+			fake.ToolCall("call_2", "sql", `{"input":"DELETE FROM users"}`),
+		),
+		fake.TextReply("done"),
+	)
+	agent := agents.NewToolCallingAgent(llm, []tools.Tool{tools.Calculator{}, tool})
+	return agents.NewExecutor(agent, opts...), llm
+}
+
+func TestExecutorApproval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		approval    agents.Approval
+		queries     []string
+		observation string
+	}{
+		{
+			name:        "approve",
+			approval:    agents.Approve(),
+			queries:     []string{"DELETE FROM users"},
+			observation: "1 row affected",
+		},
+		{
+			name:        "edit",
+			approval:    agents.EditInput("DELETE FROM users WHERE id = 1"),
+			queries:     []string{"DELETE FROM users WHERE id = 1"},
+			observation: "1 row affected",
+		},
+		{
+			name:        "reject",
+			approval:    agents.Reject("deleting every user is not allowed"),
+			observation: "The user rejected the call of sql: deleting every user is not allowed",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tool := &sqlTool{}
+			var asked []schema.AgentAction
+			executor, _ := newSQLExecutor(tool,
+				agents.WithReturnIntermediateSteps(),
+				agents.WithApproval(func(_ context.Context, action schema.AgentAction) (agents.Approval, error) {
+					asked = append(asked, action)
+					return tc.approval, nil
+				}, "SQL"))
+
+			out, err := chains.Call(context.Background(), executor, map[string]any{"input": "Clean the users."})
+			require.NoError(t, err)
+			assert.Equal(t, "done", out["output"])
+
+			// only the actions of the tools awaiting approval are asked.
+			require.Len(t, asked, 1)
+			assert.Equal(t, "sql", asked[0].Tool)
+			assert.Equal(t, tc.queries, tool.queries)
+
+			steps, ok := out["intermediateSteps"].([]schema.AgentStep)
+			require.True(t, ok)
+			require.Len(t, steps, 2)
+			assert.Equal(t, "42", steps[0].Observation)
+			assert.Equal(t, tc.observation, steps[1].Observation)
+		})
+	}
+
+	errDenied := errors.New("approval service unavailable")
+	executor, _ := newSQLExecutor(&sqlTool{},
+		agents.WithApproval(func(context.Context, schema.AgentAction) (agents.Approval, error) {
+			return agents.Approval{}, errDenied
+		}))
+	_, err := chains.Run(context.Background(), executor, "Clean the users.")
+	require.ErrorIs(t, err, errDenied)
+}
+
+func TestExecutorInterrupt(t *testing.T) {
+	t.Parallel()
+
+	tool := &sqlTool{}
+	executor, llm := newSQLExecutor(tool, agents.WithInterruptBefore("sql"))
+
+	_, err := chains.Run(context.Background(), executor, "Clean the users.")
+	require.ErrorIs(t, err, agents.ErrInterrupted)
+	var interrupt *agents.InterruptError
+	require.ErrorAs(t, err, &interrupt)
+	assert.Empty(t, tool.queries)
+
+	// the state is suspended before the tools of the iteration.
+	data, err := json.Marshal(interrupt.State)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"pending":[{"tool":"sql","tool_input":"DELETE FROM users"`)
+	var state agents.ExecutorState
+	require.NoError(t, json.Unmarshal(data, &state))
+	assert.Equal(t, map[string]string{"input": "Clean the users."}, state.Inputs)
+	assert.Equal(t, 0, state.Iteration)
+	assert.Len(t, state.Actions, 2)
+	require.Len(t, state.Pending, 1)
+	assert.Equal(t, "DELETE FROM users", state.Pending[0].ToolInput)
+
+	// the run is resumed by another executor, without planning the iteration again.
+	resumed := agents.NewExecutor(agents.NewToolCallingAgent(llm, []tools.Tool{tools.Calculator{}, tool}),
+		agents.WithInterruptBefore("sql"))
+	out, err := resumed.Resume(context.Background(), &state, agents.EditInput("DELETE FROM users WHERE id = 1"))
+	require.NoError(t, err)
+	assert.Equal(t, "done", out["output"])
+	assert.Equal(t, []string{"DELETE FROM users WHERE id = 1"}, tool.queries)
+	assert.Len(t, llm.Calls(), 2)
+
+	// without a decision, the run is interrupted again.
+	_, err = executor.Resume(context.Background(), &state)
+	require.ErrorAs(t, err, &interrupt)
+	assert.Equal(t, state.Pending, interrupt.State.Pending)
+}
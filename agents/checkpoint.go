@@ -0,0 +1,116 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCheckpointNotFound is returned by checkpointers if a thread has no checkpoint.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// CheckpointStatus is the status of a run at a checkpoint.
+type CheckpointStatus string
+
+const (
+	// CheckpointRunning is the status of a run after one of its iterations.
+	CheckpointRunning CheckpointStatus = "running"
+	// CheckpointInterrupted is the status of a run suspended with an InterruptError.
+	CheckpointInterrupted CheckpointStatus = "interrupted"
+	// CheckpointFinished is the status of a run that returned its outputs.
+	CheckpointFinished CheckpointStatus = "finished"
+	// CheckpointFailed is the status of a run that failed. Its state is the one
+	// before the failed iteration, which is run again when it is resumed.
+	CheckpointFailed CheckpointStatus = "failed"
+)
+
+// Checkpoint is the state of a run of an executor, saved after each of its
+// iterations.
+type Checkpoint struct {
+	ThreadID string           `json:"thread_id"`
+	Status   CheckpointStatus `json:"status"`
+	State    ExecutorState    `json:"state"`
+	// Outputs are the outputs of a finished run. They are decoded from JSON
+	// when loaded from the checkpointers storing them as such.
+	Outputs   map[string]any `json:"outputs,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// Checkpointer stores the checkpoints of the runs of executors by thread. The
+// checkpoints are written with WithCheckpointer for the runs whose context has
+// a thread ID, see ContextWithThreadID.
+type Checkpointer interface {
+	// Put adds a checkpoint to its thread, of which it is the latest.
+	Put(ctx context.Context, checkpoint Checkpoint) error
+	// Latest returns the latest checkpoint of a thread, or ErrCheckpointNotFound.
+	Latest(ctx context.Context, threadID string) (Checkpoint, error)
+	// List returns the checkpoints of a thread, from the oldest.
+	List(ctx context.Context, threadID string) ([]Checkpoint, error)
+	// Threads returns the IDs of the threads with checkpoints.
+	Threads(ctx context.Context) ([]string, error)
+	// Delete removes the checkpoints of a thread.
+	Delete(ctx context.Context, threadID string) error
+}
+
+type threadIDContextKey struct{}
+
+// ContextWithThreadID returns a context whose executor runs are checkpointed
+// under the thread ID.
+func ContextWithThreadID(ctx context.Context, threadID string) context.Context {
+	return context.WithValue(ctx, threadIDContextKey{}, threadID)
+}
+
+// ThreadIDFromContext returns the thread ID of the context, if any.
+func ThreadIDFromContext(ctx context.Context) (string, bool) {
+	threadID, ok := ctx.Value(threadIDContextKey{}).(string)
+	return threadID, ok && threadID != ""
+}
+
+// ResumeThread resumes the run of a thread from its latest checkpoint, e.g.
+// after a restart of the process. Suspended iterations are run with the
+// decisions like with Resume, and failed ones are run again. The outputs of
+// finished runs are returned as they are.
+func (e *Executor) ResumeThread(ctx context.Context, threadID string, decisions ...Approval) (map[string]any, error) {
+	if e.Checkpointer == nil {
+		return nil, fmt.Errorf("%w: no checkpointer", ErrCheckpointNotFound)
+	}
+	checkpoint, err := e.Checkpointer.Latest(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.Status == CheckpointFinished {
+		return checkpoint.Outputs, nil
+	}
+	return e.Resume(ContextWithThreadID(ctx, threadID), &checkpoint.State, decisions...)
+}
+
+// checkpoint saves the state of the run if the context has a thread ID.
+func (e *Executor) checkpoint(
+	ctx context.Context,
+	status CheckpointStatus,
+	state ExecutorState,
+	outputs map[string]any,
+	runErr error,
+) error {
+	threadID, ok := ThreadIDFromContext(ctx)
+	if e.Checkpointer == nil || !ok {
+		return nil
+	}
+
+	checkpoint := Checkpoint{
+		ThreadID:  threadID,
+		Status:    status,
+		State:     state,
+		Outputs:   outputs,
+		CreatedAt: time.Now(),
+	}
+	if runErr != nil {
+		checkpoint.Error = runErr.Error()
+	}
+	if err := e.Checkpointer.Put(ctx, checkpoint); err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+	return nil
+}
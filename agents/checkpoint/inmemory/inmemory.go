@@ -0,0 +1,98 @@
+// Package inmemory provides an `agents.Checkpointer` keeping the checkpoints in
+// memory, for tests and for runs which don't need to survive the process.
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/IT-Tech-Company/langchaingo/agents"
+)
+
+// InMemory is an in-memory `agents.Checkpointer`. The checkpoints are stored
+// encoded as JSON, so that they are loaded like from the other checkpointers
+// and don't share memory with the runs.
+type InMemory struct {
+	mu      sync.RWMutex
+	threads map[string][][]byte
+}
+
+var _ agents.Checkpointer = (*InMemory)(nil)
+
+// New creates an empty in-memory `agents.Checkpointer`.
+func New() *InMemory {
+	return &InMemory{threads: make(map[string][][]byte)}
+}
+
+// Put adds a checkpoint to its thread.
+func (im *InMemory) Put(_ context.Context, checkpoint agents.Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	im.threads[checkpoint.ThreadID] = append(im.threads[checkpoint.ThreadID], data)
+	return nil
+}
+
+// Latest returns the latest checkpoint of a thread.
+func (im *InMemory) Latest(_ context.Context, threadID string) (agents.Checkpoint, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	checkpoints := im.threads[threadID]
+	if len(checkpoints) == 0 {
+		return agents.Checkpoint{}, fmt.Errorf("%w: thread %s", agents.ErrCheckpointNotFound, threadID)
+	}
+	return decode(checkpoints[len(checkpoints)-1])
+}
+
+// List returns the checkpoints of a thread, from the oldest.
+func (im *InMemory) List(_ context.Context, threadID string) ([]agents.Checkpoint, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	checkpoints := make([]agents.Checkpoint, 0, len(im.threads[threadID]))
+	for _, data := range im.threads[threadID] {
+		checkpoint, err := decode(data)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, nil
+}
+
+// Threads returns the IDs of the threads with checkpoints, sorted.
+func (im *InMemory) Threads(_ context.Context) ([]string, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	threads := make([]string, 0, len(im.threads))
+	for threadID := range im.threads {
+		threads = append(threads, threadID)
+	}
+	sort.Strings(threads)
+	return threads, nil
+}
+
+// Delete removes the checkpoints of a thread.
+func (im *InMemory) Delete(_ context.Context, threadID string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	delete(im.threads, threadID)
+	return nil
+}
+
+func decode(data []byte) (agents.Checkpoint, error) {
+	var checkpoint agents.Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return agents.Checkpoint{}, fmt.Errorf("decode checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
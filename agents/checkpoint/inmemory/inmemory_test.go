@@ -0,0 +1,63 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/agents"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	checkpointer := New()
+
+	_, err := checkpointer.Latest(ctx, "thread-1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	running := agents.Checkpoint{
+		ThreadID: "thread-1",
+		Status:   agents.CheckpointRunning,
+		State: agents.ExecutorState{
+			Inputs:    map[string]string{"input": "What is 2*21?"},
+			Steps:     []schema.AgentStep{{Action: schema.AgentAction{Tool: "calculator", ToolInput: "2*21"}, Observation: "42"}},
+			Iteration: 1,
+		},
+		CreatedAt: createdAt,
+	}
+	finished := running
+	finished.Status = agents.CheckpointFinished
+	finished.Outputs = map[string]any{"output": "42"}
+	require.NoError(t, checkpointer.Put(ctx, running))
+	require.NoError(t, checkpointer.Put(ctx, finished))
+	require.NoError(t, checkpointer.Put(ctx, agents.Checkpoint{ThreadID: "thread-0", Status: agents.CheckpointFailed}))
+
+	// checkpoints don't share memory with the runs.
+	running.State.Steps[0].Observation = "changed"
+
+	latest, err := checkpointer.Latest(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Equal(t, finished.Outputs, latest.Outputs)
+	assert.Equal(t, "42", latest.State.Steps[0].Observation)
+
+	checkpoints, err := checkpointer.List(ctx, "thread-1")
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 2)
+	assert.Equal(t, agents.CheckpointRunning, checkpoints[0].Status)
+	assert.True(t, createdAt.Equal(checkpoints[0].CreatedAt))
+
+	threads, err := checkpointer.Threads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"thread-0", "thread-1"}, threads)
+
+	require.NoError(t, checkpointer.Delete(ctx, "thread-1"))
+	checkpoints, err = checkpointer.List(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Empty(t, checkpoints)
+}
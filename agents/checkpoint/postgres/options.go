@@ -0,0 +1,36 @@
+package postgres
+
+// DefaultTableName is the default name of the table of the checkpoints.
+const DefaultTableName = "langchaingo_checkpoints"
+
+// Option is an option for the Postgres checkpointer.
+type Option func(*options)
+
+type options struct {
+	conn      Conn
+	connURL   string
+	tableName string
+}
+
+// WithConn sets the connection, or the pool of connections, of the
+// checkpointer. The caller is responsible for closing it.
+func WithConn(conn Conn) Option {
+	return func(o *options) {
+		o.conn = conn
+	}
+}
+
+// WithConnectionURL sets the URL the checkpointer connects to. It is ignored
+// if a connection is given with WithConn.
+func WithConnectionURL(connURL string) Option {
+	return func(o *options) {
+		o.connURL = connURL
+	}
+}
+
+// WithTableName sets the name of the table of the checkpoints.
+func WithTableName(tableName string) Option {
+	return func(o *options) {
+		o.tableName = tableName
+	}
+}
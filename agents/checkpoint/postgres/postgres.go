@@ -0,0 +1,172 @@
+// Package postgres provides an `agents.Checkpointer` storing the checkpoints in
+// a PostgreSQL database, which several processes can share to resume the runs
+// of one another.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IT-Tech-Company/langchaingo/agents"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrMissingConnection is returned by New if neither a connection nor a
+// connection URL is given.
+var ErrMissingConnection = errors.New("postgres: missing connection or connection URL")
+
+// Conn represents both a pgx.Conn and a pgxpool.Pool.
+type Conn interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, arguments ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, arguments ...any) pgx.Row
+}
+
+// Postgres is an `agents.Checkpointer` storing the checkpoints in a PostgreSQL
+// database.
+type Postgres struct {
+	conn      Conn
+	ownConn   *pgx.Conn
+	tableName string
+}
+
+var _ agents.Checkpointer = (*Postgres)(nil)
+
+// New creates a Postgres `agents.Checkpointer`, creating its table if needed.
+// The connection is the one given with WithConn, or a new connection to the
+// URL given with WithConnectionURL.
+func New(ctx context.Context, opts ...Option) (*Postgres, error) {
+	o := options{tableName: DefaultTableName}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	p := &Postgres{
+		conn:      o.conn,
+		tableName: pgx.Identifier{o.tableName}.Sanitize(),
+	}
+	if p.conn == nil {
+		if o.connURL == "" {
+			return nil, ErrMissingConnection
+		}
+		conn, err := pgx.Connect(ctx, o.connURL)
+		if err != nil {
+			return nil, err
+		}
+		p.conn = conn
+		p.ownConn = conn
+	}
+
+	for _, statement := range []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id BIGSERIAL PRIMARY KEY,
+	thread_id TEXT NOT NULL,
+	status TEXT NOT NULL,
+	checkpoint JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL
+)`, p.tableName),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (thread_id, id)",
+			pgx.Identifier{o.tableName + "_thread_id"}.Sanitize(), p.tableName),
+	} {
+		if _, err := p.conn.Exec(ctx, statement); err != nil {
+			p.Close(ctx)
+			return nil, fmt.Errorf("create checkpoints table: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// Put adds a checkpoint to its thread.
+func (p *Postgres) Put(ctx context.Context, checkpoint agents.Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+	_, err = p.conn.Exec(ctx,
+		fmt.Sprintf("INSERT INTO %s (thread_id, status, checkpoint, created_at) VALUES ($1, $2, $3, $4)", p.tableName),
+		checkpoint.ThreadID, string(checkpoint.Status), data, checkpoint.CreatedAt)
+	return err
+}
+
+// Latest returns the latest checkpoint of a thread.
+func (p *Postgres) Latest(ctx context.Context, threadID string) (agents.Checkpoint, error) {
+	var data []byte
+	row := p.conn.QueryRow(ctx,
+		fmt.Sprintf("SELECT checkpoint FROM %s WHERE thread_id = $1 ORDER BY id DESC LIMIT 1", p.tableName), threadID)
+	if err := row.Scan(&data); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return agents.Checkpoint{}, fmt.Errorf("%w: thread %s", agents.ErrCheckpointNotFound, threadID)
+		}
+		return agents.Checkpoint{}, err
+	}
+	return decode(data)
+}
+
+// List returns the checkpoints of a thread, from the oldest.
+func (p *Postgres) List(ctx context.Context, threadID string) ([]agents.Checkpoint, error) {
+	rows, err := p.conn.Query(ctx,
+		fmt.Sprintf("SELECT checkpoint FROM %s WHERE thread_id = $1 ORDER BY id", p.tableName), threadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkpoints := make([]agents.Checkpoint, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		checkpoint, err := decode(data)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+// Threads returns the IDs of the threads with checkpoints, sorted.
+func (p *Postgres) Threads(ctx context.Context) ([]string, error) {
+	rows, err := p.conn.Query(ctx,
+		fmt.Sprintf("SELECT DISTINCT thread_id FROM %s ORDER BY thread_id", p.tableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	threads := make([]string, 0)
+	for rows.Next() {
+		var threadID string
+		if err := rows.Scan(&threadID); err != nil {
+			return nil, err
+		}
+		threads = append(threads, threadID)
+	}
+	return threads, rows.Err()
+}
+
+// Delete removes the checkpoints of a thread.
+func (p *Postgres) Delete(ctx context.Context, threadID string) error {
+	_, err := p.conn.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE thread_id = $1", p.tableName), threadID)
+	return err
+}
+
+// Close closes the connection if it was opened by New.
+func (p *Postgres) Close(ctx context.Context) error {
+	if p.ownConn == nil {
+		return nil
+	}
+	return p.ownConn.Close(ctx)
+}
+
+func decode(data []byte) (agents.Checkpoint, error) {
+	var checkpoint agents.Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return agents.Checkpoint{}, fmt.Errorf("decode checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/agents"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func getConnectionURL(t *testing.T) string {
+	t.Helper()
+
+	if url := os.Getenv("POSTGRES_CONNECTION_STRING"); url != "" {
+		return url
+	}
+
+	container, err := tcpostgres.RunContainer(
+		context.Background(),
+		testcontainers.WithImage("docker.io/postgres:16-alpine"),
+		tcpostgres.WithDatabase("db_test"),
+		tcpostgres.WithUsername("user"),
+		tcpostgres.WithPassword("passw0rd!"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second)),
+	)
+	if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+		t.Skip("Docker not available")
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, container.Terminate(context.Background()))
+	})
+
+	url, err := container.ConnectionString(context.Background(), "sslmode=disable")
+	require.NoError(t, err)
+	return url
+}
+
+func TestPostgres(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	_, err := New(ctx)
+	require.ErrorIs(t, err, ErrMissingConnection)
+
+	checkpointer, err := New(ctx, WithConnectionURL(getConnectionURL(t)), WithTableName("test_checkpoints"))
+	require.NoError(t, err)
+	defer checkpointer.Close(ctx)
+
+	_, err = checkpointer.Latest(ctx, "thread-1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+
+	running := agents.Checkpoint{
+		ThreadID: "thread-1",
+		Status:   agents.CheckpointRunning,
+		State: agents.ExecutorState{
+			Inputs:    map[string]string{"input": "What is 2*21?"},
+			Steps:     []schema.AgentStep{{Action: schema.AgentAction{Tool: "calculator", ToolInput: "2*21"}, Observation: "42"}},
+			Iteration: 1,
+		},
+		CreatedAt: time.Now(),
+	}
+	finished := running
+	finished.Status = agents.CheckpointFinished
+	finished.Outputs = map[string]any{"output": "42"}
+	require.NoError(t, checkpointer.Put(ctx, running))
+	require.NoError(t, checkpointer.Put(ctx, finished))
+	require.NoError(t, checkpointer.Put(ctx, agents.Checkpoint{ThreadID: "thread-0", Status: agents.CheckpointFailed}))
+
+	latest, err := checkpointer.Latest(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Equal(t, agents.CheckpointFinished, latest.Status)
+	assert.Equal(t, finished.Outputs, latest.Outputs)
+	assert.Equal(t, running.State.Steps, latest.State.Steps)
+
+	checkpoints, err := checkpointer.List(ctx, "thread-1")
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 2)
+	assert.Equal(t, agents.CheckpointRunning, checkpoints[0].Status)
+
+	threads, err := checkpointer.Threads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"thread-0", "thread-1"}, threads)
+
+	require.NoError(t, checkpointer.Delete(ctx, "thread-1"))
+	checkpoints, err = checkpointer.List(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Empty(t, checkpoints)
+}
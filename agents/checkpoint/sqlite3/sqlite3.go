@@ -0,0 +1,164 @@
+// Package sqlite3 provides an `agents.Checkpointer` storing the checkpoints in a
+// SQLite database, so that runs can be resumed after restarts of the process.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/agents"
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+)
+
+// SQLite is an `agents.Checkpointer` storing the checkpoints in a SQLite
+// database.
+type SQLite struct {
+	db        *sql.DB
+	ownDB     bool
+	tableName string
+}
+
+var _ agents.Checkpointer = (*SQLite)(nil)
+
+// New creates a SQLite `agents.Checkpointer`, creating its table if needed.
+// The database is the one given with WithDB, or the file given with WithPath.
+func New(ctx context.Context, opts ...Option) (*SQLite, error) {
+	o := options{
+		path:      DefaultPath,
+		tableName: DefaultTableName,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &SQLite{
+		db:        o.db,
+		tableName: quoteIdentifier(o.tableName),
+	}
+	if s.db == nil {
+		db, err := sql.Open("sqlite3", o.path)
+		if err != nil {
+			return nil, err
+		}
+		s.db = db
+		s.ownDB = true
+	}
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(_schema, s.tableName, quoteIdentifier(o.tableName+"_thread_id")))
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("create checkpoints table: %w", err)
+	}
+	return s, nil
+}
+
+const _schema = `CREATE TABLE IF NOT EXISTS %[1]s (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	thread_id TEXT NOT NULL,
+	status TEXT NOT NULL,
+	checkpoint BLOB NOT NULL,
+	created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (thread_id, id)`
+
+// quoteIdentifier quotes a table or index name for SQLite, escaping its
+// quotes.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Put adds a checkpoint to its thread.
+func (s *SQLite) Put(ctx context.Context, checkpoint agents.Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO %s (thread_id, status, checkpoint, created_at) VALUES (?, ?, ?, ?)", s.tableName),
+		checkpoint.ThreadID, string(checkpoint.Status), data, checkpoint.CreatedAt.UnixNano())
+	return err
+}
+
+// Latest returns the latest checkpoint of a thread.
+func (s *SQLite) Latest(ctx context.Context, threadID string) (agents.Checkpoint, error) {
+	var data []byte
+	row := s.db.QueryRowContext(ctx,
+		fmt.Sprintf("SELECT checkpoint FROM %s WHERE thread_id = ? ORDER BY id DESC LIMIT 1", s.tableName), threadID)
+	if err := row.Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return agents.Checkpoint{}, fmt.Errorf("%w: thread %s", agents.ErrCheckpointNotFound, threadID)
+		}
+		return agents.Checkpoint{}, err
+	}
+	return decode(data)
+}
+
+// List returns the checkpoints of a thread, from the oldest.
+func (s *SQLite) List(ctx context.Context, threadID string) ([]agents.Checkpoint, error) {
+	rows, err := s.db.QueryContext(ctx,
+		fmt.Sprintf("SELECT checkpoint FROM %s WHERE thread_id = ? ORDER BY id", s.tableName), threadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkpoints := make([]agents.Checkpoint, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		checkpoint, err := decode(data)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+// Threads returns the IDs of the threads with checkpoints, sorted.
+func (s *SQLite) Threads(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		fmt.Sprintf("SELECT DISTINCT thread_id FROM %s ORDER BY thread_id", s.tableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	threads := make([]string, 0)
+	for rows.Next() {
+		var threadID string
+		if err := rows.Scan(&threadID); err != nil {
+			return nil, err
+		}
+		threads = append(threads, threadID)
+	}
+	return threads, rows.Err()
+}
+
+// Delete removes the checkpoints of a thread.
+func (s *SQLite) Delete(ctx context.Context, threadID string) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE thread_id = ?", s.tableName), threadID)
+	return err
+}
+
+// Close closes the database if it was opened by New.
+func (s *SQLite) Close() error {
+	if !s.ownDB {
+		return nil
+	}
+	return s.db.Close()
+}
+
+func decode(data []byte) (agents.Checkpoint, error) {
+	var checkpoint agents.Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return agents.Checkpoint{}, fmt.Errorf("decode checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
@@ -0,0 +1,85 @@
+package sqlite3
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/agents"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "checkpoints.db")
+
+	checkpointer, err := New(ctx, WithPath(path))
+	require.NoError(t, err)
+
+	_, err = checkpointer.Latest(ctx, "thread-1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	running := agents.Checkpoint{
+		ThreadID: "thread-1",
+		Status:   agents.CheckpointRunning,
+		State: agents.ExecutorState{
+			Inputs:    map[string]string{"input": "What is 2*21?"},
+			Steps:     []schema.AgentStep{{Action: schema.AgentAction{Tool: "calculator", ToolInput: "2*21"}, Observation: "42"}},
+			Iteration: 1,
+		},
+		CreatedAt: createdAt,
+	}
+	finished := running
+	finished.Status = agents.CheckpointFinished
+	finished.Outputs = map[string]any{"output": "42"}
+	require.NoError(t, checkpointer.Put(ctx, running))
+	require.NoError(t, checkpointer.Put(ctx, finished))
+	require.NoError(t, checkpointer.Put(ctx, agents.Checkpoint{ThreadID: "thread-0", Status: agents.CheckpointFailed}))
+	require.NoError(t, checkpointer.Close())
+
+	// the checkpoints should survive reopening the database.
+	checkpointer, err = New(ctx, WithPath(path))
+	require.NoError(t, err)
+	defer checkpointer.Close()
+
+	latest, err := checkpointer.Latest(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Equal(t, agents.CheckpointFinished, latest.Status)
+	assert.Equal(t, finished.Outputs, latest.Outputs)
+	assert.Equal(t, running.State.Steps, latest.State.Steps)
+
+	checkpoints, err := checkpointer.List(ctx, "thread-1")
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 2)
+	assert.Equal(t, agents.CheckpointRunning, checkpoints[0].Status)
+	assert.True(t, createdAt.Equal(checkpoints[0].CreatedAt))
+
+	threads, err := checkpointer.Threads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"thread-0", "thread-1"}, threads)
+
+	require.NoError(t, checkpointer.Delete(ctx, "thread-1"))
+	checkpoints, err = checkpointer.List(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Empty(t, checkpoints)
+}
+
+func TestSQLiteTableName(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	checkpointer, err := New(ctx, WithPath(filepath.Join(t.TempDir(), "checkpoints.db")), WithTableName(`agent "runs"`))
+	require.NoError(t, err)
+	defer checkpointer.Close()
+
+	require.NoError(t, checkpointer.Put(ctx, agents.Checkpoint{ThreadID: "thread-1", Status: agents.CheckpointRunning}))
+	latest, err := checkpointer.Latest(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Equal(t, agents.CheckpointRunning, latest.Status)
+}
@@ -0,0 +1,133 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/agents"
+	"github.com/IT-Tech-Company/langchaingo/agents/checkpoint/inmemory"
+	"github.com/IT-Tech-Company/langchaingo/chains"
+	"github.com/IT-Tech-Company/langchaingo/llms/fake"
+	"github.com/IT-Tech-Company/langchaingo/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutorCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.NewScriptedLLM(
+		fake.ToolCallReply(fake.ToolCall("call_1", "calculator", `{"expression":"2*21"}`)),
+		fake.ToolCallReply(fake.ToolCall("call_2", "search", `{"input":"weather in Paris"}`)),
+	)
+	search := &flakyTool{failures: 1}
+	checkpointer := inmemory.New()
+	newExecutor := func() *agents.Executor {
+		agent := agents.NewToolCallingAgent(llm, []tools.Tool{tools.Calculator{}, search})
+		return agents.NewExecutor(agent, agents.WithCheckpointer(checkpointer))
+	}
+
+	// the run fails in its second iteration.
+	ctx := agents.ContextWithThreadID(context.Background(), "thread-1")
+	_, err := chains.Run(ctx, newExecutor(), "What is the weather in Paris?")
+	require.ErrorIs(t, err, errToolFailed)
+
+	checkpoints, err := checkpointer.List(ctx, "thread-1")
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 2)
+	assert.Equal(t, agents.CheckpointRunning, checkpoints[0].Status)
+	assert.Equal(t, 1, checkpoints[0].State.Iteration)
+	require.Len(t, checkpoints[0].State.Steps, 1)
+	assert.Equal(t, "42", checkpoints[0].State.Steps[0].Observation)
+	assert.Equal(t, agents.CheckpointFailed, checkpoints[1].Status)
+	assert.Equal(t, 1, checkpoints[1].State.Iteration)
+	assert.Equal(t, errToolFailed.Error(), checkpoints[1].Error)
+	assert.Equal(t, map[string]string{"input": "What is the weather in Paris?"}, checkpoints[1].State.Inputs)
+
+	// another executor resumes the run from its failed iteration.
+	llm.Push(
+		fake.ToolCallReply(fake.ToolCall("call_3", "search", `{"input":"weather in Paris"}`)),
+		fake.TextReply("It is sunny."),
+	)
+	out, err := newExecutor().ResumeThread(context.Background(), "thread-1")
+	require.NoError(t, err)
+	assert.Equal(t, "It is sunny.", out["output"])
+	assert.Len(t, llm.Calls(), 4)
+
+	latest, err := checkpointer.Latest(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Equal(t, agents.CheckpointFinished, latest.Status)
+	assert.Equal(t, 3, latest.State.Iteration)
+	assert.Len(t, latest.State.Steps, 2)
+	assert.Equal(t, "It is sunny.", latest.Outputs["output"])
+
+	// finished runs aren't run again.
+	out, err = newExecutor().ResumeThread(context.Background(), "thread-1")
+	require.NoError(t, err)
+	assert.Equal(t, "It is sunny.", out["output"])
+	assert.Len(t, llm.Calls(), 4)
+
+	_, err = newExecutor().ResumeThread(context.Background(), "thread-2")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+
+	// runs without a thread ID aren't checkpointed.
+	llm.Push(fake.TextReply("Hello."))
+	_, err = chains.Run(context.Background(), newExecutor(), "Hi")
+	require.NoError(t, err)
+	threads, err := checkpointer.Threads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"thread-1"}, threads)
+}
+
+func TestExecutorCheckpointsInterrupt(t *testing.T) {
+	t.Parallel()
+
+	tool := &sqlTool{}
+	checkpointer := inmemory.New()
+	executor, _ := newSQLExecutor(tool, agents.WithInterruptBefore("sql"), agents.WithCheckpointer(checkpointer))
+
+	ctx := agents.ContextWithThreadID(context.Background(), "thread-1")
+	_, err := chains.Run(ctx, executor, "Clean the users.")
+	require.ErrorIs(t, err, agents.ErrInterrupted)
+
+	latest, err := checkpointer.Latest(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Equal(t, agents.CheckpointInterrupted, latest.Status)
+	require.Len(t, latest.State.Pending, 1)
+
+	out, err := executor.ResumeThread(context.Background(), "thread-1", agents.Approve())
+	require.NoError(t, err)
+	assert.Equal(t, "done", out["output"])
+	assert.Equal(t, []string{"DELETE FROM users"}, tool.queries)
+}
+
+func TestExecutorCheckpointsFailedDecisions(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.NewScriptedLLM(
+		fake.ToolCallReply(fake.ToolCall("call_1", "search", `{"input":"weather in Paris"}`)),
+		fake.TextReply("It is sunny."),
+	)
+	search := &flakyTool{failures: 1}
+	checkpointer := inmemory.New()
+	agent := agents.NewToolCallingAgent(llm, []tools.Tool{search})
+	executor := agents.NewExecutor(agent, agents.WithInterruptBefore("search"), agents.WithCheckpointer(checkpointer))
+
+	ctx := agents.ContextWithThreadID(context.Background(), "thread-1")
+	_, err := chains.Run(ctx, executor, "What is the weather in Paris?")
+	require.ErrorIs(t, err, agents.ErrInterrupted)
+
+	// the approved action fails, and the failed checkpoint keeps the approval.
+	_, err = executor.ResumeThread(context.Background(), "thread-1", agents.Approve())
+	require.ErrorIs(t, err, errToolFailed)
+	latest, err := checkpointer.Latest(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Equal(t, agents.CheckpointFailed, latest.Status)
+	assert.Len(t, latest.State.Actions, 1)
+	assert.Equal(t, []agents.Approval{agents.Approve()}, latest.State.Decisions)
+
+	out, err := executor.ResumeThread(context.Background(), "thread-1")
+	require.NoError(t, err)
+	assert.Equal(t, "It is sunny.", out["output"])
+	assert.Equal(t, 2, search.calls)
+}
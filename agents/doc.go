@@ -10,7 +10,9 @@
 // Package agents provides and implementation of the agent interface called
 // OneShotZeroAgent. This agent uses the ReAct Framework (based on the
 // descriptions of tools) to decide what action to take. This agent is
-// optimized to be used with LLMs.
+// optimized to be used with LLMs. The ToolCallingAgent instead relies on the
+// native tool calling API of chat models, and works with every provider
+// supporting llms.WithTools.
 //
 // To make agents more powerful we need to make them iterative, i.e. call the
 // model multiple times until they arrive at the final answer. That's the job of
@@ -18,5 +20,18 @@
 // responsible for calling the agent, getting back and action and action input,
 // calling the tool that the action references with the corresponding input,
 // getting the output of the tool, and then passing all that information back
-// into the Agent to get the next action it should take.
+// into the Agent to get the next action it should take. When the agent returns
+// several actions at once, their tools are called concurrently.
+//
+// Actions of sensitive tools can await the approval of a human, given with
+// WithApproval, which approves them, rejects them with feedback for the agent
+// or edits their input. With WithInterruptBefore, or when the approval is
+// ApprovalInterrupt, the run is suspended with an InterruptError, whose
+// ExecutorState can be stored as JSON and resumed later with Executor.Resume.
+//
+// With WithCheckpointer, the executor saves a Checkpoint after each iteration
+// of the runs whose context has a thread ID, given with ContextWithThreadID.
+// Executor.ResumeThread resumes a run from the latest checkpoint of its thread,
+// e.g. after a restart. The checkpointers of the agents/checkpoint packages
+// keep the checkpoints in memory, or in SQLite or PostgreSQL databases.
 package agents
@@ -1,6 +1,10 @@
 package agents
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	// ErrExecutorInputNotString is returned if an input to the executor call function is not a string.
@@ -20,6 +24,17 @@ var (
 	// ErrInvalidChainReturnType is returned if the internal chain of the agent returns a value in the
 	// "text" filed that is not a string.
 	ErrInvalidChainReturnType = errors.New("agent chain did not return a string")
+
+	// ErrToolTimeout is returned if a tool does not return before its timeout.
+	ErrToolTimeout = errors.New("tool timed out")
+	// ErrRunTimeout is returned if the executor does not finish before the timeout of the run.
+	ErrRunTimeout = errors.New("agent run timed out")
+
+	// ErrInterrupted is returned, wrapped in an InterruptError, if a run is suspended while
+	// actions await approval.
+	ErrInterrupted = errors.New("agent run interrupted")
+	// ErrUnknownApprovalDecision is returned if an approval has an unknown decision.
+	ErrUnknownApprovalDecision = errors.New("unknown approval decision")
 )
 
 // ParserErrorHandler is the struct used to handle parse errors from the agent in the executor. If
@@ -38,3 +53,64 @@ func NewParserErrorHandler(formatFunc func(string) string) *ParserErrorHandler {
 		Formatter: formatFunc,
 	}
 }
+
+// ToolErrorPolicy is what the executor does with the error of a tool once it
+// has no retries left.
+type ToolErrorPolicy int
+
+const (
+	// ToolErrorAbort stops the run and returns the error of the tool.
+	ToolErrorAbort ToolErrorPolicy = iota
+	// ToolErrorObserve gives the error of the tool to the agent as the
+	// observation of the action, so that it can try something else.
+	ToolErrorObserve
+)
+
+const (
+	// _defaultToolRetryBackoff is the delay before the first retry of a tool.
+	_defaultToolRetryBackoff = 500 * time.Millisecond
+	// _maxToolRetryBackoff is the longest default delay between two retries.
+	_maxToolRetryBackoff = 30 * time.Second
+)
+
+// ToolErrorHandler is the struct used to handle the errors of tools in the executor. Failed tool
+// calls are retried up to MaxRetries times, then handled according to the policy. Errors of the
+// run itself, such as its cancellation, always stop the run.
+type ToolErrorHandler struct {
+	// Policy is what the executor does with the error once the retries are exhausted.
+	Policy ToolErrorPolicy
+	// MaxRetries is the number of times a failed tool call is retried.
+	MaxRetries int
+	// Backoff returns the delay before the given retry, starting at 1. If nil the delay starts
+	// at 500ms and doubles with each retry, up to 30s.
+	Backoff func(retry int) time.Duration
+	// The formatter function can be used to format the error given as an observation with
+	// ToolErrorObserve. If nil the observation is the name of the tool and the error.
+	Formatter func(tool string, err error) string
+}
+
+// NewToolErrorHandler creates a new tool error handler with a policy and a number of retries.
+func NewToolErrorHandler(policy ToolErrorPolicy, maxRetries int) *ToolErrorHandler {
+	return &ToolErrorHandler{
+		Policy:     policy,
+		MaxRetries: maxRetries,
+	}
+}
+
+func (h *ToolErrorHandler) backoff(retry int) time.Duration {
+	if h.Backoff != nil {
+		return h.Backoff(retry)
+	}
+	delay := _defaultToolRetryBackoff
+	for i := 1; i < retry && delay < _maxToolRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, _maxToolRetryBackoff)
+}
+
+func (h *ToolErrorHandler) observation(tool string, err error) string {
+	if h.Formatter != nil {
+		return h.Formatter(tool, err)
+	}
+	return fmt.Sprintf("%s returned an error: %s", tool, err)
+}
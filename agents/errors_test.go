@@ -0,0 +1,21 @@
+package agents
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolErrorHandlerBackoff(t *testing.T) {
+	t.Parallel()
+
+	handler := NewToolErrorHandler(ToolErrorAbort, math.MaxInt)
+	assert.Equal(t, 500*time.Millisecond, handler.backoff(1))
+	assert.Equal(t, 2*time.Second, handler.backoff(3))
+	assert.Equal(t, 30*time.Second, handler.backoff(7))
+	// the delay doesn't overflow with large retry counts.
+	assert.Equal(t, 30*time.Second, handler.backoff(100))
+	assert.Equal(t, 30*time.Second, handler.backoff(math.MaxInt))
+}
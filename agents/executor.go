@@ -2,12 +2,17 @@ package agents
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
 	"github.com/IT-Tech-Company/langchaingo/chains"
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
 	"github.com/IT-Tech-Company/langchaingo/schema"
 	"github.com/IT-Tech-Company/langchaingo/tools"
 )
@@ -20,9 +25,29 @@ type Executor struct {
 	Memory           schema.Memory
 	CallbacksHandler callbacks.Handler
 	ErrorHandler     *ParserErrorHandler
+	// ToolErrorHandler handles the errors of tools. If nil, they stop the run.
+	ToolErrorHandler *ToolErrorHandler
 
 	MaxIterations           int
 	ReturnIntermediateSteps bool
+
+	// ToolTimeout is the time tools have to return. Zero means no timeout.
+	ToolTimeout time.Duration
+	// ToolTimeouts overrides ToolTimeout for the tools with the given names.
+	ToolTimeouts map[string]time.Duration
+	// Timeout is the time a run has to finish. Zero means no timeout.
+	Timeout time.Duration
+
+	// Approver decides on the actions awaiting approval. If nil, runs are
+	// interrupted before them.
+	Approver ApprovalFunc
+	// ApprovalTools are the tools whose actions await approval. If empty, the
+	// actions of every tool await approval when Approver is set.
+	ApprovalTools []string
+
+	// Checkpointer saves the state of the runs with a thread ID after each
+	// iteration. If nil, runs aren't checkpointed.
+	Checkpointer Checkpointer
 }
 
 var (
@@ -44,6 +69,13 @@ func NewExecutor(agent Agent, opts ...Option) *Executor {
 		ReturnIntermediateSteps: options.returnIntermediateSteps,
 		CallbacksHandler:        options.callbacksHandler,
 		ErrorHandler:            options.errorHandler,
+		ToolErrorHandler:        options.toolErrorHandler,
+		ToolTimeout:             options.toolTimeout,
+		ToolTimeouts:            options.toolTimeouts,
+		Timeout:                 options.timeout,
+		Approver:                options.approver,
+		ApprovalTools:           options.approvalTools,
+		Checkpointer:            options.checkpointer,
 	}
 }
 
@@ -52,14 +84,75 @@ func (e *Executor) Call(ctx context.Context, inputValues map[string]any, _ ...ch
 	if err != nil {
 		return nil, err
 	}
+	return e.run(ctx, &ExecutorState{Inputs: inputs}, nil)
+}
+
+// Resume continues a run suspended with an InterruptError from its state,
+// which can come from another process. The decisions are taken on the pending
+// actions of the state in order, and the approval function is asked for the
+// ones without a decision. Like chains.Call, Resume calls the callbacks
+// handler and saves the context of the run to the memory.
+func (e *Executor) Resume(ctx context.Context, state *ExecutorState, decisions ...Approval) (map[string]any, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindChain, "Executor")
+
+	// the inputs of the state include the variables of the memory.
+	inputValues := make(map[string]any, len(state.Inputs))
+	for key, value := range state.Inputs {
+		inputValues[key] = value
+	}
+	for _, key := range e.Memory.MemoryVariables(ctx) {
+		delete(inputValues, key)
+	}
+
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleChainStart(ctx, inputValues)
+	}
+	outputValues, err := e.run(ctx, state, append(slices.Clone(state.Decisions), decisions...))
+	if err != nil {
+		if e.CallbacksHandler != nil {
+			e.CallbacksHandler.HandleChainError(ctx, err)
+		}
+		return outputValues, err
+	}
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleChainEnd(ctx, outputValues)
+	}
+
+	if err := e.Memory.SaveContext(ctx, inputValues, outputValues); err != nil {
+		return outputValues, err
+	}
+	return outputValues, nil
+}
+
+// run runs the iterations of the agent from a state. The actions of a
+// suspended iteration are run with the decisions instead of planning it.
+func (e *Executor) run(ctx context.Context, state *ExecutorState, decisions []Approval) (map[string]any, error) {
 	nameToTool := getNameToTool(e.Agent.GetTools())
 
-	steps := make([]schema.AgentStep, 0)
-	for i := 0; i < e.MaxIterations; i++ {
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, e.Timeout, ErrRunTimeout)
+		defer cancel()
+	}
+
+	steps := append(make([]schema.AgentStep, 0, len(state.Steps)), state.Steps...)
+	actions := state.Actions
+	var err error
+	for i := state.Iteration; i < e.MaxIterations; i++ {
 		var finish map[string]any
-		steps, finish, err = e.doIteration(ctx, steps, nameToTool, inputs)
+		stepCtx := e.startStep(ctx, i)
+		if actions != nil {
+			steps, finish, err = e.doActions(stepCtx, steps, nameToTool, actions, decisions)
+			actions = nil
+		} else {
+			steps, finish, err = e.doIteration(stepCtx, steps, nameToTool, state.Inputs)
+		}
+		if err != nil && errors.Is(context.Cause(ctx), ErrRunTimeout) {
+			err = fmt.Errorf("%w after %s: %w", ErrRunTimeout, e.Timeout, err)
+		}
+		e.endStep(stepCtx, err)
 		if finish != nil || err != nil {
-			return finish, err
+			return finish, e.endRun(ctx, state, decisions, i, steps, finish, err)
 		}
 
 		if e.MaxIterations > 2 && i == e.MaxIterations-2 {
@@ -67,6 +160,10 @@ func (e *Executor) Call(ctx context.Context, inputValues map[string]any, _ ...ch
 				Observation: "\n Important: Do you have enough data to answer? Provide the final answer \n",
 			})
 		}
+		next := ExecutorState{Inputs: state.Inputs, Steps: steps, Iteration: i + 1}
+		if err := e.checkpoint(context.WithoutCancel(ctx), CheckpointRunning, next, nil, nil); err != nil {
+			return nil, err
+		}
 	}
 
 	if e.CallbacksHandler != nil {
@@ -74,10 +171,73 @@ func (e *Executor) Call(ctx context.Context, inputValues map[string]any, _ ...ch
 			ReturnValues: map[string]any{"output": ErrNotFinished.Error()},
 		}, callbacks.WithExecutedSteps(steps))
 	}
-	return e.getReturn(
+	outputs := e.getReturn(
 		&schema.AgentFinish{ReturnValues: make(map[string]any)},
 		steps,
-	), ErrNotFinished
+	)
+	final := ExecutorState{Inputs: state.Inputs, Steps: steps, Iteration: e.MaxIterations}
+	if err := e.checkpoint(context.WithoutCancel(ctx), CheckpointFailed, final, nil, ErrNotFinished); err != nil {
+		return outputs, err
+	}
+	return outputs, ErrNotFinished
+}
+
+// endRun completes the state of the interrupted runs, and saves the last
+// checkpoint of the run ending at the iteration. It returns the error of the
+// run.
+func (e *Executor) endRun(
+	ctx context.Context,
+	state *ExecutorState,
+	decisions []Approval,
+	iteration int,
+	steps []schema.AgentStep,
+	outputs map[string]any,
+	runErr error,
+) error {
+	ctx = context.WithoutCancel(ctx)
+
+	var interrupt *InterruptError
+	switch {
+	case errors.As(runErr, &interrupt):
+		interrupt.State.Inputs = state.Inputs
+		interrupt.State.Iteration = iteration
+		if err := e.checkpoint(ctx, CheckpointInterrupted, *interrupt.State, nil, runErr); err != nil {
+			return err
+		}
+	case runErr != nil:
+		// the failed iteration is run again when the run is resumed, with the
+		// decisions already taken on its actions.
+		failed := ExecutorState{Inputs: state.Inputs, Steps: steps, Iteration: iteration}
+		if iteration == state.Iteration && state.Actions != nil {
+			failed.Actions = state.Actions
+			failed.Decisions = decisions
+		}
+		if err := e.checkpoint(ctx, CheckpointFailed, failed, nil, runErr); err != nil {
+			return err
+		}
+	default:
+		finished := ExecutorState{Inputs: state.Inputs, Steps: steps, Iteration: iteration + 1}
+		if err := e.checkpoint(ctx, CheckpointFinished, finished, outputs, nil); err != nil {
+			return err
+		}
+	}
+	return runErr
+}
+
+// startStep starts the run of an iteration, whose children are the runs of
+// its planning and of its tools.
+func (e *Executor) startStep(ctx context.Context, iteration int) context.Context {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindAgent, strings.TrimPrefix(fmt.Sprintf("%T", e.Agent), "*"))
+	if h, ok := e.CallbacksHandler.(callbacks.AgentStepHandler); ok {
+		h.HandleAgentStepStart(ctx, iteration)
+	}
+	return ctx
+}
+
+func (e *Executor) endStep(ctx context.Context, err error) {
+	if h, ok := e.CallbacksHandler.(callbacks.AgentStepHandler); ok {
+		h.HandleAgentStepEnd(ctx, err)
+	}
 }
 
 func (e *Executor) doIteration( // nolint
@@ -112,79 +272,226 @@ func (e *Executor) doIteration( // nolint
 		return steps, e.getReturn(finish, steps), nil
 	}
 
-	for _, action := range actions {
-		steps, err = e.checkRepeatedAction(steps, action)
-		if err != nil {
-			return steps, nil, nil // not returning the error because we're giving the chance to the LLM to write the final answer
+	return e.doActions(ctx, steps, nameToTool, actions, nil)
+}
+
+// doActions runs the actions of an iteration and appends their steps in the
+// order of the actions. Models with parallel tool calling can return several
+// actions at once, in which case the tools are called concurrently. The
+// actions awaiting approval are decided on before any tool is called.
+func (e *Executor) doActions(
+	ctx context.Context,
+	steps []schema.AgentStep,
+	nameToTool map[string]tools.Tool,
+	actions []schema.AgentAction,
+	decisions []Approval,
+) ([]schema.AgentStep, map[string]any, error) {
+	actions, rejected, err := e.approveActions(ctx, steps, nameToTool, actions, decisions)
+	if err != nil {
+		return steps, nil, err
+	}
+
+	newSteps := make([]schema.AgentStep, len(actions))
+	errs := make([]error, len(actions))
+	var wg sync.WaitGroup
+	for i, action := range actions {
+		if step, ok := rejected[i]; ok {
+			newSteps[i] = step
+			continue
 		}
+		// Repeated actions aren't run, but every action still gets a step, as
+		// models with tool calling expect a result for each of their calls.
+		if step, repeated := e.checkRepeatedAction(steps, action); repeated {
+			newSteps[i] = step
+			continue
+		}
+
+		if e.CallbacksHandler != nil {
+			e.CallbacksHandler.HandleAgentAction(ctx, action)
+		}
+		if len(actions) == 1 {
+			newSteps[i], errs[i] = e.doAction(ctx, steps, nameToTool, action)
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, action schema.AgentAction) {
+			defer wg.Done()
+			newSteps[i], errs[i] = e.doAction(ctx, steps, nameToTool, action)
+		}(i, action)
+	}
+	wg.Wait()
 
-		steps, err = e.doAction(ctx, steps, nameToTool, action)
+	for _, err := range errs {
 		if err != nil {
 			return steps, nil, err
 		}
 	}
-
-	return steps, nil, nil
+	return append(steps, newSteps...), nil, nil
 }
 
-func (e *Executor) checkRepeatedAction(steps []schema.AgentStep, action schema.AgentAction) ([]schema.AgentStep, error) {
+// checkRepeatedAction reports whether the action was already taken, in which
+// case it returns the step asking the model to do something else.
+func (e *Executor) checkRepeatedAction(steps []schema.AgentStep, action schema.AgentAction) (schema.AgentStep, bool) {
 	for _, step := range steps {
 		if step.Action.Tool == action.Tool && step.Action.ToolInput == action.ToolInput {
-			return append(steps, schema.AgentStep{
+			return schema.AgentStep{
 				Action:      action,
 				Observation: "ATTENTION: you are repeating the same action. Now, you have just 2 options: 1. Write the final answer. 2. Write a different action",
-			}), fmt.Errorf("repeated action: %s", action.Tool)
+			}, true
 		}
 	}
 
-	return steps, nil
+	return schema.AgentStep{}, false
 }
+
+// doAction calls the tool of an action and returns the resulting step. The
+// steps before the action are available to the tool under StepsContextKey.
 func (e *Executor) doAction(
 	ctx context.Context,
 	steps []schema.AgentStep,
 	nameToTool map[string]tools.Tool,
 	action schema.AgentAction,
-) ([]schema.AgentStep, error) {
-	if e.CallbacksHandler != nil {
-		e.CallbacksHandler.HandleAgentAction(ctx, action)
-	}
-
+) (schema.AgentStep, error) {
 	tool, ok := nameToTool[strings.ToUpper(action.Tool)]
 	if !ok {
 		if strings.ToLower(action.Tool) == "none" {
-			steps = append(steps, schema.AgentStep{
+			return schema.AgentStep{
 				Action:      action,
 				Observation: "ATTENTION: write the final answer. use the format -> Final Answer: ",
-			})
-
-			ctx = context.WithValue(ctx, StepsContextKey, steps)
-			return steps, nil
+			}, nil
 		}
 
-		steps = append(steps, schema.AgentStep{
+		return schema.AgentStep{
 			Action:      action,
 			Observation: fmt.Sprintf("%s is not a valid tool, try another one", action.Tool),
-		})
+		}, nil
+	}
 
-		ctx = context.WithValue(ctx, StepsContextKey, steps)
-		return steps, nil
+	// Invalid arguments are reported to the model so that it can fix them.
+	if err := e.validateToolInput(tool, action); err != nil {
+		return schema.AgentStep{
+			Action:      action,
+			Observation: fmt.Sprintf("invalid arguments for %s, fix them and try again: %s", action.Tool, err),
+		}, nil
 	}
 
 	ctx = context.WithValue(ctx, StepsContextKey, steps)
 
-	observation, err := tool.Call(ctx, action.ToolInput)
+	observation, err := e.runTool(ctx, tool, action)
 	if err != nil {
-		return nil, err
+		return schema.AgentStep{}, err
 	}
 
-	steps = append(steps, schema.AgentStep{
+	return schema.AgentStep{
 		Action:      action,
 		Observation: observation,
-	})
+	}, nil
+}
 
-	ctx = context.WithValue(ctx, StepsContextKey, steps)
+// runTool calls the tool of an action, handling its errors with the
+// ToolErrorHandler of the executor.
+func (e *Executor) runTool(ctx context.Context, tool tools.Tool, action schema.AgentAction) (string, error) {
+	for retry := 1; ; retry++ {
+		observation, err := e.callToolWithTimeout(ctx, tool, action)
+		// errors of the run, such as its cancellation, aren't errors of the tool.
+		if err == nil || e.ToolErrorHandler == nil || ctx.Err() != nil {
+			return observation, err
+		}
+
+		if retry <= e.ToolErrorHandler.MaxRetries {
+			timer := time.NewTimer(e.ToolErrorHandler.backoff(retry))
+			select {
+			case <-timer.C:
+				continue
+			case <-ctx.Done():
+				timer.Stop()
+				return "", err
+			}
+		}
+
+		if e.ToolErrorHandler.Policy == ToolErrorObserve {
+			return e.ToolErrorHandler.observation(action.Tool, err), nil
+		}
+		return "", err
+	}
+}
+
+// callToolWithTimeout calls the tool, failing with ErrToolTimeout if it
+// doesn't return before its timeout. Tools ignoring the cancellation of their
+// context are left running in the background.
+func (e *Executor) callToolWithTimeout(ctx context.Context, tool tools.Tool, action schema.AgentAction) (string, error) { //nolint:lll
+	timeout := e.toolTimeout(tool.Name())
+	if timeout <= 0 {
+		return callTool(ctx, tool, action.ToolInput, e.hasToolArguments(action))
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, ErrToolTimeout)
+	defer cancel()
+
+	type result struct {
+		observation string
+		err         error
+	}
+	results := make(chan result, 1)
+	go func() {
+		observation, err := callTool(ctx, tool, action.ToolInput, e.hasToolArguments(action))
+		results <- result{observation, err}
+	}()
+
+	select {
+	case r := <-results:
+		if r.err != nil && errors.Is(context.Cause(ctx), ErrToolTimeout) {
+			return "", fmt.Errorf("%w: %s after %s", ErrToolTimeout, tool.Name(), timeout)
+		}
+		return r.observation, r.err
+	case <-ctx.Done():
+		if errors.Is(context.Cause(ctx), ErrToolTimeout) {
+			return "", fmt.Errorf("%w: %s after %s", ErrToolTimeout, tool.Name(), timeout)
+		}
+		return "", ctx.Err()
+	}
+}
+
+func (e *Executor) toolTimeout(name string) time.Duration {
+	for toolName, timeout := range e.ToolTimeouts {
+		if strings.EqualFold(toolName, name) {
+			return timeout
+		}
+	}
+	return e.ToolTimeout
+}
+
+// callTool calls the tool with the input of an action, or with its JSON
+// arguments if the input holds them.
+func callTool(ctx context.Context, tool tools.Tool, input string, arguments bool) (string, error) {
+	if schemaTool, ok := tool.(tools.SchemaTool); ok && arguments {
+		return schemaTool.CallWithArgs(ctx, json.RawMessage(input))
+	}
+	return tool.Call(ctx, input)
+}
+
+// validateToolInput checks the JSON arguments of the actions of schema tools
+// against their schema.
+func (e *Executor) validateToolInput(tool tools.Tool, action schema.AgentAction) error {
+	if schemaTool, ok := tool.(tools.SchemaTool); ok && e.hasToolArguments(action) {
+		return jsonschema.Validate(schemaTool.Schema(), []byte(action.ToolInput))
+	}
+	return nil
+}
+
+// toolArgumentsAgent is implemented by the agents whose tool calls have the
+// JSON arguments of schema tools as input.
+type toolArgumentsAgent interface {
+	toolArguments() bool
+}
 
-	return steps, nil
+// hasToolArguments reports whether the input of the action holds the JSON
+// arguments of its tool: it is a tool call of an agent passing them, rather
+// than an input parsed from the text of the model.
+func (e *Executor) hasToolArguments(action schema.AgentAction) bool {
+	agent, ok := e.Agent.(toolArgumentsAgent)
+	return ok && agent.toolArguments() && action.ToolID != ""
 }
 
 func (e *Executor) getReturn(finish *schema.AgentFinish, steps []schema.AgentStep) map[string]any {
@@ -2,17 +2,24 @@ package agents_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/IT-Tech-Company/langchaingo/agents"
 	"github.com/IT-Tech-Company/langchaingo/chains"
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/llms/fake"
 	"github.com/IT-Tech-Company/langchaingo/llms/openai"
 	"github.com/IT-Tech-Company/langchaingo/prompts"
 	"github.com/IT-Tech-Company/langchaingo/schema"
 	"github.com/IT-Tech-Company/langchaingo/tools"
 	"github.com/IT-Tech-Company/langchaingo/tools/serpapi"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -141,3 +148,170 @@ func TestExecutorWithOpenAIFunctionAgent(t *testing.T) {
 	require.True(t, strings.Contains(result, "47") || strings.Contains(result, "49"),
 		"correct answer 47 or 49 not in response")
 }
+
+// flakyTool fails the first calls, or all of them if failures is negative,
+// and sleeps before returning.
+type flakyTool struct {
+	failures int
+	delay    time.Duration
+	calls    int
+}
+
+var errToolFailed = errors.New("connection reset")
+
+func (t *flakyTool) Name() string        { return "search" }
+func (t *flakyTool) Description() string { return "Searches the web." }
+
+func (t *flakyTool) Call(ctx context.Context, _ string) (string, error) {
+	t.calls++
+	select {
+	case <-time.After(t.delay):
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	if t.failures < 0 || t.calls <= t.failures {
+		return "", errToolFailed
+	}
+	return "sunny", nil
+}
+
+func newSearchExecutor(tool tools.Tool, opts ...agents.Option) (*agents.Executor, *fake.ScriptedLLM) {
+	llm := fake.NewScriptedLLM(
+		fake.ToolCallReply(fake.ToolCall("call_1", "search", `{"__arg1":"weather in Paris"}`)),
+		fake.TextReply("done"),
+	)
+	agent := agents.NewOpenAIFunctionsAgent(llm, []tools.Tool{tool})
+	return agents.NewExecutor(agent, opts...), llm
+}
+
+func lastObservation(t *testing.T, llm *fake.ScriptedLLM) string {
+	t.Helper()
+
+	call, ok := llm.LastCall()
+	require.True(t, ok)
+	last := call.Messages[len(call.Messages)-1]
+	require.Equal(t, llms.ChatMessageTypeFunction, last.Role)
+	return last.Parts[0].(llms.ToolCallResponse).Content
+}
+
+// echoTool is a schema tool telling whether it was called with an input or
+// with arguments.
+type echoTool struct{}
+
+func (echoTool) Name() string                  { return "echo" }
+func (echoTool) Description() string           { return "Echoes its input." }
+func (echoTool) Schema() jsonschema.Definition { return jsonschema.Definition{Type: jsonschema.Object} }
+
+func (echoTool) Call(_ context.Context, input string) (string, error) {
+	return "input " + input, nil
+}
+
+func (echoTool) CallWithArgs(_ context.Context, args json.RawMessage) (string, error) {
+	return "arguments " + string(args), nil
+}
+
+func TestExecutorSchemaToolInput(t *testing.T) {
+	t.Parallel()
+
+	// the inputs parsed from the text of the model are passed as they are,
+	// even if they are JSON objects.
+	llm := fake.NewScriptedLLM(
+		fake.TextReply("Action: echo\nAction Input: {\"text\":\"hi\"}"),
+		fake.TextReply("Final Answer: done"),
+	)
+	executor := agents.NewExecutor(agents.NewOneShotAgent(llm, []tools.Tool{echoTool{}}),
+		agents.WithReturnIntermediateSteps())
+	result, err := chains.Call(context.Background(), executor, map[string]any{"input": "Echo hi."})
+	require.NoError(t, err)
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 1)
+	assert.Equal(t, `input {"text":"hi"}`, steps[0].Observation)
+
+	// the tool calls of tool calling agents are passed as arguments.
+	llm = fake.NewScriptedLLM(
+		fake.ToolCallReply(fake.ToolCall("call_1", "echo", `{"text":"hi"}`)),
+		fake.TextReply("done"),
+	)
+	executor = agents.NewExecutor(agents.NewToolCallingAgent(llm, []tools.Tool{echoTool{}}),
+		agents.WithReturnIntermediateSteps())
+	result, err = chains.Call(context.Background(), executor, map[string]any{"input": "Echo hi."})
+	require.NoError(t, err)
+	steps, ok = result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 1)
+	assert.Equal(t, `arguments {"text":"hi"}`, steps[0].Observation)
+}
+
+func TestExecutorToolErrors(t *testing.T) {
+	t.Parallel()
+
+	// errors of tools stop the run by default.
+	executor, _ := newSearchExecutor(&flakyTool{failures: -1})
+	_, err := chains.Run(context.Background(), executor, "What is the weather in Paris?")
+	require.ErrorIs(t, err, errToolFailed)
+
+	// with the observe policy, they are given to the model.
+	tool := &flakyTool{failures: -1}
+	executor, llm := newSearchExecutor(tool,
+		agents.WithToolErrorHandler(agents.NewToolErrorHandler(agents.ToolErrorObserve, 0)))
+	out, err := chains.Run(context.Background(), executor, "What is the weather in Paris?")
+	require.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.Equal(t, 1, tool.calls)
+	assert.Equal(t, "search returned an error: connection reset", lastObservation(t, llm))
+
+	handler := agents.NewToolErrorHandler(agents.ToolErrorObserve, 2)
+	handler.Backoff = func(int) time.Duration { return time.Millisecond }
+	handler.Formatter = func(tool string, err error) string { return "retry later: " + err.Error() }
+
+	// retries hide transient errors.
+	tool = &flakyTool{failures: 2}
+	executor, llm = newSearchExecutor(tool, agents.WithToolErrorHandler(handler))
+	_, err = chains.Run(context.Background(), executor, "What is the weather in Paris?")
+	require.NoError(t, err)
+	assert.Equal(t, 3, tool.calls)
+	assert.Equal(t, "sunny", lastObservation(t, llm))
+
+	tool = &flakyTool{failures: 3}
+	executor, llm = newSearchExecutor(tool, agents.WithToolErrorHandler(handler))
+	_, err = chains.Run(context.Background(), executor, "What is the weather in Paris?")
+	require.NoError(t, err)
+	assert.Equal(t, 3, tool.calls)
+	assert.Equal(t, "retry later: connection reset", lastObservation(t, llm))
+
+	handler = agents.NewToolErrorHandler(agents.ToolErrorAbort, 1)
+	handler.Backoff = func(int) time.Duration { return time.Millisecond }
+	tool = &flakyTool{failures: -1}
+	executor, _ = newSearchExecutor(tool, agents.WithToolErrorHandler(handler))
+	_, err = chains.Run(context.Background(), executor, "What is the weather in Paris?")
+	require.ErrorIs(t, err, errToolFailed)
+	assert.Equal(t, 2, tool.calls)
+}
+
+func TestExecutorTimeouts(t *testing.T) {
+	t.Parallel()
+
+	executor, _ := newSearchExecutor(&flakyTool{delay: time.Second},
+		agents.WithToolTimeout(time.Hour), agents.WithToolTimeoutFor("Search", 10*time.Millisecond))
+	_, err := chains.Run(context.Background(), executor, "What is the weather in Paris?")
+	require.ErrorIs(t, err, agents.ErrToolTimeout)
+
+	// timeouts are errors of the tool.
+	executor, llm := newSearchExecutor(&flakyTool{delay: time.Second},
+		agents.WithToolTimeout(10*time.Millisecond),
+		agents.WithToolErrorHandler(agents.NewToolErrorHandler(agents.ToolErrorObserve, 0)))
+	_, err = chains.Run(context.Background(), executor, "What is the weather in Paris?")
+	require.NoError(t, err)
+	assert.Contains(t, lastObservation(t, llm), agents.ErrToolTimeout.Error())
+
+	// the deadline of the run isn't handled as an error of the tool.
+	executor, _ = newSearchExecutor(&flakyTool{delay: time.Second},
+		agents.WithTimeout(10*time.Millisecond),
+		agents.WithToolErrorHandler(agents.NewToolErrorHandler(agents.ToolErrorObserve, 3)))
+	start := time.Now()
+	_, err = chains.Run(context.Background(), executor, "What is the weather in Paris?")
+	require.ErrorIs(t, err, agents.ErrRunTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
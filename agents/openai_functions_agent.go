@@ -51,16 +51,21 @@ func NewOpenAIFunctionsAgent(llm llms.Model, tools []tools.Tool, opts ...Option)
 func (o *OpenAIFunctionsAgent) functions() []llms.FunctionDefinition {
 	res := make([]llms.FunctionDefinition, 0)
 	for _, tool := range o.Tools {
+		var parameters any = map[string]any{
+			"properties": map[string]any{
+				"__arg1": map[string]string{"title": "__arg1", "type": "string"},
+			},
+			"required": []string{"__arg1"},
+			"type":     "object",
+		}
+		if schemaTool, ok := tool.(tools.SchemaTool); ok {
+			parameters = schemaTool.Schema()
+		}
+
 		res = append(res, llms.FunctionDefinition{
 			Name:        tool.Name(),
 			Description: tool.Description(),
-			Parameters: map[string]any{
-				"properties": map[string]any{
-					"__arg1": map[string]string{"title": "__arg1", "type": "string"},
-				},
-				"required": []string{"__arg1"},
-				"type":     "object",
-			},
+			Parameters:  parameters,
 		})
 	}
 	return res
@@ -172,15 +177,13 @@ func (o *OpenAIFunctionsAgent) GetTools() []tools.Tool {
 }
 
 func createOpenAIFunctionPrompt(opts Options) prompts.ChatPromptTemplate {
-	messageFormatters := []prompts.MessageFormatter{prompts.NewSystemMessagePromptTemplate(opts.systemMessage, nil)}
-	messageFormatters = append(messageFormatters, opts.extraMessages...)
-	messageFormatters = append(messageFormatters, prompts.NewHumanMessagePromptTemplate("{{.input}}", []string{"input"}))
-	messageFormatters = append(messageFormatters, prompts.MessagesPlaceholder{
-		VariableName: agentScratchpad,
-	})
-
-	tmpl := prompts.NewChatPromptTemplate(messageFormatters)
-	return tmpl
+	return createToolCallingPrompt(opts)
+}
+
+// toolArguments reports that the inputs of the function calls of schema tools
+// are their JSON arguments.
+func (o *OpenAIFunctionsAgent) toolArguments() bool {
+	return true
 }
 
 func (o *OpenAIFunctionsAgent) constructScratchPad(steps []schema.AgentStep) []llms.ChatMessage {
@@ -224,8 +227,9 @@ func (o *OpenAIFunctionsAgent) ParseOutput(contentResp *llms.ContentResponse) (
 		return nil, nil, err
 	}
 
+	// schema tools take the JSON arguments, the others their __arg1 input.
 	toolInput := toolInputStr
-	if arg1, ok := toolInputMap["__arg1"]; ok {
+	if arg1, ok := toolInputMap["__arg1"]; ok && !isSchemaTool(o.Tools, functionName) {
 		toolInputCheck, ok := arg1.(string)
 		if ok {
 			toolInput = toolInputCheck
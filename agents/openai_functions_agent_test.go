@@ -0,0 +1,66 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/agents"
+	"github.com/IT-Tech-Company/langchaingo/chains"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/llms/fake"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIFunctionsAgent(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.NewScriptedLLM(
+		fake.ToolCallReply(fake.ToolCall("call_1", "calculator", `{"expression":"3*3*3*3"}`)),
+		fake.TextReply("3 to the power of 4 is 81."),
+	)
+	agent := agents.NewOpenAIFunctionsAgent(llm, []tools.Tool{tools.Calculator{}})
+	executor := agents.NewExecutor(agent)
+
+	out, err := chains.Run(context.Background(), executor, "What is 3 to the power of 4?")
+	require.NoError(t, err)
+	assert.Equal(t, "3 to the power of 4 is 81.", out)
+
+	calls := llm.Calls()
+	require.Len(t, calls, 2)
+	require.Len(t, calls[0].Options.Functions, 1)
+	assert.Equal(t, "calculator", calls[0].Options.Functions[0].Name)
+	// the model is scripted with the arguments of the advertised schema.
+	assert.Equal(t, tools.Calculator{}.Schema(), calls[0].Options.Functions[0].Parameters)
+
+	// the result computed by the tool is given to the model in the second call.
+	last := calls[1].Messages[len(calls[1].Messages)-1]
+	assert.Equal(t, llms.ChatMessageTypeFunction, last.Role)
+	require.Len(t, last.Parts, 1)
+	assert.Equal(t, "81", last.Parts[0].(llms.ToolCallResponse).Content)
+}
+
+func TestOpenAIFunctionsAgentToolInputs(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.NewScriptedLLM(
+		fake.ToolCallReply(fake.ToolCall("call_1", "calculator", `{"expression":"6 * 7"}`)),
+		fake.ToolCallReply(fake.ToolCall("call_2", "sql", `{"__arg1":"SELECT 42"}`)),
+		fake.TextReply("42"),
+	)
+	sql := &sqlTool{}
+	agent := agents.NewOpenAIFunctionsAgent(llm, []tools.Tool{tools.Calculator{}, sql})
+	executor := agents.NewExecutor(agent, agents.WithReturnIntermediateSteps())
+
+	out, err := chains.Call(context.Background(), executor, map[string]any{"input": "What is 6 times 7?"})
+	require.NoError(t, err)
+
+	// the schema tool gets its arguments, the other tool its __arg1 input.
+	steps, ok := out["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 2)
+	assert.Equal(t, "42", steps[0].Observation)
+	assert.Equal(t, []string{"SELECT 42"}, sql.queries)
+}
@@ -1,6 +1,8 @@
 package agents
 
 import (
+	"time"
+
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
 	"github.com/IT-Tech-Company/langchaingo/memory"
 	"github.com/IT-Tech-Company/langchaingo/prompts"
@@ -17,6 +19,13 @@ type Options struct {
 	memory                  schema.Memory
 	callbacksHandler        callbacks.Handler
 	errorHandler            *ParserErrorHandler
+	toolErrorHandler        *ToolErrorHandler
+	toolTimeout             time.Duration
+	toolTimeouts            map[string]time.Duration
+	timeout                 time.Duration
+	approver                ApprovalFunc
+	approvalTools           []string
+	checkpointer            Checkpointer
 	maxIterations           int
 	returnIntermediateSteps bool
 	outputKey               string
@@ -24,7 +33,7 @@ type Options struct {
 	formatInstructions      string
 	promptSuffix            string
 
-	// openai
+	// openai and tool calling
 	systemMessage string
 	extraMessages []prompts.MessageFormatter
 }
@@ -66,6 +75,10 @@ func openAIFunctionsDefaultOptions() Options {
 	}
 }
 
+func toolCallingDefaultOptions() Options {
+	return openAIFunctionsDefaultOptions()
+}
+
 func (co Options) getMrklPrompt(tools []tools.Tool) prompts.PromptTemplate {
 	if co.prompt.Template != "" {
 		return co.prompt
@@ -165,20 +178,92 @@ func WithParserErrorHandler(errorHandler *ParserErrorHandler) Option {
 	}
 }
 
-type OpenAIOption struct{}
+// WithToolErrorHandler is an option for setting how an executor handles the errors of tools.
+// Without it, the errors of tools stop the run.
+func WithToolErrorHandler(errorHandler *ToolErrorHandler) Option {
+	return func(co *Options) {
+		co.toolErrorHandler = errorHandler
+	}
+}
 
-func NewOpenAIOption() OpenAIOption {
-	return OpenAIOption{}
+// WithToolTimeout is an option for setting the time the tools of an executor have to return.
+// Tools that time out fail with ErrToolTimeout, which is handled like their other errors.
+func WithToolTimeout(timeout time.Duration) Option {
+	return func(co *Options) {
+		co.toolTimeout = timeout
+	}
 }
 
-func (o OpenAIOption) WithSystemMessage(msg string) Option {
+// WithToolTimeoutFor is an option for setting the timeout of a tool of an executor, which
+// overrides the one set with WithToolTimeout.
+func WithToolTimeoutFor(toolName string, timeout time.Duration) Option {
+	return func(co *Options) {
+		if co.toolTimeouts == nil {
+			co.toolTimeouts = make(map[string]time.Duration)
+		}
+		co.toolTimeouts[toolName] = timeout
+	}
+}
+
+// WithTimeout is an option for setting the time an executor has to finish a run, after which
+// it stops with ErrRunTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(co *Options) {
+		co.timeout = timeout
+	}
+}
+
+// WithApproval is an option for asking the approval function before an executor runs the
+// actions using the given tools, or any tool if none is given.
+func WithApproval(approver ApprovalFunc, toolNames ...string) Option {
+	return func(co *Options) {
+		co.approver = approver
+		co.approvalTools = toolNames
+	}
+}
+
+// WithInterruptBefore is an option for suspending the runs of an executor before the actions
+// using the given tools, which fail with an InterruptError until resumed with a decision.
+func WithInterruptBefore(toolNames ...string) Option {
+	return func(co *Options) {
+		co.approvalTools = toolNames
+	}
+}
+
+// WithCheckpointer is an option for saving the state of the runs of an executor after each
+// iteration, for the runs whose context has a thread ID given with ContextWithThreadID.
+func WithCheckpointer(checkpointer Checkpointer) Option {
+	return func(co *Options) {
+		co.checkpointer = checkpointer
+	}
+}
+
+// WithSystemMessage is an option for setting the system message of the prompt
+// used by the tool calling agents.
+func WithSystemMessage(msg string) Option {
 	return func(co *Options) {
 		co.systemMessage = msg
 	}
 }
 
-func (o OpenAIOption) WithExtraMessages(extraMessages []prompts.MessageFormatter) Option {
+// WithExtraMessages is an option for adding messages between the system
+// message and the input in the prompt used by the tool calling agents.
+func WithExtraMessages(extraMessages []prompts.MessageFormatter) Option {
 	return func(co *Options) {
 		co.extraMessages = extraMessages
 	}
 }
+
+type OpenAIOption struct{}
+
+func NewOpenAIOption() OpenAIOption {
+	return OpenAIOption{}
+}
+
+func (o OpenAIOption) WithSystemMessage(msg string) Option {
+	return WithSystemMessage(msg)
+}
+
+func (o OpenAIOption) WithExtraMessages(extraMessages []prompts.MessageFormatter) Option {
+	return WithExtraMessages(extraMessages)
+}
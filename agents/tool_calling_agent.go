@@ -0,0 +1,321 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/prompts"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/tools"
+)
+
+// toolInputKey is the name of the single argument of the tools given to the
+// model.
+const toolInputKey = "input"
+
+// ToolCallingAgent is an Agent driven by the native tool calling API of the
+// model. It works with every model supporting llms.WithTools, and may ask for
+// several tools to be called in parallel. Tools implementing tools.SchemaTool
+// are given their own argument schema, the others take a single string input.
+type ToolCallingAgent struct {
+	// LLM is the model used to plan the next actions.
+	LLM llms.Model
+	// Prompt is the prompt used to call the model. The intermediate steps are
+	// added as tool calls and results after the prompt's messages.
+	Prompt prompts.FormatPrompter
+	// Tools is a list of the tools the agent can use.
+	Tools []tools.Tool
+	// Output key is the key where the final output is placed.
+	OutputKey string
+	// CallbacksHandler is the handler for callbacks.
+	CallbacksHandler callbacks.Handler
+}
+
+var _ Agent = (*ToolCallingAgent)(nil)
+
+// NewToolCallingAgent creates a new ToolCallingAgent.
+func NewToolCallingAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *ToolCallingAgent {
+	options := toolCallingDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &ToolCallingAgent{
+		LLM:              llm,
+		Prompt:           createToolCallingPrompt(options),
+		Tools:            tools,
+		OutputKey:        options.outputKey,
+		CallbacksHandler: options.callbacksHandler,
+	}
+}
+
+// Plan decides what action to take or returns the final result of the input.
+func (a *ToolCallingAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	fullInputs := make(map[string]any, len(inputs)+1)
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+	// The steps can't be represented as chat messages faithfully, so they are
+	// appended to the formatted prompt instead.
+	fullInputs[agentScratchpad] = []llms.ChatMessage(nil)
+
+	prompt, err := a.Prompt.FormatPrompt(fullInputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages := make([]llms.MessageContent, 0, len(prompt.Messages())+2*len(intermediateSteps))
+	for _, msg := range prompt.Messages() {
+		messages = append(messages, llms.TextParts(msg.GetType(), msg.GetContent()))
+	}
+	messages = append(messages, a.constructScratchPad(intermediateSteps)...)
+
+	options := []llms.CallOption{llms.WithTools(a.tools())}
+	if a.CallbacksHandler != nil {
+		options = append(options, llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			a.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
+			return nil
+		}))
+	}
+
+	result, err := a.LLM.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.ParseOutput(result)
+}
+
+// ParseOutput converts the response of the model into the actions to take, one
+// for each tool call, or into a finish if the model didn't call any tool.
+func (a *ToolCallingAgent) ParseOutput(contentResp *llms.ContentResponse) (
+	[]schema.AgentAction, *schema.AgentFinish, error,
+) {
+	// Some models return the text and each tool call as separate choices.
+	var content strings.Builder
+	var toolCalls []llms.ToolCall
+	for _, choice := range contentResp.Choices {
+		content.WriteString(choice.Content)
+		toolCalls = append(toolCalls, choice.ToolCalls...)
+	}
+
+	if len(toolCalls) == 0 {
+		return nil, &schema.AgentFinish{
+			ReturnValues: map[string]any{
+				a.OutputKey: content.String(),
+			},
+			Log: content.String(),
+		}, nil
+	}
+
+	for _, toolCall := range toolCalls {
+		if toolCall.FunctionCall == nil {
+			return nil, nil, fmt.Errorf("%w: tool call without function", ErrUnableToParseOutput)
+		}
+	}
+
+	// All the actions of a response share the log, which is how the scratch
+	// pad tells them apart from the actions of other responses.
+	log := toolCallingLog(content.String(), toolCalls)
+	actions := make([]schema.AgentAction, 0, len(toolCalls))
+	for _, toolCall := range toolCalls {
+		actions = append(actions, schema.AgentAction{
+			Tool:      toolCall.FunctionCall.Name,
+			ToolInput: a.toolCallInput(toolCall.FunctionCall.Name, toolCall.FunctionCall.Arguments),
+			Log:       log,
+			ToolID:    toolCall.ID,
+		})
+	}
+	return actions, nil, nil
+}
+
+func (a *ToolCallingAgent) GetInputKeys() []string {
+	chainInputs := a.Prompt.GetInputVariables()
+
+	// Remove inputs given in plan.
+	agentInput := make([]string, 0, len(chainInputs))
+	for _, v := range chainInputs {
+		if v == agentScratchpad {
+			continue
+		}
+		agentInput = append(agentInput, v)
+	}
+
+	return agentInput
+}
+
+func (a *ToolCallingAgent) GetOutputKeys() []string {
+	return []string{a.OutputKey}
+}
+
+func (a *ToolCallingAgent) GetTools() []tools.Tool {
+	return a.Tools
+}
+
+// tools returns the definitions of the agent's tools for the model.
+func (a *ToolCallingAgent) tools() []llms.Tool {
+	res := make([]llms.Tool, 0, len(a.Tools))
+	for _, tool := range a.Tools {
+		var parameters any = map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				toolInputKey: map[string]any{
+					"type":        "string",
+					"description": "The input of the tool.",
+				},
+			},
+			"required": []string{toolInputKey},
+		}
+		if schemaTool, ok := tool.(tools.SchemaTool); ok {
+			parameters = schemaTool.Schema()
+		}
+
+		res = append(res, llms.Tool{
+			Type: "function",
+			Function: &llms.FunctionDefinition{
+				Name:        tool.Name(),
+				Description: tool.Description(),
+				Parameters:  parameters,
+			},
+		})
+	}
+	return res
+}
+
+// toolArguments reports that the inputs of the tool calls of schema tools are
+// their JSON arguments, see toolCallInput.
+func (a *ToolCallingAgent) toolArguments() bool {
+	return true
+}
+
+// isSchemaTool reports whether the tool of the agent with the given name takes
+// structured arguments.
+func isSchemaTool(agentTools []tools.Tool, name string) bool {
+	for _, tool := range agentTools {
+		if strings.EqualFold(tool.Name(), name) {
+			_, ok := tool.(tools.SchemaTool)
+			return ok
+		}
+	}
+	return false
+}
+
+func createToolCallingPrompt(opts Options) prompts.ChatPromptTemplate {
+	messageFormatters := []prompts.MessageFormatter{prompts.NewSystemMessagePromptTemplate(opts.systemMessage, nil)}
+	messageFormatters = append(messageFormatters, opts.extraMessages...)
+	messageFormatters = append(messageFormatters, prompts.NewHumanMessagePromptTemplate("{{.input}}", []string{"input"}))
+	messageFormatters = append(messageFormatters, prompts.MessagesPlaceholder{
+		VariableName: agentScratchpad,
+	})
+
+	return prompts.NewChatPromptTemplate(messageFormatters)
+}
+
+// constructScratchPad converts the intermediate steps into an AI message with
+// the tool calls of each response of the model, followed by a tool message with
+// the result of each call.
+func (a *ToolCallingAgent) constructScratchPad(steps []schema.AgentStep) []llms.MessageContent {
+	messages := make([]llms.MessageContent, 0, 2*len(steps))
+	for i := 0; i < len(steps); {
+		// Steps without an action, e.g. for parser errors, are passed to the
+		// model as text.
+		if steps[i].Action.Tool == "" {
+			messages = append(messages, llms.TextParts(llms.ChatMessageTypeHuman, steps[i].Observation))
+			i++
+			continue
+		}
+
+		// The actions of one response are next to each other and share the
+		// same log.
+		j := i + 1
+		for j < len(steps) && steps[j].Action.Tool != "" && steps[j].Action.Log == steps[i].Action.Log {
+			j++
+		}
+
+		ai := llms.MessageContent{Role: llms.ChatMessageTypeAI}
+		if content := toolCallingContent(steps[i].Action.Log); content != "" {
+			ai.Parts = append(ai.Parts, llms.TextContent{Text: content})
+		}
+		for _, step := range steps[i:j] {
+			ai.Parts = append(ai.Parts, llms.ToolCall{
+				ID:   step.Action.ToolID,
+				Type: "function",
+				FunctionCall: &llms.FunctionCall{
+					Name:      step.Action.Tool,
+					Arguments: a.toolCallArguments(step.Action.Tool, step.Action.ToolInput),
+				},
+			})
+		}
+		messages = append(messages, ai)
+
+		for _, step := range steps[i:j] {
+			messages = append(messages, llms.MessageContent{
+				Role: llms.ChatMessageTypeTool,
+				Parts: []llms.ContentPart{llms.ToolCallResponse{
+					ToolCallID: step.Action.ToolID,
+					Name:       step.Action.Tool,
+					Content:    step.Observation,
+				}},
+			})
+		}
+		i = j
+	}
+	return messages
+}
+
+// toolCallInput returns the tool input from the arguments of a tool call. The
+// arguments of schema tools are kept as they are, as are the arguments not
+// following the schema of the other tools.
+func (a *ToolCallingAgent) toolCallInput(tool, arguments string) string {
+	if isSchemaTool(a.Tools, tool) {
+		return arguments
+	}
+
+	var args map[string]any
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return arguments
+	}
+	if input, ok := args[toolInputKey].(string); ok && len(args) == 1 {
+		return input
+	}
+	return arguments
+}
+
+// toolCallArguments returns the arguments of a tool call for the tool input.
+func (a *ToolCallingAgent) toolCallArguments(tool, input string) string {
+	if isSchemaTool(a.Tools, tool) {
+		return input
+	}
+
+	args, _ := json.Marshal(map[string]string{toolInputKey: input}) // nolint:errchkjson
+	return string(args)
+}
+
+// toolCallingLogSeparator separates the text of the response from the tool
+// calls in the log of an action.
+const toolCallingLogSeparator = "\nInvoking: "
+
+func toolCallingLog(content string, toolCalls []llms.ToolCall) string {
+	var sb strings.Builder
+	sb.WriteString(content)
+	for _, toolCall := range toolCalls {
+		fmt.Fprintf(&sb, "%s%s %s with %s", toolCallingLogSeparator,
+			toolCall.ID, toolCall.FunctionCall.Name, toolCall.FunctionCall.Arguments)
+	}
+	return sb.String()
+}
+
+// toolCallingContent returns the text of the response an action's log was
+// created from.
+func toolCallingContent(log string) string {
+	content, _, _ := strings.Cut(log, toolCallingLogSeparator)
+	return content
+}
@@ -0,0 +1,222 @@
+package agents_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/agents"
+	"github.com/IT-Tech-Company/langchaingo/chains"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toolCallingModel returns its responses in order and records the messages
+// and options of each call.
+type toolCallingModel struct {
+	responses []*llms.ContentResponse
+
+	mu       sync.Mutex
+	messages [][]llms.MessageContent
+	options  []llms.CallOptions
+}
+
+func (m *toolCallingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *toolCallingModel) GenerateContent(_ context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	m.messages = append(m.messages, messages)
+	m.options = append(m.options, opts)
+
+	response := m.responses[0]
+	m.responses = m.responses[1:]
+	return response, nil
+}
+
+// barrierTool only returns once all the tools sharing its barrier are called,
+// so it fails unless the tools run concurrently.
+type barrierTool struct {
+	name    string
+	barrier *sync.WaitGroup
+}
+
+func (t barrierTool) Name() string        { return t.name }
+func (t barrierTool) Description() string { return "Looks up the weather of a city." }
+
+func (t barrierTool) Call(_ context.Context, input string) (string, error) {
+	t.barrier.Done()
+	done := make(chan struct{})
+	go func() {
+		t.barrier.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return "sunny in " + input, nil
+	case <-time.After(5 * time.Second):
+		return "", context.DeadlineExceeded
+	}
+}
+
+func toolCall(id, name, arguments string) llms.ToolCall {
+	return llms.ToolCall{
+		ID:           id,
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: name, Arguments: arguments},
+	}
+}
+
+func TestToolCallingAgent(t *testing.T) {
+	t.Parallel()
+
+	model := &toolCallingModel{responses: []*llms.ContentResponse{
+		{Choices: []*llms.ContentChoice{{
+			Content: "Let me check.",
+			ToolCalls: []llms.ToolCall{
+				toolCall("call_1", "weather", `{"input":"Paris"}`),
+				toolCall("call_2", "weather", `{"input":"Rome"}`),
+			},
+		}}},
+		{Choices: []*llms.ContentChoice{{Content: "Sunny in both cities."}}},
+	}}
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	agent := agents.NewToolCallingAgent(model, []tools.Tool{barrierTool{name: "weather", barrier: &barrier}},
+		agents.WithSystemMessage("You are a weather assistant."))
+	executor := agents.NewExecutor(agent, agents.WithReturnIntermediateSteps())
+
+	result, err := chains.Call(context.Background(), executor, map[string]any{"input": "Weather in Paris and Rome?"})
+	require.NoError(t, err)
+	assert.Equal(t, "Sunny in both cities.", result["output"])
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 2)
+	assert.Equal(t, "Paris", steps[0].Action.ToolInput)
+	assert.Equal(t, "call_1", steps[0].Action.ToolID)
+	assert.Equal(t, "sunny in Paris", steps[0].Observation)
+	assert.Equal(t, "call_2", steps[1].Action.ToolID)
+	assert.Equal(t, "sunny in Rome", steps[1].Observation)
+
+	require.Len(t, model.options, 2)
+	require.Len(t, model.options[0].Tools, 1)
+	assert.Equal(t, "weather", model.options[0].Tools[0].Function.Name)
+
+	require.Len(t, model.messages, 2)
+	assert.Equal(t, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "You are a weather assistant."),
+		llms.TextParts(llms.ChatMessageTypeHuman, "Weather in Paris and Rome?"),
+	}, model.messages[0])
+	assert.Equal(t, []llms.MessageContent{
+		{
+			Role: llms.ChatMessageTypeAI,
+			Parts: []llms.ContentPart{
+				llms.TextContent{Text: "Let me check."},
+				toolCall("call_1", "weather", `{"input":"Paris"}`),
+				toolCall("call_2", "weather", `{"input":"Rome"}`),
+			},
+		},
+		{
+			Role: llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{llms.ToolCallResponse{
+				ToolCallID: "call_1", Name: "weather", Content: "sunny in Paris",
+			}},
+		},
+		{
+			Role: llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{llms.ToolCallResponse{
+				ToolCallID: "call_2", Name: "weather", Content: "sunny in Rome",
+			}},
+		},
+	}, model.messages[1][2:])
+}
+
+func TestToolCallingAgentParseOutput(t *testing.T) {
+	t.Parallel()
+
+	agent := agents.NewToolCallingAgent(&toolCallingModel{}, nil)
+
+	// Arguments not following the tool schema are passed as they are.
+	actions, finish, err := agent.ParseOutput(&llms.ContentResponse{Choices: []*llms.ContentChoice{
+		{Content: "Searching."},
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "search", `{"query":"go"}`)}},
+	}})
+	require.NoError(t, err)
+	assert.Nil(t, finish)
+	require.Len(t, actions, 1)
+	assert.Equal(t, `{"query":"go"}`, actions[0].ToolInput)
+	assert.Equal(t, "search", actions[0].Tool)
+
+	actions, finish, err = agent.ParseOutput(&llms.ContentResponse{Choices: []*llms.ContentChoice{
+		{Content: "Done."},
+	}})
+	require.NoError(t, err)
+	assert.Empty(t, actions)
+	require.NotNil(t, finish)
+	assert.Equal(t, "Done.", finish.ReturnValues["output"])
+
+	// tool calls without function are rejected.
+	_, _, err = agent.ParseOutput(&llms.ContentResponse{Choices: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{{ID: "x"}}},
+	}})
+	require.ErrorIs(t, err, agents.ErrUnableToParseOutput)
+}
+
+func TestToolCallingAgentSchemaTool(t *testing.T) {
+	t.Parallel()
+
+	model := &toolCallingModel{responses: []*llms.ContentResponse{
+		{Choices: []*llms.ContentChoice{{
+			ToolCalls: []llms.ToolCall{toolCall("call_1", "calculator", `{"expression":"6 * 7"}`)},
+		}}},
+		{Choices: []*llms.ContentChoice{{Content: "42"}}},
+	}}
+	agent := agents.NewToolCallingAgent(model, []tools.Tool{tools.Calculator{}})
+	executor := agents.NewExecutor(agent, agents.WithReturnIntermediateSteps())
+
+	result, err := chains.Call(context.Background(), executor, map[string]any{"input": "What is 6 times 7?"})
+	require.NoError(t, err)
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 1)
+	assert.Equal(t, `{"expression":"6 * 7"}`, steps[0].Action.ToolInput)
+	assert.Equal(t, "42", steps[0].Observation)
+
+	assert.Equal(t, tools.Calculator{}.Schema(), model.options[0].Tools[0].Function.Parameters)
+	assert.Equal(t, toolCall("call_1", "calculator", `{"expression":"6 * 7"}`), model.messages[1][2].Parts[0])
+}
+
+func TestToolCallingAgentInvalidArguments(t *testing.T) {
+	t.Parallel()
+
+	model := &toolCallingModel{responses: []*llms.ContentResponse{
+		{Choices: []*llms.ContentChoice{{
+			ToolCalls: []llms.ToolCall{toolCall("call_1", "calculator", `{"expr":"6 * 7"}`)},
+		}}},
+		{Choices: []*llms.ContentChoice{{Content: "I don't know."}}},
+	}}
+	agent := agents.NewToolCallingAgent(model, []tools.Tool{tools.Calculator{}})
+	executor := agents.NewExecutor(agent, agents.WithReturnIntermediateSteps())
+
+	result, err := chains.Call(context.Background(), executor, map[string]any{"input": "What is 6 times 7?"})
+	require.NoError(t, err)
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 1)
+	assert.Contains(t, steps[0].Observation, "invalid arguments for calculator")
+	assert.Contains(t, steps[0].Observation, `missing required property "expression"`)
+}
@@ -12,7 +12,10 @@ type CombiningHandler struct {
 	Callbacks []Handler
 }
 
-var _ Handler = CombiningHandler{}
+var (
+	_ Handler          = CombiningHandler{}
+	_ AgentStepHandler = CombiningHandler{}
+)
 
 func (l CombiningHandler) HandleText(ctx context.Context, text string) {
 	for _, handle := range l.Callbacks {
@@ -109,3 +112,19 @@ func (l CombiningHandler) HandleToolError(ctx context.Context, err error) {
 		handle.HandleToolError(ctx, err)
 	}
 }
+
+func (l CombiningHandler) HandleAgentStepStart(ctx context.Context, iteration int) {
+	for _, handle := range l.Callbacks {
+		if h, ok := handle.(AgentStepHandler); ok {
+			h.HandleAgentStepStart(ctx, iteration)
+		}
+	}
+}
+
+func (l CombiningHandler) HandleAgentStepEnd(ctx context.Context, err error) {
+	for _, handle := range l.Callbacks {
+		if h, ok := handle.(AgentStepHandler); ok {
+			h.HandleAgentStepEnd(ctx, err)
+		}
+	}
+}
@@ -1,4 +1,11 @@
 // Package callbacks includes a standard interface for hooking into various
 // stages of your LLM application. The package contains an implementation of
 // this interface that prints to the standard output.
+//
+// Each execution of a component is a Run, carried by the context of its
+// events. Runs started during another run are its children, which lets
+// handlers such as the one of the opentelemetry package rebuild the tree of
+// the runs of an application, even when components run concurrently. The
+// Tracer handler records these trees with the inputs, outputs, timings and
+// errors of the runs, and exports them as JSON.
 package callbacks
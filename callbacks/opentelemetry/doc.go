@@ -0,0 +1,22 @@
+// Package opentelemetry provides a callbacks handler that traces the runs of
+// an application with OpenTelemetry and records their metrics.
+//
+// Each run of a chain, model, tool, retriever or agent step is a span, nested
+// under the span of its parent run, so concurrent calls, e.g. with
+// chains.Apply, produce separate trees:
+//
+//	handler, err := opentelemetry.New(
+//		opentelemetry.WithTracerProvider(tracerProvider),
+//		opentelemetry.WithMeterProvider(meterProvider),
+//	)
+//	llm, err := openai.New(openai.WithCallback(handler))
+//	chain := chains.NewLLMChain(llm, prompt)
+//	chain.CallbacksHandler = handler
+//
+// The spans and metrics of model calls follow the OpenTelemetry semantic
+// conventions for generative AI: spans have the provider, the requested
+// model, the token usage and the finish reasons as attributes, and the
+// gen_ai.client.operation.duration and gen_ai.client.token.usage histograms
+// record the latency and the tokens of the calls. The langchaingo.run.duration
+// histogram records the latency of the runs of every kind.
+package opentelemetry
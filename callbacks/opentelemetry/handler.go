@@ -0,0 +1,362 @@
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer and of the meter.
+const instrumentationName = "github.com/IT-Tech-Company/langchaingo/callbacks/opentelemetry"
+
+// Attributes of the spans and metrics. The gen_ai ones are defined by the
+// semantic conventions for generative AI.
+const (
+	AttributeRunID          = attribute.Key("langchaingo.run.id")
+	AttributeRunKind        = attribute.Key("langchaingo.run.kind")
+	AttributeRunName        = attribute.Key("langchaingo.run.name")
+	AttributeAgentIteration = attribute.Key("langchaingo.agent.iteration")
+	AttributeDocuments      = attribute.Key("langchaingo.retriever.documents")
+	AttributeToolName       = attribute.Key("langchaingo.tool.name")
+
+	AttributeGenAISystem        = attribute.Key("gen_ai.system")
+	AttributeGenAIOperationName = attribute.Key("gen_ai.operation.name")
+	AttributeGenAIRequestModel  = attribute.Key("gen_ai.request.model")
+	AttributeGenAIInputTokens   = attribute.Key("gen_ai.usage.input_tokens")
+	AttributeGenAIOutputTokens  = attribute.Key("gen_ai.usage.output_tokens")
+	AttributeGenAIFinishReasons = attribute.Key("gen_ai.response.finish_reasons")
+	AttributeGenAITokenType     = attribute.Key("gen_ai.token.type")
+	AttributeErrorType          = attribute.Key("error.type")
+)
+
+// operationChat is the gen_ai.operation.name of the calls to models.
+const operationChat = "chat"
+
+// Handler is a callbacks handler that records the runs of an application as
+// OpenTelemetry spans and metrics. The runs are identified by the
+// callbacks.Run of the context of the events, and events without a run are
+// ignored.
+//
+// A Handler is safe for concurrent use, and can be shared by all the
+// components of an application.
+type Handler struct {
+	tracer trace.Tracer
+
+	operationDuration metric.Float64Histogram
+	tokenUsage        metric.Int64Histogram
+	runDuration       metric.Float64Histogram
+
+	mu    sync.Mutex
+	spans map[string]*runSpan
+}
+
+// runSpan is the span of a run in progress.
+type runSpan struct {
+	run callbacks.Run
+	// parentID is the ID of the nearest ancestor of the run with a span.
+	parentID string
+	span     trace.Span
+	start    time.Time
+	attrs    []attribute.KeyValue
+}
+
+var (
+	_ callbacks.Handler          = (*Handler)(nil)
+	_ callbacks.AgentStepHandler = (*Handler)(nil)
+)
+
+// New creates a Handler, which uses the global providers unless others are
+// given with WithTracerProvider and WithMeterProvider.
+func New(opts ...Option) (*Handler, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	meter := o.meterProvider.Meter(instrumentationName)
+	operationDuration, err := meter.Float64Histogram(
+		"gen_ai.client.operation.duration",
+		metric.WithDescription("Duration of the calls to models."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("opentelemetry: create histogram: %w", err)
+	}
+	tokenUsage, err := meter.Int64Histogram(
+		"gen_ai.client.token.usage",
+		metric.WithDescription("Number of input and output tokens of the calls to models."),
+		metric.WithUnit("{token}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("opentelemetry: create histogram: %w", err)
+	}
+	runDuration, err := meter.Float64Histogram(
+		"langchaingo.run.duration",
+		metric.WithDescription("Duration of the runs of chains, models, tools, retrievers and agent steps."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("opentelemetry: create histogram: %w", err)
+	}
+
+	return &Handler{
+		tracer:            o.tracerProvider.Tracer(instrumentationName),
+		operationDuration: operationDuration,
+		tokenUsage:        tokenUsage,
+		runDuration:       runDuration,
+		spans:             make(map[string]*runSpan),
+	}, nil
+}
+
+// startSpan starts the span of the run of the context. The span is a child of
+// the span of the nearest ancestor run with a span, e.g. skipping the runs of
+// chains without this handler, or of the span of the context for root runs.
+func (h *Handler) startSpan(ctx context.Context, attrs ...attribute.KeyValue) {
+	run, ok := callbacks.RunFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	name := fmt.Sprintf("%s %s", run.Kind, run.Name)
+	attrs = append(attrs,
+		AttributeRunKind.String(string(run.Kind)),
+		AttributeRunName.String(run.Name),
+	)
+	if run.Kind == callbacks.RunKindLLM {
+		name = operationChat
+		if run.Model != "" {
+			name += " " + run.Model
+			attrs = append(attrs, AttributeGenAIRequestModel.String(run.Model))
+		}
+		attrs = append(attrs,
+			AttributeGenAISystem.String(run.Name),
+			AttributeGenAIOperationName.String(operationChat),
+		)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, started := h.spans[run.ID]; started {
+		return
+	}
+	var parentID string
+	for _, id := range run.AncestorIDs {
+		if parent, ok := h.spans[id]; ok {
+			ctx = trace.ContextWithSpan(ctx, parent.span)
+			parentID = id
+			break
+		}
+	}
+	spanKind := trace.SpanKindInternal
+	if run.Kind == callbacks.RunKindLLM {
+		spanKind = trace.SpanKindClient
+	}
+	_, span := h.tracer.Start(ctx, name,
+		trace.WithSpanKind(spanKind),
+		trace.WithAttributes(append(attrs, AttributeRunID.String(run.ID))...),
+	)
+	h.spans[run.ID] = &runSpan{run: run, parentID: parentID, span: span, start: time.Now(), attrs: attrs}
+}
+
+// endSpan ends the span of the run of the context, and the spans of its
+// descendants which are still in progress, e.g. because an error stopped them
+// before their end event.
+func (h *Handler) endSpan(ctx context.Context, err error, attrs ...attribute.KeyValue) {
+	run, ok := callbacks.RunFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	h.mu.Lock()
+	s, ok := h.spans[run.ID]
+	if !ok {
+		h.mu.Unlock()
+		return
+	}
+	ended := h.removeDescendants(run.ID)
+	delete(h.spans, run.ID)
+	h.mu.Unlock()
+
+	for _, d := range ended {
+		h.finish(ctx, d, nil)
+	}
+	s.span.SetAttributes(attrs...)
+	s.attrs = append(s.attrs, attrs...)
+	h.finish(ctx, s, err)
+}
+
+// removeDescendants removes the spans of the descendants of a run and returns
+// them, the deepest first. The lock must be held.
+func (h *Handler) removeDescendants(id string) []*runSpan {
+	var descendants []*runSpan
+	for childID, s := range h.spans {
+		if s.parentID != id {
+			continue
+		}
+		descendants = append(descendants, h.removeDescendants(childID)...)
+		descendants = append(descendants, s)
+		delete(h.spans, childID)
+	}
+	return descendants
+}
+
+// finish ends a span and records the duration of its run.
+func (h *Handler) finish(ctx context.Context, s *runSpan, err error) {
+	durationAttrs := []attribute.KeyValue{
+		AttributeRunKind.String(string(s.run.Kind)),
+		AttributeRunName.String(s.run.Name),
+	}
+	if err != nil {
+		errorType := fmt.Sprintf("%T", err)
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+		s.span.SetAttributes(AttributeErrorType.String(errorType))
+		durationAttrs = append(durationAttrs, AttributeErrorType.String(errorType))
+	}
+	s.span.End()
+
+	duration := time.Since(s.start).Seconds()
+	h.runDuration.Record(ctx, duration, metric.WithAttributes(durationAttrs...))
+	if s.run.Kind != callbacks.RunKindLLM {
+		return
+	}
+
+	genAIAttrs := []attribute.KeyValue{
+		AttributeGenAISystem.String(s.run.Name),
+		AttributeGenAIOperationName.String(operationChat),
+	}
+	if s.run.Model != "" {
+		genAIAttrs = append(genAIAttrs, AttributeGenAIRequestModel.String(s.run.Model))
+	}
+	operationAttrs := metric.WithAttributes(genAIAttrs...)
+	if err != nil {
+		operationAttrs = metric.WithAttributes(append(slices.Clip(genAIAttrs), AttributeErrorType.String(fmt.Sprintf("%T", err)))...)
+	}
+	h.operationDuration.Record(ctx, duration, operationAttrs)
+
+	for _, attr := range s.attrs {
+		var tokenType string
+		switch attr.Key {
+		case AttributeGenAIInputTokens:
+			tokenType = "input"
+		case AttributeGenAIOutputTokens:
+			tokenType = "output"
+		default:
+			continue
+		}
+		h.tokenUsage.Record(ctx, attr.Value.AsInt64(), metric.WithAttributes(
+			append(slices.Clip(genAIAttrs), AttributeGenAITokenType.String(tokenType))...,
+		))
+	}
+}
+
+// addEvent adds an event to the span of the run of the context.
+func (h *Handler) addEvent(ctx context.Context, name string, attrs ...attribute.KeyValue) {
+	run, ok := callbacks.RunFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.spans[run.ID]; ok {
+		s.span.AddEvent(name, trace.WithAttributes(attrs...))
+	}
+}
+
+func (h *Handler) HandleText(context.Context, string) {}
+
+func (h *Handler) HandleLLMStart(context.Context, []string) {}
+
+func (h *Handler) HandleLLMGenerateContentStart(ctx context.Context, _ []llms.MessageContent) {
+	h.startSpan(ctx)
+}
+
+func (h *Handler) HandleLLMGenerateContentEnd(ctx context.Context, res *llms.ContentResponse) {
+	var attrs []attribute.KeyValue
+	if res != nil {
+		if res.Usage != nil {
+			attrs = append(attrs,
+				AttributeGenAIInputTokens.Int(res.Usage.InputTokens),
+				AttributeGenAIOutputTokens.Int(res.Usage.OutputTokens),
+			)
+		}
+		reasons := make([]string, 0, len(res.Choices))
+		for _, choice := range res.Choices {
+			if choice != nil && choice.StopReason != "" {
+				reasons = append(reasons, choice.StopReason)
+			}
+		}
+		if len(reasons) > 0 {
+			attrs = append(attrs, AttributeGenAIFinishReasons.StringSlice(reasons))
+		}
+	}
+	h.endSpan(ctx, nil, attrs...)
+}
+
+func (h *Handler) HandleLLMError(ctx context.Context, err error) {
+	h.endSpan(ctx, err)
+}
+
+func (h *Handler) HandleChainStart(ctx context.Context, _ map[string]any) {
+	h.startSpan(ctx)
+}
+
+func (h *Handler) HandleChainEnd(ctx context.Context, _ map[string]any) {
+	h.endSpan(ctx, nil)
+}
+
+func (h *Handler) HandleChainError(ctx context.Context, err error) {
+	h.endSpan(ctx, err)
+}
+
+func (h *Handler) HandleToolStart(ctx context.Context, _ string) {
+	h.startSpan(ctx)
+}
+
+func (h *Handler) HandleToolEnd(ctx context.Context, _ string) {
+	h.endSpan(ctx, nil)
+}
+
+func (h *Handler) HandleToolError(ctx context.Context, err error) {
+	h.endSpan(ctx, err)
+}
+
+func (h *Handler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	h.addEvent(ctx, "agent_action", AttributeToolName.String(action.Tool))
+}
+
+func (h *Handler) HandleAgentFinish(ctx context.Context, _ schema.AgentFinish, _ ...callbacks.Option) {
+	h.addEvent(ctx, "agent_finish")
+}
+
+// HandleAgentStepStart starts the span of a step of an agent.
+func (h *Handler) HandleAgentStepStart(ctx context.Context, iteration int) {
+	h.startSpan(ctx, AttributeAgentIteration.Int(iteration))
+}
+
+// HandleAgentStepEnd ends the span of a step of an agent.
+func (h *Handler) HandleAgentStepEnd(ctx context.Context, err error) {
+	h.endSpan(ctx, err)
+}
+
+func (h *Handler) HandleRetrieverStart(ctx context.Context, _ string) {
+	h.startSpan(ctx)
+}
+
+func (h *Handler) HandleRetrieverEnd(ctx context.Context, _ string, documents []schema.Document) {
+	h.endSpan(ctx, nil, AttributeDocuments.Int(len(documents)))
+}
+
+func (h *Handler) HandleStreamingFunc(context.Context, []byte) {}
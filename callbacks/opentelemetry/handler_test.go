@@ -0,0 +1,198 @@
+package opentelemetry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestHandler(t *testing.T) (*Handler, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	h, err := New(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	require.NoError(t, err)
+	return h, recorder, reader
+}
+
+func spansByName(recorder *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	return spans
+}
+
+func attributeValue(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+// runAgent simulates the events of a chain running an agent step, which calls
+// a model through a chain without this handler, and a tool.
+func runAgent(ctx context.Context, h *Handler, model string) {
+	chainCtx := callbacks.StartRun(ctx, callbacks.RunKindChain, "Executor")
+	h.HandleChainStart(chainCtx, nil)
+
+	stepCtx := callbacks.StartRun(chainCtx, callbacks.RunKindAgent, "OneShotZeroAgent")
+	h.HandleAgentStepStart(stepCtx, 0)
+
+	// the chain of the agent has no events.
+	agentChainCtx := callbacks.StartRun(stepCtx, callbacks.RunKindChain, "LLMChain")
+	llmCtx := callbacks.StartRun(agentChainCtx, callbacks.RunKindLLM, "openai", callbacks.WithRunModel(model))
+	h.HandleLLMGenerateContentStart(llmCtx, nil)
+	h.HandleLLMGenerateContentEnd(llmCtx, &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: "calculator", StopReason: "stop"}},
+		Usage:   &llms.Usage{InputTokens: 10, OutputTokens: 4},
+	})
+
+	toolCtx := callbacks.StartRun(stepCtx, callbacks.RunKindTool, "calculator")
+	h.HandleToolStart(toolCtx, "1+1")
+	h.HandleToolEnd(toolCtx, "2")
+
+	h.HandleAgentStepEnd(stepCtx, nil)
+	h.HandleChainEnd(chainCtx, nil)
+}
+
+func TestHandler_Spans(t *testing.T) {
+	t.Parallel()
+
+	h, recorder, _ := newTestHandler(t)
+	runAgent(context.Background(), h, "gpt-4o")
+
+	spans := spansByName(recorder)
+	require.Len(t, spans, 4)
+	chain := spans["chain Executor"]
+	step := spans["agent OneShotZeroAgent"]
+	llm := spans["chat gpt-4o"]
+	tool := spans["tool calculator"]
+	require.NotNil(t, chain)
+	require.NotNil(t, step)
+	require.NotNil(t, llm)
+	require.NotNil(t, tool)
+
+	assert.False(t, chain.Parent().IsValid())
+	assert.Equal(t, chain.SpanContext().SpanID(), step.Parent().SpanID())
+	assert.Equal(t, step.SpanContext().SpanID(), llm.Parent().SpanID())
+	assert.Equal(t, step.SpanContext().SpanID(), tool.Parent().SpanID())
+	assert.Equal(t, chain.SpanContext().TraceID(), tool.SpanContext().TraceID())
+
+	assert.Equal(t, "openai", attributeValue(llm, AttributeGenAISystem).AsString())
+	assert.Equal(t, "gpt-4o", attributeValue(llm, AttributeGenAIRequestModel).AsString())
+	assert.Equal(t, int64(10), attributeValue(llm, AttributeGenAIInputTokens).AsInt64())
+	assert.Equal(t, int64(4), attributeValue(llm, AttributeGenAIOutputTokens).AsInt64())
+	assert.Equal(t, []string{"stop"}, attributeValue(llm, AttributeGenAIFinishReasons).AsStringSlice())
+	assert.Equal(t, int64(0), attributeValue(step, AttributeAgentIteration).AsInt64())
+}
+
+func TestHandler_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	h, recorder, _ := newTestHandler(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runAgent(context.Background(), h, "gpt-4o")
+		}()
+	}
+	wg.Wait()
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 40)
+	traces := make(map[string]int)
+	for _, span := range ended {
+		traces[span.SpanContext().TraceID().String()]++
+	}
+	assert.Len(t, traces, 10, "each run should be its own trace")
+	for _, n := range traces {
+		assert.Equal(t, 4, n)
+	}
+	assert.Empty(t, h.spans)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+
+	h, recorder, _ := newTestHandler(t)
+	ctx := context.Background()
+
+	chainCtx := callbacks.StartRun(ctx, callbacks.RunKindChain, "LLMChain")
+	h.HandleChainStart(chainCtx, nil)
+	llmCtx := callbacks.StartRun(chainCtx, callbacks.RunKindLLM, "anthropic")
+	h.HandleLLMGenerateContentStart(llmCtx, nil)
+	// the model returns without its end event, the chain fails.
+	h.HandleChainError(chainCtx, errors.New("boom"))
+
+	spans := spansByName(recorder)
+	require.Len(t, spans, 2)
+	chain := spans["chain LLMChain"]
+	require.NotNil(t, chain)
+	assert.Equal(t, codes.Error, chain.Status().Code)
+	assert.Equal(t, "boom", chain.Status().Description)
+	assert.NotNil(t, spans["chat"], "the spans of the children should be ended")
+	assert.Empty(t, h.spans)
+
+	// events without a run are ignored.
+	h.HandleToolStart(ctx, "input")
+	h.HandleToolEnd(ctx, "output")
+	assert.Len(t, recorder.Ended(), 2)
+}
+
+func TestHandler_Metrics(t *testing.T) {
+	t.Parallel()
+
+	h, _, reader := newTestHandler(t)
+	ctx := context.Background()
+	runAgent(ctx, h, "gpt-4o")
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	operation, ok := metrics["gen_ai.client.operation.duration"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, operation.DataPoints, 1)
+	assert.Equal(t, uint64(1), operation.DataPoints[0].Count)
+	model, _ := operation.DataPoints[0].Attributes.Value(AttributeGenAIRequestModel)
+	assert.Equal(t, "gpt-4o", model.AsString())
+
+	tokens, ok := metrics["gen_ai.client.token.usage"].(metricdata.Histogram[int64])
+	require.True(t, ok)
+	sums := make(map[string]int64)
+	for _, dp := range tokens.DataPoints {
+		tokenType, _ := dp.Attributes.Value(AttributeGenAITokenType)
+		sums[tokenType.AsString()] = dp.Sum
+	}
+	assert.Equal(t, map[string]int64{"input": 10, "output": 4}, sums)
+
+	runs, ok := metrics["langchaingo.run.duration"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	assert.Len(t, runs.DataPoints, 4, "one series per kind and name of run")
+}
@@ -0,0 +1,101 @@
+package callbacks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RunKind is the kind of component a run executes.
+type RunKind string
+
+const (
+	// RunKindChain is the run of a chain, started by chains.Call.
+	RunKindChain RunKind = "chain"
+	// RunKindLLM is the run of a call to a model.
+	RunKindLLM RunKind = "llm"
+	// RunKindTool is the run of a tool.
+	RunKindTool RunKind = "tool"
+	// RunKindRetriever is the run of a retriever.
+	RunKindRetriever RunKind = "retriever"
+	// RunKindAgent is the run of a step of an agent, from the planning to the
+	// execution of its actions.
+	RunKindAgent RunKind = "agent"
+)
+
+// Run identifies an execution of a component, e.g. a call to a model. Runs
+// started while another run is in progress are its children, which forms a
+// tree of the runs of an application.
+type Run struct {
+	// ID is the unique identifier of the run.
+	ID string `json:"id"`
+	// ParentID is the ID of the run in progress when the run started, or an
+	// empty string for root runs.
+	ParentID string `json:"parent_id,omitempty"`
+	// AncestorIDs are the IDs of the runs in progress when the run started,
+	// from its parent to its root. Handlers which don't record every run use
+	// them to attach a run to its nearest recorded ancestor.
+	AncestorIDs []string `json:"-"`
+	// Kind is the kind of component the run executes.
+	Kind RunKind `json:"kind"`
+	// Name is the name of the component, e.g. the name of a tool or of the
+	// provider of a model.
+	Name string `json:"name"`
+	// Model is the name of the model requested by runs of kind RunKindLLM,
+	// when the provider knows it.
+	Model string `json:"model,omitempty"`
+}
+
+// RunOption is a function that configures a Run.
+type RunOption func(*Run)
+
+// WithRunModel sets the name of the model requested by the run.
+func WithRunModel(model string) RunOption {
+	return func(r *Run) {
+		r.Model = model
+	}
+}
+
+type runContextKey struct{}
+
+// StartRun starts a run of a component and returns a context carrying it.
+// Components call it before their start event, and give the returned
+// context to the events of the run and to the components they call, whose
+// runs become children of the run.
+//
+// Handlers get the run of an event with RunFromContext.
+func StartRun(ctx context.Context, kind RunKind, name string, opts ...RunOption) context.Context {
+	run := Run{
+		ID:   uuid.NewString(),
+		Kind: kind,
+		Name: name,
+	}
+	for _, opt := range opts {
+		opt(&run)
+	}
+	if parent, ok := RunFromContext(ctx); ok {
+		run.ParentID = parent.ID
+		run.AncestorIDs = append([]string{parent.ID}, parent.AncestorIDs...)
+	}
+	return context.WithValue(ctx, runContextKey{}, run)
+}
+
+// RunFromContext returns the run in progress in the context, which is the
+// run an event belongs to.
+func RunFromContext(ctx context.Context) (Run, bool) {
+	run, ok := ctx.Value(runContextKey{}).(Run)
+	return run, ok
+}
+
+// AgentStepHandler is implemented by handlers that handle the steps of agents,
+// which are runs of kind RunKindAgent. It is separate from Handler so that
+// existing handlers don't need to implement it: executors check whether their
+// handler implements it.
+type AgentStepHandler interface {
+	// HandleAgentStepStart is called when an agent starts a step, before it
+	// plans its actions. The iteration is the index of the step.
+	HandleAgentStepStart(ctx context.Context, iteration int)
+	// HandleAgentStepEnd is called when the step ends, after the tools of its
+	// actions returned. The error is the one that stopped the agent, if any.
+	HandleAgentStepEnd(ctx context.Context, err error)
+}
@@ -0,0 +1,41 @@
+package callbacks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	_, ok := RunFromContext(ctx)
+	assert.False(t, ok)
+
+	chainCtx := StartRun(ctx, RunKindChain, "LLMChain")
+	chain, ok := RunFromContext(chainCtx)
+	require.True(t, ok)
+	assert.NotEmpty(t, chain.ID)
+	assert.Empty(t, chain.ParentID)
+	assert.Equal(t, RunKindChain, chain.Kind)
+	assert.Equal(t, "LLMChain", chain.Name)
+
+	llmCtx := StartRun(chainCtx, RunKindLLM, "openai", WithRunModel("gpt-4o"))
+	llm, ok := RunFromContext(llmCtx)
+	require.True(t, ok)
+	assert.NotEqual(t, chain.ID, llm.ID)
+	assert.Equal(t, chain.ID, llm.ParentID)
+	assert.Equal(t, []string{chain.ID}, llm.AncestorIDs)
+	assert.Equal(t, "gpt-4o", llm.Model)
+
+	toolCtx := StartRun(llmCtx, RunKindTool, "calculator")
+	tool, _ := RunFromContext(toolCtx)
+	assert.Equal(t, []string{llm.ID, chain.ID}, tool.AncestorIDs)
+
+	// the context of the parent still carries the parent.
+	run, _ := RunFromContext(chainCtx)
+	assert.Equal(t, chain, run)
+}
@@ -0,0 +1,295 @@
+package callbacks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+// TracedRun is a run recorded by a Tracer, with its inputs, outputs, timings
+// and children.
+type TracedRun struct {
+	Run
+
+	// Inputs are the inputs of the run, e.g. the input values of a chain or
+	// the messages given to a model.
+	Inputs map[string]any `json:"inputs,omitempty"`
+	// Outputs are the outputs of the run, e.g. the output values of a chain or
+	// the response of a model.
+	Outputs map[string]any `json:"outputs,omitempty"`
+	// Error is the error of the run, if it failed.
+	Error string `json:"error,omitempty"`
+	// StartTime is the time of the start event of the run.
+	StartTime time.Time `json:"start_time"`
+	// EndTime is the time of the end or error event of the run. It is nil for
+	// runs in progress, and for runs which returned without an end event.
+	EndTime *time.Time `json:"end_time,omitempty"`
+	// Events are the events of the run between its start and its end, e.g.
+	// the actions chosen by an agent.
+	Events []TracedEvent `json:"events,omitempty"`
+	// Children are the runs started during the run, in the order they
+	// started.
+	Children []*TracedRun `json:"children,omitempty"`
+}
+
+// Duration returns the duration of the run, or zero if it didn't end.
+func (r *TracedRun) Duration() time.Duration {
+	if r.EndTime == nil {
+		return 0
+	}
+	return r.EndTime.Sub(r.StartTime)
+}
+
+// TracedEvent is an event of a run which neither starts nor ends it.
+type TracedEvent struct {
+	Name string         `json:"name"`
+	Time time.Time      `json:"time"`
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// Tracer is a callback handler that records the runs of an application and
+// rebuilds their tree, which can be inspected or exported as JSON to debug
+// the runs of chains and agents:
+//
+//	tracer := callbacks.NewTracer()
+//	executor := agents.NewExecutor(agent, agents.WithCallbacksHandler(tracer))
+//	_, err := chains.Run(ctx, executor, "What is 3 to the power of 4?")
+//	err = tracer.WriteJSON(os.Stdout)
+//
+// The runs are identified by the Run of the context of the events, and events
+// without a run are ignored. Runs whose parent wasn't recorded, e.g. a chain
+// without the tracer as handler, are children of their nearest recorded
+// ancestor, whose ID is their ParentID. A Tracer is safe for concurrent use.
+type Tracer struct {
+	now func() time.Time
+
+	mu    sync.Mutex
+	runs  map[string]*TracedRun
+	roots []*TracedRun
+}
+
+var (
+	_ Handler          = (*Tracer)(nil)
+	_ AgentStepHandler = (*Tracer)(nil)
+)
+
+// NewTracer creates a Tracer.
+func NewTracer() *Tracer {
+	return &Tracer{
+		now:  time.Now,
+		runs: make(map[string]*TracedRun),
+	}
+}
+
+// Runs returns a copy of the trees of the runs recorded by the tracer, in the
+// order their roots started.
+func (t *Tracer) Runs() []*TracedRun {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	roots := make([]*TracedRun, len(t.roots))
+	for i, root := range t.roots {
+		roots[i] = root.clone()
+	}
+	return roots
+}
+
+// Run returns a copy of the tree of the run with the given ID.
+func (t *Tracer) Run(id string) (*TracedRun, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	run, ok := t.runs[id]
+	if !ok {
+		return nil, false
+	}
+	return run.clone(), true
+}
+
+// Reset forgets the runs recorded by the tracer.
+func (t *Tracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.runs = make(map[string]*TracedRun)
+	t.roots = nil
+}
+
+// MarshalJSON encodes the trees of the runs as a JSON array.
+func (t *Tracer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Runs())
+}
+
+// WriteJSON writes the trees of the runs to w as an indented JSON array.
+func (t *Tracer) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(t.Runs())
+}
+
+func (r *TracedRun) clone() *TracedRun {
+	c := *r
+	c.Inputs = maps.Clone(r.Inputs)
+	c.Outputs = maps.Clone(r.Outputs)
+	c.Events = append([]TracedEvent(nil), r.Events...)
+	if r.EndTime != nil {
+		end := *r.EndTime
+		c.EndTime = &end
+	}
+	c.Children = make([]*TracedRun, len(r.Children))
+	for i, child := range r.Children {
+		c.Children[i] = child.clone()
+	}
+	return &c
+}
+
+// start records the start of the run of the context.
+func (t *Tracer) start(ctx context.Context, inputs map[string]any) {
+	run, ok := RunFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, started := t.runs[run.ID]; started {
+		return
+	}
+	traced := &TracedRun{
+		Run:       run,
+		Inputs:    inputs,
+		StartTime: t.now(),
+	}
+	t.runs[run.ID] = traced
+	// runs without events, e.g. chains without this handler, are skipped.
+	traced.ParentID = ""
+	for _, id := range run.AncestorIDs {
+		if parent, ok := t.runs[id]; ok {
+			traced.ParentID = id
+			parent.Children = append(parent.Children, traced)
+			return
+		}
+	}
+	t.roots = append(t.roots, traced)
+}
+
+// end records the end of the run of the context.
+func (t *Tracer) end(ctx context.Context, outputs map[string]any, err error) {
+	t.update(ctx, func(traced *TracedRun) {
+		if traced.EndTime != nil {
+			return
+		}
+		end := t.now()
+		traced.EndTime = &end
+		if outputs != nil {
+			traced.Outputs = outputs
+		}
+		if err != nil {
+			traced.Error = err.Error()
+		}
+	})
+}
+
+// event records an event of the run of the context.
+func (t *Tracer) event(ctx context.Context, name string, data map[string]any) {
+	t.update(ctx, func(traced *TracedRun) {
+		traced.Events = append(traced.Events, TracedEvent{Name: name, Time: t.now(), Data: data})
+	})
+}
+
+func (t *Tracer) update(ctx context.Context, f func(*TracedRun)) {
+	run, ok := RunFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if traced, ok := t.runs[run.ID]; ok {
+		f(traced)
+	}
+}
+
+func (t *Tracer) HandleText(ctx context.Context, text string) {
+	t.event(ctx, "text", map[string]any{"text": text})
+}
+
+func (t *Tracer) HandleLLMStart(context.Context, []string) {}
+
+func (t *Tracer) HandleLLMGenerateContentStart(ctx context.Context, ms []llms.MessageContent) {
+	t.start(ctx, map[string]any{"messages": ms})
+}
+
+func (t *Tracer) HandleLLMGenerateContentEnd(ctx context.Context, res *llms.ContentResponse) {
+	t.end(ctx, map[string]any{"response": res}, nil)
+}
+
+func (t *Tracer) HandleLLMError(ctx context.Context, err error) {
+	t.end(ctx, nil, err)
+}
+
+func (t *Tracer) HandleChainStart(ctx context.Context, inputs map[string]any) {
+	t.start(ctx, maps.Clone(inputs))
+}
+
+func (t *Tracer) HandleChainEnd(ctx context.Context, outputs map[string]any) {
+	t.end(ctx, maps.Clone(outputs), nil)
+}
+
+func (t *Tracer) HandleChainError(ctx context.Context, err error) {
+	t.end(ctx, nil, err)
+}
+
+func (t *Tracer) HandleToolStart(ctx context.Context, input string) {
+	t.start(ctx, map[string]any{"input": input})
+}
+
+func (t *Tracer) HandleToolEnd(ctx context.Context, output string) {
+	t.end(ctx, map[string]any{"output": output}, nil)
+}
+
+func (t *Tracer) HandleToolError(ctx context.Context, err error) {
+	t.end(ctx, nil, err)
+}
+
+func (t *Tracer) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	t.event(ctx, "agent_action", map[string]any{
+		"tool":       action.Tool,
+		"tool_input": action.ToolInput,
+		"log":        action.Log,
+	})
+}
+
+func (t *Tracer) HandleAgentFinish(ctx context.Context, finish schema.AgentFinish, _ ...Option) {
+	t.event(ctx, "agent_finish", map[string]any{
+		"return_values": maps.Clone(finish.ReturnValues),
+		"log":           finish.Log,
+	})
+}
+
+// HandleAgentStepStart records the start of a step of an agent.
+func (t *Tracer) HandleAgentStepStart(ctx context.Context, iteration int) {
+	t.start(ctx, map[string]any{"iteration": iteration})
+}
+
+// HandleAgentStepEnd records the end of a step of an agent.
+func (t *Tracer) HandleAgentStepEnd(ctx context.Context, err error) {
+	t.end(ctx, nil, err)
+}
+
+func (t *Tracer) HandleRetrieverStart(ctx context.Context, query string) {
+	t.start(ctx, map[string]any{"query": query})
+}
+
+func (t *Tracer) HandleRetrieverEnd(ctx context.Context, _ string, documents []schema.Document) {
+	t.end(ctx, map[string]any{"documents": documents}, nil)
+}
+
+func (t *Tracer) HandleStreamingFunc(context.Context, []byte) {}
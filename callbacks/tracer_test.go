@@ -0,0 +1,156 @@
+package callbacks_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/agents"
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/chains"
+	"github.com/IT-Tech-Company/langchaingo/llms/fake"
+	"github.com/IT-Tech-Company/langchaingo/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runAgent runs an executor with a one shot agent, whose model and tool send
+// their events to the handler. The chain of the agent has no handler, so its
+// runs aren't recorded.
+func runAgent(t *testing.T, h callbacks.Handler, replies ...fake.Reply) (string, error) {
+	t.Helper()
+
+	if len(replies) == 0 {
+		replies = []fake.Reply{
+			fake.TextReply("I should use the calculator.\nAction: calculator\nAction Input: 3*3*3*3"),
+			fake.TextReply("I now know the final answer.\nFinal Answer:81"),
+		}
+	}
+	llm := fake.NewScriptedLLM(replies...)
+	llm.CallbacksHandler = h
+	agent := agents.NewOneShotAgent(llm, []tools.Tool{tools.Calculator{CallbacksHandler: h}})
+	executor := agents.NewExecutor(agent, agents.WithCallbacksHandler(h))
+	return chains.Run(context.Background(), executor, "What is 3 to the power of 4?")
+}
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+
+	tracer := callbacks.NewTracer()
+	out, err := runAgent(t, tracer)
+	require.NoError(t, err)
+	assert.Equal(t, "81", out)
+
+	runs := tracer.Runs()
+	require.Len(t, runs, 1)
+	executor := runs[0]
+	assert.Equal(t, callbacks.RunKindChain, executor.Kind)
+	assert.Equal(t, "Executor", executor.Name)
+	assert.Equal(t, map[string]any{"input": "What is 3 to the power of 4?"}, executor.Inputs)
+	assert.Equal(t, map[string]any{"output": "81"}, executor.Outputs)
+	require.NotNil(t, executor.EndTime)
+
+	// the runs of the model are children of the steps, although the chain of
+	// the agent between them isn't recorded.
+	require.Len(t, executor.Children, 2)
+	step := executor.Children[0]
+	assert.Equal(t, callbacks.RunKindAgent, step.Kind)
+	assert.Equal(t, executor.ID, step.ParentID)
+	require.Len(t, step.Events, 1)
+	assert.Equal(t, "agent_action", step.Events[0].Name)
+	assert.Equal(t, "calculator", step.Events[0].Data["tool"])
+
+	require.Len(t, step.Children, 2)
+	llm, tool := step.Children[0], step.Children[1]
+	assert.Equal(t, callbacks.RunKindLLM, llm.Kind)
+	assert.Equal(t, step.ID, llm.ParentID)
+	assert.NotNil(t, llm.Outputs["response"])
+	assert.Equal(t, callbacks.RunKindTool, tool.Kind)
+	assert.Equal(t, "calculator", tool.Name)
+	assert.Equal(t, map[string]any{"input": "3*3*3*3"}, tool.Inputs)
+	assert.Equal(t, map[string]any{"output": "81"}, tool.Outputs)
+
+	finish := executor.Children[1]
+	require.Len(t, finish.Children, 1)
+	assert.Equal(t, callbacks.RunKindLLM, finish.Children[0].Kind)
+	assert.Equal(t, "agent_finish", finish.Events[len(finish.Events)-1].Name)
+
+	run, ok := tracer.Run(tool.ID)
+	require.True(t, ok)
+	assert.Equal(t, tool, run)
+
+	tracer.Reset()
+	assert.Empty(t, tracer.Runs())
+}
+
+func TestTracer_Errors(t *testing.T) {
+	t.Parallel()
+
+	tracer := callbacks.NewTracer()
+	_, err := runAgent(t, tracer, fake.ErrorReply(errors.New("boom")))
+	require.Error(t, err)
+
+	// events without a run are ignored.
+	tracer.HandleToolStart(context.Background(), "input")
+
+	runs := tracer.Runs()
+	require.Len(t, runs, 1)
+	assert.Contains(t, runs[0].Error, "boom")
+	require.NotNil(t, runs[0].EndTime)
+	require.Len(t, runs[0].Children, 1)
+	step := runs[0].Children[0]
+	assert.Contains(t, step.Error, "boom")
+	require.Len(t, step.Children, 1)
+	assert.Equal(t, "boom", step.Children[0].Error)
+}
+
+func TestTracer_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	tracer := callbacks.NewTracer()
+	handler := callbacks.CombiningHandler{Callbacks: []callbacks.Handler{callbacks.SimpleHandler{}, tracer}}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := runAgent(t, handler)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	runs := tracer.Runs()
+	require.Len(t, runs, 10)
+	for _, run := range runs {
+		require.Len(t, run.Children, 2)
+		assert.Len(t, run.Children[0].Children, 2)
+	}
+}
+
+func TestTracer_JSON(t *testing.T) {
+	t.Parallel()
+
+	tracer := callbacks.NewTracer()
+	_, err := runAgent(t, tracer)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, tracer.WriteJSON(&buf))
+
+	var runs []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &runs))
+	require.Len(t, runs, 1)
+	assert.Equal(t, "chain", runs[0]["kind"])
+	assert.Equal(t, "Executor", runs[0]["name"])
+	assert.Contains(t, runs[0], "start_time")
+	assert.Contains(t, runs[0], "end_time")
+	assert.NotContains(t, runs[0], "parent_id")
+
+	b, err := json.Marshal(tracer)
+	require.NoError(t, err)
+	assert.JSONEq(t, buf.String(), string(b))
+}
@@ -3,6 +3,8 @@ package chains
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
@@ -28,6 +30,8 @@ type Chain interface {
 
 // Call is the standard function used for executing chains.
 func Call(ctx context.Context, c Chain, inputValues map[string]any, options ...ChainCallOption) (map[string]any, error) { // nolint: lll
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindChain, chainName(c))
+
 	fullValues := make(map[string]any, 0)
 	for key, value := range inputValues {
 		fullValues[key] = value
@@ -66,6 +70,17 @@ func Call(ctx context.Context, c Chain, inputValues map[string]any, options ...C
 	return outputValues, nil
 }
 
+// chainName returns the name of the type of a chain, without its type
+// arguments, used as the name of its runs.
+func chainName(c Chain) string {
+	t := reflect.TypeOf(c)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	name, _, _ := strings.Cut(t.Name(), "[")
+	return name
+}
+
 func callChain(
 	ctx context.Context,
 	c Chain,
@@ -0,0 +1,27 @@
+// Package graph provides workflows whose steps are the nodes of a graph over a
+// typed state, for flows which don't fit a sequence of chains, such as
+// routing, reflection loops and multi-agent collaboration.
+//
+// Nodes are Go functions returning the new state, or chains whose input and
+// output values are read from and written to the fields of the state. Edges
+// lead from a node to the next ones, and conditional edges pick the next node
+// from the state. A node with several successors starts branches which run in
+// parallel, and whose states are merged before the next step. Cycles are
+// allowed, within a limit of steps:
+//
+//	g := graph.New[State]()
+//	g.AddNode("draft", draft)
+//	g.AddChain("critique", critiqueChain)
+//	g.AddEdge(graph.Start, "draft")
+//	g.AddEdge("draft", "critique")
+//	g.AddConditionalEdge("critique", func(_ context.Context, s State) (string, error) {
+//		if s.Approved {
+//			return graph.End, nil
+//		}
+//		return "draft", nil
+//	})
+//	workflow, err := g.Compile(graph.WithInputKeys("topic"), graph.WithOutputKeys("text"))
+//
+// A compiled Workflow is a chains.Chain, so it can be run with chains.Call and
+// be a node of other graphs.
+package graph
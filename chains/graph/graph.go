@@ -0,0 +1,160 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IT-Tech-Company/langchaingo/chains"
+)
+
+const (
+	// Start is the virtual node the edges to the first nodes of a graph lead from.
+	Start = "__start__"
+	// End is the virtual node ending the branches whose edges lead to it.
+	End = "__end__"
+)
+
+var (
+	// ErrInvalidGraph is returned by Compile if the graph has errors, such as
+	// edges between unknown nodes.
+	ErrInvalidGraph = errors.New("invalid graph")
+	// ErrUnknownNode is returned if a conditional edge leads to an unknown node.
+	ErrUnknownNode = errors.New("unknown node")
+	// ErrMaxSteps is returned if a workflow doesn't end within its maximum
+	// number of steps.
+	ErrMaxSteps = errors.New("workflow reached the maximum number of steps")
+)
+
+// Node is a step of a workflow, returning the new state from the current one.
+// The state must not be modified in place by nodes which run in parallel.
+type Node[S any] func(ctx context.Context, state S) (S, error)
+
+// Condition returns the name of the node following a node, or End.
+type Condition[S any] func(ctx context.Context, state S) (string, error)
+
+// Graph is the definition of a workflow over states of type S. Its errors are
+// reported by Compile.
+type Graph[S any] struct {
+	nodes      map[string]Node[S]
+	edges      map[string][]string
+	conditions map[string]Condition[S]
+	errs       []error
+}
+
+// New creates an empty graph over states of type S. The states are usually
+// structs, whose fields are read and written by chain nodes by their JSON
+// names, or maps.
+func New[S any]() *Graph[S] {
+	return &Graph[S]{
+		nodes:      make(map[string]Node[S]),
+		edges:      make(map[string][]string),
+		conditions: make(map[string]Condition[S]),
+	}
+}
+
+// AddNode adds a node to the graph.
+func (g *Graph[S]) AddNode(name string, node Node[S]) *Graph[S] {
+	switch {
+	case name == "" || name == Start || name == End:
+		g.errs = append(g.errs, fmt.Errorf("reserved node name %q", name))
+	case g.nodes[name] != nil:
+		g.errs = append(g.errs, fmt.Errorf("duplicate node %q", name))
+	case node == nil:
+		g.errs = append(g.errs, fmt.Errorf("nil node %q", name))
+	default:
+		g.nodes[name] = node
+	}
+	return g
+}
+
+// AddChain adds a node running a chain, see ChainNode.
+func (g *Graph[S]) AddChain(name string, chain chains.Chain, opts ...ChainNodeOption) *Graph[S] {
+	return g.AddNode(name, ChainNode[S](chain, opts...))
+}
+
+// AddEdge adds an edge between two nodes. The nodes following the same node
+// run in parallel.
+func (g *Graph[S]) AddEdge(from, to string) *Graph[S] {
+	g.edges[from] = append(g.edges[from], to)
+	return g
+}
+
+// AddConditionalEdge adds an edge from a node to the node picked by the
+// condition, evaluated on the state after the node.
+func (g *Graph[S]) AddConditionalEdge(from string, condition Condition[S]) *Graph[S] {
+	if g.conditions[from] != nil {
+		g.errs = append(g.errs, fmt.Errorf("duplicate conditional edge from %q", from))
+	}
+	g.conditions[from] = condition
+	return g
+}
+
+// Compile checks the graph and returns the workflow running it. The graph
+// must not be changed afterwards.
+func (g *Graph[S]) Compile(opts ...Option) (*Workflow[S], error) {
+	errs := append([]error(nil), g.errs...)
+	if len(g.edges[Start]) == 0 {
+		errs = append(errs, errors.New("no edge from the start"))
+	}
+	if len(g.edges[End]) > 0 || g.conditions[End] != nil {
+		errs = append(errs, errors.New("edge from the end"))
+	}
+	for from, tos := range g.edges {
+		if from != Start && g.nodes[from] == nil {
+			errs = append(errs, fmt.Errorf("edge from unknown node %q", from))
+		}
+		for _, to := range tos {
+			if to == Start || (to != End && g.nodes[to] == nil) {
+				errs = append(errs, fmt.Errorf("edge from %q to unknown node %q", from, to))
+			}
+		}
+	}
+	for from := range g.conditions {
+		if from == Start || g.nodes[from] == nil {
+			errs = append(errs, fmt.Errorf("conditional edge from unknown node %q", from))
+		}
+	}
+	for name := range g.nodes {
+		if len(g.edges[name]) == 0 && g.conditions[name] == nil {
+			errs = append(errs, fmt.Errorf("no edge from node %q", name))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGraph, errors.Join(errs...))
+	}
+
+	return newWorkflow(g, opts...)
+}
+
+// successors returns the nodes following the nodes of a step, in order and
+// without duplicates.
+func (g *Graph[S]) successors(ctx context.Context, nodes []string, state S) ([]string, error) {
+	var next []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != End && !seen[name] {
+			seen[name] = true
+			next = append(next, name)
+		}
+	}
+
+	for _, name := range nodes {
+		for _, to := range g.edges[name] {
+			add(to)
+		}
+		condition := g.conditions[name]
+		if condition == nil {
+			continue
+		}
+		to, err := condition(ctx, state)
+		if err != nil {
+			return nil, fmt.Errorf("condition of %s: %w", name, err)
+		}
+		if to != End && g.nodes[to] == nil {
+			return nil, fmt.Errorf("%w: %q, from %s", ErrUnknownNode, to, name)
+		}
+		add(to)
+	}
+	return next, nil
+}
@@ -0,0 +1,235 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/chains"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/llms/fake"
+	"github.com/IT-Tech-Company/langchaingo/prompts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type essay struct {
+	Topic    string   `json:"topic"`
+	Draft    string   `json:"draft,omitempty"`
+	Critique string   `json:"critique,omitempty"`
+	Drafts   int      `json:"drafts"`
+	Sources  []string `json:"sources,omitempty"`
+	Outline  string   `json:"outline,omitempty"`
+}
+
+func TestWorkflow_Reflection(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.NewScriptedLLM(fake.TextReply("Too short."), fake.TextReply("Good."))
+	critique := chains.NewLLMChain(llm, prompts.NewPromptTemplate("Critique: {{.draft}}", []string{"draft"}))
+
+	g := New[essay]()
+	g.AddNode("draft", func(_ context.Context, s essay) (essay, error) {
+		s.Drafts++
+		s.Draft = strings.Repeat("Go is great. ", s.Drafts)
+		return s, nil
+	})
+	g.AddChain("critique", critique, MapOutput("text", "critique"))
+	g.AddEdge(Start, "draft")
+	g.AddEdge("draft", "critique")
+	g.AddConditionalEdge("critique", func(_ context.Context, s essay) (string, error) {
+		if s.Critique == "Good." {
+			return End, nil
+		}
+		return "draft", nil
+	})
+	workflow, err := g.Compile(WithInputKeys("topic"), WithOutputKeys("draft"))
+	require.NoError(t, err)
+
+	state, err := workflow.Invoke(context.Background(), essay{Topic: "Go"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, state.Drafts)
+	assert.Equal(t, "Good.", state.Critique)
+
+	// the chain was given the draft of the state.
+	calls := llm.Calls()
+	require.Len(t, calls, 2)
+	assert.Contains(t, calls[1].Messages[0].Parts[0].(llms.TextContent).Text, "Go is great. Go is great.")
+
+	// workflows are chains.
+	llm.Push(fake.TextReply("Good."))
+	out, err := chains.Run(context.Background(), workflow, "Go")
+	require.NoError(t, err)
+	assert.Equal(t, "Go is great. ", out)
+}
+
+// newResearchGraph returns a graph whose research and outline nodes run in
+// parallel, as research waits for outline, before write.
+func newResearchGraph() *Graph[essay] {
+	started := make(chan struct{})
+	g := New[essay]()
+	g.AddNode("research", func(_ context.Context, s essay) (essay, error) {
+		<-started
+		s.Sources = []string{"go.dev"}
+		return s, nil
+	})
+	g.AddNode("outline", func(_ context.Context, s essay) (essay, error) {
+		close(started)
+		s.Outline = "intro, body, conclusion"
+		return s, nil
+	})
+	g.AddNode("write", func(_ context.Context, s essay) (essay, error) {
+		s.Drafts++
+		s.Draft = s.Outline + " from " + strings.Join(s.Sources, ", ")
+		return s, nil
+	})
+	g.AddEdge(Start, "research")
+	g.AddEdge(Start, "outline")
+	g.AddEdge("research", "write")
+	g.AddEdge("outline", "write")
+	g.AddEdge("write", End)
+	return g
+}
+
+func TestWorkflow_Parallel(t *testing.T) {
+	t.Parallel()
+
+	workflow, err := newResearchGraph().Compile()
+	require.NoError(t, err)
+
+	// write runs once, with the merged state.
+	state, err := workflow.Invoke(context.Background(), essay{Topic: "Go"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, state.Drafts)
+	assert.Equal(t, "intro, body, conclusion from go.dev", state.Draft)
+
+	workflow, err = newResearchGraph().Compile(WithMerge(func(base essay, branches []essay) (essay, error) {
+		base.Outline = branches[1].Outline + " (merged)"
+		return base, nil
+	}))
+	require.NoError(t, err)
+	state, err = workflow.Invoke(context.Background(), essay{Topic: "Go"})
+	require.NoError(t, err)
+	assert.Equal(t, "intro, body, conclusion (merged)", state.Outline)
+	assert.Equal(t, "intro, body, conclusion (merged) from ", state.Draft)
+}
+
+func TestWorkflow_MapState(t *testing.T) {
+	t.Parallel()
+
+	g := New[map[string]any]()
+	g.AddNode("count", func(_ context.Context, s map[string]any) (map[string]any, error) {
+		s = map[string]any{"n": s["n"].(int) + 1}
+		return s, nil
+	})
+	g.AddEdge(Start, "count")
+	g.AddEdge("count", "count")
+	workflow, err := g.Compile(WithMaxSteps(3))
+	require.NoError(t, err)
+
+	// cycles are limited.
+	_, err = workflow.Call(context.Background(), map[string]any{"n": 0})
+	require.ErrorIs(t, err, ErrMaxSteps)
+
+	g = New[map[string]any]()
+	g.AddNode("count", func(_ context.Context, s map[string]any) (map[string]any, error) {
+		return map[string]any{"n": s["n"].(int) + 1}, nil
+	})
+	g.AddEdge(Start, "count")
+	g.AddConditionalEdge("count", func(_ context.Context, s map[string]any) (string, error) {
+		if s["n"].(int) < 5 {
+			return "count", nil
+		}
+		return End, nil
+	})
+	workflow, err = g.Compile()
+	require.NoError(t, err)
+	out, err := workflow.Call(context.Background(), map[string]any{"n": 0})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"n": 5}, out)
+}
+
+func TestWorkflow_Errors(t *testing.T) {
+	t.Parallel()
+
+	identity := func(_ context.Context, s essay) (essay, error) { return s, nil }
+
+	_, err := New[essay]().AddNode("a", identity).Compile()
+	require.ErrorIs(t, err, ErrInvalidGraph)
+
+	g := New[essay]()
+	g.AddNode("a", identity).AddNode("a", identity).AddNode(End, identity)
+	g.AddEdge(Start, "a").AddEdge("a", "b")
+	_, err = g.Compile()
+	require.ErrorIs(t, err, ErrInvalidGraph)
+	assert.Contains(t, err.Error(), `duplicate node "a"`)
+	assert.Contains(t, err.Error(), `reserved node name "__end__"`)
+	assert.Contains(t, err.Error(), `unknown node "b"`)
+
+	errFailed := errors.New("failed")
+	g = New[essay]()
+	g.AddNode("a", identity)
+	g.AddNode("b", func(context.Context, essay) (essay, error) { return essay{}, errFailed })
+	g.AddEdge(Start, "a")
+	g.AddConditionalEdge("a", func(_ context.Context, s essay) (string, error) { return s.Topic, nil })
+	g.AddEdge("b", End)
+	workflow, err := g.Compile()
+	require.NoError(t, err)
+
+	_, err = workflow.Invoke(context.Background(), essay{Topic: "c"})
+	require.ErrorIs(t, err, ErrUnknownNode)
+	_, err = workflow.Invoke(context.Background(), essay{Topic: "b"})
+	require.ErrorIs(t, err, errFailed)
+	assert.Contains(t, err.Error(), "node b")
+
+	// merge functions of other states are rejected.
+	g = New[essay]()
+	g.AddNode("a", identity).AddEdge(Start, "a").AddEdge("a", End)
+	_, err = g.Compile(WithMerge(func(base map[string]any, _ []map[string]any) (map[string]any, error) {
+		return base, nil
+	}))
+	require.ErrorIs(t, err, ErrInvalidGraph)
+	assert.Contains(t, err.Error(), "graph.essay")
+}
+
+// chainRecorder records the chain callbacks of the runs.
+type chainRecorder struct {
+	callbacks.SimpleHandler
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *chainRecorder) record(ctx context.Context, event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run, _ := callbacks.RunFromContext(ctx)
+	r.events = append(r.events, event+" "+run.Name)
+}
+
+func (r *chainRecorder) HandleChainStart(ctx context.Context, _ map[string]any) {
+	r.record(ctx, "start")
+}
+func (r *chainRecorder) HandleChainEnd(ctx context.Context, _ map[string]any) { r.record(ctx, "end") }
+func (r *chainRecorder) HandleChainError(ctx context.Context, _ error)        { r.record(ctx, "error") }
+
+func TestWorkflow_Callbacks(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+	g := New[essay]()
+	g.AddNode("draft", func(_ context.Context, s essay) (essay, error) { s.Draft = "draft"; return s, nil })
+	g.AddNode("review", func(context.Context, essay) (essay, error) { return essay{}, errFailed })
+	g.AddEdge(Start, "draft").AddEdge("draft", "review").AddEdge("review", End)
+	handler := &chainRecorder{}
+	workflow, err := g.Compile(WithCallbacksHandler(handler))
+	require.NoError(t, err)
+
+	_, err = chains.Call(context.Background(), workflow, map[string]any{"topic": "go"})
+	require.ErrorIs(t, err, errFailed)
+	assert.Equal(t, []string{
+		"start Workflow", "start draft", "end draft", "start review", "error review", "error Workflow",
+	}, handler.events)
+}
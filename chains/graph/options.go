@@ -0,0 +1,87 @@
+package graph
+
+import "github.com/IT-Tech-Company/langchaingo/callbacks"
+
+const _defaultMaxSteps = 25
+
+// MergeFunc merges the states of the branches which ran in parallel from the
+// same state.
+type MergeFunc[S any] func(base S, branches []S) (S, error)
+
+// Option is an option for a compiled workflow.
+type Option func(*options)
+
+type options struct {
+	maxSteps   int
+	inputKeys  []string
+	outputKeys []string
+	merge      any
+
+	callbacksHandler callbacks.Handler
+}
+
+// WithMaxSteps sets the maximum number of steps of the runs of a workflow, in
+// which every node to run next runs once. The default is 25.
+func WithMaxSteps(maxSteps int) Option {
+	return func(o *options) {
+		o.maxSteps = maxSteps
+	}
+}
+
+// WithInputKeys sets the input keys of the workflow as a chain. The input
+// values are the initial fields of the state.
+func WithInputKeys(inputKeys ...string) Option {
+	return func(o *options) {
+		o.inputKeys = inputKeys
+	}
+}
+
+// WithOutputKeys sets the output keys of the workflow as a chain, the fields
+// of the final state it returns. By default, all the fields are returned.
+func WithOutputKeys(outputKeys ...string) Option {
+	return func(o *options) {
+		o.outputKeys = outputKeys
+	}
+}
+
+// WithMerge sets the function merging the states of parallel branches. By
+// default, the fields changed by each branch are set in the order of the
+// branches.
+func WithMerge[S any](merge MergeFunc[S]) Option {
+	return func(o *options) {
+		o.merge = merge
+	}
+}
+
+// WithCallbacksHandler sets the handler receiving the callbacks of the
+// workflow, including the chain callbacks of its nodes.
+func WithCallbacksHandler(handler callbacks.Handler) Option {
+	return func(o *options) {
+		o.callbacksHandler = handler
+	}
+}
+
+// ChainNodeOption is an option for a chain node.
+type ChainNodeOption func(*chainNodeOptions)
+
+type chainNodeOptions struct {
+	inputs  map[string]string
+	outputs map[string]string
+}
+
+// MapInput sets the field of the state given to the chain as its input key.
+// By default, the inputs are the fields with the names of the input keys.
+func MapInput(field, inputKey string) ChainNodeOption {
+	return func(o *chainNodeOptions) {
+		o.inputs[inputKey] = field
+	}
+}
+
+// MapOutput sets the field of the state the output key of the chain is
+// written to. By default, the outputs are written to the fields with their
+// names.
+func MapOutput(outputKey, field string) ChainNodeOption {
+	return func(o *chainNodeOptions) {
+		o.outputs[outputKey] = field
+	}
+}
@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+
+	"github.com/IT-Tech-Company/langchaingo/chains"
+)
+
+// ChainNode returns a node running a chain with chains.Call. The input values
+// of the chain are read from the fields of the state, and its output values
+// are written to them. The fields of struct states are their JSON names.
+func ChainNode[S any](chain chains.Chain, opts ...ChainNodeOption) Node[S] {
+	o := chainNodeOptions{
+		inputs:  make(map[string]string),
+		outputs: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(ctx context.Context, state S) (S, error) {
+		fields, err := toMap(state)
+		if err != nil {
+			return state, err
+		}
+
+		// only the input keys are given, as chains may reject values of other types.
+		inputs := fields
+		if keys := chain.GetInputKeys(); len(keys) > 0 {
+			inputs = make(map[string]any, len(keys))
+			for _, key := range keys {
+				field := key
+				if mapped, ok := o.inputs[key]; ok {
+					field = mapped
+				}
+				if value, ok := fields[field]; ok {
+					inputs[key] = value
+				}
+			}
+		}
+
+		outputs, err := chains.Call(ctx, chain, inputs)
+		if err != nil {
+			return state, err
+		}
+		for key, value := range outputs {
+			field := key
+			if mapped, ok := o.outputs[key]; ok {
+				field = mapped
+			}
+			fields[field] = value
+		}
+		return fromMap[S](fields)
+	}
+}
+
+// toMap returns the fields of a state. Map states are copied, and the other
+// ones are converted through JSON.
+func toMap[S any](state S) (map[string]any, error) {
+	if m, ok := any(state).(map[string]any); ok {
+		return maps.Clone(m), nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("encode state: %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("encode state: %w", err)
+	}
+	return fields, nil
+}
+
+// fromMap returns the state with the fields.
+func fromMap[S any](fields map[string]any) (S, error) {
+	var state S
+	if _, ok := any(state).(map[string]any); ok {
+		return any(fields).(S), nil
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return state, fmt.Errorf("decode state: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("decode state: %w", err)
+	}
+	return state, nil
+}
+
+// mergeChanges is the default MergeFunc, which applies the fields changed by
+// each branch, including the removed ones, in order.
+func mergeChanges[S any](base S, branches []S) (S, error) {
+	baseFields, err := toMap(base)
+	if err != nil {
+		return base, err
+	}
+
+	merged := maps.Clone(baseFields)
+	for _, branch := range branches {
+		fields, err := toMap(branch)
+		if err != nil {
+			return base, err
+		}
+		for key, value := range fields {
+			if old, ok := baseFields[key]; !ok || !reflect.DeepEqual(old, value) {
+				merged[key] = value
+			}
+		}
+		for key := range baseFields {
+			if _, ok := fields[key]; !ok {
+				delete(merged, key)
+			}
+		}
+	}
+	return fromMap[S](merged)
+}
@@ -0,0 +1,197 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"sync"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/chains"
+	"github.com/IT-Tech-Company/langchaingo/memory"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+// Workflow runs a compiled graph. It is a chains.Chain whose input values are
+// the initial fields of the state, and whose outputs are the fields of the
+// final state.
+type Workflow[S any] struct {
+	// CallbacksHandler receives the chain callbacks of the nodes, whose runs
+	// are named after them.
+	CallbacksHandler callbacks.Handler
+
+	graph      *Graph[S]
+	maxSteps   int
+	inputKeys  []string
+	outputKeys []string
+	merge      MergeFunc[S]
+	memory     schema.Memory
+}
+
+var (
+	_ chains.Chain           = (*Workflow[map[string]any])(nil)
+	_ callbacks.HandlerHaver = (*Workflow[map[string]any])(nil)
+)
+
+func newWorkflow[S any](g *Graph[S], opts ...Option) (*Workflow[S], error) {
+	o := options{maxSteps: _defaultMaxSteps}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	w := &Workflow[S]{
+		CallbacksHandler: o.callbacksHandler,
+		graph:            g,
+		maxSteps:         o.maxSteps,
+		inputKeys:        o.inputKeys,
+		outputKeys:       o.outputKeys,
+		merge:            mergeChanges[S],
+		memory:           memory.NewSimple(),
+	}
+	if o.merge != nil {
+		merge, ok := o.merge.(MergeFunc[S])
+		if !ok {
+			return nil, fmt.Errorf("%w: merge function %T doesn't merge states of type %v",
+				ErrInvalidGraph, o.merge, reflect.TypeFor[S]())
+		}
+		w.merge = merge
+	}
+	return w, nil
+}
+
+// Invoke runs the workflow from a state and returns the final state, once
+// every branch has reached the end.
+//
+// The nodes run in steps. The first step runs the nodes following the start,
+// and each next step runs the nodes following the ones of the previous step,
+// once each. The nodes of a step run in parallel from the same state, and
+// their states are merged at the end of the step.
+func (w *Workflow[S]) Invoke(ctx context.Context, state S) (S, error) {
+	nodes, err := w.graph.successors(ctx, []string{Start}, state)
+	if err != nil {
+		return state, err
+	}
+
+	for step := 0; len(nodes) > 0; step++ {
+		if step >= w.maxSteps {
+			return state, fmt.Errorf("%w (%d)", ErrMaxSteps, w.maxSteps)
+		}
+		if state, err = w.runStep(ctx, nodes, state); err != nil {
+			return state, err
+		}
+		if nodes, err = w.graph.successors(ctx, nodes, state); err != nil {
+			return state, err
+		}
+	}
+	return state, nil
+}
+
+// runStep runs the nodes of a step and merges their states.
+func (w *Workflow[S]) runStep(ctx context.Context, nodes []string, state S) (S, error) {
+	if len(nodes) == 1 {
+		return w.runNode(ctx, nodes[0], state)
+	}
+
+	branches := make([]S, len(nodes))
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+	for i, name := range nodes {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			branches[i], errs[i] = w.runNode(ctx, name, state)
+		}(i, name)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return state, err
+		}
+	}
+	merged, err := w.merge(state, branches)
+	if err != nil {
+		return state, fmt.Errorf("merge %v: %w", nodes, err)
+	}
+	return merged, nil
+}
+
+func (w *Workflow[S]) runNode(ctx context.Context, name string, state S) (S, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindChain, name)
+	if w.CallbacksHandler != nil {
+		w.CallbacksHandler.HandleChainStart(ctx, stateValues(state))
+	}
+
+	next, err := w.graph.nodes[name](ctx, state)
+	if err != nil {
+		err = fmt.Errorf("node %s: %w", name, err)
+		if w.CallbacksHandler != nil {
+			w.CallbacksHandler.HandleChainError(ctx, err)
+		}
+		return state, err
+	}
+
+	if w.CallbacksHandler != nil {
+		w.CallbacksHandler.HandleChainEnd(ctx, stateValues(next))
+	}
+	return next, nil
+}
+
+// stateValues returns the fields of a state given to the callbacks, or nil if
+// the state has no fields.
+func stateValues[S any](state S) map[string]any {
+	fields, err := toMap(state)
+	if err != nil {
+		return nil
+	}
+	return fields
+}
+
+// Call runs the workflow from the state with the input values, and returns the
+// output keys of the final state.
+func (w *Workflow[S]) Call(ctx context.Context, inputs map[string]any, _ ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+	state, err := fromMap[S](maps.Clone(inputs))
+	if err != nil {
+		return nil, err
+	}
+	state, err = w.Invoke(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := toMap(state)
+	if err != nil {
+		return nil, err
+	}
+	if len(w.outputKeys) == 0 {
+		return fields, nil
+	}
+	outputs := make(map[string]any, len(w.outputKeys))
+	for _, key := range w.outputKeys {
+		if value, ok := fields[key]; ok {
+			outputs[key] = value
+		}
+	}
+	return outputs, nil
+}
+
+// GetCallbackHandler returns the callbacks handler of the workflow.
+func (w *Workflow[S]) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	return w.CallbacksHandler
+}
+
+// GetMemory returns the memory of the workflow, which is a simple memory.
+func (w *Workflow[S]) GetMemory() schema.Memory { //nolint:ireturn
+	return w.memory
+}
+
+// GetInputKeys returns the input keys set with WithInputKeys.
+func (w *Workflow[S]) GetInputKeys() []string {
+	return w.inputKeys
+}
+
+// GetOutputKeys returns the output keys set with WithOutputKeys.
+func (w *Workflow[S]) GetOutputKeys() []string {
+	return w.outputKeys
+}
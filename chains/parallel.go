@@ -0,0 +1,317 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/IT-Tech-Company/langchaingo/memory"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+// Parallel is a chain that runs several chains concurrently on the same input
+// values, and merges their output values.
+type Parallel struct {
+	chains     []Chain
+	inputKeys  []string
+	outputKeys []string
+	memory     schema.Memory
+}
+
+var _ Chain = (*Parallel)(nil)
+
+// NewParallel creates a parallel chain. The chains must not have output keys
+// in common.
+func NewParallel(chains ...Chain) (*Parallel, error) {
+	p := &Parallel{
+		chains: chains,
+		memory: memory.NewSimple(),
+	}
+	if len(chains) == 0 {
+		return nil, fmt.Errorf("%w: parallel chain without chains", ErrChainInitialization)
+	}
+
+	for i, c := range chains {
+		for _, key := range c.GetInputKeys() {
+			if !slices.Contains(p.inputKeys, key) {
+				p.inputKeys = append(p.inputKeys, key)
+			}
+		}
+		for _, key := range c.GetOutputKeys() {
+			if slices.Contains(p.outputKeys, key) {
+				return nil, fmt.Errorf("%w: chain at index %d has output key that already exists: %s",
+					ErrChainInitialization, i, key)
+			}
+			p.outputKeys = append(p.outputKeys, key)
+		}
+	}
+	return p, nil
+}
+
+// Call runs the chains and returns their merged output values. If chains
+// fail, the output values of the others are returned with their errors.
+func (p *Parallel) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	results := make([]map[string]any, len(p.chains))
+	errs := make([]error, len(p.chains))
+	var wg sync.WaitGroup
+	for i, c := range p.chains {
+		wg.Add(1)
+		go func(i int, c Chain) {
+			defer wg.Done()
+			results[i], errs[i] = Call(ctx, c, inputs, options...)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("chain at index %d: %w", i, errs[i])
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	outputs := make(map[string]any)
+	for i, result := range results {
+		if errs[i] != nil {
+			continue
+		}
+		for key, value := range result {
+			outputs[key] = value
+		}
+	}
+	return outputs, errors.Join(errs...)
+}
+
+// GetMemory gets the memory of the chain.
+func (p *Parallel) GetMemory() schema.Memory { //nolint:ireturn
+	return p.memory
+}
+
+// GetInputKeys returns the input keys of all the chains.
+func (p *Parallel) GetInputKeys() []string {
+	return p.inputKeys
+}
+
+// GetOutputKeys returns the output keys of all the chains.
+func (p *Parallel) GetOutputKeys() []string {
+	return p.outputKeys
+}
+
+// MapError is returned by a Map chain whose chain failed on some items.
+type MapError struct {
+	// Errors are the errors of the items, nil for the successful ones.
+	Errors []error
+}
+
+func (e *MapError) Error() string {
+	var msgs []string
+	for i, err := range e.Errors {
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("item %d: %s", i, err))
+		}
+	}
+	return fmt.Sprintf("%d of %d items failed: %s", len(msgs), len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the failed items.
+func (e *MapError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// MapOption is a function that configures a Map chain.
+type MapOption func(*Map)
+
+// WithMaxConcurrency sets the maximum number of items the chain runs on
+// concurrently. The default is 5.
+func WithMaxConcurrency(maxConcurrency int) MapOption {
+	return func(m *Map) {
+		m.maxConcurrency = maxConcurrency
+	}
+}
+
+// WithMapItemKey sets the input key of the chain the items are given as. By
+// default, it is the only input key of the chain which isn't among the other
+// input values of the Map chain.
+func WithMapItemKey(itemKey string) MapOption {
+	return func(m *Map) {
+		m.itemKey = itemKey
+	}
+}
+
+// WithMapErrorsKey sets an output key where the errors of the items are
+// returned as strings, empty for the successful items, instead of failing
+// with a MapError.
+func WithMapErrorsKey(errorsKey string) MapOption {
+	return func(m *Map) {
+		m.errorsKey = errorsKey
+	}
+}
+
+// Map is a chain that runs a chain on each item of a list input value, with
+// bounded concurrency.
+//
+// Items which are maps are used as input values of the chain, and the other
+// ones are given under its item key, see WithMapItemKey. The other input
+// values of the Map chain are given to the chain with every item. The output
+// is the list of the results of the items, in order: the output value of the
+// chain if it has a single output key, or else its output values.
+type Map struct {
+	chain          Chain
+	inputKey       string
+	outputKey      string
+	itemKey        string
+	errorsKey      string
+	maxConcurrency int
+	memory         schema.Memory
+}
+
+var _ Chain = (*Map)(nil)
+
+// NewMap creates a Map chain running the chain on the items of the input key,
+// and returning their results under the output key.
+func NewMap(chain Chain, inputKey, outputKey string, opts ...MapOption) *Map {
+	m := &Map{
+		chain:          chain,
+		inputKey:       inputKey,
+		outputKey:      outputKey,
+		maxConcurrency: _defaultApplyMaxNumberWorkers,
+		memory:         memory.NewSimple(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Call runs the chain on the items. If it fails on some items, the results of
+// the others are returned with a MapError, unless WithMapErrorsKey is used.
+func (m *Map) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	list := reflect.ValueOf(inputs[m.inputKey])
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%w: %w: %s is not a list", ErrInvalidInputValues, ErrInputValuesWrongType, m.inputKey)
+	}
+
+	shared := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		if key != m.inputKey {
+			shared[key] = value
+		}
+	}
+	itemKey := m.itemKey
+	if itemKey == "" {
+		itemKey = m.defaultItemKey(shared)
+	}
+
+	results := make([]any, list.Len())
+	errs := make([]error, list.Len())
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(1, min(m.maxConcurrency, list.Len())); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = m.callItem(ctx, list.Index(i).Interface(), itemKey, shared, options...)
+			}
+		}()
+	}
+	for i := 0; i < list.Len(); i++ {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	outputs := map[string]any{m.outputKey: results}
+	if m.errorsKey != "" {
+		messages := make([]string, len(errs))
+		for i, err := range errs {
+			if err != nil {
+				messages[i] = err.Error()
+			}
+		}
+		outputs[m.errorsKey] = messages
+		return outputs, nil
+	}
+	for _, err := range errs {
+		if err != nil {
+			return outputs, &MapError{Errors: errs}
+		}
+	}
+	return outputs, nil
+}
+
+func (m *Map) callItem(
+	ctx context.Context,
+	item any,
+	itemKey string,
+	shared map[string]any,
+	options ...ChainCallOption,
+) (any, error) {
+	inputs := make(map[string]any, len(shared)+1)
+	for key, value := range shared {
+		inputs[key] = value
+	}
+	if values, ok := item.(map[string]any); ok {
+		for key, value := range values {
+			inputs[key] = value
+		}
+	} else {
+		if itemKey == "" {
+			return nil, fmt.Errorf("%w: no input key of the chain for the items", ErrInvalidInputValues)
+		}
+		inputs[itemKey] = item
+	}
+
+	outputs, err := Call(ctx, m.chain, inputs, options...)
+	if err != nil {
+		return nil, err
+	}
+	if keys := m.chain.GetOutputKeys(); len(keys) == 1 {
+		return outputs[keys[0]], nil
+	}
+	return outputs, nil
+}
+
+// defaultItemKey returns the only input key of the chain without value, or
+// an empty string.
+func (m *Map) defaultItemKey(shared map[string]any) string {
+	var missing []string
+	for _, key := range m.chain.GetInputKeys() {
+		if _, ok := shared[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) != 1 {
+		return ""
+	}
+	return missing[0]
+}
+
+// GetMemory gets the memory of the chain.
+func (m *Map) GetMemory() schema.Memory { //nolint:ireturn
+	return m.memory
+}
+
+// GetInputKeys returns the input key of the list.
+func (m *Map) GetInputKeys() []string {
+	return []string{m.inputKey}
+}
+
+// GetOutputKeys returns the output key of the results, and the one of the
+// errors if set.
+func (m *Map) GetOutputKeys() []string {
+	if m.errorsKey != "" {
+		return []string{m.outputKey, m.errorsKey}
+	}
+	return []string{m.outputKey}
+}
@@ -0,0 +1,111 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errShout = errors.New("cannot shout numbers")
+
+// shoutChain upper-cases its text input, and fails on digits.
+func shoutChain(inputKey, outputKey string) Chain {
+	return NewTransform(func(_ context.Context, inputs map[string]any, _ ...ChainCallOption) (map[string]any, error) {
+		text, _ := inputs[inputKey].(string)
+		if strings.ContainsAny(text, "0123456789") {
+			return nil, errShout
+		}
+		return map[string]any{outputKey: strings.ToUpper(text) + inputs["suffix"].(string)}, nil
+	}, []string{inputKey, "suffix"}, []string{outputKey})
+}
+
+func TestParallel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	reverse := NewTransform(func(_ context.Context, inputs map[string]any, _ ...ChainCallOption) (map[string]any, error) {
+		runes := []rune(inputs["text"].(string))
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return map[string]any{"reversed": string(runes)}, nil
+	}, []string{"text"}, []string{"reversed"})
+
+	p, err := NewParallel(shoutChain("text", "shouted"), reverse)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"text", "suffix"}, p.GetInputKeys())
+	assert.Equal(t, []string{"shouted", "reversed"}, p.GetOutputKeys())
+
+	out, err := Call(ctx, p, map[string]any{"text": "hello", "suffix": "!"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"shouted": "HELLO!", "reversed": "olleh"}, out)
+
+	// the outputs of the other chains are returned with the errors.
+	out, err = p.Call(ctx, map[string]any{"text": "h3llo", "suffix": "!"})
+	require.ErrorIs(t, err, errShout)
+	assert.Contains(t, err.Error(), "chain at index 0")
+	assert.Equal(t, map[string]any{"reversed": "oll3h"}, out)
+
+	_, err = NewParallel(reverse, reverse)
+	require.ErrorIs(t, err, ErrChainInitialization)
+}
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var running, maxRunning atomic.Int32
+	slow := NewTransform(func(ctx context.Context, inputs map[string]any, opts ...ChainCallOption) (map[string]any, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return shoutChain("word", "text").Call(ctx, inputs, opts...)
+	}, []string{"word", "suffix"}, []string{"text"})
+
+	m := NewMap(slow, "words", "shouted", WithMaxConcurrency(2))
+	out, err := Call(ctx, m, map[string]any{
+		"words":  []string{"a", "b", "c", "d", "e"},
+		"suffix": "!",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"A!", "B!", "C!", "D!", "E!"}, out["shouted"])
+	assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+
+	// maps are input values.
+	out, err = Call(ctx, m, map[string]any{
+		"words":  []map[string]any{{"word": "a"}, {"word": "b", "suffix": "?"}},
+		"suffix": "!",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"A!", "B?"}, out["shouted"])
+
+	// the errors are reported by item, with the other results.
+	out, err = Call(ctx, m, map[string]any{"words": []any{"a", "b2", "c"}, "suffix": "!"})
+	var mapErr *MapError
+	require.ErrorAs(t, err, &mapErr)
+	require.ErrorIs(t, err, errShout)
+	assert.Equal(t, []error{nil, errShout, nil}, mapErr.Errors)
+	assert.Equal(t, "1 of 3 items failed: item 1: cannot shout numbers", err.Error())
+	assert.Equal(t, []any{"A!", nil, "C!"}, out["shouted"])
+
+	m = NewMap(shoutChain("word", "text"), "words", "shouted", WithMapErrorsKey("errors"))
+	out, err = Call(ctx, m, map[string]any{"words": []any{"a", "b2"}, "suffix": "!"})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"A!", nil}, out["shouted"])
+	assert.Equal(t, []string{"", errShout.Error()}, out["errors"])
+
+	_, err = Call(ctx, m, map[string]any{"words": "a", "suffix": "!"})
+	require.ErrorIs(t, err, ErrInputValuesWrongType)
+}
@@ -0,0 +1,255 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/embeddings"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/memory"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+const (
+	_routerDefaultInputKey = "input"
+	// _routerDefaultName is the name the model picks when no destination fits.
+	_routerDefaultName = "DEFAULT"
+)
+
+// ErrNoRoute is returned by a router without default chain when no destination
+// fits an input.
+var ErrNoRoute = errors.New("no destination for the input")
+
+// Destination is a chain a Router can dispatch the inputs to.
+type Destination struct {
+	// Name is the name of the destination, given to the model choosing it.
+	Name string
+	// Description describes the inputs the destination handles. It is given
+	// to the model choosing the destination, or compared with the inputs by
+	// the routers using embeddings.
+	Description string
+	Chain       Chain
+}
+
+// RouterOption is a function that configures a Router.
+type RouterOption func(*Router)
+
+// WithDefaultChain sets the chain of the inputs no destination fits. Without
+// it, these inputs fail with ErrNoRoute.
+func WithDefaultChain(chain Chain) RouterOption {
+	return func(r *Router) {
+		r.defaultChain = chain
+	}
+}
+
+// WithRouterInputKey sets the input key whose value is routed. The default is
+// "input".
+func WithRouterInputKey(inputKey string) RouterOption {
+	return func(r *Router) {
+		r.inputKey = inputKey
+	}
+}
+
+// WithSimilarityThreshold sets the minimum cosine similarity between the
+// embeddings of an input and of the description of its destination, with
+// NewEmbeddingRouter. Inputs less similar to every destination go to the
+// default chain.
+func WithSimilarityThreshold(threshold float32) RouterOption {
+	return func(r *Router) {
+		r.threshold = threshold
+	}
+}
+
+// routeFunc returns the index of the destination of an input, or -1.
+type routeFunc func(ctx context.Context, input string) (int, error)
+
+// Router is a chain that dispatches each input to one of its destinations,
+// chosen by a model or by the similarity of their embeddings, or to a default
+// chain. The whole input values are given to the destination chain, and its
+// output values are returned.
+type Router struct {
+	destinations []Destination
+	defaultChain Chain
+	inputKey     string
+	threshold    float32
+	route        routeFunc
+	memory       schema.Memory
+}
+
+var _ Chain = (*Router)(nil)
+
+func newRouter(destinations []Destination, opts ...RouterOption) (*Router, error) {
+	r := &Router{
+		destinations: destinations,
+		inputKey:     _routerDefaultInputKey,
+		memory:       memory.NewSimple(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if len(destinations) == 0 {
+		return nil, fmt.Errorf("%w: router without destinations", ErrChainInitialization)
+	}
+	names := make(map[string]bool, len(destinations))
+	for _, d := range destinations {
+		if d.Name == "" || d.Chain == nil {
+			return nil, fmt.Errorf("%w: destinations need a name and a chain", ErrChainInitialization)
+		}
+		if names[d.Name] || d.Name == _routerDefaultName {
+			return nil, fmt.Errorf("%w: duplicate destination %q", ErrChainInitialization, d.Name)
+		}
+		names[d.Name] = true
+	}
+	return r, nil
+}
+
+// NewLLMRouter creates a router asking a model for the destination of each
+// input, from the names and descriptions of the destinations.
+func NewLLMRouter(llm llms.Model, destinations []Destination, opts ...RouterOption) (*Router, error) {
+	r, err := newRouter(destinations, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	r.route = func(ctx context.Context, input string) (int, error) {
+		choice, err := llms.GenerateObject[routerChoice](ctx, llm, []llms.MessageContent{
+			llms.TextParts(llms.ChatMessageTypeHuman, r.routerPrompt(input)),
+		}, llms.WithObjectName("destination"))
+		if err != nil {
+			return -1, err
+		}
+		for i, d := range r.destinations {
+			if strings.EqualFold(strings.TrimSpace(choice.Destination), d.Name) {
+				return i, nil
+			}
+		}
+		return -1, nil
+	}
+	return r, nil
+}
+
+// NewEmbeddingRouter creates a router sending each input to the destination
+// whose description is the most similar to it, by the cosine similarity of
+// their embeddings. The descriptions are embedded once, when the router is
+// created.
+func NewEmbeddingRouter(
+	ctx context.Context,
+	embedder embeddings.Embedder,
+	destinations []Destination,
+	opts ...RouterOption,
+) (*Router, error) {
+	r, err := newRouter(destinations, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions := make([]string, len(destinations))
+	for i, d := range destinations {
+		descriptions[i] = d.Description
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, descriptions)
+	if err != nil {
+		return nil, fmt.Errorf("embed destinations: %w", err)
+	}
+	if len(vectors) != len(destinations) {
+		return nil, fmt.Errorf("embed destinations: got %d vectors for %d descriptions", len(vectors), len(destinations))
+	}
+
+	r.route = func(ctx context.Context, input string) (int, error) {
+		vector, err := embedder.EmbedQuery(ctx, input)
+		if err != nil {
+			return -1, err
+		}
+		best, bestSimilarity := -1, r.threshold
+		for i, v := range vectors {
+			if similarity := embeddings.CosineSimilarity(vector, v); similarity >= bestSimilarity {
+				best, bestSimilarity = i, similarity
+			}
+		}
+		return best, nil
+	}
+	return r, nil
+}
+
+// routerChoice is the object a model routing an input responds with.
+type routerChoice struct {
+	Destination string `json:"destination" jsonschema:"description=The name of the destination"`
+}
+
+func (r *Router) routerPrompt(input string) string {
+	var b strings.Builder
+	b.WriteString("Select the destination best suited to handle the input.\n\nDestinations:\n")
+	for _, d := range r.destinations {
+		fmt.Fprintf(&b, "- %s: %s\n", d.Name, d.Description)
+	}
+	fmt.Fprintf(&b, "\nIf no destination is suited, select %s.\n\nInput: %s", _routerDefaultName, input)
+	return b.String()
+}
+
+// Route returns the chain handling the input values, and the name of its
+// destination, empty for the default chain.
+func (r *Router) Route(ctx context.Context, inputs map[string]any) (Chain, string, error) {
+	input, ok := inputs[r.inputKey].(string)
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %w: %s", ErrInvalidInputValues, ErrInputValuesWrongType, r.inputKey)
+	}
+
+	i, err := r.route(ctx, input)
+	if err != nil {
+		return nil, "", fmt.Errorf("route input: %w", err)
+	}
+	if i >= 0 {
+		return r.destinations[i].Chain, r.destinations[i].Name, nil
+	}
+	if r.defaultChain == nil {
+		return nil, "", ErrNoRoute
+	}
+	return r.defaultChain, "", nil
+}
+
+// Call routes the input values and returns the output values of the chain of
+// their destination.
+func (r *Router) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	chain, _, err := r.Route(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return Call(ctx, chain, inputs, options...)
+}
+
+// GetMemory gets the memory of the chain.
+func (r *Router) GetMemory() schema.Memory { //nolint:ireturn
+	return r.memory
+}
+
+// GetInputKeys returns the input key whose value is routed.
+func (r *Router) GetInputKeys() []string {
+	return []string{r.inputKey}
+}
+
+// GetOutputKeys returns the output keys returned by all the destinations.
+func (r *Router) GetOutputKeys() []string {
+	chains := make([]Chain, 0, len(r.destinations)+1)
+	for _, d := range r.destinations {
+		chains = append(chains, d.Chain)
+	}
+	if r.defaultChain != nil {
+		chains = append(chains, r.defaultChain)
+	}
+
+	outputKeys := make([]string, 0)
+	for _, key := range chains[0].GetOutputKeys() {
+		shared := true
+		for _, c := range chains[1:] {
+			shared = shared && slices.Contains(c.GetOutputKeys(), key)
+		}
+		if shared {
+			outputKeys = append(outputKeys, key)
+		}
+	}
+	return outputKeys
+}
@@ -0,0 +1,120 @@
+package chains
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/llms/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoChain returns a chain answering with its name and its input.
+func echoChain(name string) Chain {
+	return NewTransform(func(_ context.Context, inputs map[string]any, _ ...ChainCallOption) (map[string]any, error) {
+		return map[string]any{"text": name + ": " + inputs["input"].(string)}, nil
+	}, []string{"input"}, []string{"text"})
+}
+
+func supportDestinations() []Destination {
+	return []Destination{
+		{Name: "billing", Description: "Questions about invoices and payments", Chain: echoChain("billing")},
+		{Name: "technical", Description: "Questions about errors and bugs", Chain: echoChain("technical")},
+	}
+}
+
+// supportEmbedder embeds texts by counting the occurrences of a few words.
+type supportEmbedder struct{}
+
+func (supportEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = supportEmbedder{}.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (supportEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	words := []string{"invoices", "payments", "errors", "bugs", "weather"}
+	vector := make([]float32, len(words))
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		for i, w := range words {
+			if strings.Trim(word, "?,.") == w {
+				vector[i]++
+			}
+		}
+	}
+	return vector, nil
+}
+
+func TestLLMRouter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	llm := fake.NewScriptedLLM(
+		fake.TextReply(`{"destination": "technical"}`),
+		fake.TextReply(`{"destination": "DEFAULT"}`),
+		fake.TextReply(`{"destination": "Billing"}`),
+	)
+	router, err := NewLLMRouter(llm, supportDestinations(), WithDefaultChain(echoChain("general")))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"input"}, router.GetInputKeys())
+	assert.Equal(t, []string{"text"}, router.GetOutputKeys())
+
+	out, err := Run(ctx, router, "The app crashes on start.")
+	require.NoError(t, err)
+	assert.Equal(t, "technical: The app crashes on start.", out)
+
+	// the model is given the destinations.
+	call, ok := llm.LastCall()
+	require.True(t, ok)
+	prompt := call.Messages[0].Parts[0].(llms.TextContent).Text
+	assert.Contains(t, prompt, "- billing: Questions about invoices and payments")
+	assert.Contains(t, prompt, "Input: The app crashes on start.")
+
+	out, err = Run(ctx, router, "Hello!")
+	require.NoError(t, err)
+	assert.Equal(t, "general: Hello!", out)
+
+	chain, name, err := router.Route(ctx, map[string]any{"input": "Where is my invoice?"})
+	require.NoError(t, err)
+	assert.Equal(t, "billing", name)
+	assert.NotNil(t, chain)
+}
+
+func TestEmbeddingRouter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	router, err := NewEmbeddingRouter(ctx, supportEmbedder{}, supportDestinations(),
+		WithRouterInputKey("question"), WithSimilarityThreshold(0.5))
+	require.NoError(t, err)
+
+	_, name, err := router.Route(ctx, map[string]any{"question": "Why are my payments failing?"})
+	require.NoError(t, err)
+	assert.Equal(t, "billing", name)
+
+	_, name, err = router.Route(ctx, map[string]any{"question": "I found bugs."})
+	require.NoError(t, err)
+	assert.Equal(t, "technical", name)
+
+	// without a default chain, inputs without destination fail.
+	_, err = Call(ctx, router, map[string]any{"question": "How is the weather?"})
+	require.ErrorIs(t, err, ErrNoRoute)
+
+	_, err = Call(ctx, router, map[string]any{"question": 42})
+	require.ErrorIs(t, err, ErrInputValuesWrongType)
+}
+
+func TestRouter_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewLLMRouter(fake.NewScriptedLLM(), nil)
+	require.ErrorIs(t, err, ErrChainInitialization)
+
+	destinations := append(supportDestinations(), Destination{Name: "billing", Chain: echoChain("billing")})
+	_, err = NewLLMRouter(fake.NewScriptedLLM(), destinations)
+	require.ErrorIs(t, err, ErrChainInitialization)
+}
@@ -81,3 +81,21 @@ func getNorm(v []float32) float32 {
 
 	return float32(math.Sqrt(float64(sum)))
 }
+
+// CosineSimilarity returns the cosine of the angle between two vectors of the
+// same size, from -1 for opposite vectors to 1 for vectors pointing the same
+// way. It returns 0 if the sizes differ or if a vector is null.
+func CosineSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot float32
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	norms := getNorm(a) * getNorm(b)
+	if norms == 0 {
+		return 0
+	}
+	return dot / norms
+}
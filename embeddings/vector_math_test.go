@@ -82,3 +82,13 @@ func TestGetNorm(t *testing.T) {
 		assert.InEpsilon(t, tc.expected, getNorm(tc.vector), 0.0001)
 	}
 }
+
+func TestCosineSimilarity(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 1, CosineSimilarity([]float32{1, 2, 3}, []float32{2, 4, 6}), 1e-6)
+	assert.InDelta(t, 0, CosineSimilarity([]float32{1, 0}, []float32{0, 1}), 1e-6)
+	assert.InDelta(t, -1, CosineSimilarity([]float32{1, 1}, []float32{-1, -1}), 1e-6)
+	assert.Zero(t, CosineSimilarity([]float32{1, 1}, []float32{1}))
+	assert.Zero(t, CosineSimilarity([]float32{0, 0}, []float32{1, 1}))
+}
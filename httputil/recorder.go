@@ -0,0 +1,378 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrInteractionNotFound is returned by a replaying Recorder for requests
+// which don't match any unused interaction of its cassette.
+var ErrInteractionNotFound = errors.New("httputil: no recorded interaction matches the request")
+
+// redactedValue replaces the values of the redacted headers and query
+// parameters in cassettes.
+const redactedValue = "REDACTED"
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeAuto replays the cassette if its file exists, and records it
+	// otherwise.
+	ModeAuto Mode = iota
+	// ModeReplay serves the requests from the cassette, without network.
+	ModeReplay
+	// ModeRecord sends the requests and records them, replacing the
+	// cassette.
+	ModeRecord
+)
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response it got.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request of a cassette.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response of a cassette.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is the body of a request or a response. It is encoded as a JSON string
+// when it is valid UTF-8, e.g. for JSON and server-sent events, and as an
+// object with the base64 of the body otherwise.
+type Body []byte
+
+// MarshalJSON implements json.Marshaler.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(struct {
+		Base64 string `json:"base64"`
+	}{base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// Recorder is an http.RoundTripper that records the requests it sends and
+// their responses to a cassette file, and replays them, which allows testing
+// clients of HTTP APIs without network:
+//
+//	recorder, err := httputil.NewRecorder("testdata/chat.json")
+//	llm, err := openai.New(openai.WithHTTPClient(recorder.Client()))
+//
+// Requests are matched on their method, URL and body, JSON bodies being
+// compared regardless of formatting and key order. Identical requests are
+// served the responses recorded for them in order.
+//
+// Secrets are redacted from cassettes: the values of the headers and query
+// parameters which carry credentials are replaced, and requests are matched
+// on their redacted URL. Responses are read entirely before being recorded,
+// so server-sent events are recorded and replayed as a whole.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	redactedHeaders     map[string]bool
+	redactedQueryParams map[string]bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+var _ http.RoundTripper = (*Recorder)(nil)
+
+// NewRecorder creates a Recorder of the cassette at path. In ModeReplay, and
+// in ModeAuto when the file exists, the cassette is loaded from the file.
+func NewRecorder(path string, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:                path,
+		mode:                ModeAuto,
+		transport:           http.DefaultTransport,
+		redactedHeaders:     make(map[string]bool),
+		redactedQueryParams: make(map[string]bool),
+	}
+	WithRedactedHeaders(defaultRedactedHeaders...)(r)
+	WithRedactedQueryParams(defaultRedactedQueryParams...)(r)
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeAuto {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("httputil: stat cassette: %w", err)
+		}
+	}
+	if r.mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("httputil: read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("httputil: decode cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns the mode of the recorder, which is never ModeAuto.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an http.Client using the recorder as transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Handler returns an http.Handler which serves the requests it receives with
+// the recorder, as if they were sent to baseURL. It is meant for clients
+// whose transport can't be set but whose URL can:
+//
+//	server := httptest.NewServer(recorder.Handler("https://api.mistral.ai"))
+//	llm, err := mistral.New(mistral.WithEndpoint(server.URL))
+//
+// Errors of the recorder are served as 502 Bad Gateway responses.
+func (r *Recorder) Handler(baseURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		target, err := url.Parse(baseURL)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		target = target.JoinPath(req.URL.Path)
+		target.RawQuery = req.URL.RawQuery
+
+		out := req.Clone(req.Context())
+		out.URL = target
+		out.Host = target.Host
+		out.RequestURI = ""
+		resp, err := r.RoundTrip(out)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	})
+}
+
+// RoundTrip implements http.RoundTripper. In ModeRecord, the cassette file is
+// written after each request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	sent, body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := RecordedRequest{
+		Method:  req.Method,
+		URL:     r.redactURL(req.URL),
+		Headers: r.redactHeaders(req.Header),
+		Body:    body,
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(sent, recorded)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := normalizeBody(recorded.Body)
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] ||
+			interaction.Request.Method != recorded.Method ||
+			interaction.Request.URL != recorded.URL ||
+			!bytes.Equal(normalizeBody(interaction.Request.Body), key) {
+			continue
+		}
+		r.used[i] = true
+		return newResponse(req, interaction.Response), nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, recorded.Method, recorded.URL)
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    r.redactHeaders(resp.Header),
+			Body:       body,
+		},
+	})
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// save writes the cassette file. The lock must be held.
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("httputil: encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("httputil: write cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0o600); err != nil {
+		return fmt.Errorf("httputil: write cassette: %w", err)
+	}
+	return nil
+}
+
+func (r *Recorder) redactHeaders(headers http.Header) http.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+	redacted := headers.Clone()
+	for name := range redacted {
+		if r.redactedHeaders[strings.ToLower(name)] {
+			redacted[name] = []string{redactedValue}
+		}
+	}
+	return redacted
+}
+
+func (r *Recorder) redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	changed := false
+	for name := range query {
+		if r.redactedQueryParams[strings.ToLower(name)] {
+			query[name] = []string{redactedValue}
+			changed = true
+		}
+	}
+	if changed {
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
+
+// readRequestBody reads the body of a request, and returns a copy of the
+// request with the body restored for the transport, as a RoundTripper must
+// not modify the request.
+func readRequestBody(req *http.Request) (*http.Request, []byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	sent := req.Clone(req.Context())
+	sent.Body = io.NopCloser(bytes.NewReader(body))
+	sent.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return sent, body, nil
+}
+
+// normalizeBody returns the body in a form which doesn't depend on the
+// formatting and the order of the keys of JSON bodies.
+func normalizeBody(body []byte) []byte {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return normalized
+}
+
+func newResponse(req *http.Request, recorded RecordedResponse) *http.Response {
+	headers := recorded.Headers.Clone()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,69 @@
+package httputil
+
+import (
+	"net/http"
+	"strings"
+)
+
+// defaultRedactedHeaders are the headers which carry the credentials of the
+// providers.
+var defaultRedactedHeaders = []string{ //nolint:gochecknoglobals
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"Api-Key",
+	"X-Api-Key",
+	"X-Goog-Api-Key",
+	"OpenAI-Organization",
+	"OpenAI-Project",
+}
+
+// defaultRedactedQueryParams are the query parameters which carry the
+// credentials of the providers.
+var defaultRedactedQueryParams = []string{ //nolint:gochecknoglobals
+	"key",
+	"api_key",
+	"apikey",
+	"access_token",
+}
+
+// RecorderOption is a function that configures a Recorder.
+type RecorderOption func(*Recorder)
+
+// WithMode sets the mode of the recorder. The default is ModeAuto.
+func WithMode(mode Mode) RecorderOption {
+	return func(r *Recorder) {
+		r.mode = mode
+	}
+}
+
+// WithTransport sets the transport sending the requests in ModeRecord. The
+// default is http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithRedactedHeaders adds headers whose values are redacted from the
+// cassette, in addition to the ones carrying the credentials of the
+// providers, e.g. Authorization and X-Api-Key.
+func WithRedactedHeaders(names ...string) RecorderOption {
+	return func(r *Recorder) {
+		for _, name := range names {
+			r.redactedHeaders[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// WithRedactedQueryParams adds query parameters whose values are redacted
+// from the cassette, in addition to the ones carrying the credentials of the
+// providers, e.g. key and access_token.
+func WithRedactedQueryParams(names ...string) RecorderOption {
+	return func(r *Recorder) {
+		for _, name := range names {
+			r.redactedQueryParams[strings.ToLower(name)] = true
+		}
+	}
+}
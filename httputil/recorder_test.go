@@ -0,0 +1,215 @@
+package httputil
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/stream":
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, word := range []string{"hello", "world"} {
+				fmt.Fprintf(w, "data: %s\n\n", word)
+				w.(http.Flusher).Flush()
+			}
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		case "/binary":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte{0xff, 0x00, 0xfe})
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Set-Cookie", "session=secret")
+			fmt.Fprintf(w, `{"call":%d,"echo":%s}`, calls, body)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func post(t *testing.T, client *http.Client, url, body string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer sk-secret")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(b)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "cassettes", "chat.json")
+
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+	assert.Equal(t, ModeRecord, recorder.Mode())
+	client := recorder.Client()
+
+	_, first := post(t, client, server.URL+"/chat?key=secret&model=m", `{"a": 1, "b": 2}`)
+	_, second := post(t, client, server.URL+"/chat?key=secret&model=m", `{"a": 1, "b": 2}`)
+	assert.Equal(t, `{"call":1,"echo":{"a": 1, "b": 2}}`, first)
+	assert.Equal(t, `{"call":2,"echo":{"a": 1, "b": 2}}`, second)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "sk-secret")
+	assert.NotContains(t, string(data), "key=secret")
+	assert.NotContains(t, string(data), "session=secret")
+
+	// the cassette exists, it is replayed without the server.
+	server.Close()
+	replayer, err := NewRecorder(path)
+	require.NoError(t, err)
+	assert.Equal(t, ModeReplay, replayer.Mode())
+	client = replayer.Client()
+
+	// JSON bodies match regardless of their formatting and key order.
+	resp, body := post(t, client, server.URL+"/chat?model=m&key=other", `{"b":2,"a":1}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, first, body)
+	_, body = post(t, client, server.URL+"/chat?key=secret&model=m", `{"a": 1, "b": 2}`)
+	assert.Equal(t, second, body)
+
+	// the interactions were used.
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL+"/chat?key=secret&model=m", strings.NewReader(`{"a": 1, "b": 2}`)) //nolint:lll
+	require.NoError(t, err)
+	_, err = client.Do(req) //nolint:bodyclose
+	require.ErrorIs(t, err, ErrInteractionNotFound)
+}
+
+func TestRecorder_Streams(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "stream.json")
+
+	recorder, err := NewRecorder(path, WithMode(ModeRecord))
+	require.NoError(t, err)
+	_, recorded := post(t, recorder.Client(), server.URL+"/stream", `{"stream":true}`)
+	_, binary := post(t, recorder.Client(), server.URL+"/binary", "")
+
+	replayer, err := NewRecorder(path, WithMode(ModeReplay))
+	require.NoError(t, err)
+	resp, err := replayer.Client().Post(server.URL+"/stream", "application/json", strings.NewReader(`{"stream": true}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var events []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			events = append(events, data)
+		}
+	}
+	assert.Equal(t, []string{"hello", "world", "[DONE]"}, events)
+	assert.Equal(t, "data: hello\n\ndata: world\n\ndata: [DONE]\n\n", recorded)
+
+	_, replayed := post(t, replayer.Client(), server.URL+"/binary", "")
+	assert.Equal(t, binary, replayed)
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), WithMode(ModeReplay))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := Cassette{Interactions: []*Interaction{{
+		Request:  RecordedRequest{Method: http.MethodGet, URL: "https://example.com/models"},
+		Response: RecordedResponse{StatusCode: http.StatusTooManyRequests, Body: Body("slow down")},
+	}}}
+	data, err := json.Marshal(cassette)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+	resp, err := recorder.Client().Get("https://example.com/models")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "429 Too Many Requests", resp.Status)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "slow down", string(body))
+}
+
+func TestRecorder_DoesNotModifyRequest(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+	recorder, err := NewRecorder(filepath.Join(t.TempDir(), "chat.json"), WithMode(ModeRecord))
+	require.NoError(t, err)
+
+	body := io.NopCloser(strings.NewReader(`{"a": 1}`))
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL+"/chat", body)
+	require.NoError(t, err)
+	resp, err := recorder.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, body, req.Body)
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"call":1,"echo":{"a": 1}}`, string(b))
+}
+
+func TestRecorder_Handler(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := Cassette{Interactions: []*Interaction{{
+		Request: RecordedRequest{Method: http.MethodPost, URL: "https://example.com/v1/chat?stream=false", Body: Body(`{"a":1}`)},
+		Response: RecordedResponse{
+			StatusCode: http.StatusOK,
+			Headers:    http.Header{"Content-Type": {"application/json"}},
+			Body:       Body(`{"b":2}`),
+		},
+	}}}
+	data, err := json.Marshal(cassette)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+	server := httptest.NewServer(recorder.Handler("https://example.com/v1"))
+	t.Cleanup(server.Close)
+
+	resp, body := post(t, server.Client(), server.URL+"/chat?stream=false", `{"a": 1}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `{"b":2}`, body)
+
+	// the interaction is used.
+	resp, _ = post(t, server.Client(), server.URL+"/chat?stream=false", `{"a": 1}`)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+}
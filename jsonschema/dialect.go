@@ -0,0 +1,268 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnsupportedSchema is returned when a schema can't be converted to the
+// subset of JSON Schema supported by a provider.
+var ErrUnsupportedSchema = errors.New("unsupported schema")
+
+// ToOpenAIStrict converts a schema to the subset supported by the structured
+// outputs of OpenAI, used for functions and response formats with strict
+// mode:
+//
+//   - Objects don't allow additional properties, and all their properties are
+//     required; the optional ones are made nullable instead.
+//   - Nullable schemas are combined with the null type in anyOf, and oneOf
+//     is replaced by anyOf.
+//   - The string lengths and the formats OpenAI doesn't know are removed.
+//
+// Objects with additional properties, such as maps, can't be converted.
+func ToOpenAIStrict(def Definition) (Definition, error) {
+	return transform(def, func(def Definition) (Definition, error) {
+		if def.AdditionalProperties != nil && def.AdditionalProperties != false {
+			return Definition{}, fmt.Errorf("%w: OpenAI strict mode doesn't allow additional properties", ErrUnsupportedSchema)
+		}
+		if def.Type == Object {
+			def = requireAll(def)
+			def.AdditionalProperties = false
+		}
+		if len(def.OneOf) > 0 {
+			def.AnyOf = append(def.AnyOf[:len(def.AnyOf):len(def.AnyOf)], def.OneOf...)
+			def.OneOf = nil
+		}
+		def.MinLength, def.MaxLength = nil, nil
+		if !openAIFormats[def.Format] {
+			def.Format = ""
+		}
+		return withNullType(def), nil
+	})
+}
+
+//nolint:gochecknoglobals
+var openAIFormats = map[string]bool{
+	"date-time": true, "time": true, "date": true, "duration": true, "email": true,
+	"hostname": true, "ipv4": true, "ipv6": true, "uuid": true,
+}
+
+// requireAll makes all the properties of an object required, making the
+// optional ones nullable.
+func requireAll(def Definition) Definition {
+	required := make(map[string]bool, len(def.Required))
+	for _, name := range def.Required {
+		required[name] = true
+	}
+
+	optional := make([]string, 0, len(def.Properties))
+	for name := range def.Properties {
+		if !required[name] {
+			optional = append(optional, name)
+		}
+	}
+	sort.Strings(optional)
+
+	properties := make(map[string]Definition, len(def.Properties))
+	for name, prop := range def.Properties {
+		if !required[name] && !allowsNull(prop) {
+			prop.Nullable = true
+			prop = withNullType(prop)
+		}
+		properties[name] = prop
+	}
+	def.Properties = properties
+	def.Required = append(def.Required[:len(def.Required):len(def.Required)], optional...)
+	return def
+}
+
+func allowsNull(def Definition) bool {
+	if def.Nullable || def.Type == Null {
+		return true
+	}
+	for _, option := range def.AnyOf {
+		if option.Type == Null {
+			return true
+		}
+	}
+	return false
+}
+
+// withNullType replaces Nullable, which isn't part of JSON Schema, with an
+// anyOf allowing null.
+func withNullType(def Definition) Definition {
+	if !def.Nullable {
+		return def
+	}
+	def.Nullable = false
+	null := Definition{Type: Null}
+	if len(def.AnyOf) > 0 && def.Type == "" {
+		def.AnyOf = append(def.AnyOf, null)
+		return def
+	}
+	description := def.Description
+	def.Description = ""
+	return Definition{Description: description, AnyOf: []Definition{def, null}}
+}
+
+// ToGemini converts a schema to the OpenAPI subset supported by Gemini:
+//
+//   - References are replaced by the schemas they reference; recursive
+//     schemas can't be converted.
+//   - The combinations of a schema with the null type are made nullable.
+//     Other combinations are replaced by their first schema.
+//   - Additional properties are removed, as well as the formats Gemini
+//     doesn't know.
+func ToGemini(def Definition) (Definition, error) {
+	return toGemini(def, def, map[string]bool{})
+}
+
+func toGemini(root, def Definition, refs map[string]bool) (Definition, error) {
+	if def.Ref != "" {
+		if refs[def.Ref] {
+			return Definition{}, fmt.Errorf("%w: Gemini doesn't support recursive schemas", ErrUnsupportedSchema)
+		}
+		ref, err := resolveRef(root, def.Ref)
+		if err != nil {
+			return Definition{}, err
+		}
+		refs[def.Ref] = true
+		defer delete(refs, def.Ref)
+
+		ref.Defs = nil
+		if def.Description != "" {
+			ref.Description = def.Description
+		}
+		ref.Nullable = ref.Nullable || def.Nullable
+		return toGemini(root, ref, refs)
+	}
+
+	if len(def.AnyOf) > 0 || len(def.OneOf) > 0 {
+		combined := Definition{}
+		nullable := def.Nullable
+		for _, option := range append(append([]Definition{}, def.AnyOf...), def.OneOf...) {
+			if option.Type == Null {
+				nullable = true
+			} else if combined.Type == "" && combined.Ref == "" {
+				combined = option
+			}
+		}
+		if def.Description != "" {
+			combined.Description = def.Description
+		}
+		combined.Nullable = combined.Nullable || nullable
+		return toGemini(root, combined, refs)
+	}
+
+	def.Defs = nil
+	def.AdditionalProperties = nil
+	if !geminiFormats[def.Type][def.Format] {
+		def.Format = ""
+	}
+
+	if def.Items != nil {
+		items, err := toGemini(root, *def.Items, refs)
+		if err != nil {
+			return Definition{}, err
+		}
+		def.Items = &items
+	}
+	if def.Properties != nil {
+		properties := make(map[string]Definition, len(def.Properties))
+		for name, prop := range def.Properties {
+			prop, err := toGemini(root, prop, refs)
+			if err != nil {
+				return Definition{}, err
+			}
+			properties[name] = prop
+		}
+		def.Properties = properties
+	}
+	return def, nil
+}
+
+//nolint:gochecknoglobals
+var geminiFormats = map[DataType]map[string]bool{
+	String:  {"enum": true, "date-time": true},
+	Integer: {"int32": true, "int64": true},
+	Number:  {"float": true, "double": true},
+}
+
+// ToAnthropic converts a schema to the input schema of the tools of Anthropic.
+// The schema must describe an object; Nullable, which isn't part of JSON
+// Schema, is replaced by an anyOf allowing null.
+func ToAnthropic(def Definition) (Definition, error) {
+	if def.Type != Object && def.Ref == "" {
+		return Definition{}, fmt.Errorf("%w: Anthropic input schemas must be objects", ErrUnsupportedSchema)
+	}
+	return transform(def, func(def Definition) (Definition, error) {
+		return withNullType(def), nil
+	})
+}
+
+// transform applies fn to the schema and all the schemas it contains, from
+// the innermost ones.
+func transform(def Definition, fn func(Definition) (Definition, error)) (Definition, error) {
+	var err error
+	if def.Items != nil {
+		items, err := transform(*def.Items, fn)
+		if err != nil {
+			return Definition{}, err
+		}
+		def.Items = &items
+	}
+	if def.Properties, err = transformMap(def.Properties, fn); err != nil {
+		return Definition{}, err
+	}
+	if def.Defs, err = transformMap(def.Defs, fn); err != nil {
+		return Definition{}, err
+	}
+	if def.AnyOf, err = transformSlice(def.AnyOf, fn); err != nil {
+		return Definition{}, err
+	}
+	if def.OneOf, err = transformSlice(def.OneOf, fn); err != nil {
+		return Definition{}, err
+	}
+	switch additional := def.AdditionalProperties.(type) {
+	case Definition:
+		if def.AdditionalProperties, err = transform(additional, fn); err != nil {
+			return Definition{}, err
+		}
+	case *Definition:
+		if def.AdditionalProperties, err = transform(*additional, fn); err != nil {
+			return Definition{}, err
+		}
+	}
+	return fn(def)
+}
+
+func transformMap(defs map[string]Definition, fn func(Definition) (Definition, error)) (map[string]Definition, error) {
+	if defs == nil {
+		return nil, nil
+	}
+	res := make(map[string]Definition, len(defs))
+	for name, def := range defs {
+		def, err := transform(def, fn)
+		if err != nil {
+			return nil, err
+		}
+		res[name] = def
+	}
+	return res, nil
+}
+
+func transformSlice(defs []Definition, fn func(Definition) (Definition, error)) ([]Definition, error) {
+	if defs == nil {
+		return nil, nil
+	}
+	res := make([]Definition, 0, len(defs))
+	for _, def := range defs {
+		def, err := transform(def, fn)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, def)
+	}
+	return res, nil
+}
@@ -0,0 +1,94 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// weatherSchema has optional, nullable, combined and referenced properties.
+func weatherSchema() jsonschema.Definition {
+	return jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"city": {Type: jsonschema.String, MaxLength: ptr(100), Format: "uri"},
+			"days": {Type: jsonschema.Integer, Nullable: true, Description: "Number of days"},
+			"id":   {OneOf: []jsonschema.Definition{{Type: jsonschema.String}, {Type: jsonschema.Integer}}},
+			"unit": {Ref: "#/$defs/unit"},
+		},
+		Required: []string{"city", "days"},
+		Defs: map[string]jsonschema.Definition{
+			"unit": {Type: jsonschema.String, Enum: []string{"celsius", "fahrenheit"}},
+		},
+	}
+}
+
+func TestToOpenAIStrict(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.ToOpenAIStrict(weatherSchema())
+	require.NoError(t, err)
+
+	null := jsonschema.Definition{Type: jsonschema.Null}
+	assert.Equal(t, jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"city": {Type: jsonschema.String},
+			"days": {Description: "Number of days", AnyOf: []jsonschema.Definition{{Type: jsonschema.Integer}, null}},
+			"id": {AnyOf: []jsonschema.Definition{
+				{Type: jsonschema.String}, {Type: jsonschema.Integer}, null,
+			}},
+			"unit": {AnyOf: []jsonschema.Definition{{Ref: "#/$defs/unit"}, null}},
+		},
+		Required:             []string{"city", "days", "id", "unit"},
+		AdditionalProperties: false,
+		Defs: map[string]jsonschema.Definition{
+			"unit": {Type: jsonschema.String, Enum: []string{"celsius", "fahrenheit"}},
+		},
+	}, def)
+
+	_, err = jsonschema.ToOpenAIStrict(jsonschema.Definition{
+		Type:                 jsonschema.Object,
+		AdditionalProperties: jsonschema.Definition{Type: jsonschema.String},
+	})
+	require.ErrorIs(t, err, jsonschema.ErrUnsupportedSchema)
+}
+
+func TestToGemini(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.ToGemini(weatherSchema())
+	require.NoError(t, err)
+	assert.Equal(t, jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"city": {Type: jsonschema.String, MaxLength: ptr(100)},
+			"days": {Type: jsonschema.Integer, Nullable: true, Description: "Number of days"},
+			"id":   {Type: jsonschema.String},
+			"unit": {Type: jsonschema.String, Enum: []string{"celsius", "fahrenheit"}},
+		},
+		Required: []string{"city", "days"},
+	}, def)
+
+	recursive, err := jsonschema.For[tree]()
+	require.NoError(t, err)
+	_, err = jsonschema.ToGemini(recursive)
+	require.ErrorIs(t, err, jsonschema.ErrUnsupportedSchema)
+}
+
+func TestToAnthropic(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.ToAnthropic(weatherSchema())
+	require.NoError(t, err)
+	assert.Equal(t, jsonschema.Definition{
+		Description: "Number of days",
+		AnyOf:       []jsonschema.Definition{{Type: jsonschema.Integer}, {Type: jsonschema.Null}},
+	}, def.Properties["days"])
+	assert.Equal(t, weatherSchema().Properties["id"], def.Properties["id"])
+
+	_, err = jsonschema.ToAnthropic(jsonschema.Definition{Type: jsonschema.String})
+	require.ErrorIs(t, err, jsonschema.ErrUnsupportedSchema)
+}
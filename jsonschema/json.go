@@ -1,7 +1,8 @@
-// Package jsonschema provides very simple functionality for representing a JSON schema as a
-// (nested) struct. This struct can be used with the chat completion "function call" feature.
-// For more complicated schemas, it is recommended to use a dedicated JSON schema library
-// and/or pass in the schema in []byte format.
+// Package jsonschema provides functionality for representing a JSON schema as a (nested)
+// struct. This struct can be used with the chat completion "function call" feature.
+// The schema of a Go type can be derived with Reflect and For, values can be checked
+// against a schema with Validate, and ToOpenAIStrict, ToGemini and ToAnthropic convert
+// a schema to the subset of JSON Schema supported by each provider.
 package jsonschema
 
 import "encoding/json"
@@ -19,7 +20,8 @@ const (
 )
 
 // Definition is a struct for describing a JSON Schema.
-// It is fairly limited, and you may have better luck using a third-party library.
+// It covers the keywords used to describe the arguments of tools and structured outputs; use
+// ToOpenAIStrict, ToGemini and ToAnthropic to convert it to the subset supported by a provider.
 type Definition struct {
 	// Type specifies the data type of the schema.
 	Type DataType `json:"type,omitempty"`
@@ -34,16 +36,106 @@ type Definition struct {
 	Required []string `json:"required,omitempty"`
 	// Items specifies which data type an array contains, if the schema type is Array.
 	Items *Definition `json:"items,omitempty"`
+	// AdditionalProperties describes the properties of an object that aren't listed in
+	// Properties. It is either a bool, false forbidding other properties, or a Definition
+	// the values of the other properties must match.
+	AdditionalProperties any `json:"additionalProperties,omitempty"`
+	// AnyOf requires the value to match at least one of the schemas.
+	AnyOf []Definition `json:"anyOf,omitempty"`
+	// OneOf requires the value to match exactly one of the schemas.
+	OneOf []Definition `json:"oneOf,omitempty"`
+	// Ref references another schema: "#" for the root schema, or "#/$defs/name" for one of the
+	// Defs of the root schema.
+	Ref string `json:"$ref,omitempty"`
+	// Defs holds the schemas referenced with Ref. It is only used in the root schema.
+	Defs map[string]Definition `json:"$defs,omitempty"`
+	// Nullable allows the value to be null, as in the OpenAPI subset of JSON Schema.
+	Nullable bool `json:"nullable,omitempty"`
+	// Format is the format of a string, e.g. "date-time", "date", "email", "uri" or "uuid".
+	Format string `json:"format,omitempty"`
+	// Pattern is a regular expression a string must match.
+	Pattern string `json:"pattern,omitempty"`
+	// MinLength is the minimum length of a string.
+	MinLength *int `json:"minLength,omitempty"`
+	// MaxLength is the maximum length of a string.
+	MaxLength *int `json:"maxLength,omitempty"`
+	// Minimum is the minimum value of a number.
+	Minimum *float64 `json:"minimum,omitempty"`
+	// Maximum is the maximum value of a number.
+	Maximum *float64 `json:"maximum,omitempty"`
+	// MinItems is the minimum number of items of an array.
+	MinItems *int `json:"minItems,omitempty"`
+	// MaxItems is the maximum number of items of an array.
+	MaxItems *int `json:"maxItems,omitempty"`
 }
 
 func (d Definition) MarshalJSON() ([]byte, error) {
+	type Alias Definition
+	// References and combinations of schemas don't have properties of their own.
+	if d.Properties == nil && (d.Ref != "" || len(d.AnyOf) > 0 || len(d.OneOf) > 0) {
+		return json.Marshal(struct {
+			Alias
+			Properties map[string]Definition `json:"properties,omitempty"`
+		}{
+			Alias: (Alias)(d),
+		})
+	}
 	if d.Properties == nil {
 		d.Properties = make(map[string]Definition)
 	}
-	type Alias Definition
 	return json.Marshal(struct {
 		Alias
 	}{
 		Alias: (Alias)(d),
 	})
 }
+
+func (d *Definition) UnmarshalJSON(data []byte) error {
+	type Alias Definition
+	aux := struct {
+		*Alias
+		Type                 json.RawMessage `json:"type,omitempty"`
+		AdditionalProperties json.RawMessage `json:"additionalProperties,omitempty"`
+	}{
+		Alias: (*Alias)(d),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	// The type can be a list of types; a null type makes the schema nullable.
+	if len(aux.Type) > 0 && aux.Type[0] == '[' {
+		var types []DataType
+		if err := json.Unmarshal(aux.Type, &types); err != nil {
+			return err
+		}
+		for _, t := range types {
+			if t == Null && len(types) > 1 {
+				d.Nullable = true
+				continue
+			}
+			d.Type = t
+		}
+	} else if len(aux.Type) > 0 {
+		if err := json.Unmarshal(aux.Type, &d.Type); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case len(aux.AdditionalProperties) == 0:
+	case aux.AdditionalProperties[0] == '{':
+		var additional Definition
+		if err := json.Unmarshal(aux.AdditionalProperties, &additional); err != nil {
+			return err
+		}
+		d.AdditionalProperties = additional
+	default:
+		var additional bool
+		if err := json.Unmarshal(aux.AdditionalProperties, &additional); err != nil {
+			return err
+		}
+		d.AdditionalProperties = additional
+	}
+	return nil
+}
@@ -211,3 +211,43 @@ func structToMap(t *testing.T, v any) map[string]any {
 	}
 	return got
 }
+
+func TestDefinition_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var def jsonschema.Definition
+	err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": ["string", "null"], "minLength": 1},
+			"tags": {"type": "object", "additionalProperties": {"type": "string"}},
+			"user": {"$ref": "#/$defs/user"}
+		},
+		"additionalProperties": false,
+		"$defs": {"user": {"type": "object"}}
+	}`), &def)
+	if err != nil {
+		t.Fatalf("Failed to Unmarshal JSON: error = %v", err)
+	}
+
+	minLength := 1
+	want := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"name": {Type: jsonschema.String, Nullable: true, MinLength: &minLength},
+			"tags": {Type: jsonschema.Object, AdditionalProperties: jsonschema.Definition{Type: jsonschema.String}},
+			"user": {Ref: "#/$defs/user"},
+		},
+		AdditionalProperties: false,
+		Defs:                 map[string]jsonschema.Definition{"user": {Type: jsonschema.Object}},
+	}
+	if !reflect.DeepEqual(def, want) {
+		t.Errorf("UnmarshalJSON() got = %+v, want %+v", def, want)
+	}
+
+	// References don't get the empty properties of other schemas.
+	got := structToMap(t, def.Properties["user"])
+	if !reflect.DeepEqual(got, map[string]any{"$ref": "#/$defs/user"}) {
+		t.Errorf("MarshalJSON() got = %v", got)
+	}
+}
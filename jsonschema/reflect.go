@@ -0,0 +1,286 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnsupportedType is returned when a Go type can't be described by a
+	// JSON schema, e.g. channels and functions.
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrInvalidTag is returned for jsonschema tags with invalid values.
+	ErrInvalidTag = errors.New("invalid jsonschema tag")
+)
+
+// For returns the schema of the values of the Go type T, which is usually a
+// struct. See Reflect for how types are described.
+func For[T any]() (Definition, error) {
+	return Reflect(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Reflect returns the schema of the values of a Go type, following the rules
+// of encoding/json:
+//
+//   - Struct fields are named after their json tag, and skipped if the tag is
+//     "-" or if they are unexported. Embedded structs are flattened.
+//   - Fields are required unless their json tag has the omitempty option.
+//   - Pointers are described by the type they point to.
+//   - Maps are objects whose additional properties are described by the type
+//     of the values, and time.Time is a date-time string.
+//
+// Struct types referencing themselves are described once in the $defs of the
+// schema, and referenced with $ref.
+//
+// The jsonschema tag sets the keywords of the schema of a field, as a comma
+// separated list of keywords and key=value pairs:
+//
+//	Unit string `json:"unit" jsonschema:"description=The unit of the temperature,enum=celsius,enum=fahrenheit"`
+//	Days int    `json:"days,omitempty" jsonschema:"minimum=1,maximum=14"`
+//
+// The supported keys are description, enum, format, pattern, minimum,
+// maximum, minLength, maxLength, minItems and maxItems, and the keywords are
+// required and nullable. Descriptions containing commas can be given in the
+// jsonschema_description tag instead.
+func Reflect(t reflect.Type) (Definition, error) {
+	r := &reflector{
+		root:      indirect(t),
+		recursive: map[reflect.Type]bool{},
+	}
+	r.findRecursive(r.root, map[reflect.Type]bool{})
+
+	def, err := r.reflectType(t, true)
+	if err != nil {
+		return Definition{}, err
+	}
+	def.Defs = r.defs
+	return def, nil
+}
+
+//nolint:gochecknoglobals
+var timeType = reflect.TypeOf(time.Time{})
+
+// reflector holds the state of the reflection of a type.
+type reflector struct {
+	root reflect.Type
+	// recursive is the set of the struct types referencing themselves.
+	recursive map[reflect.Type]bool
+	// defs holds the schemas of the recursive types other than the root.
+	defs map[string]Definition
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// findRecursive adds the struct types referencing themselves to r.recursive.
+func (r *reflector) findRecursive(t reflect.Type, stack map[reflect.Type]bool) {
+	t = indirect(t)
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Slice, reflect.Array, reflect.Map:
+		r.findRecursive(t.Elem(), stack)
+	case reflect.Struct:
+		if t == timeType {
+			return
+		}
+		if stack[t] {
+			r.recursive[t] = true
+			return
+		}
+		stack[t] = true
+		defer delete(stack, t)
+		for i := 0; i < t.NumField(); i++ {
+			r.findRecursive(t.Field(i).Type, stack)
+		}
+	}
+}
+
+func (r *reflector) reflectType(t reflect.Type, root bool) (Definition, error) {
+	t = indirect(t)
+	if t == timeType {
+		return Definition{Type: String, Format: "date-time"}, nil
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String:
+		return Definition{Type: String}, nil
+	case reflect.Bool:
+		return Definition{Type: Boolean}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Definition{Type: Integer}, nil
+	case reflect.Float32, reflect.Float64:
+		return Definition{Type: Number}, nil
+	case reflect.Slice, reflect.Array:
+		// encoding/json encodes byte slices as base64 strings.
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return Definition{Type: String}, nil
+		}
+		items, err := r.reflectType(t.Elem(), false)
+		if err != nil {
+			return Definition{}, err
+		}
+		return Definition{Type: Array, Items: &items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return Definition{}, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+		}
+		values, err := r.reflectType(t.Elem(), false)
+		if err != nil {
+			return Definition{}, err
+		}
+		return Definition{Type: Object, AdditionalProperties: values}, nil
+	case reflect.Interface:
+		return Definition{}, nil
+	case reflect.Struct:
+		return r.reflectStruct(t, root)
+	default:
+		return Definition{}, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+}
+
+func (r *reflector) reflectStruct(t reflect.Type, root bool) (Definition, error) {
+	if !root && r.recursive[t] {
+		if t == r.root {
+			return Definition{Ref: "#"}, nil
+		}
+		name := t.Name()
+		if _, ok := r.defs[name]; !ok {
+			if r.defs == nil {
+				r.defs = map[string]Definition{}
+			}
+			// Reserve the name before reflecting the fields, which reference
+			// the type.
+			r.defs[name] = Definition{}
+			def, err := r.reflectStruct(t, true)
+			if err != nil {
+				return Definition{}, err
+			}
+			r.defs[name] = def
+		}
+		return Definition{Ref: "#/$defs/" + name}, nil
+	}
+
+	def := Definition{Type: Object, Properties: map[string]Definition{}}
+	if err := r.reflectFields(t, &def); err != nil {
+		return Definition{}, err
+	}
+	return def, nil
+}
+
+func (r *reflector) reflectFields(t reflect.Type, def *Definition) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(jsonTag, ",")
+
+		if field.Anonymous && name == "" {
+			if ft := indirect(field.Type); ft.Kind() == reflect.Struct {
+				if err := r.reflectFields(ft, def); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := r.reflectType(field.Type, false)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		required := !hasOption(opts, "omitempty")
+		if err := applyTag(&prop, &required, field.Tag); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		def.Properties[name] = prop
+		if required {
+			def.Required = append(def.Required, name)
+		}
+	}
+	return nil
+}
+
+// applyTag sets the schema keywords given in the jsonschema tags of a field.
+func applyTag(def *Definition, required *bool, tag reflect.StructTag) error {
+	for _, item := range strings.Split(tag.Get("jsonschema"), ",") {
+		key, value, _ := strings.Cut(item, "=")
+		var err error
+		switch key {
+		case "":
+		case "description":
+			def.Description = value
+		case "enum":
+			def.Enum = append(def.Enum, value)
+		case "format":
+			def.Format = value
+		case "pattern":
+			def.Pattern = value
+		case "minimum":
+			def.Minimum, err = parseFloat(value)
+		case "maximum":
+			def.Maximum, err = parseFloat(value)
+		case "minLength":
+			def.MinLength, err = parseInt(value)
+		case "maxLength":
+			def.MaxLength, err = parseInt(value)
+		case "minItems":
+			def.MinItems, err = parseInt(value)
+		case "maxItems":
+			def.MaxItems, err = parseInt(value)
+		case "required":
+			*required = true
+		case "nullable":
+			def.Nullable = true
+		default:
+			err = fmt.Errorf("%w: unknown key %q", ErrInvalidTag, key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if description, ok := tag.Lookup("jsonschema_description"); ok {
+		def.Description = description
+	}
+	return nil
+}
+
+func parseFloat(s string) (*float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTag, err)
+	}
+	return &f, nil
+}
+
+func parseInt(s string) (*int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTag, err)
+	}
+	return &i, nil
+}
+
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
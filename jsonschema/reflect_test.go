@@ -0,0 +1,113 @@
+package jsonschema_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type location struct {
+	City    string `json:"city" jsonschema:"description=The name of the city"`
+	Country string `json:"country,omitempty" jsonschema_description:"The country, e.g. France, Italy"`
+}
+
+type weatherRequest struct {
+	location
+	Unit     string            `json:"unit" jsonschema:"enum=celsius,enum=fahrenheit"`
+	Days     *int              `json:"days,omitempty" jsonschema:"minimum=1,maximum=14,nullable"`
+	Hourly   bool              `json:"hourly"`
+	Tags     []string          `json:"tags,omitempty"`
+	Since    time.Time         `json:"since"`
+	Extra    map[string]string `json:"extra,omitempty"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+type node struct {
+	Children []*node `json:"children"`
+}
+
+func TestFor(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.For[weatherRequest]()
+	require.NoError(t, err)
+	assert.Equal(t, jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"city":    {Type: jsonschema.String, Description: "The name of the city"},
+			"country": {Type: jsonschema.String, Description: "The country, e.g. France, Italy"},
+			"unit":    {Type: jsonschema.String, Enum: []string{"celsius", "fahrenheit"}},
+			"days":    {Type: jsonschema.Integer, Minimum: ptr(1.0), Maximum: ptr(14.0), Nullable: true},
+			"hourly":  {Type: jsonschema.Boolean},
+			"tags":    {Type: jsonschema.Array, Items: &jsonschema.Definition{Type: jsonschema.String}},
+			"since":   {Type: jsonschema.String, Format: "date-time"},
+			"extra":   {Type: jsonschema.Object, AdditionalProperties: jsonschema.Definition{Type: jsonschema.String}},
+		},
+		Required: []string{"city", "unit", "hourly", "since"},
+	}, def)
+}
+
+type tree struct {
+	Root  node `json:"root"`
+	Owner user `json:"owner"`
+}
+
+type user struct {
+	Email string `json:"email" jsonschema:"format=email,required"`
+	Age   int    `json:"age,omitempty" jsonschema:"minimum=0"`
+}
+
+func TestForRecursive(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.For[node]()
+	require.NoError(t, err)
+	assert.Equal(t, jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"children": {Type: jsonschema.Array, Items: &jsonschema.Definition{Ref: "#"}},
+		},
+		Required: []string{"children"},
+	}, def)
+
+	def, err = jsonschema.For[tree]()
+	require.NoError(t, err)
+	assert.Equal(t, jsonschema.Definition{Ref: "#/$defs/node"}, def.Properties["root"])
+	assert.Equal(t, jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"children": {Type: jsonschema.Array, Items: &jsonschema.Definition{Ref: "#/$defs/node"}},
+		},
+		Required: []string{"children"},
+	}, def.Defs["node"])
+	assert.Equal(t, jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"email": {Type: jsonschema.String, Format: "email"},
+			"age":   {Type: jsonschema.Integer, Minimum: ptr(0.0)},
+		},
+		Required: []string{"email"},
+	}, def.Properties["owner"])
+}
+
+func TestForErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := jsonschema.For[struct {
+		C chan int `json:"c"`
+	}]()
+	require.ErrorIs(t, err, jsonschema.ErrUnsupportedType)
+
+	_, err = jsonschema.For[struct {
+		N int `json:"n" jsonschema:"minimum=one"`
+	}]()
+	require.ErrorIs(t, err, jsonschema.ErrInvalidTag)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
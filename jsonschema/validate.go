@@ -0,0 +1,294 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrUnresolvedRef is returned for references to schemas that aren't in the
+// $defs of the root schema.
+var ErrUnresolvedRef = errors.New("unresolved reference")
+
+// ValidationError is an error of a value not matching its schema.
+type ValidationError struct {
+	// Path is the location of the value in the validated document, e.g.
+	// "$.tags[0]".
+	Path string
+	// Message describes the problem.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate checks that the JSON document data matches the schema. The
+// returned error joins a *ValidationError for each problem found.
+func Validate(def Definition, data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return &ValidationError{Path: "$", Message: fmt.Sprintf("invalid JSON: %s", err)}
+	}
+	return ValidateValue(def, v)
+}
+
+// ValidateValue checks that a value decoded by encoding/json into an any
+// matches the schema. See Validate.
+func ValidateValue(def Definition, v any) error {
+	val := &validator{root: def}
+	val.validate(def, v, "$")
+	return errors.Join(val.errs...)
+}
+
+type validator struct {
+	root Definition
+	errs []error
+}
+
+func (val *validator) fail(path, format string, args ...any) {
+	val.errs = append(val.errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// check returns whether the value matches the schema, without recording the
+// problems.
+func (val *validator) check(def Definition, v any, path string) bool {
+	sub := &validator{root: val.root}
+	sub.validate(def, v, path)
+	return len(sub.errs) == 0
+}
+
+func (val *validator) validate(def Definition, v any, path string) { //nolint:cyclop
+	if v == nil && def.Nullable {
+		return
+	}
+
+	if def.Ref != "" {
+		ref, err := resolveRef(val.root, def.Ref)
+		if err != nil {
+			val.fail(path, "%s", err)
+			return
+		}
+		val.validate(ref, v, path)
+	}
+
+	if len(def.AnyOf) > 0 {
+		matched := false
+		for _, option := range def.AnyOf {
+			if val.check(option, v, path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			val.fail(path, "value doesn't match any of the allowed schemas")
+		}
+	}
+	if len(def.OneOf) > 0 {
+		matches := 0
+		for _, option := range def.OneOf {
+			if val.check(option, v, path) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			val.fail(path, "value matches %d of the schemas instead of exactly one", matches)
+		}
+	}
+
+	if def.Type != "" && !hasType(v, def.Type) {
+		val.fail(path, "expected %s, got %s", def.Type, typeOf(v))
+		return
+	}
+
+	if len(def.Enum) > 0 && !inEnum(v, def.Enum) {
+		val.fail(path, "value must be one of %s", strings.Join(def.Enum, ", "))
+	}
+
+	switch v := v.(type) {
+	case string:
+		val.validateString(def, v, path)
+	case float64:
+		if def.Minimum != nil && v < *def.Minimum {
+			val.fail(path, "value must be at least %v", *def.Minimum)
+		}
+		if def.Maximum != nil && v > *def.Maximum {
+			val.fail(path, "value must be at most %v", *def.Maximum)
+		}
+	case []any:
+		if def.MinItems != nil && len(v) < *def.MinItems {
+			val.fail(path, "array must have at least %d items", *def.MinItems)
+		}
+		if def.MaxItems != nil && len(v) > *def.MaxItems {
+			val.fail(path, "array must have at most %d items", *def.MaxItems)
+		}
+		if def.Items != nil {
+			for i, item := range v {
+				val.validate(*def.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case map[string]any:
+		val.validateObject(def, v, path)
+	}
+}
+
+func (val *validator) validateString(def Definition, s, path string) {
+	length := utf8.RuneCountInString(s)
+	if def.MinLength != nil && length < *def.MinLength {
+		val.fail(path, "string must have at least %d characters", *def.MinLength)
+	}
+	if def.MaxLength != nil && length > *def.MaxLength {
+		val.fail(path, "string must have at most %d characters", *def.MaxLength)
+	}
+	if def.Pattern != "" {
+		re, err := regexp.Compile(def.Pattern)
+		if err != nil {
+			val.fail(path, "invalid pattern %q in schema: %s", def.Pattern, err)
+		} else if !re.MatchString(s) {
+			val.fail(path, "string must match the pattern %q", def.Pattern)
+		}
+	}
+	if def.Format != "" && !matchesFormat(s, def.Format) {
+		val.fail(path, "string must be a valid %s", def.Format)
+	}
+}
+
+func (val *validator) validateObject(def Definition, obj map[string]any, path string) {
+	for _, name := range def.Required {
+		if _, ok := obj[name]; !ok {
+			val.fail(path, "missing required property %q", name)
+		}
+	}
+	for name, value := range obj {
+		propPath := path + "." + name
+		if prop, ok := def.Properties[name]; ok {
+			val.validate(prop, value, propPath)
+			continue
+		}
+		switch additional := def.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				val.fail(path, "unexpected property %q", name)
+			}
+		case Definition:
+			val.validate(additional, value, propPath)
+		case *Definition:
+			val.validate(*additional, value, propPath)
+		}
+	}
+}
+
+func hasType(v any, t DataType) bool {
+	switch t {
+	case Object:
+		_, ok := v.(map[string]any)
+		return ok
+	case Array:
+		_, ok := v.([]any)
+		return ok
+	case String:
+		_, ok := v.(string)
+		return ok
+	case Number:
+		_, ok := v.(float64)
+		return ok
+	case Integer:
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case Boolean:
+		_, ok := v.(bool)
+		return ok
+	case Null:
+		return v == nil
+	}
+	return true
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return string(Null)
+	case map[string]any:
+		return string(Object)
+	case []any:
+		return string(Array)
+	case string:
+		return string(String)
+	case float64:
+		return string(Number)
+	case bool:
+		return string(Boolean)
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func inEnum(v any, enum []string) bool {
+	s, ok := v.(string)
+	if !ok {
+		s = fmt.Sprint(v)
+	}
+	for _, e := range enum {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+//nolint:gochecknoglobals
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// matchesFormat reports whether the string has the format. Unknown formats
+// always match.
+func matchesFormat(s, format string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	case "time":
+		_, err := time.Parse("15:04:05Z07:00", s)
+		if err != nil {
+			_, err = time.Parse(time.TimeOnly, s)
+		}
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	case "ipv6":
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	}
+	return true
+}
+
+// resolveRef returns the schema referenced by ref in the root schema.
+func resolveRef(root Definition, ref string) (Definition, error) {
+	if ref == "#" {
+		return root, nil
+	}
+	if name, ok := strings.CutPrefix(ref, "#/$defs/"); ok {
+		if def, ok := root.Defs[name]; ok {
+			return def, nil
+		}
+	}
+	return Definition{}, fmt.Errorf("%w: %s", ErrUnresolvedRef, ref)
+}
@@ -0,0 +1,112 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	def := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"city":  {Type: jsonschema.String, MinLength: ptr(2)},
+			"unit":  {Type: jsonschema.String, Enum: []string{"celsius", "fahrenheit"}},
+			"days":  {Type: jsonschema.Integer, Minimum: ptr(1.0), Maximum: ptr(14.0), Nullable: true},
+			"since": {Type: jsonschema.String, Format: "date-time"},
+			"tags":  {Type: jsonschema.Array, Items: &jsonschema.Definition{Type: jsonschema.String}, MaxItems: ptr(2)},
+			"owner": {Ref: "#/$defs/user"},
+			"id":    {OneOf: []jsonschema.Definition{{Type: jsonschema.String}, {Type: jsonschema.Integer}}},
+		},
+		Required:             []string{"city", "unit"},
+		AdditionalProperties: false,
+		Defs: map[string]jsonschema.Definition{
+			"user": {
+				Type:       jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{"email": {Type: jsonschema.String, Format: "email"}},
+				Required:   []string{"email"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		data   string
+		errors []string
+	}{
+		{
+			name: "valid",
+			data: `{"city":"Paris","unit":"celsius","days":null,"since":"2024-01-02T15:04:05Z",` +
+				`"tags":["a"],"owner":{"email":"gopher@example.com"},"id":3}`,
+		},
+		{
+			name:   "missing required",
+			data:   `{"city":"Paris"}`,
+			errors: []string{`$: missing required property "unit"`},
+		},
+		{
+			name: "invalid values",
+			data: `{"city":"P","unit":"kelvin","days":1.5,"since":"yesterday","tags":["a","b",3],` +
+				`"owner":{"email":"nope"},"extra":true}`,
+			errors: []string{
+				"$.city: string must have at least 2 characters",
+				"$.unit: value must be one of celsius, fahrenheit",
+				"$.days: expected integer, got number",
+				"$.since: string must be a valid date-time",
+				"$.tags: array must have at most 2 items",
+				"$.tags[2]: expected string, got number",
+				"$.owner.email: string must be a valid email",
+				`$: unexpected property "extra"`,
+			},
+		},
+		{
+			name:   "not JSON",
+			data:   `{"city":`,
+			errors: []string{"$: invalid JSON: unexpected end of JSON input"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := jsonschema.Validate(def, []byte(tt.data))
+			if len(tt.errors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.ElementsMatch(t, tt.errors, validationErrors(err))
+		})
+	}
+}
+
+func validationErrors(err error) []string {
+	var res []string
+	if joined, ok := err.(interface{ Unwrap() []error }); ok { //nolint:errorlint
+		for _, err := range joined.Unwrap() {
+			res = append(res, validationErrors(err)...)
+		}
+		return res
+	}
+	var validationErr *jsonschema.ValidationError
+	if errors.As(err, &validationErr) {
+		return []string{validationErr.Error()}
+	}
+	return []string{err.Error()}
+}
+
+func TestValidateRecursive(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.For[node]()
+	require.NoError(t, err)
+
+	require.NoError(t, jsonschema.Validate(def, []byte(`{"children":[{"children":[]}]}`)))
+	require.Error(t, jsonschema.Validate(def, []byte(`{"children":[{"children":1}]}`)))
+}
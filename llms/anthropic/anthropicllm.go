@@ -10,6 +10,7 @@ import (
 	"os"
 
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
 	"github.com/IT-Tech-Company/langchaingo/llms"
 	"github.com/IT-Tech-Company/langchaingo/llms/anthropic/internal/anthropicclient"
 )
@@ -34,7 +35,12 @@ type LLM struct {
 	client           *anthropicclient.Client
 }
 
-var _ llms.Model = (*LLM)(nil)
+var (
+	_ llms.Model          = (*LLM)(nil)
+	_ llms.StreamingModel = (*LLM)(nil)
+
+	_ llms.StructuredOutputModel = (*LLM)(nil)
+)
 
 // New returns a new Anthropic LLM.
 func New(opts ...Option) (*LLM, error) {
@@ -76,21 +82,38 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 
 // GenerateContent implements the Model interface.
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := &llms.CallOptions{}
 	for _, opt := range options {
 		opt(opts)
 	}
 
+	model := opts.Model
+	if model == "" {
+		model = o.client.Model
+	}
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "anthropic", callbacks.WithRunModel(model))
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
 	if o.client.UseLegacyTextCompletionsAPI {
 		return generateCompletionsContent(ctx, o, messages, opts)
 	}
 	return generateMessagesContent(ctx, o, messages, opts)
 }
 
+// SupportsResponseSchema implements the llms.StructuredOutputModel interface.
+// Objects are generated by forcing the model to use a tool taking the object
+// as input, which the legacy text completions API doesn't support.
+func (o *LLM) SupportsResponseSchema() bool {
+	return !o.client.UseLegacyTextCompletionsAPI
+}
+
+// GenerateContentStream implements the llms.StreamingModel interface.
+func (o *LLM) GenerateContentStream(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) llms.StreamSeq {
+	return llms.StreamContentEvents(ctx, o, messages, options...)
+}
+
 func generateCompletionsContent(ctx context.Context, o *LLM, messages []llms.MessageContent, opts *llms.CallOptions) (*llms.ContentResponse, error) {
 	if len(messages) == 0 || len(messages[0].Parts) == 0 {
 		return nil, ErrEmptyResponse
@@ -110,7 +133,7 @@ func generateCompletionsContent(ctx context.Context, o *LLM, messages []llms.Mes
 		StopWords:     opts.StopWords,
 		Temperature:   opts.Temperature,
 		TopP:          opts.TopP,
-		StreamingFunc: opts.StreamingFunc,
+		StreamingFunc: completionsStreamingFunc(opts),
 	})
 	if err != nil {
 		if o.CallbacksHandler != nil {
@@ -129,23 +152,56 @@ func generateCompletionsContent(ctx context.Context, o *LLM, messages []llms.Mes
 	return resp, nil
 }
 
+// completionsStreamingFunc returns the streaming function for the legacy
+// text completions API, which reports text deltas to the StreamingEventFunc
+// too.
+func completionsStreamingFunc(opts *llms.CallOptions) func(ctx context.Context, chunk []byte) error {
+	if opts.StreamingEventFunc == nil {
+		return opts.StreamingFunc
+	}
+	return func(ctx context.Context, chunk []byte) error {
+		if opts.StreamingFunc != nil {
+			if err := opts.StreamingFunc(ctx, chunk); err != nil {
+				return err
+			}
+		}
+		return opts.StreamingEventFunc(ctx, llms.StreamEvent{Type: llms.StreamEventTextDelta, Delta: string(chunk)})
+	}
+}
+
 func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.MessageContent, opts *llms.CallOptions) (*llms.ContentResponse, error) {
 	chatMessages, systemPrompt, err := processMessages(messages)
 	if err != nil {
 		return nil, fmt.Errorf("anthropic: failed to process messages: %w", err)
 	}
 
-	tools := toolsToTools(opts.Tools)
+	tools, err := toolsToTools(opts.Tools)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: failed to convert tools: %w", err)
+	}
+	var toolChoice *anthropicclient.ToolChoice
+	if opts.ResponseSchema != nil {
+		// Anthropic has no response formats: the model is forced to call a tool
+		// whose input is the object.
+		tool, err := responseSchemaTool(opts.ResponseSchema)
+		if err != nil {
+			return nil, fmt.Errorf("anthropic: failed to convert response schema: %w", err)
+		}
+		tools = append(tools, tool)
+		toolChoice = &anthropicclient.ToolChoice{Type: "tool", Name: tool.Name}
+	}
 	result, err := o.client.CreateMessage(ctx, &anthropicclient.MessageRequest{
-		Model:         opts.Model,
-		Messages:      chatMessages,
-		System:        systemPrompt,
-		MaxTokens:     opts.MaxTokens,
-		StopWords:     opts.StopWords,
-		Temperature:   opts.Temperature,
-		TopP:          opts.TopP,
-		Tools:         tools,
-		StreamingFunc: opts.StreamingFunc,
+		Model:              opts.Model,
+		Messages:           chatMessages,
+		System:             systemPrompt,
+		MaxTokens:          opts.MaxTokens,
+		StopWords:          opts.StopWords,
+		Temperature:        opts.Temperature,
+		TopP:               opts.TopP,
+		Tools:              tools,
+		ToolChoice:         toolChoice,
+		StreamingFunc:      opts.StreamingFunc,
+		StreamingEventFunc: opts.StreamingEventFunc,
 	})
 	if err != nil {
 		if o.CallbacksHandler != nil {
@@ -179,6 +235,17 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
 				if err != nil {
 					return nil, fmt.Errorf("anthropic: failed to marshal tool use arguments: %w", err)
 				}
+				if toolChoice != nil && toolUseContent.Name == toolChoice.Name {
+					choices[i] = &llms.ContentChoice{
+						Content:    string(argumentsJSON),
+						StopReason: result.StopReason,
+						GenerationInfo: map[string]any{
+							"InputTokens":  result.Usage.InputTokens,
+							"OutputTokens": result.Usage.OutputTokens,
+						},
+					}
+					continue
+				}
 				choices[i] = &llms.ContentChoice{
 					ToolCalls: []llms.ToolCall{
 						{
@@ -205,20 +272,57 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
 
 	resp := &llms.ContentResponse{
 		Choices: choices,
+		Usage: &llms.Usage{
+			// Anthropic reports cached tokens separately from input_tokens.
+			InputTokens: result.Usage.InputTokens + result.Usage.CacheCreationInputTokens +
+				result.Usage.CacheReadInputTokens,
+			OutputTokens:      result.Usage.OutputTokens,
+			CachedInputTokens: result.Usage.CacheReadInputTokens,
+		},
 	}
 	return resp, nil
 }
 
-func toolsToTools(tools []llms.Tool) []anthropicclient.Tool {
+func toolsToTools(tools []llms.Tool) ([]anthropicclient.Tool, error) {
 	toolReq := make([]anthropicclient.Tool, len(tools))
 	for i, tool := range tools {
+		inputSchema, err := inputSchema(tool.Function.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: %w", tool.Function.Name, err)
+		}
 		toolReq[i] = anthropicclient.Tool{
 			Name:        tool.Function.Name,
 			Description: tool.Function.Description,
-			InputSchema: tool.Function.Parameters,
+			InputSchema: inputSchema,
 		}
 	}
-	return toolReq
+	return toolReq, nil
+}
+
+// responseSchemaTool returns the tool used to generate objects matching the
+// response schema.
+func responseSchemaTool(schema *llms.ResponseSchema) (anthropicclient.Tool, error) {
+	inputSchema, err := jsonschema.ToAnthropic(schema.Schema)
+	if err != nil {
+		return anthropicclient.Tool{}, err
+	}
+	return anthropicclient.Tool{
+		Name:        schema.Name,
+		Description: schema.Description,
+		InputSchema: inputSchema,
+	}, nil
+}
+
+// inputSchema converts the schemas of the parameters of a tool to the input
+// schemas supported by Anthropic.
+func inputSchema(parameters any) (any, error) {
+	switch def := parameters.(type) {
+	case jsonschema.Definition:
+		return jsonschema.ToAnthropic(def)
+	case *jsonschema.Definition:
+		return jsonschema.ToAnthropic(*def)
+	}
+	return parameters, nil
 }
 
 func processMessages(messages []llms.MessageContent) ([]anthropicclient.ChatMessage, string, error) {
@@ -249,6 +353,14 @@ func processMessages(messages []llms.MessageContent) ([]anthropicclient.ChatMess
 			if err != nil {
 				return nil, "", fmt.Errorf("anthropic: failed to handle tool message: %w", err)
 			}
+			// The results of parallel tool calls have to be sent in a single
+			// user message.
+			if n := len(chatMessages); n > 0 && isToolResultMessage(chatMessages[n-1]) {
+				last := chatMessages[n-1].Content.([]anthropicclient.Content) //nolint:forcetypeassert
+
+				chatMessages[n-1].Content = append(last, chatMessage.Content.([]anthropicclient.Content)...) //nolint:forcetypeassert
+				continue
+			}
 			chatMessages = append(chatMessages, chatMessage)
 		case llms.ChatMessageTypeGeneric, llms.ChatMessageTypeFunction:
 			return nil, "", fmt.Errorf("anthropic: %w: %v", ErrUnsupportedMessageType, msg.Role)
@@ -301,34 +413,43 @@ func handleHumanMessage(msg llms.MessageContent) (anthropicclient.ChatMessage, e
 }
 
 func handleAIMessage(msg llms.MessageContent) (anthropicclient.ChatMessage, error) {
-	if toolCall, ok := msg.Parts[0].(llms.ToolCall); ok {
-		var inputStruct map[string]interface{}
-		err := json.Unmarshal([]byte(toolCall.FunctionCall.Arguments), &inputStruct)
-		if err != nil {
-			return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: failed to unmarshal tool call arguments: %w", err)
-		}
-		toolUse := anthropicclient.ToolUseContent{
-			Type:  "tool_use",
-			ID:    toolCall.ID,
-			Name:  toolCall.FunctionCall.Name,
-			Input: inputStruct,
+	contents := make([]anthropicclient.Content, 0, len(msg.Parts))
+	for _, part := range msg.Parts {
+		switch p := part.(type) {
+		case llms.TextContent:
+			if p.Text == "" {
+				continue
+			}
+			contents = append(contents, &anthropicclient.TextContent{
+				Type: "text",
+				Text: p.Text,
+			})
+		case llms.ToolCall:
+			inputStruct := map[string]interface{}{}
+			if p.FunctionCall.Arguments != "" {
+				err := json.Unmarshal([]byte(p.FunctionCall.Arguments), &inputStruct)
+				if err != nil {
+					return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: failed to unmarshal tool call arguments: %w", err)
+				}
+			}
+			contents = append(contents, anthropicclient.ToolUseContent{
+				Type:  "tool_use",
+				ID:    p.ID,
+				Name:  p.FunctionCall.Name,
+				Input: inputStruct,
+			})
+		default:
+			return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: %w for AI message", ErrInvalidContentType)
 		}
-
-		return anthropicclient.ChatMessage{
-			Role:    RoleAssistant,
-			Content: []anthropicclient.Content{toolUse},
-		}, nil
 	}
-	if textContent, ok := msg.Parts[0].(llms.TextContent); ok {
-		return anthropicclient.ChatMessage{
-			Role: RoleAssistant,
-			Content: []anthropicclient.Content{&anthropicclient.TextContent{
-				Type: "text",
-				Text: textContent.Text,
-			}},
-		}, nil
+	if len(contents) == 0 {
+		return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: %w for AI message", ErrInvalidContentType)
 	}
-	return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: %w for AI message", ErrInvalidContentType)
+
+	return anthropicclient.ChatMessage{
+		Role:    RoleAssistant,
+		Content: contents,
+	}, nil
 }
 
 type ToolResult struct {
@@ -338,17 +459,39 @@ type ToolResult struct {
 }
 
 func handleToolMessage(msg llms.MessageContent) (anthropicclient.ChatMessage, error) {
-	if toolCallResponse, ok := msg.Parts[0].(llms.ToolCallResponse); ok {
-		toolContent := anthropicclient.ToolResultContent{
+	contents := make([]anthropicclient.Content, 0, len(msg.Parts))
+	for _, part := range msg.Parts {
+		toolCallResponse, ok := part.(llms.ToolCallResponse)
+		if !ok {
+			return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: %w for tool message", ErrInvalidContentType)
+		}
+		contents = append(contents, anthropicclient.ToolResultContent{
 			Type:      "tool_result",
 			ToolUseID: toolCallResponse.ToolCallID,
 			Content:   toolCallResponse.Content,
-		}
+		})
+	}
+	if len(contents) == 0 {
+		return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: %w for tool message", ErrInvalidContentType)
+	}
+
+	return anthropicclient.ChatMessage{
+		Role:    RoleUser,
+		Content: contents,
+	}, nil
+}
 
-		return anthropicclient.ChatMessage{
-			Role:    RoleUser,
-			Content: []anthropicclient.Content{toolContent},
-		}, nil
+// isToolResultMessage reports whether msg is a user message consisting of
+// tool results only.
+func isToolResultMessage(msg anthropicclient.ChatMessage) bool {
+	contents, ok := msg.Content.([]anthropicclient.Content)
+	if msg.Role != RoleUser || !ok || len(contents) == 0 {
+		return false
+	}
+	for _, content := range contents {
+		if _, ok := content.(anthropicclient.ToolResultContent); !ok {
+			return false
+		}
 	}
-	return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: %w for tool message", ErrInvalidContentType)
+	return true
 }
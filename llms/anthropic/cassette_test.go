@@ -0,0 +1,31 @@
+package anthropic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/httputil"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateContentCassette(t *testing.T) {
+	t.Parallel()
+
+	recorder, err := httputil.NewRecorder("testdata/messages.json", httputil.WithMode(httputil.ModeReplay))
+	require.NoError(t, err)
+	llm, err := New(WithToken("sk-ant-test"), WithModel("claude-3-5-haiku-20241022"), WithHTTPClient(recorder.Client()))
+	require.NoError(t, err)
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What is the capital of France?"),
+	}, llms.WithTemperature(0), llms.WithMaxTokens(100))
+	require.NoError(t, err)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "The capital of France is Paris.", resp.Choices[0].Content)
+	assert.Equal(t, "end_turn", resp.Choices[0].StopReason)
+	require.NotNil(t, resp.Usage)
+	assert.Equal(t, 14, resp.Usage.InputTokens)
+	assert.Equal(t, 10, resp.Usage.OutputTokens)
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 const (
@@ -131,25 +133,29 @@ type MessageRequest struct {
 	MaxTokens   int           `json:"max_tokens,omitempty"`
 	TopP        float64       `json:"top_p,omitempty"`
 	Tools       []Tool        `json:"tools,omitempty"`
+	ToolChoice  *ToolChoice   `json:"tool_choice,omitempty"`
 	StopWords   []string      `json:"stop_sequences,omitempty"`
 	Stream      bool          `json:"stream,omitempty"`
 
-	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	StreamingFunc      func(ctx context.Context, chunk []byte) error           `json:"-"`
+	StreamingEventFunc func(ctx context.Context, event llms.StreamEvent) error `json:"-"`
 }
 
 // CreateMessage creates message for the messages api.
 func (c *Client) CreateMessage(ctx context.Context, r *MessageRequest) (*MessageResponsePayload, error) {
 	resp, err := c.createMessage(ctx, &messagePayload{
-		Model:         r.Model,
-		Messages:      r.Messages,
-		System:        r.System,
-		Temperature:   r.Temperature,
-		MaxTokens:     r.MaxTokens,
-		StopWords:     r.StopWords,
-		TopP:          r.TopP,
-		Tools:         r.Tools,
-		Stream:        r.Stream,
-		StreamingFunc: r.StreamingFunc,
+		Model:              r.Model,
+		Messages:           r.Messages,
+		System:             r.System,
+		Temperature:        r.Temperature,
+		MaxTokens:          r.MaxTokens,
+		StopWords:          r.StopWords,
+		TopP:               r.TopP,
+		Tools:              r.Tools,
+		ToolChoice:         r.ToolChoice,
+		Stream:             r.Stream,
+		StreamingFunc:      r.StreamingFunc,
+		StreamingEventFunc: r.StreamingEventFunc,
 	})
 	if err != nil {
 		return nil, err
@@ -201,8 +207,14 @@ func (c *Client) decodeError(resp *http.Response) error {
 	msg := fmt.Sprintf("API returned unexpected status code: %d", resp.StatusCode)
 
 	var errResp errorMessage
-	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
-		return errors.New(msg) // nolint:goerr113
+	var err error
+	if decodeErr := json.NewDecoder(resp.Body).Decode(&errResp); decodeErr != nil {
+		err = errors.New(msg) // nolint:goerr113
+	} else {
+		err = fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
 	}
-	return fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+
+	apiErr := llms.NewError("anthropic", resp.StatusCode, err)
+	apiErr.RetryAfter = llms.ParseRetryAfter(resp.Header)
+	return apiErr
 }
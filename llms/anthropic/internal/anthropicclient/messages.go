@@ -11,6 +11,8 @@ import (
 	"log"
 	"net/http"
 	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 var (
@@ -23,6 +25,7 @@ var (
 	ErrInvalidDeltaTextField   = fmt.Errorf("invalid delta text field type")
 	ErrContentIndexOutOfRange  = fmt.Errorf("content index out of range")
 	ErrFailedCastToTextContent = fmt.Errorf("failed to cast content to TextContent")
+	ErrFailedCastToToolUse     = fmt.Errorf("failed to cast content to ToolUseContent")
 	ErrInvalidFieldType        = fmt.Errorf("invalid field type")
 )
 
@@ -40,9 +43,15 @@ type messagePayload struct {
 	Stream      bool          `json:"stream,omitempty"`
 	Temperature float64       `json:"temperature"`
 	Tools       []Tool        `json:"tools,omitempty"`
+	ToolChoice  *ToolChoice   `json:"tool_choice,omitempty"`
 	TopP        float64       `json:"top_p,omitempty"`
 
-	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	StreamingFunc      func(ctx context.Context, chunk []byte) error           `json:"-"`
+	StreamingEventFunc func(ctx context.Context, event llms.StreamEvent) error `json:"-"`
+}
+
+func (p *messagePayload) isStreaming() bool {
+	return p.StreamingFunc != nil || p.StreamingEventFunc != nil
 }
 
 // Tool used for the request message payload.
@@ -52,6 +61,14 @@ type Tool struct {
 	InputSchema any    `json:"input_schema,omitempty"`
 }
 
+// ToolChoice controls how the model uses the tools of the request.
+type ToolChoice struct {
+	// Type is "auto", "any" or "tool".
+	Type string `json:"type"`
+	// Name is the name of the tool to use when Type is "tool".
+	Name string `json:"name,omitempty"`
+}
+
 // Content can be TextContent or ToolUseContent depending on the type.
 type Content interface {
 	GetType() string
@@ -86,6 +103,9 @@ type ToolUseContent struct {
 	ID    string                 `json:"id"`
 	Name  string                 `json:"name"`
 	Input map[string]interface{} `json:"input"`
+
+	// partialInput collects the input JSON while the tool use is streamed.
+	partialInput string
 }
 
 func (tuc ToolUseContent) GetType() string {
@@ -111,8 +131,10 @@ type MessageResponsePayload struct {
 	StopSequence string    `json:"stop_sequence"`
 	Type         string    `json:"type"`
 	Usage        struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
+		InputTokens              int `json:"input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
 	} `json:"usage"`
 }
 
@@ -178,7 +200,7 @@ func (c *Client) setMessageDefaults(payload *messagePayload) {
 	default:
 		payload.Model = defaultModel
 	}
-	if payload.StreamingFunc != nil {
+	if payload.isStreaming() {
 		payload.Stream = true
 	}
 }
@@ -201,7 +223,7 @@ func (c *Client) createMessage(ctx context.Context, payload *messagePayload) (*M
 		return nil, c.decodeError(resp)
 	}
 
-	if payload.StreamingFunc != nil {
+	if payload.isStreaming() {
 		return parseStreamingMessageResponse(ctx, resp, payload)
 	}
 
@@ -272,11 +294,11 @@ func processStreamEvent(ctx context.Context, event map[string]interface{}, paylo
 	case "message_start":
 		return handleMessageStartEvent(event, response)
 	case "content_block_start":
-		return handleContentBlockStartEvent(event, response)
+		return handleContentBlockStartEvent(ctx, event, response, payload)
 	case "content_block_delta":
 		return handleContentBlockDeltaEvent(ctx, event, response, payload)
 	case "content_block_stop":
-		// Nothing to do here
+		return handleContentBlockStopEvent(ctx, event, response, payload)
 	case "message_delta":
 		return handleMessageDeltaEvent(event, response)
 	case "message_stop":
@@ -284,7 +306,8 @@ func processStreamEvent(ctx context.Context, event map[string]interface{}, paylo
 	case "ping":
 		// Nothing to do here
 	case "error":
-		eventChan <- MessageEvent{Response: nil, Err: fmt.Errorf("received error event: %v", event)}
+		err := fmt.Errorf("received error event: %v", event)
+		eventChan <- MessageEvent{Response: nil, Err: llms.NewError("anthropic", 0, err)}
 	default:
 		log.Printf("unknown event type: %s - %v", eventType, event)
 	}
@@ -312,11 +335,17 @@ func handleMessageStartEvent(event map[string]interface{}, response MessageRespo
 	response.Role = getString(message, "role")
 	response.Type = getString(message, "type")
 	response.Usage.InputTokens = int(inputTokens)
+	if cacheCreation, ok := usage["cache_creation_input_tokens"].(float64); ok {
+		response.Usage.CacheCreationInputTokens = int(cacheCreation)
+	}
+	if cacheRead, ok := usage["cache_read_input_tokens"].(float64); ok {
+		response.Usage.CacheReadInputTokens = int(cacheRead)
+	}
 
 	return response, nil
 }
 
-func handleContentBlockStartEvent(event map[string]interface{}, response MessageResponsePayload) (MessageResponsePayload, error) {
+func handleContentBlockStartEvent(ctx context.Context, event map[string]interface{}, response MessageResponsePayload, payload *messagePayload) (MessageResponsePayload, error) {
 	indexValue, ok := event["index"].(float64)
 	if !ok {
 		return response, ErrInvalidIndexField
@@ -324,15 +353,39 @@ func handleContentBlockStartEvent(event map[string]interface{}, response Message
 	index := int(indexValue)
 
 	var eventType string
-	if cb, ok := event["content_block"].(map[string]any); ok {
+	cb, _ := event["content_block"].(map[string]any)
+	if cb != nil {
 		typ, _ := cb["type"].(string)
 		eventType = typ
 	}
 
-	if len(response.Content) <= index {
+	if len(response.Content) > index {
+		return response, nil
+	}
+	if eventType != "tool_use" {
 		response.Content = append(response.Content, &TextContent{
 			Type: eventType,
 		})
+		return response, nil
+	}
+
+	toolUse := &ToolUseContent{
+		Type: eventType,
+		ID:   getString(cb, "id"),
+		Name: getString(cb, "name"),
+	}
+	response.Content = append(response.Content, toolUse)
+	if payload.StreamingEventFunc != nil {
+		err := payload.StreamingEventFunc(ctx, llms.StreamEvent{
+			Type: llms.StreamEventToolCallStart,
+			ToolCall: &llms.ToolCall{
+				ID:           toolUse.ID,
+				FunctionCall: &llms.FunctionCall{Name: toolUse.Name},
+			},
+		})
+		if err != nil {
+			return response, fmt.Errorf("streaming event func returned an error: %w", err)
+		}
 	}
 	return response, nil
 }
@@ -353,29 +406,93 @@ func handleContentBlockDeltaEvent(ctx context.Context, event map[string]interfac
 		return response, ErrInvalidDeltaTypeField
 	}
 
-	if deltaType == "text_delta" {
+	if len(response.Content) <= index {
+		return response, ErrContentIndexOutOfRange
+	}
+
+	switch deltaType {
+	case "text_delta":
 		text, ok := delta["text"].(string)
 		if !ok {
 			return response, ErrInvalidDeltaTextField
 		}
-		if len(response.Content) <= index {
-			return response, ErrContentIndexOutOfRange
-		}
 		textContent, ok := response.Content[index].(*TextContent)
 		if !ok {
 			return response, ErrFailedCastToTextContent
 		}
 		textContent.Text += text
-	}
 
-	if payload.StreamingFunc != nil {
-		text, ok := delta["text"].(string)
+		if payload.StreamingFunc != nil {
+			err := payload.StreamingFunc(ctx, []byte(text))
+			if err != nil {
+				return response, fmt.Errorf("streaming func returned an error: %w", err)
+			}
+		}
+		if payload.StreamingEventFunc != nil {
+			err := payload.StreamingEventFunc(ctx, llms.StreamEvent{Type: llms.StreamEventTextDelta, Delta: text})
+			if err != nil {
+				return response, fmt.Errorf("streaming event func returned an error: %w", err)
+			}
+		}
+	case "input_json_delta":
+		partialJSON, ok := delta["partial_json"].(string)
 		if !ok {
 			return response, ErrInvalidDeltaTextField
 		}
-		err := payload.StreamingFunc(ctx, []byte(text))
+		toolUse, ok := response.Content[index].(*ToolUseContent)
+		if !ok {
+			return response, ErrFailedCastToToolUse
+		}
+		toolUse.partialInput += partialJSON
+
+		if payload.StreamingEventFunc != nil && partialJSON != "" {
+			err := payload.StreamingEventFunc(ctx, llms.StreamEvent{
+				Type:     llms.StreamEventToolCallDelta,
+				Delta:    partialJSON,
+				ToolCall: &llms.ToolCall{ID: toolUse.ID},
+			})
+			if err != nil {
+				return response, fmt.Errorf("streaming event func returned an error: %w", err)
+			}
+		}
+	}
+	return response, nil
+}
+
+func handleContentBlockStopEvent(ctx context.Context, event map[string]interface{}, response MessageResponsePayload, payload *messagePayload) (MessageResponsePayload, error) {
+	indexValue, ok := event["index"].(float64)
+	if !ok {
+		return response, ErrInvalidIndexField
+	}
+	index := int(indexValue)
+	if len(response.Content) <= index {
+		return response, ErrContentIndexOutOfRange
+	}
+
+	toolUse, ok := response.Content[index].(*ToolUseContent)
+	if !ok {
+		return response, nil
+	}
+	toolUse.Input = map[string]interface{}{}
+	if toolUse.partialInput != "" {
+		if err := json.Unmarshal([]byte(toolUse.partialInput), &toolUse.Input); err != nil {
+			return response, fmt.Errorf("parse tool use input: %w", err)
+		}
+	}
+
+	if payload.StreamingEventFunc != nil {
+		err := payload.StreamingEventFunc(ctx, llms.StreamEvent{
+			Type: llms.StreamEventToolCallEnd,
+			ToolCall: &llms.ToolCall{
+				ID: toolUse.ID,
+				FunctionCall: &llms.FunctionCall{
+					Name:      toolUse.Name,
+					Arguments: toolUse.partialInput,
+				},
+			},
+		})
 		if err != nil {
-			return response, fmt.Errorf("streaming func returned an error: %w", err)
+			return response, fmt.Errorf("streaming event func returned an error: %w", err)
 		}
 	}
 	return response, nil
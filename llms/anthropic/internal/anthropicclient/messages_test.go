@@ -0,0 +1,92 @@
+package anthropicclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStreamingMessageResponse_ToolUse(t *testing.T) {
+	t.Parallel()
+	mockBody := `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-3-5-sonnet","usage":{"input_tokens":10,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Checking."}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"search","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"q\": "}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"go\"}"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":1}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":20}}
+
+event: message_stop
+data: {"type":"message_stop"}
+`
+	r := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(mockBody)),
+	}
+
+	var chunks []string
+	var events []llms.StreamEvent
+	payload := &messagePayload{
+		StreamingFunc: func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		},
+		StreamingEventFunc: func(_ context.Context, event llms.StreamEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	}
+
+	resp, err := parseStreamingMessageResponse(context.Background(), r, payload)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Content, 2)
+	assert.Equal(t, "Checking.", resp.Content[0].(*TextContent).Text)
+	toolUse, ok := resp.Content[1].(*ToolUseContent)
+	require.True(t, ok)
+	assert.Equal(t, "toolu_1", toolUse.ID)
+	assert.Equal(t, map[string]interface{}{"q": "go"}, toolUse.Input)
+	assert.Equal(t, "tool_use", resp.StopReason)
+
+	// Only text is passed to the streaming func.
+	assert.Equal(t, []string{"Checking."}, chunks)
+
+	types := make([]llms.StreamEventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []llms.StreamEventType{
+		llms.StreamEventTextDelta,
+		llms.StreamEventToolCallStart,
+		llms.StreamEventToolCallDelta,
+		llms.StreamEventToolCallDelta,
+		llms.StreamEventToolCallEnd,
+	}, types)
+	assert.Equal(t, "search", events[1].ToolCall.FunctionCall.Name)
+	assert.Equal(t, `{"q": "go"}`, events[4].ToolCall.FunctionCall.Arguments)
+}
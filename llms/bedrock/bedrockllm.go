@@ -9,6 +9,7 @@ import (
 	"github.com/IT-Tech-Company/langchaingo/llms/bedrock/internal/bedrockclient"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
 )
 
 const defaultModel = ModelAmazonTitanTextLiteV1
@@ -60,10 +61,6 @@ func (l *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 
 // GenerateContent implements llms.Model.
 func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
-	if l.CallbacksHandler != nil {
-		l.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := llms.CallOptions{
 		Model: l.modelID,
 	}
@@ -71,6 +68,11 @@ func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		opt(&opts)
 	}
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "bedrock", callbacks.WithRunModel(opts.Model))
+	if l.CallbacksHandler != nil {
+		l.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
 	m, err := processMessages(messages)
 	if err != nil {
 		return nil, err
@@ -78,6 +80,7 @@ func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 
 	res, err := l.client.CreateCompletion(ctx, opts.Model, m, opts)
 	if err != nil {
+		err = mapError(err)
 		if l.CallbacksHandler != nil {
 			l.CallbacksHandler.HandleLLMError(ctx, err)
 		}
@@ -91,6 +94,35 @@ func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	return res, nil
 }
 
+// mapError classifies the errors returned by the Bedrock runtime API with the
+// llms error kinds.
+func mapError(err error) error {
+	var statusCode int
+	var httpErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &httpErr) {
+		statusCode = httpErr.HTTPStatusCode()
+	}
+
+	apiErr := llms.NewError("bedrock", statusCode, err)
+	var (
+		throttling   *types.ThrottlingException
+		quota        *types.ServiceQuotaExceededException
+		notReady     *types.ModelNotReadyException
+		timeout      *types.ModelTimeoutException
+		internal     *types.InternalServerException
+		accessDenied *types.AccessDeniedException
+	)
+	switch {
+	case errors.As(err, &throttling), errors.As(err, &quota):
+		apiErr.Kind = llms.ErrRateLimited
+	case errors.As(err, &notReady), errors.As(err, &timeout), errors.As(err, &internal):
+		apiErr.Kind = llms.ErrOverloaded
+	case errors.As(err, &accessDenied):
+		apiErr.Kind = llms.ErrAuthentication
+	}
+	return apiErr
+}
+
 func processMessages(messages []llms.MessageContent) ([]bedrockclient.Message, error) {
 	bedrockMsgs := make([]bedrockclient.Message, 0, len(messages))
 
@@ -110,6 +142,24 @@ func processMessages(messages []llms.MessageContent) ([]bedrockclient.Message, e
 					MimeType: part.MIMEType,
 					Type:     "image",
 				})
+			case llms.ToolCall:
+				msg := bedrockclient.Message{
+					Role:       m.Role,
+					Type:       "tool_use",
+					ToolCallID: part.ID,
+				}
+				if part.FunctionCall != nil {
+					msg.ToolName = part.FunctionCall.Name
+					msg.Content = part.FunctionCall.Arguments
+				}
+				bedrockMsgs = append(bedrockMsgs, msg)
+			case llms.ToolCallResponse:
+				bedrockMsgs = append(bedrockMsgs, bedrockclient.Message{
+					Role:       m.Role,
+					Content:    part.Content,
+					Type:       "tool_result",
+					ToolCallID: part.ToolCallID,
+				})
 			default:
 				return nil, errors.New("unsupported message type")
 			}
@@ -3,10 +3,14 @@ package bedrockclient
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/IT-Tech-Company/langchaingo/llms"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
 // Client is a Bedrock client.
@@ -22,10 +26,15 @@ type Client struct {
 type Message struct {
 	Role    llms.ChatMessageType
 	Content string
-	// Type may be "text" or "image"
+	// Type may be "text", "image", "tool_use" or "tool_result"
 	Type string
 	// MimeType is the MIME type
 	MimeType string
+	// ToolCallID is the ID of the tool call, for "tool_use" and "tool_result"
+	// messages.
+	ToolCallID string
+	// ToolName is the name of the tool called, for "tool_use" messages.
+	ToolName string
 }
 
 func getProvider(modelID string) string {
@@ -92,3 +101,16 @@ func getMaxTokens(maxTokens, defaultValue int) int {
 	}
 	return maxTokens
 }
+
+// invocationUsage returns the token counts of a model invocation, which
+// bedrock reports in the headers of the response for the models whose output
+// doesn't have them.
+func invocationUsage(metadata middleware.Metadata) *llms.Usage {
+	resp, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response)
+	if !ok {
+		return nil
+	}
+	inputTokens, _ := strconv.Atoi(resp.Header.Get("X-Amzn-Bedrock-Input-Token-Count"))
+	outputTokens, _ := strconv.Atoi(resp.Header.Get("X-Amzn-Bedrock-Output-Token-Count"))
+	return &llms.Usage{InputTokens: inputTokens, OutputTokens: outputTokens}
+}
@@ -118,8 +118,10 @@ func createAi21Completion(ctx context.Context, client *bedrockruntime.Client, mo
 		return nil, err
 	}
 
+	usage := &llms.Usage{InputTokens: len(output.Prompt.Tokens)}
 	choices := make([]*llms.ContentChoice, len(output.Completions))
 	for i, completion := range output.Completions {
+		usage.OutputTokens += len(completion.Data.Tokens)
 		choices[i] = &llms.ContentChoice{
 			Content:    completion.Data.Text,
 			StopReason: completion.FinishReason.Reason,
@@ -131,5 +133,5 @@ func createAi21Completion(ctx context.Context, client *bedrockruntime.Client, mo
 		}
 	}
 
-	return &llms.ContentResponse{Choices: choices}, nil
+	return &llms.ContentResponse{Choices: choices, Usage: usage}, nil
 }
@@ -6,7 +6,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
 	"github.com/IT-Tech-Company/langchaingo/llms"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
@@ -32,12 +34,29 @@ type anthropicBinGenerationInputSource struct {
 // anthropicTextGenerationInputContent is a single message in the input.
 type anthropicTextGenerationInputContent struct {
 	// The type of the content. Required.
-	// One of: "text", "image"
+	// One of: "text", "image", "tool_use", "tool_result"
 	Type string `json:"type"`
 	// The source of the content. Required if type is "image"
 	Source *anthropicBinGenerationInputSource `json:"source,omitempty"`
 	// The text content. Required if type is "text"
 	Text string `json:"text,omitempty"`
+	// The ID of the tool call. Required if type is "tool_use"
+	ID string `json:"id,omitempty"`
+	// The name of the tool called. Required if type is "tool_use"
+	Name string `json:"name,omitempty"`
+	// The input of the tool call. Required if type is "tool_use"
+	Input json.RawMessage `json:"input,omitempty"`
+	// The ID of the tool call the result is for. Required if type is "tool_result"
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	// The result of the tool call. Required if type is "tool_result"
+	Content string `json:"content,omitempty"`
+}
+
+// anthropicTool is a tool the model may call.
+type anthropicTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema any    `json:"input_schema"`
 }
 
 type anthropicTextGenerationInputMessage struct {
@@ -69,6 +88,8 @@ type anthropicTextGenerationInput struct {
 	TopK int `json:"top_k,omitempty"`
 	// Sequences that will cause the model to stop generating tokens. Optional
 	StopSequences []string `json:"stop_sequences,omitempty"`
+	// The tools the model may call. Optional
+	Tools []anthropicTool `json:"tools,omitempty"`
 }
 
 // anthropicTextGenerationOutput is the generated output.
@@ -80,13 +101,16 @@ type anthropicTextGenerationOutput struct {
 	// This will always be "assistant".
 	Role string `json:"role"`
 	// This is an array of content blocks, each of which has a type that determines its shape.
-	// Currently, the only type in responses is "text".
+	// One of: "text", "tool_use"
 	Content []struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
+		Type  string          `json:"type"`
+		Text  string          `json:"text"`
+		ID    string          `json:"id"`
+		Name  string          `json:"name"`
+		Input json.RawMessage `json:"input"`
 	} `json:"content"`
 	// The reason for the completion of the generation.
-	// One of: ["end_turn", "max_tokens", "stop_sequence"]
+	// One of: ["end_turn", "max_tokens", "stop_sequence", "tool_use"]
 	StopReason string `json:"stop_reason"`
 	// Which custom stop sequence was matched, if any.
 	StopSequence string `json:"stop_sequence"`
@@ -101,6 +125,7 @@ const (
 	AnthropicCompletionReasonEndTurn      = "end_turn"
 	AnthropicCompletionReasonMaxTokens    = "max_tokens"
 	AnthropicCompletionReasonStopSequence = "stop_sequence"
+	AnthropicCompletionReasonToolUse      = "tool_use"
 )
 
 // The latest version of the model.
@@ -117,8 +142,10 @@ const (
 
 // Type attribute for the anthropic message.
 const (
-	AnthropicMessageTypeText  = "text"
-	AnthropicMessageTypeImage = "image"
+	AnthropicMessageTypeText       = "text"
+	AnthropicMessageTypeImage      = "image"
+	AnthropicMessageTypeToolUse    = "tool_use"
+	AnthropicMessageTypeToolResult = "tool_result"
 )
 
 func createAnthropicCompletion(ctx context.Context,
@@ -132,6 +159,11 @@ func createAnthropicCompletion(ctx context.Context,
 		return nil, err
 	}
 
+	tools, err := getAnthropicTools(options.Tools)
+	if err != nil {
+		return nil, err
+	}
+
 	input := anthropicTextGenerationInput{
 		AnthropicVersion: AnthropicLatestVersion,
 		MaxTokens:        getMaxTokens(options.MaxTokens, 2048),
@@ -141,6 +173,7 @@ func createAnthropicCompletion(ctx context.Context,
 		TopP:             options.TopP,
 		TopK:             options.TopK,
 		StopSequences:    options.StopWords,
+		Tools:            tools,
 	}
 
 	body, err := json.Marshal(input)
@@ -177,7 +210,8 @@ func createAnthropicCompletion(ctx context.Context,
 
 	if len(output.Content) == 0 {
 		return nil, errors.New("no results")
-	} else if stopReason := output.StopReason; stopReason != AnthropicCompletionReasonEndTurn && stopReason != AnthropicCompletionReasonStopSequence {
+	} else if stopReason := output.StopReason; stopReason != AnthropicCompletionReasonEndTurn &&
+		stopReason != AnthropicCompletionReasonStopSequence && stopReason != AnthropicCompletionReasonToolUse {
 		return nil, errors.New("completed due to " + stopReason + ". Maybe try increasing max tokens")
 	}
 	Contentchoices := make([]*llms.ContentChoice, len(output.Content))
@@ -190,18 +224,38 @@ func createAnthropicCompletion(ctx context.Context,
 				"output_tokens": output.Usage.OutputTokens,
 			},
 		}
+		if c.Type == AnthropicMessageTypeToolUse {
+			Contentchoices[i].ToolCalls = []llms.ToolCall{{
+				ID:   c.ID,
+				Type: "function",
+				FunctionCall: &llms.FunctionCall{
+					Name:      c.Name,
+					Arguments: string(c.Input),
+				},
+			}}
+		}
 	}
 	return &llms.ContentResponse{
 		Choices: Contentchoices,
+		Usage: &llms.Usage{
+			InputTokens:  output.Usage.InputTokens,
+			OutputTokens: output.Usage.OutputTokens,
+		},
 	}, nil
 }
 
 type streamingCompletionResponseChunk struct {
-	Type  string `json:"type"`
-	Index int    `json:"index"`
+	Type         string `json:"type"`
+	Index        int    `json:"index"`
+	ContentBlock struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"content_block"`
 	Delta struct {
 		Type         string `json:"type"`
 		Text         string `json:"text"`
+		PartialJSON  string `json:"partial_json"`
 		StopReason   string `json:"stop_reason"`
 		StopSequence any    `json:"stop_sequence"`
 	} `json:"delta"`
@@ -241,6 +295,7 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 	defer stream.Close()
 
 	contentchoices := []*llms.ContentChoice{{GenerationInfo: map[string]interface{}{}}}
+	usage := &llms.Usage{}
 	for e := range stream.Events() {
 		if err = stream.Err(); err != nil {
 			return nil, err
@@ -256,7 +311,22 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 			switch resp.Type {
 			case "message_start":
 				contentchoices[0].GenerationInfo["input_tokens"] = resp.Message.Usage.InputTokens
+				usage.InputTokens = resp.Message.Usage.InputTokens
+			case "content_block_start":
+				if resp.ContentBlock.Type == AnthropicMessageTypeToolUse {
+					contentchoices[0].ToolCalls = append(contentchoices[0].ToolCalls, llms.ToolCall{
+						ID:           resp.ContentBlock.ID,
+						Type:         "function",
+						FunctionCall: &llms.FunctionCall{Name: resp.ContentBlock.Name},
+					})
+				}
 			case "content_block_delta":
+				if resp.Delta.Type == "input_json_delta" {
+					if n := len(contentchoices[0].ToolCalls); n > 0 {
+						contentchoices[0].ToolCalls[n-1].FunctionCall.Arguments += resp.Delta.PartialJSON
+					}
+					continue
+				}
 				if err = options.StreamingFunc(ctx, []byte(resp.Delta.Text)); err != nil {
 					return nil, err
 				}
@@ -264,6 +334,7 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 			case "message_delta":
 				contentchoices[0].StopReason = resp.Delta.StopReason
 				contentchoices[0].GenerationInfo["output_tokens"] = resp.Usage.OutputTokens
+				usage.OutputTokens = resp.Usage.OutputTokens
 			}
 		}
 	}
@@ -273,6 +344,7 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 
 	return &llms.ContentResponse{
 		Choices: contentchoices,
+		Usage:   usage,
 	}, nil
 }
 
@@ -281,16 +353,22 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 func processInputMessagesAnthropic(messages []Message) ([]*anthropicTextGenerationInputMessage, string, error) {
 	chunkedMessages := make([][]Message, 0, len(messages))
 	currentChunk := make([]Message, 0, len(messages))
-	var lastRole llms.ChatMessageType
+	var lastRole string
 	for _, message := range messages {
-		if message.Role != lastRole {
+		// Chunk by the anthropic role, so that tool results are sent in the
+		// same user message as the text that follows them.
+		role, err := getAnthropicRole(message.Role)
+		if err != nil {
+			return nil, "", err
+		}
+		if role != lastRole {
 			if len(currentChunk) > 0 {
 				chunkedMessages = append(chunkedMessages, currentChunk)
 			}
 			currentChunk = make([]Message, 0, len(messages))
 		}
 		currentChunk = append(currentChunk, message)
-		lastRole = message.Role
+		lastRole = role
 	}
 	if len(currentChunk) > 0 {
 		chunkedMessages = append(chunkedMessages, currentChunk)
@@ -339,9 +417,9 @@ func getAnthropicRole(role llms.ChatMessageType) (string, error) {
 
 	case llms.ChatMessageTypeGeneric:
 		fallthrough
-	case llms.ChatMessageTypeHuman:
+	case llms.ChatMessageTypeHuman, llms.ChatMessageTypeTool:
 		return AnthropicRoleUser, nil
-	case llms.ChatMessageTypeFunction, llms.ChatMessageTypeTool:
+	case llms.ChatMessageTypeFunction:
 		fallthrough
 	default:
 		return "", errors.New("role not supported")
@@ -365,6 +443,52 @@ func getAnthropicInputContent(message Message) anthropicTextGenerationInputConte
 				Data:      base64.StdEncoding.EncodeToString([]byte(message.Content)),
 			},
 		}
+	case AnthropicMessageTypeToolUse:
+		input := json.RawMessage(message.Content)
+		if len(input) == 0 {
+			input = json.RawMessage("{}")
+		}
+		c = anthropicTextGenerationInputContent{
+			Type:  message.Type,
+			ID:    message.ToolCallID,
+			Name:  message.ToolName,
+			Input: input,
+		}
+	case AnthropicMessageTypeToolResult:
+		c = anthropicTextGenerationInputContent{
+			Type:      message.Type,
+			ToolUseID: message.ToolCallID,
+			Content:   message.Content,
+		}
 	}
 	return c
 }
+
+func getAnthropicTools(tools []llms.Tool) ([]anthropicTool, error) {
+	if len(tools) == 0 {
+		return nil, nil
+	}
+	out := make([]anthropicTool, 0, len(tools))
+	for _, tool := range tools {
+		if tool.Function == nil {
+			continue
+		}
+		inputSchema := tool.Function.Parameters
+		var err error
+		switch def := inputSchema.(type) {
+		case jsonschema.Definition:
+			inputSchema, err = jsonschema.ToAnthropic(def)
+		case *jsonschema.Definition:
+			inputSchema, err = jsonschema.ToAnthropic(*def)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: %w", tool.Function.Name, err)
+		}
+		out = append(out, anthropicTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: inputSchema,
+		})
+	}
+	return out, nil
+}
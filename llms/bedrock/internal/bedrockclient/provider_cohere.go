@@ -115,7 +115,9 @@ func createCohereCompletion(ctx context.Context,
 		}
 	}
 
+	// the output of the cohere models has no token counts.
 	return &llms.ContentResponse{
 		Choices: choices,
+		Usage:   invocationUsage(resp.ResultMetadata),
 	}, nil
 }
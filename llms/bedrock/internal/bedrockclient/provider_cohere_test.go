@@ -0,0 +1,49 @@
+package bedrockclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCohereCompletion(t *testing.T) {
+	t.Parallel()
+
+	var input cohereTextGenerationInput
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/model/cohere.command-text-v14/invoke", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		// bedrock reports the token counts of the cohere models in the headers.
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Amzn-Bedrock-Input-Token-Count", "12")
+		w.Header().Set("X-Amzn-Bedrock-Output-Token-Count", "4")
+		_, _ = w.Write([]byte(`{"id": "1", "generations": [
+			{"id": "2", "index": 0, "finish_reason": "COMPLETE", "text": "Tokyo."}
+		]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := bedrockruntime.New(bedrockruntime.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  aws.AnonymousCredentials{},
+	})
+	resp, err := NewClient(client).CreateCompletion(context.Background(), "cohere.command-text-v14", []Message{
+		{Role: llms.ChatMessageTypeHuman, Content: "What is the capital of Japan?", Type: "text"},
+	}, llms.CallOptions{MaxTokens: 10})
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, input.MaxTokens)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "Tokyo.", resp.Choices[0].Content)
+	assert.Equal(t, CohereCompletionReasonComplete, resp.Choices[0].StopReason)
+	assert.Equal(t, &llms.Usage{InputTokens: 12, OutputTokens: 4}, resp.Usage)
+}
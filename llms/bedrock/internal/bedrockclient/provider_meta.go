@@ -94,5 +94,9 @@ func createMetaCompletion(ctx context.Context,
 				},
 			},
 		},
+		Usage: &llms.Usage{
+			InputTokens:  output.PromptTokenCount,
+			OutputTokens: output.GenerationTokenCount,
+		},
 	}, nil
 }
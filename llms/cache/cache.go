@@ -5,33 +5,67 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 // Backend is the interface that needs to be implemented by cache backends.
 type Backend interface {
-	// Get a value from the cache. If the key is not found, return `nil`.
-	Get(ctx context.Context, key string) *llms.ContentResponse
-	// Put a value into the cache.
-	Put(ctx context.Context, key string, response *llms.ContentResponse)
+	// Get a value from the cache. If the key is not found, or if its entry has
+	// expired, return `nil` and no error.
+	Get(ctx context.Context, key string) (*llms.ContentResponse, error)
+	// Put a value into the cache. If ttl is positive the entry expires after
+	// it, otherwise it is kept as long as the backend allows.
+	Put(ctx context.Context, key string, response *llms.ContentResponse, ttl time.Duration) error
+}
+
+// PromptBackend is a Backend looking up responses from the text of the
+// prompts rather than from their exact key, such as the semantic cache. The
+// Cacher calls GetPrompt and PutPrompt instead of Get and Put for them.
+//
+// The scope is a key of the call options: responses must only be shared
+// between calls with the same scope.
+type PromptBackend interface {
+	Backend
+
+	// GetPrompt returns the response cached for a prompt matching the given
+	// one, or `nil` and no error if there is none.
+	GetPrompt(ctx context.Context, scope, prompt string) (*llms.ContentResponse, error)
+	// PutPrompt puts the response to a prompt into the cache. If ttl is
+	// positive the entry expires after it.
+	PutPrompt(ctx context.Context, scope, prompt string, response *llms.ContentResponse, ttl time.Duration) error
 }
 
 // Cacher is an LLM wrapper that caches the responses from the LLM.
 type Cacher struct {
 	llm   llms.Model
 	cache Backend
+	opts  options
 }
 
-// assert that `Cacher` implements the `llms.Model` interface.
-var _ llms.Model = (*Cacher)(nil)
+// assert that `Cacher` implements the `llms.StreamingModel` and
+// `llms.StructuredOutputModel` interfaces.
+var (
+	_ llms.StreamingModel        = (*Cacher)(nil)
+	_ llms.StructuredOutputModel = (*Cacher)(nil)
+)
 
 // New wraps a Model and adds caching capabilities using the provided
 // cache backend.
-func New(llm llms.Model, backend Backend) *Cacher {
+func New(llm llms.Model, backend Backend, opts ...Option) *Cacher {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	return &Cacher{
 		llm:   llm,
 		cache: backend,
+		opts:  o,
 	}
 }
 
@@ -49,36 +83,243 @@ func (c *Cacher) Call(ctx context.Context, prompt string, options ...llms.CallOp
 // GenerateContent asks the model to generate content from a sequence of
 // messages. It's the most general interface for multi-modal LLMs that support
 // chat-like interactions.
+//
+// Cached responses are replayed word by word to the streaming functions of
+// the call, as if the model was streaming them.
 func (c *Cacher) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
 	var opts llms.CallOptions
 	for _, opt := range options {
 		opt(&opts)
 	}
 
-	key, err := hashKeyForCache(messages, opts)
+	response, err := c.get(ctx, messages, opts)
+	if err != nil {
+		if err := c.handleError(ctx, fmt.Errorf("cache: get: %w", err)); err != nil {
+			return nil, err
+		}
+	}
+	if response != nil {
+		if err := replay(ctx, response, opts); err != nil {
+			return nil, err
+		}
+
+		return response, nil
+	}
+
+	response, err = c.llm.GenerateContent(ctx, messages, options...)
 	if err != nil {
 		return nil, err
 	}
 
-	if response := c.cache.Get(ctx, key); response != nil {
-		if opts.StreamingFunc != nil && len(response.Choices) > 0 {
-			// only stream the first choice.
-			if err := opts.StreamingFunc(ctx, []byte(response.Choices[0].Content)); err != nil {
-				return nil, err
+	if err := c.put(ctx, messages, opts, response); err != nil {
+		if err := c.handleError(ctx, fmt.Errorf("cache: put: %w", err)); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// GenerateContentStream streams the response of the model, as by
+// llms.GenerateContentStream. Cached responses are replayed word by word, and
+// the streamed responses are cached once complete.
+func (c *Cacher) GenerateContentStream(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) llms.StreamSeq { //nolint:lll
+	streamer, ok := c.llm.(llms.StreamingModel)
+	if !ok {
+		return llms.GenerateContentStream(ctx, contentModel{c}, messages, options...)
+	}
+
+	return func(yield func(llms.StreamEvent, error) bool) {
+		var opts llms.CallOptions
+		for _, opt := range options {
+			opt(&opts)
+		}
+
+		response, err := c.get(ctx, messages, opts)
+		if err != nil {
+			if err := c.handleError(ctx, fmt.Errorf("cache: get: %w", err)); err != nil {
+				yield(llms.StreamEvent{}, err)
+				return
 			}
 		}
+		if response != nil {
+			llms.StreamContentEvents(ctx, cachedModel{response}, messages, options...)(yield)
+			return
+		}
 
-		return response, nil
+		streamer.GenerateContentStream(ctx, messages, options...)(func(event llms.StreamEvent, err error) bool {
+			if err == nil && event.Type == llms.StreamEventFinish && event.Response != nil {
+				if err := c.put(ctx, messages, opts, event.Response); err != nil {
+					if err := c.handleError(ctx, fmt.Errorf("cache: put: %w", err)); err != nil {
+						return yield(llms.StreamEvent{}, err)
+					}
+				}
+			}
+			return yield(event, err)
+		})
+	}
+}
+
+// SupportsResponseSchema reports whether the model supports
+// llms.WithResponseSchema.
+func (c *Cacher) SupportsResponseSchema() bool {
+	s, ok := c.llm.(llms.StructuredOutputModel)
+	return ok && s.SupportsResponseSchema()
+}
+
+// contentModel hides the GenerateContentStream method of a Model, so that
+// llms.GenerateContentStream streams it with GenerateContent.
+type contentModel struct {
+	llms.Model
+}
+
+// cachedModel is a Model replaying a cached response.
+type cachedModel struct {
+	response *llms.ContentResponse
+}
+
+func (m cachedModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m cachedModel) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if err := replay(ctx, m.response, opts); err != nil {
+		return nil, err
 	}
+	return m.response, nil
+}
 
-	response, err := c.llm.GenerateContent(ctx, messages, options...)
+func (c *Cacher) get(ctx context.Context, messages []llms.MessageContent, opts llms.CallOptions) (*llms.ContentResponse, error) {
+	if backend, ok := c.cache.(PromptBackend); ok {
+		prompt, ok := promptText(messages)
+		if !ok {
+			return nil, nil
+		}
+		scope, err := hashKeyForCache(nil, opts)
+		if err != nil {
+			return nil, err
+		}
+		return backend.GetPrompt(ctx, scope, prompt)
+	}
+
+	key, err := hashKeyForCache(messages, opts)
 	if err != nil {
 		return nil, err
 	}
+	return c.cache.Get(ctx, key)
+}
+
+func (c *Cacher) put(ctx context.Context, messages []llms.MessageContent, opts llms.CallOptions, response *llms.ContentResponse) error { //nolint:lll
+	if backend, ok := c.cache.(PromptBackend); ok {
+		prompt, ok := promptText(messages)
+		if !ok {
+			return nil
+		}
+		scope, err := hashKeyForCache(nil, opts)
+		if err != nil {
+			return err
+		}
+		return backend.PutPrompt(ctx, scope, prompt, response, c.opts.ttl)
+	}
 
-	c.cache.Put(ctx, key, response)
+	key, err := hashKeyForCache(messages, opts)
+	if err != nil {
+		return err
+	}
+	return c.cache.Put(ctx, key, response, c.opts.ttl)
+}
 
-	return response, nil
+// handleError passes an error of the backend to the error handler, or returns
+// it if there is none.
+func (c *Cacher) handleError(ctx context.Context, err error) error {
+	if c.opts.errorHandler == nil {
+		return err
+	}
+	c.opts.errorHandler(ctx, err)
+	return nil
+}
+
+// replay sends a cached response to the streaming functions of the call.
+func replay(ctx context.Context, response *llms.ContentResponse, opts llms.CallOptions) error {
+	if len(response.Choices) == 0 {
+		return nil
+	}
+
+	// only stream the first choice.
+	choice := response.Choices[0]
+	for _, chunk := range replayChunks(choice.Content) {
+		if opts.StreamingFunc != nil {
+			if err := opts.StreamingFunc(ctx, []byte(chunk)); err != nil {
+				return err
+			}
+		}
+		if opts.StreamingEventFunc != nil {
+			event := llms.StreamEvent{Type: llms.StreamEventTextDelta, Delta: chunk}
+			if err := opts.StreamingEventFunc(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.StreamingEventFunc != nil {
+		for _, toolCall := range choice.ToolCalls {
+			if err := llms.SendToolCallEvents(ctx, opts.StreamingEventFunc, toolCall); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// replayChunks splits a cached text into chunks made of a word and the spaces
+// following it.
+func replayChunks(text string) []string {
+	var chunks []string
+	start := 0
+	inSpace := false
+	for i, r := range text {
+		space := unicode.IsSpace(r)
+		if inSpace && !space {
+			chunks = append(chunks, text[start:i])
+			start = i
+		}
+		inSpace = space
+	}
+	if start < len(text) {
+		chunks = append(chunks, text[start:])
+	}
+	return chunks
+}
+
+// promptText returns the text of the messages given to PromptBackends, with a
+// line per part prefixed by the role of its message. The tool calls and their
+// responses are in the text, so that the iterations of an agent don't match
+// each other. It returns false if a part has no text, e.g. an image, in which
+// case the call isn't cached.
+func promptText(messages []llms.MessageContent) (string, bool) {
+	var sb strings.Builder
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			switch part := part.(type) {
+			case llms.TextContent:
+				fmt.Fprintf(&sb, "%s: %s\n", message.Role, part.Text)
+			case llms.ToolCall:
+				if part.FunctionCall == nil {
+					return "", false
+				}
+				fmt.Fprintf(&sb, "%s: call %s %s\n", message.Role, part.FunctionCall.Name, part.FunctionCall.Arguments)
+			case llms.ToolCallResponse:
+				fmt.Fprintf(&sb, "%s: %s returned %s\n", message.Role, part.Name, part.Content)
+			default:
+				return "", false
+			}
+		}
+	}
+	return sb.String(), true
 }
 
 // hashKeyForCache is a helper function that generates a unique key for a given
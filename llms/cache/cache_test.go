@@ -2,9 +2,13 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/llms/fake"
 	"github.com/stretchr/testify/require"
 )
 
@@ -172,3 +176,205 @@ func TestCache_Call_Streaming(t *testing.T) {
 	rq.True(mockCache.hit)
 	rq.True(stream)
 }
+
+func TestCache_Call_StreamingChunks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	exp := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content: "hello  big\nworld",
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call_1",
+				FunctionCall: &llms.FunctionCall{Name: "search", Arguments: `{"q":"x"}`},
+			}},
+		}},
+	}
+	llm := New(newMockLLM(exp, nil), newMockCache())
+
+	_, err := llm.Call(ctx, "hello")
+	rq.NoError(err)
+
+	var chunks []string
+	var events []llms.StreamEventType
+	_, err = llm.GenerateContent(ctx,
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hello")},
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		}),
+		llms.WithStreamingEventFunc(func(_ context.Context, event llms.StreamEvent) error {
+			events = append(events, event.Type)
+			return nil
+		}),
+	)
+	rq.NoError(err)
+	rq.Equal([]string{"hello  ", "big\n", "world"}, chunks)
+	rq.Equal([]llms.StreamEventType{
+		llms.StreamEventTextDelta, llms.StreamEventTextDelta, llms.StreamEventTextDelta,
+		llms.StreamEventToolCallStart, llms.StreamEventToolCallDelta, llms.StreamEventToolCallEnd,
+	}, events)
+}
+
+type schemaModel struct {
+	*fake.ScriptedLLM
+}
+
+func (schemaModel) SupportsResponseSchema() bool { return true }
+
+func TestCache_GenerateContentStream(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	inner := fake.NewScriptedLLM(fake.TextReply("hello big world"))
+	mockCache := newMockCache()
+	llm := New(schemaModel{inner}, mockCache)
+	rq.True(llm.SupportsResponseSchema())
+	rq.False(New(newMockLLM(nil, nil), mockCache).SupportsResponseSchema())
+
+	stream := func() ([]string, *llms.ContentResponse) {
+		var chunks []string
+		var response *llms.ContentResponse
+		messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hello")}
+		llms.GenerateContentStream(ctx, llm, messages)(func(event llms.StreamEvent, err error) bool {
+			rq.NoError(err)
+			if event.Type == llms.StreamEventTextDelta {
+				chunks = append(chunks, event.Delta)
+			}
+			if event.Type == llms.StreamEventFinish {
+				response = event.Response
+			}
+			return true
+		})
+		return chunks, response
+	}
+
+	// the streamed response is cached, then replayed.
+	_, response := stream()
+	rq.Equal("hello big world", response.Choices[0].Content)
+	rq.Equal(1, mockCache.puts)
+	chunks, response := stream()
+	rq.True(mockCache.hit)
+	rq.Equal([]string{"hello ", "big ", "world"}, chunks)
+	rq.Equal("hello big world", response.Choices[0].Content)
+	rq.Len(inner.Calls(), 1)
+}
+
+func TestCache_TTL(t *testing.T) {
+	t.Parallel()
+
+	mockCache := newMockCache()
+	llm := New(newMockLLM(&llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "world"}}}, nil),
+		mockCache, WithTTL(time.Minute))
+
+	_, err := llm.Call(context.Background(), "hello")
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, mockCache.ttl)
+}
+
+func TestCache_Errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errBackend := errors.New("backend down")
+	exp := &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "world"}}}
+
+	mockCache := newMockCache()
+	mockCache.err = errBackend
+	mockLLM := newMockLLM(exp, nil)
+	_, err := New(mockLLM, mockCache).Call(ctx, "hello")
+	require.ErrorIs(t, err, errBackend)
+	require.Equal(t, 0, mockLLM.called)
+
+	var handled []error
+	llm := New(mockLLM, mockCache, WithErrorHandler(func(_ context.Context, err error) {
+		handled = append(handled, err)
+	}))
+	act, err := llm.Call(ctx, "hello")
+	require.NoError(t, err)
+	require.Equal(t, "world", act)
+	require.Equal(t, 1, mockLLM.called)
+	require.Len(t, handled, 2)
+	require.ErrorIs(t, handled[0], errBackend)
+	require.ErrorIs(t, handled[1], errBackend)
+}
+
+// promptCache is a PromptBackend matching prompts case-insensitively.
+type promptCache struct {
+	mockCache
+	prompts map[string]*llms.ContentResponse
+}
+
+func (p *promptCache) GetPrompt(_ context.Context, scope, prompt string) (*llms.ContentResponse, error) {
+	return p.prompts[scope+strings.ToLower(prompt)], nil
+}
+
+func (p *promptCache) PutPrompt(_ context.Context, scope, prompt string, response *llms.ContentResponse, _ time.Duration) error { //nolint:lll
+	p.prompts[scope+strings.ToLower(prompt)] = response
+	return nil
+}
+
+func TestCache_PromptBackend(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockLLM := newMockLLM(&llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "world"}}}, nil)
+	backend := &promptCache{mockCache: *newMockCache(), prompts: map[string]*llms.ContentResponse{}}
+	llm := New(mockLLM, backend)
+
+	_, err := llm.Call(ctx, "Hello")
+	require.NoError(t, err)
+	_, err = llm.Call(ctx, "hello")
+	require.NoError(t, err)
+	require.Equal(t, 1, mockLLM.called)
+	require.Empty(t, backend.entries, "Put shouldn't be used for prompt backends")
+
+	_, err = llm.Call(ctx, "hello", llms.WithTemperature(0.5))
+	require.NoError(t, err)
+	require.Equal(t, 2, mockLLM.called, "responses shouldn't be shared between different options")
+}
+
+func TestCache_PromptBackendToolCalls(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockLLM := newMockLLM(&llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "world"}}}, nil)
+	backend := &promptCache{mockCache: *newMockCache(), prompts: map[string]*llms.ContentResponse{}}
+	llm := New(mockLLM, backend)
+
+	// the iterations of an agent have the same text, but other tool calls.
+	step := func(result string) []llms.MessageContent {
+		return []llms.MessageContent{
+			llms.TextParts(llms.ChatMessageTypeHuman, "What is 3 to the power of 4?"),
+			{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{llms.ToolCall{
+				ID: "call_1", FunctionCall: &llms.FunctionCall{Name: "calculator", Arguments: `{"expression":"3*3"}`},
+			}}},
+			{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{llms.ToolCallResponse{
+				ToolCallID: "call_1", Name: "calculator", Content: result,
+			}}},
+		}
+	}
+	_, err := llm.GenerateContent(ctx, step("9"))
+	require.NoError(t, err)
+	_, err = llm.GenerateContent(ctx, step("81"))
+	require.NoError(t, err)
+	require.Equal(t, 2, mockLLM.called)
+	_, err = llm.GenerateContent(ctx, step("81"))
+	require.NoError(t, err)
+	require.Equal(t, 2, mockLLM.called)
+
+	// prompts with parts without text aren't cached.
+	image := []llms.MessageContent{{Role: llms.ChatMessageTypeHuman, Parts: []llms.ContentPart{
+		llms.TextContent{Text: "What is in the image?"},
+		llms.ImageURLContent{URL: "https://example.com/cat.png"},
+	}}}
+	for range 2 {
+		_, err = llm.GenerateContent(ctx, image)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 4, mockLLM.called)
+}
@@ -1,4 +1,12 @@
 // Package cache provides a generic wrapper that adds caching to a `llms.Model`. Responses are
 // cached under a key calculated based on the provided messages and options. Different cache
-// backends can be used when creating the wrapper.
+// backends can be used when creating the wrapper:
+//
+//   - inmemory keeps the responses in the memory of the process.
+//   - sqlite3 stores them in a SQLite database file, so that they survive restarts.
+//   - redis stores them in Redis, so that several processes can share them.
+//   - semantic returns the response of a similar prompt, by comparing the embeddings of the
+//     prompts.
+//
+// Cached responses are replayed word by word to the streaming functions of the calls.
 package cache
@@ -2,6 +2,7 @@ package inmemory
 
 import (
 	"context"
+	"time"
 
 	cache "github.com/Code-Hex/go-generics-cache"
 	"github.com/IT-Tech-Company/langchaingo/llms"
@@ -29,15 +30,20 @@ func New(ctx context.Context, opts ...Option) (*InMemory, error) {
 }
 
 // Get a value from the cache. If the key is not found, return `nil`.
-func (im *InMemory) Get(_ context.Context, key string) *llms.ContentResponse {
-	// errors are ignored, instead we return `nil` and pretend the key
-	// wasn't found.
+func (im *InMemory) Get(_ context.Context, key string) (*llms.ContentResponse, error) {
 	v, _ := im.cache.Get(key)
 
-	return v
+	return v, nil
 }
 
-// Put a value into the cache.
-func (im *InMemory) Put(_ context.Context, key string, value *llms.ContentResponse) {
-	im.cache.Set(key, value, im.Options.ItemOptions...)
+// Put a value into the cache. A positive ttl overrides the expiration set
+// with WithExpiration.
+func (im *InMemory) Put(_ context.Context, key string, value *llms.ContentResponse, ttl time.Duration) error {
+	itemOptions := im.Options.ItemOptions
+	if ttl > 0 {
+		itemOptions = append(itemOptions[:len(itemOptions):len(itemOptions)], cache.WithExpiration(ttl))
+	}
+	im.cache.Set(key, value, itemOptions...)
+
+	return nil
 }
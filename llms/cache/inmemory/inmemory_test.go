@@ -28,7 +28,13 @@ func TestInMemory(t *testing.T) {
 	)
 	rq.NoError(err)
 
-	rq.Nil(cache.Get(ctx, "key1"), "empty cache should be empty")
+	get := func(key string) *llms.ContentResponse {
+		v, err := cache.Get(ctx, key)
+		rq.NoError(err)
+		return v
+	}
+
+	rq.Nil(get("key1"), "empty cache should be empty")
 
 	val := &llms.ContentResponse{
 		Choices: []*llms.ContentChoice{{
@@ -36,13 +42,36 @@ func TestInMemory(t *testing.T) {
 		}},
 	}
 
-	cache.Put(ctx, "key1", val)
-	rq.Equal(val, cache.Get(ctx, "key1"))
+	rq.NoError(cache.Put(ctx, "key1", val, 0))
+	rq.Equal(val, get("key1"))
 
-	cache.Put(ctx, "key2", val)
-	rq.Nil(cache.Get(ctx, "key1"), "first value should have been evicted")
-	rq.NotNil(cache.Get(ctx, "key2"))
+	rq.NoError(cache.Put(ctx, "key2", val, 0))
+	rq.Nil(get("key1"), "first value should have been evicted")
+	rq.NotNil(get("key2"))
 
 	time.Sleep(ttl * 2) // double the ttl to make sure the value has timed out.
-	rq.Nil(cache.Get(ctx, "key2"), "second value should have been evicted")
+	rq.Nil(get("key2"), "second value should have been evicted")
+}
+
+func TestInMemory_TTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+	ttl := time.Second / 2
+
+	cache, err := New(ctx)
+	rq.NoError(err)
+
+	val := &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "value"}}}
+	rq.NoError(cache.Put(ctx, "short", val, ttl))
+	rq.NoError(cache.Put(ctx, "long", val, 0))
+
+	time.Sleep(ttl * 2)
+	v, err := cache.Get(ctx, "short")
+	rq.NoError(err)
+	rq.Nil(v, "value with a ttl should have expired")
+	v, err = cache.Get(ctx, "long")
+	rq.NoError(err)
+	rq.NotNil(v)
 }
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"time"
 
 	"github.com/IT-Tech-Company/langchaingo/llms"
 )
@@ -59,16 +60,29 @@ type mockCache struct {
 	entries map[string]*llms.ContentResponse
 	puts    int
 	hit     bool
+	ttl     time.Duration
+	err     error
 }
 
-func (m *mockCache) Get(_ context.Context, key string) *llms.ContentResponse {
+func (m *mockCache) Get(_ context.Context, key string) (*llms.ContentResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
 	v, ok := m.entries[key]
 	m.hit = ok
 
-	return v
+	return v, nil
 }
 
-func (m *mockCache) Put(_ context.Context, key string, response *llms.ContentResponse) {
+func (m *mockCache) Put(_ context.Context, key string, response *llms.ContentResponse, ttl time.Duration) error {
+	if m.err != nil {
+		return m.err
+	}
+
 	m.entries[key] = response
+	m.ttl = ttl
 	m.puts++
+
+	return nil
 }
@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Option is an option for the Cacher.
+type Option func(*options)
+
+type options struct {
+	ttl          time.Duration
+	errorHandler func(ctx context.Context, err error)
+}
+
+// WithTTL sets the time-to-live of the responses put into the cache. By
+// default they are kept as long as the backend allows.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithErrorHandler sets a function called with the errors of the backend,
+// which are otherwise returned. The errors are then ignored: a failed lookup
+// is handled as a miss and a failed put doesn't fail the call, so that the
+// model can still be used when the cache is unavailable.
+func WithErrorHandler(handler func(ctx context.Context, err error)) Option {
+	return func(o *options) {
+		o.errorHandler = handler
+	}
+}
@@ -0,0 +1,36 @@
+package redis
+
+import "github.com/redis/rueidis"
+
+// Option is an option for the Redis cache.
+type Option func(*options)
+
+type options struct {
+	client rueidis.Client
+	url    string
+	prefix string
+}
+
+// WithClient sets the client of the cache. The caller is responsible for
+// closing it.
+func WithClient(client rueidis.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithURL sets the URL of the Redis server, e.g. "redis://localhost:6379/0".
+// It is ignored if a client is given with WithClient.
+func WithURL(url string) Option {
+	return func(o *options) {
+		o.url = url
+	}
+}
+
+// WithPrefix sets the prefix of the keys of the cache, to share a database
+// with other data or between several caches.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
@@ -0,0 +1,96 @@
+// Package redis provides a `cache.Backend` storing the responses in Redis, so
+// that they can be shared by several processes.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/llms/cache"
+	"github.com/redis/rueidis"
+)
+
+// ErrMissingClient is returned by New when neither a client nor a URL is
+// given.
+var ErrMissingClient = errors.New("missing redis client or URL")
+
+// DefaultPrefix is the default prefix of the keys of the cache.
+const DefaultPrefix = "langchaingo:cache:"
+
+// Redis is a `cache.Backend` storing the responses in Redis. The entries
+// expire with the TTL of the Redis keys.
+type Redis struct {
+	client    rueidis.Client
+	ownClient bool
+	prefix    string
+}
+
+var _ cache.Backend = (*Redis)(nil)
+
+// New creates a Redis `cache.Backend` using the client given with WithClient,
+// or connecting to the URL given with WithURL.
+func New(opts ...Option) (*Redis, error) {
+	o := options{prefix: DefaultPrefix}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	r := &Redis{client: o.client, prefix: o.prefix}
+	if r.client != nil {
+		return r, nil
+	}
+	if o.url == "" {
+		return nil, ErrMissingClient
+	}
+
+	clientOption, err := rueidis.ParseURL(o.url)
+	if err != nil {
+		return nil, err
+	}
+	if r.client, err = rueidis.NewClient(clientOption); err != nil {
+		return nil, err
+	}
+	r.ownClient = true
+	return r, nil
+}
+
+// Get a value from the cache. If the key is not found, return `nil`.
+func (r *Redis) Get(ctx context.Context, key string) (*llms.ContentResponse, error) {
+	data, err := r.client.Do(ctx, r.client.B().Get().Key(r.prefix+key).Build()).AsBytes()
+	if err != nil {
+		if rueidis.IsRedisNil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var response llms.ContentResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Put a value into the cache, replacing the previous value of the key.
+func (r *Redis) Put(ctx context.Context, key string, response *llms.ContentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	set := r.client.B().Set().Key(r.prefix + key).Value(rueidis.BinaryString(data))
+	if ttl > 0 {
+		return r.client.Do(ctx, set.Px(ttl).Build()).Error()
+	}
+	return r.client.Do(ctx, set.Build()).Error()
+}
+
+// Close closes the client if it was created by New.
+func (r *Redis) Close() {
+	if r.ownClient {
+		r.client.Close()
+	}
+}
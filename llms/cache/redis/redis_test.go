@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+func getRedisURL(t *testing.T) string {
+	t.Helper()
+
+	if url := os.Getenv("REDIS_URL"); url != "" {
+		return url
+	}
+
+	ctx := context.Background()
+	container, err := tcredis.RunContainer(ctx, testcontainers.WithImage("docker.io/redis:7"))
+	if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+		t.Skip("Docker not available")
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, container.Terminate(context.Background()))
+	})
+
+	url, err := container.ConnectionString(ctx)
+	require.NoError(t, err)
+	return url
+}
+
+func TestNew_MissingClient(t *testing.T) {
+	t.Parallel()
+
+	_, err := New()
+	require.ErrorIs(t, err, ErrMissingClient)
+}
+
+func TestRedis(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	cache, err := New(WithURL(getRedisURL(t)), WithPrefix(t.Name()+":"))
+	rq.NoError(err)
+	defer cache.Close()
+
+	v, err := cache.Get(ctx, "key1")
+	rq.NoError(err)
+	rq.Nil(v, "empty cache should be empty")
+
+	val := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: "value", StopReason: "stop"}},
+	}
+	rq.NoError(cache.Put(ctx, "key1", val, 0))
+	rq.NoError(cache.Put(ctx, "key2", val, time.Second/2))
+
+	v, err = cache.Get(ctx, "key1")
+	rq.NoError(err)
+	rq.Equal(val, v)
+	v, err = cache.Get(ctx, "key2")
+	rq.NoError(err)
+	rq.Equal(val, v)
+
+	time.Sleep(time.Second)
+	v, err = cache.Get(ctx, "key2")
+	rq.NoError(err)
+	rq.Nil(v, "value with a ttl should have expired")
+}
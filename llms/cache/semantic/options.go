@@ -0,0 +1,25 @@
+package semantic
+
+// Option is an option for the semantic cache.
+type Option func(*options)
+
+type options struct {
+	threshold  float32
+	maxEntries int
+}
+
+// WithThreshold sets the minimum cosine similarity between the embeddings of
+// two prompts for them to share a response. The default is DefaultThreshold.
+func WithThreshold(threshold float32) Option {
+	return func(o *options) {
+		o.threshold = threshold
+	}
+}
+
+// WithMaxEntries sets the maximum number of entries of the cache, above which
+// the oldest ones are removed. There is no limit by default.
+func WithMaxEntries(maxEntries int) Option {
+	return func(o *options) {
+		o.maxEntries = maxEntries
+	}
+}
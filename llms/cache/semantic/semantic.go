@@ -0,0 +1,151 @@
+// Package semantic provides a `cache.Backend` returning the cached response of
+// a prompt similar to the given one, rather than only of the same prompt.
+// The similarity of two prompts is the cosine similarity of their
+// embeddings.
+package semantic
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/embeddings"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/llms/cache"
+)
+
+// ErrInvalidThreshold is returned by New for similarity thresholds outside of
+// ]0, 1].
+var ErrInvalidThreshold = errors.New("similarity threshold must be in ]0, 1]")
+
+// DefaultThreshold is the default similarity threshold of the prompts.
+const DefaultThreshold = 0.95
+
+// Semantic is an in-memory `cache.PromptBackend` matching prompts by the
+// similarity of their embeddings.
+type Semantic struct {
+	embedder   embeddings.Embedder
+	threshold  float32
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.RWMutex
+	entries []entry
+}
+
+type entry struct {
+	key       string
+	scope     string
+	vector    []float32
+	response  *llms.ContentResponse
+	expiresAt time.Time
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+var _ cache.PromptBackend = (*Semantic)(nil)
+
+// New creates a semantic cache embedding the prompts with the embedder.
+func New(embedder embeddings.Embedder, opts ...Option) (*Semantic, error) {
+	o := options{threshold: DefaultThreshold}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.threshold <= 0 || o.threshold > 1 {
+		return nil, ErrInvalidThreshold
+	}
+
+	return &Semantic{
+		embedder:   embedder,
+		threshold:  o.threshold,
+		maxEntries: o.maxEntries,
+		now:        time.Now,
+	}, nil
+}
+
+// GetPrompt returns the response of the most similar prompt of the scope, if
+// its similarity with the prompt reaches the threshold.
+func (s *Semantic) GetPrompt(ctx context.Context, scope, prompt string) (*llms.ContentResponse, error) {
+	vector, err := s.embedder.EmbedQuery(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.now()
+	var (
+		best           *llms.ContentResponse
+		bestSimilarity float32
+	)
+	for _, e := range s.entries {
+		if e.vector == nil || e.scope != scope || e.expired(now) {
+			continue
+		}
+		similarity := embeddings.CosineSimilarity(vector, e.vector)
+		if similarity >= s.threshold && similarity > bestSimilarity {
+			best, bestSimilarity = e.response, similarity
+		}
+	}
+	return best, nil
+}
+
+// PutPrompt embeds the prompt and puts its response into the cache.
+func (s *Semantic) PutPrompt(ctx context.Context, scope, prompt string, response *llms.ContentResponse, ttl time.Duration) error { //nolint:lll
+	vector, err := s.embedder.EmbedQuery(ctx, prompt)
+	if err != nil {
+		return err
+	}
+
+	s.add(entry{scope: scope, vector: vector, response: response}, ttl)
+	return nil
+}
+
+// Get a value from the cache by its exact key, when the cache isn't used
+// with the prompts. If the key is not found, return `nil`.
+func (s *Semantic) Get(_ context.Context, key string) (*llms.ContentResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.now()
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		if e := s.entries[i]; e.vector == nil && e.key == key && !e.expired(now) {
+			return e.response, nil
+		}
+	}
+	return nil, nil
+}
+
+// Put a value into the cache by its exact key.
+func (s *Semantic) Put(_ context.Context, key string, response *llms.ContentResponse, ttl time.Duration) error {
+	s.add(entry{key: key, response: response}, ttl)
+	return nil
+}
+
+// add adds an entry, removing the expired entries and the oldest ones above
+// the maximum number of entries.
+func (s *Semantic) add(e entry, ttl time.Duration) {
+	now := s.now()
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.entries[:0]
+	for _, old := range s.entries {
+		if !old.expired(now) {
+			entries = append(entries, old)
+		}
+	}
+	entries = append(entries, e)
+	if s.maxEntries > 0 && len(entries) > s.maxEntries {
+		entries = append([]entry(nil), entries[len(entries)-s.maxEntries:]...)
+	}
+	s.entries = entries
+}
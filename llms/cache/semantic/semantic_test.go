@@ -0,0 +1,142 @@
+package semantic
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/llms/cache"
+	"github.com/stretchr/testify/require"
+)
+
+// wordEmbedder embeds texts by counting the occurrences of a few words.
+type wordEmbedder struct{}
+
+func (wordEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = wordEmbedder{}.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (wordEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	words := []string{"weather", "paris", "london", "capital", "france"}
+	vector := make([]float32, len(words))
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		for i, w := range words {
+			if strings.Trim(word, "?,.") == w {
+				vector[i]++
+			}
+		}
+	}
+	return vector, nil
+}
+
+func response(content string) *llms.ContentResponse {
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: content}}}
+}
+
+func TestNew_InvalidThreshold(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(wordEmbedder{}, WithThreshold(0))
+	require.ErrorIs(t, err, ErrInvalidThreshold)
+	_, err = New(wordEmbedder{}, WithThreshold(1.5))
+	require.ErrorIs(t, err, ErrInvalidThreshold)
+}
+
+func TestSemantic(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	s, err := New(wordEmbedder{}, WithThreshold(0.9))
+	rq.NoError(err)
+
+	rq.NoError(s.PutPrompt(ctx, "scope", "What is the weather in Paris?", response("sunny"), 0))
+	rq.NoError(s.PutPrompt(ctx, "scope", "What is the capital of France?", response("Paris"), 0))
+
+	v, err := s.GetPrompt(ctx, "scope", "Weather in Paris, please")
+	rq.NoError(err)
+	rq.Equal(response("sunny"), v)
+
+	v, err = s.GetPrompt(ctx, "scope", "What is the weather in London?")
+	rq.NoError(err)
+	rq.Nil(v, "prompts below the threshold shouldn't match")
+
+	v, err = s.GetPrompt(ctx, "other", "What is the weather in Paris?")
+	rq.NoError(err)
+	rq.Nil(v, "prompts of other scopes shouldn't match")
+
+	rq.NoError(s.Put(ctx, "key", response("exact"), 0))
+	v, err = s.Get(ctx, "key")
+	rq.NoError(err)
+	rq.Equal(response("exact"), v)
+}
+
+func TestSemantic_Expiration(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	s, err := New(wordEmbedder{}, WithMaxEntries(2))
+	rq.NoError(err)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	rq.NoError(s.PutPrompt(ctx, "", "weather", response("1"), time.Minute))
+	rq.NoError(s.PutPrompt(ctx, "", "paris", response("2"), 0))
+	rq.NoError(s.PutPrompt(ctx, "", "london", response("3"), 0))
+	rq.Len(s.entries, 2, "the oldest entry should have been removed")
+
+	v, err := s.GetPrompt(ctx, "", "weather")
+	rq.NoError(err)
+	rq.Nil(v)
+
+	rq.NoError(s.PutPrompt(ctx, "", "capital", response("4"), time.Minute))
+	now = now.Add(2 * time.Minute)
+	v, err = s.GetPrompt(ctx, "", "capital")
+	rq.NoError(err)
+	rq.Nil(v, "value with an elapsed ttl should have expired")
+}
+
+type mockLLM struct {
+	calls int
+}
+
+func (m *mockLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *mockLLM) GenerateContent(_ context.Context, _ []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	m.calls++
+	return response("sunny"), nil
+}
+
+func TestSemantic_Cacher(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	s, err := New(wordEmbedder{})
+	rq.NoError(err)
+	llm := &mockLLM{}
+	cached := cache.New(llm, s)
+
+	_, err = cached.Call(ctx, "What is the weather in Paris?")
+	rq.NoError(err)
+	act, err := cached.Call(ctx, "Paris weather?")
+	rq.NoError(err)
+	rq.Equal("sunny", act)
+	rq.Equal(1, llm.calls)
+
+	_, err = cached.Call(ctx, "Paris weather?", llms.WithModel("other"))
+	rq.NoError(err)
+	rq.Equal(2, llm.calls)
+}
@@ -0,0 +1,42 @@
+package sqlite3
+
+import "database/sql"
+
+const (
+	// DefaultPath is the default path of the database file.
+	DefaultPath = "langchaingo_cache.db"
+	// DefaultTableName is the default name of the table of the cache.
+	DefaultTableName = "langchaingo_cache"
+)
+
+// Option is an option for the SQLite cache.
+type Option func(*options)
+
+type options struct {
+	db        *sql.DB
+	path      string
+	tableName string
+}
+
+// WithDB sets the database of the cache. The caller is responsible for
+// closing it.
+func WithDB(db *sql.DB) Option {
+	return func(o *options) {
+		o.db = db
+	}
+}
+
+// WithPath sets the path of the database file, or any data source name of the
+// sqlite3 driver. It is ignored if a database is given with WithDB.
+func WithPath(path string) Option {
+	return func(o *options) {
+		o.path = path
+	}
+}
+
+// WithTableName sets the name of the table of the cache.
+func WithTableName(tableName string) Option {
+	return func(o *options) {
+		o.tableName = tableName
+	}
+}
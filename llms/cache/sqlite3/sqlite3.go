@@ -0,0 +1,133 @@
+// Package sqlite3 provides a `cache.Backend` storing the responses in a SQLite
+// database, so that they survive restarts of the process.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/llms/cache"
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+)
+
+// SQLite is a `cache.Backend` storing the responses in a SQLite database.
+type SQLite struct {
+	db        *sql.DB
+	ownDB     bool
+	tableName string
+	now       func() time.Time
+}
+
+var _ cache.Backend = (*SQLite)(nil)
+
+// New creates a SQLite `cache.Backend`, creating its table if needed. The
+// database is the one given with WithDB, or the file given with WithPath.
+func New(ctx context.Context, opts ...Option) (*SQLite, error) {
+	o := options{
+		path:      DefaultPath,
+		tableName: DefaultTableName,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &SQLite{
+		db:        o.db,
+		tableName: quoteIdentifier(o.tableName),
+		now:       time.Now,
+	}
+	if s.db == nil {
+		db, err := sql.Open("sqlite3", o.path)
+		if err != nil {
+			return nil, err
+		}
+		s.db = db
+		s.ownDB = true
+	}
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(_schema, s.tableName))
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("create cache table: %w", err)
+	}
+	return s, nil
+}
+
+const _schema = `CREATE TABLE IF NOT EXISTS %s (
+	key TEXT PRIMARY KEY,
+	response BLOB NOT NULL,
+	expires_at INTEGER
+)`
+
+// quoteIdentifier quotes a table name for SQLite, escaping its quotes.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Get a value from the cache. If the key is not found or if its entry has
+// expired, return `nil`.
+func (s *SQLite) Get(ctx context.Context, key string) (*llms.ContentResponse, error) {
+	var (
+		data      []byte
+		expiresAt sql.NullInt64
+	)
+	row := s.db.QueryRowContext(ctx,
+		fmt.Sprintf("SELECT response, expires_at FROM %s WHERE key = ?", s.tableName), key)
+	if err := row.Scan(&data, &expiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if expiresAt.Valid && s.now().UnixNano() >= expiresAt.Int64 {
+		_, err := s.db.ExecContext(ctx,
+			fmt.Sprintf("DELETE FROM %s WHERE key = ? AND expires_at = ?", s.tableName), key, expiresAt.Int64)
+		return nil, err
+	}
+
+	var response llms.ContentResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Put a value into the cache, replacing the previous value of the key.
+func (s *SQLite) Put(ctx context.Context, key string, response *llms.ContentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt sql.NullInt64
+	if ttl > 0 {
+		expiresAt = sql.NullInt64{Int64: s.now().Add(ttl).UnixNano(), Valid: true}
+	}
+	_, err = s.db.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (key, response, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET response = excluded.response, expires_at = excluded.expires_at`, s.tableName),
+		key, data, expiresAt)
+	return err
+}
+
+// DeleteExpired removes the expired entries from the database. Expired entries
+// are never returned, but they are only removed when they are looked up.
+func (s *SQLite) DeleteExpired(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE expires_at <= ?", s.tableName), s.now().UnixNano())
+	return err
+}
+
+// Close closes the database if it was opened by New.
+func (s *SQLite) Close() error {
+	if !s.ownDB {
+		return nil
+	}
+	return s.db.Close()
+}
@@ -0,0 +1,104 @@
+package sqlite3
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	cache, err := New(ctx, WithPath(path))
+	rq.NoError(err)
+
+	v, err := cache.Get(ctx, "key1")
+	rq.NoError(err)
+	rq.Nil(v, "empty cache should be empty")
+
+	val := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content:    "value",
+			StopReason: "stop",
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call_1",
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "search", Arguments: `{"q":"x"}`},
+			}},
+		}},
+		Usage: &llms.Usage{InputTokens: 3, OutputTokens: 5},
+	}
+	rq.NoError(cache.Put(ctx, "key1", val, 0))
+	rq.NoError(cache.Put(ctx, "key2", &llms.ContentResponse{}, 0))
+	rq.NoError(cache.Put(ctx, "key2", val, time.Hour))
+	rq.NoError(cache.Close())
+
+	// the entries should survive reopening the database.
+	cache, err = New(ctx, WithPath(path))
+	rq.NoError(err)
+	defer cache.Close()
+
+	for _, key := range []string{"key1", "key2"} {
+		v, err = cache.Get(ctx, key)
+		rq.NoError(err)
+		rq.Equal(val, v)
+	}
+}
+
+func TestSQLite_TTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	cache, err := New(ctx, WithPath(filepath.Join(t.TempDir(), "cache.db")), WithTableName("responses"))
+	rq.NoError(err)
+	defer cache.Close()
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	val := &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "value"}}}
+	rq.NoError(cache.Put(ctx, "short", val, time.Minute))
+	rq.NoError(cache.Put(ctx, "long", val, time.Hour))
+
+	now = now.Add(2 * time.Minute)
+	v, err := cache.Get(ctx, "short")
+	rq.NoError(err)
+	rq.Nil(v, "value with an elapsed ttl should have expired")
+	v, err = cache.Get(ctx, "long")
+	rq.NoError(err)
+	rq.Equal(val, v)
+
+	now = now.Add(time.Hour)
+	rq.NoError(cache.DeleteExpired(ctx))
+	var count int
+	rq.NoError(cache.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM responses").Scan(&count))
+	rq.Zero(count)
+}
+
+func TestSQLite_TableName(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	cache, err := New(ctx, WithPath(filepath.Join(t.TempDir(), "cache.db")), WithTableName(`llm "responses"`))
+	rq.NoError(err)
+	defer cache.Close()
+
+	val := &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "value"}}}
+	rq.NoError(cache.Put(ctx, "key", val, time.Hour))
+	v, err := cache.Get(ctx, "key")
+	rq.NoError(err)
+	rq.Equal(val, v)
+	rq.NoError(cache.DeleteExpired(ctx))
+}
@@ -9,6 +9,8 @@ import (
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 // CreateEmbedding creates an embedding from the given texts.
@@ -38,7 +40,7 @@ func (c *Client) CreateEmbedding(ctx context.Context, texts *CreateEmbeddingRequ
 	}
 
 	if resp.StatusCode > 299 {
-		return nil, fmt.Errorf("error: %s", body)
+		return nil, decodeError(resp, body)
 	}
 
 	var createEmbeddingResponse CreateEmbeddingResponse
@@ -82,7 +84,7 @@ func (c *Client) GenerateContent(ctx context.Context, request *GenerateContentRe
 		}
 
 		if response.StatusCode > 299 {
-			return nil, fmt.Errorf("error: %s", body)
+			return nil, decodeError(response, body)
 		}
 
 		var generateResponse GenerateContentResponse
@@ -94,6 +96,16 @@ func (c *Client) GenerateContent(ctx context.Context, request *GenerateContentRe
 		return &generateResponse, nil
 	}
 
+	if response.StatusCode > 299 {
+		var body []byte
+
+		body, err = io.ReadAll(response.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, decodeError(response, body)
+	}
+
 	scanner := bufio.NewScanner(response.Body)
 	// increase the buffer size to avoid running out of space
 	scanBuf := make([]byte, 0, maxBufferSize)
@@ -119,18 +131,6 @@ func (c *Client) GenerateContent(ctx context.Context, request *GenerateContentRe
 			return nil, err
 		}
 
-		if response.StatusCode >= http.StatusBadRequest {
-			var body []byte
-
-			body, err = io.ReadAll(response.Body)
-			if err != nil {
-				return nil, err
-			}
-			return &GenerateContentResponse{
-				Errors: []APIError{{Message: string(body)}},
-			}, nil
-		}
-
 		if err = request.StreamingFunc(ctx, bts); err != nil {
 			return nil, err
 		}
@@ -166,7 +166,7 @@ func (c *Client) Summarize(ctx context.Context, inputText string, maxLength int)
 	}
 
 	if resp.StatusCode > 299 {
-		return nil, fmt.Errorf("error: %s", body)
+		return nil, decodeError(resp, body)
 	}
 
 	var summarizeResponse SummarizeResponse
@@ -177,3 +177,10 @@ func (c *Client) Summarize(ctx context.Context, inputText string, maxLength int)
 
 	return &summarizeResponse, nil
 }
+
+// decodeError returns the error of a failed response with the given body.
+func decodeError(resp *http.Response, body []byte) error {
+	apiErr := llms.NewError("cloudflare", resp.StatusCode, fmt.Errorf("error: %s", body)) // nolint:goerr113
+	apiErr.RetryAfter = llms.ParseRetryAfter(resp.Header)
+	return apiErr
+}
@@ -2,11 +2,15 @@ package cloudflareclient
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 type mockHTTPClient struct {
@@ -48,7 +52,9 @@ func TestClient_GenerateContent(t *testing.T) { // nolint:funlen
 				httpClient: &mockHTTPClient{
 					response: &http.Response{
 						StatusCode: http.StatusOK,
-						Body:       io.NopCloser(strings.NewReader(`{"result": {"response": "response"}}`)),
+						Body: io.NopCloser(strings.NewReader(
+							`{"result": {"response": "response", "usage": {"prompt_tokens": 12, "completion_tokens": 3, "total_tokens": 15}}}`,
+						)),
 					},
 				},
 				accountID:          "accountID",
@@ -67,10 +73,9 @@ func TestClient_GenerateContent(t *testing.T) { // nolint:funlen
 				},
 			},
 			want: &GenerateContentResponse{
-				Result: struct {
-					Response string `json:"response"`
-				}{
+				Result: GenerateContentResult{
 					Response: "response",
+					Usage:    Usage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15},
 				},
 			},
 		},
@@ -106,11 +111,7 @@ func TestClient_GenerateContent(t *testing.T) { // nolint:funlen
 				},
 			},
 			want: &GenerateContentResponse{
-				Result: struct {
-					Response string `json:"response"`
-				}{
-					Response: "",
-				},
+				Result: GenerateContentResult{Response: ""},
 			},
 		},
 	}
@@ -143,3 +144,29 @@ func TestClient_GenerateContent(t *testing.T) { // nolint:funlen
 		})
 	}
 }
+
+func TestClient_GenerateContentRateLimited(t *testing.T) {
+	t.Parallel()
+
+	for _, stream := range []bool{false, true} {
+		c := Client{httpClient: &mockHTTPClient{
+			response: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"3"}},
+				Body:       io.NopCloser(strings.NewReader(`{"errors": [{"message": "Capacity temporarily exceeded"}]}`)),
+			},
+		}}
+
+		request := &GenerateContentRequest{Stream: stream}
+		if stream {
+			request.StreamingFunc = func(context.Context, []byte) error { return nil }
+		}
+		_, err := c.GenerateContent(context.Background(), request)
+		if !errors.Is(err, llms.ErrRateLimited) {
+			t.Errorf("GenerateContent() stream = %v, error = %v, want %v", stream, err, llms.ErrRateLimited)
+		}
+		if got := llms.RetryAfter(err); got != 3*time.Second {
+			t.Errorf("GenerateContent() stream = %v, retry after = %v, want %v", stream, got, 3*time.Second)
+		}
+	}
+}
@@ -17,12 +17,23 @@ type Message struct {
 }
 
 type GenerateContentResponse struct {
-	Errors   []APIError `json:"errors"`
-	Messages []string   `json:"messages"`
-	Result   struct {
-		Response string `json:"response"`
-	} `json:"result"`
-	Success bool `json:"success"`
+	Errors   []APIError            `json:"errors"`
+	Messages []string              `json:"messages"`
+	Result   GenerateContentResult `json:"result"`
+	Success  bool                  `json:"success"`
+}
+
+type GenerateContentResult struct {
+	Response string `json:"response"`
+	// Usage is the token usage of the request. Models that don't report
+	// usage leave it empty.
+	Usage Usage `json:"usage"`
+}
+
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
 }
 
 type StreamingResponse struct {
@@ -31,6 +31,7 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 // GenerateContent implements the Model interface.
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint: lll, cyclop, whitespace
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "cohere")
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
@@ -60,6 +61,12 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 			},
 		},
 	}
+	if result.InputTokens > 0 || result.OutputTokens > 0 {
+		resp.Usage = &llms.Usage{
+			InputTokens:  result.InputTokens,
+			OutputTokens: result.OutputTokens,
+		}
+	}
 	return resp, nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/IT-Tech-Company/langchaingo/llms"
 	"github.com/cohere-ai/tokenizer"
 )
 
@@ -70,6 +71,9 @@ type GenerationRequest struct {
 
 type Generation struct {
 	Text string `json:"text"`
+	// InputTokens and OutputTokens are the tokens billed for the generation.
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
 }
 
 type generateRequestPayload struct {
@@ -84,6 +88,12 @@ type generateResponsePayload struct {
 		ID   string `json:"id,omitempty"`
 		Text string `json:"text,omitempty"`
 	} `json:"generations,omitempty"`
+	Meta struct {
+		BilledUnits struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"billed_units"`
+	} `json:"meta"`
 }
 
 func (c *Client) CreateGeneration(ctx context.Context, r *GenerationRequest) (*Generation, error) {
@@ -120,6 +130,10 @@ func (c *Client) CreateGeneration(ctx context.Context, r *GenerationRequest) (*G
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, decodeError(res)
+	}
+
 	var response generateResponsePayload
 	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
 		return nil, fmt.Errorf("parse response: %w", err)
@@ -134,6 +148,28 @@ func (c *Client) CreateGeneration(ctx context.Context, r *GenerationRequest) (*G
 
 	var generation Generation
 	generation.Text = response.Generations[0].Text
+	generation.InputTokens = response.Meta.BilledUnits.InputTokens
+	generation.OutputTokens = response.Meta.BilledUnits.OutputTokens
 
 	return &generation, nil
 }
+
+// decodeError returns the error of a failed response.
+func decodeError(res *http.Response) error {
+	msg := fmt.Sprintf("API returned unexpected status code: %d", res.StatusCode)
+
+	var response generateResponsePayload
+	var err error
+	switch decodeErr := json.NewDecoder(res.Body).Decode(&response); {
+	case decodeErr != nil || response.Message == "":
+		err = errors.New(msg) // nolint:goerr113
+	case strings.HasPrefix(response.Message, "model not found"):
+		err = fmt.Errorf("%w: %s", ErrModelNotFound, response.Message)
+	default:
+		err = fmt.Errorf("%s: %s", msg, response.Message) // nolint:goerr113
+	}
+
+	apiErr := llms.NewError("cohere", res.StatusCode, err)
+	apiErr.RetryAfter = llms.ParseRetryAfter(res.Header)
+	return apiErr
+}
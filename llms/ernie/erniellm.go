@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
@@ -66,6 +67,7 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 // GenerateContent implements the Model interface.
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint: lll, cyclop, whitespace
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "ernie")
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
@@ -93,8 +95,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		return nil, err
 	}
 	if result.ErrorCode > 0 {
-		err = fmt.Errorf("%w, error_code:%v, erro_msg:%v, id:%v",
-			ErrCodeResponse, result.ErrorCode, result.ErrorMsg, result.ID)
+		err = codeError(result.ErrorCode, result.ErrorMsg, result.ID)
 		if o.CallbacksHandler != nil {
 			o.CallbacksHandler.HandleLLMError(ctx, err)
 		}
@@ -107,6 +108,10 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 				Content: result.Result,
 			},
 		},
+		Usage: &llms.Usage{
+			InputTokens:  result.Usage.PromptTokens,
+			OutputTokens: result.Usage.CompletionTokens,
+		},
 	}
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
@@ -126,8 +131,7 @@ func (o *LLM) CreateEmbedding(ctx context.Context, texts []string) ([][]float32,
 	}
 
 	if resp.ErrorCode > 0 {
-		return nil, fmt.Errorf("%w, error_code:%v, erro_msg:%v, id:%v",
-			ErrCodeResponse, resp.ErrorCode, resp.ErrorMsg, resp.ID)
+		return nil, codeError(resp.ErrorCode, resp.ErrorMsg, resp.ID)
 	}
 
 	emb := make([][]float32, 0, len(texts))
@@ -169,3 +173,20 @@ func modelToPath(model ModelName) ernieclient.ModelPath {
 		return ernieclient.DefaultCompletionModelPath
 	}
 }
+
+// codeError returns the error of a response with the given error code. The
+// API reports errors with a 200 status, so the codes of rate limit, overload
+// and authentication errors are mapped to the matching HTTP status codes.
+func codeError(code int, msg, id string) error {
+	var statusCode int
+	switch code {
+	case 4, 17, 18, 19, 336501, 336502, 336503:
+		statusCode = http.StatusTooManyRequests
+	case 2, 336100:
+		statusCode = http.StatusServiceUnavailable
+	case 6, 110, 111:
+		statusCode = http.StatusUnauthorized
+	}
+	err := fmt.Errorf("%w, error_code:%v, erro_msg:%v, id:%v", ErrCodeResponse, code, msg, id)
+	return llms.NewError("ernie", statusCode, err)
+}
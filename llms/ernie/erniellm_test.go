@@ -0,0 +1,32 @@
+package ernie
+
+import (
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code int
+		kind error
+	}{
+		{18, llms.ErrRateLimited},
+		{336501, llms.ErrRateLimited},
+		{336100, llms.ErrOverloaded},
+		{111, llms.ErrAuthentication},
+		{100, nil},
+	}
+	for _, tc := range tests {
+		err := codeError(tc.code, "message", "id")
+		require.ErrorIs(t, err, ErrCodeResponse)
+
+		var apiErr *llms.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, tc.kind, apiErr.Kind, "code %d", tc.code)
+	}
+}
@@ -176,11 +176,14 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatRes
 		// No need to check the error here: if it fails, we'll just return the
 		// status code.
 		var errResp errorMessage
-		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
+		err := errors.New(msg) // nolint:goerr113
+		if decodeErr := json.NewDecoder(r.Body).Decode(&errResp); decodeErr == nil {
+			err = fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
 		}
 
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		apiErr := llms.NewError("ernie", r.StatusCode, err)
+		apiErr.RetryAfter = llms.ParseRetryAfter(r.Header)
+		return nil, apiErr
 	}
 	if payload.StreamingFunc != nil {
 		return parseStreamingChatResponse(ctx, r, payload)
@@ -12,6 +12,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 var (
@@ -213,7 +215,7 @@ func (c *Client) CreateCompletion(ctx context.Context, modelPath ModelPath, r *C
 
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: %d", ErrCompletionCode, resp.StatusCode)
+		return nil, statusError(resp, ErrCompletionCode)
 	}
 
 	if r.Stream {
@@ -252,7 +254,7 @@ func (c *Client) CreateEmbedding(ctx context.Context, texts []string) (*Embeddin
 
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: %d", ErrEmbeddingCode, resp.StatusCode)
+		return nil, statusError(resp, ErrEmbeddingCode)
 	}
 
 	var response EmbeddingResponse
@@ -277,7 +279,7 @@ func (c *Client) getAccessToken(ctx context.Context) (*authResponse, error) {
 
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: %d", ErrAccessTokenCode, resp.StatusCode)
+		return nil, statusError(resp, ErrAccessTokenCode)
 	}
 
 	var response authResponse
@@ -367,3 +369,10 @@ func (c *Client) buildURL(modelpath ModelPath) string {
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
 }
+
+// statusError returns the error of a response with an unexpected status code.
+func statusError(resp *http.Response, err error) error {
+	apiErr := llms.NewError("ernie", resp.StatusCode, fmt.Errorf("%w: %d", err, resp.StatusCode))
+	apiErr.RetryAfter = llms.ParseRetryAfter(resp.Header)
+	return apiErr
+}
@@ -0,0 +1,204 @@
+package llms
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors classifying why a model call failed. Backends wrap the errors they
+// return in an [Error] carrying one of these, so callers can make decisions
+// with errors.Is regardless of the provider in use.
+var (
+	// ErrRateLimited is returned when the provider rejected the request
+	// because a rate limit or quota was exceeded.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrOverloaded is returned when the provider is temporarily unable to
+	// serve the request, e.g. because it is overloaded or unavailable.
+	ErrOverloaded = errors.New("model overloaded")
+	// ErrContextLengthExceeded is returned when the request doesn't fit into
+	// the context window of the model.
+	ErrContextLengthExceeded = errors.New("context length exceeded")
+	// ErrAuthentication is returned when the credentials are missing or
+	// invalid, or lack permissions for the request.
+	ErrAuthentication = errors.New("authentication failed")
+	// ErrContentFiltered is returned when the provider refused the request or
+	// the response because of its content policy.
+	ErrContentFiltered = errors.New("content filtered")
+)
+
+// Error is an error returned by a model provider, annotated with its
+// classification.
+type Error struct {
+	// Provider is the name of the provider that returned the error, e.g.
+	// "openai".
+	Provider string
+	// StatusCode is the HTTP status code of the response, if any.
+	StatusCode int
+	// Kind is one of the classification errors above (e.g. ErrRateLimited),
+	// or nil if the error couldn't be classified.
+	Kind error
+	// RetryAfter is how long the provider asked to wait before retrying, if
+	// it said so.
+	RetryAfter time.Duration
+	// Err is the underlying error.
+	Err error
+}
+
+// NewError creates an Error for the given provider, classifying err by the
+// HTTP status code and the error message.
+func NewError(provider string, statusCode int, err error) *Error {
+	return &Error{
+		Provider:   provider,
+		StatusCode: statusCode,
+		Kind:       classifyError(statusCode, err),
+		Err:        err,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		if e.Kind == nil {
+			return e.Provider + ": unknown error"
+		}
+		return e.Provider + ": " + e.Kind.Error()
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns both the classification and the underlying error, so that
+// errors.Is and errors.As match either of them.
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// IsRetryable reports whether err is a transient provider error, i.e. the
+// same request may succeed if it is retried later.
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrOverloaded)
+}
+
+// RetryAfter returns how long the provider asked to wait before retrying
+// the request that failed with err, or zero if it didn't say.
+func RetryAfter(err error) time.Duration {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.RetryAfter
+	}
+	return 0
+}
+
+// ParseRetryAfter parses the Retry-After (or retry-after-ms) header of an
+// HTTP response. It returns zero if the header is absent or malformed.
+func ParseRetryAfter(header http.Header) time.Duration {
+	if ms := header.Get("Retry-After-Ms"); ms != "" {
+		if v, err := strconv.ParseFloat(ms, 64); err == nil && v > 0 {
+			return time.Duration(v * float64(time.Millisecond))
+		}
+	}
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+var (
+	contextLengthMessages = []string{
+		"context_length_exceeded",
+		"context length",
+		"context window",
+		"maximum context",
+		"prompt is too long",
+		"input is too long",
+		"too many tokens",
+		"reduce the length",
+	}
+	contentFilterMessages = []string{
+		"content_filter",
+		"content management policy",
+		"content_policy_violation",
+		"responsibleaipolicyviolation",
+	}
+	rateLimitMessages = []string{
+		"rate limit",
+		"rate_limit",
+		"too many requests",
+		"quota",
+	}
+	overloadedMessages = []string{
+		"overloaded",
+		"temporarily unavailable",
+		"server is busy",
+	}
+)
+
+func classifyError(statusCode int, err error) error {
+	var msg string
+	if err != nil {
+		msg = strings.ToLower(err.Error())
+	}
+
+	// The message is more specific than the status code: providers use 400
+	// for both context length and content filter errors, and some return
+	// 200 or 500 with a rate limit message.
+	switch {
+	case containsAny(msg, contextLengthMessages):
+		return ErrContextLengthExceeded
+	case containsAny(msg, contentFilterMessages):
+		return ErrContentFiltered
+	}
+
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrAuthentication
+	case http.StatusRequestEntityTooLarge:
+		return ErrContextLengthExceeded
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout, statusOverloaded:
+		return ErrOverloaded
+	}
+
+	switch {
+	case containsAny(msg, rateLimitMessages):
+		return ErrRateLimited
+	case containsAny(msg, overloadedMessages):
+		return ErrOverloaded
+	}
+	return nil
+}
+
+// statusOverloaded is the non-standard status code Anthropic uses for
+// overloaded errors.
+const statusOverloaded = 529
+
+func containsAny(s string, substrs []string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package llms
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		statusCode int
+		message    string
+		want       error
+	}{
+		{"rate limit status", http.StatusTooManyRequests, "slow down", ErrRateLimited},
+		{"rate limit message", 0, "Rate limit reached for requests", ErrRateLimited},
+		{"unauthorized", http.StatusUnauthorized, "invalid api key", ErrAuthentication},
+		{"overloaded status", 529, "overloaded_error", ErrOverloaded},
+		{"unavailable", http.StatusServiceUnavailable, "try again", ErrOverloaded},
+		{"context length", http.StatusBadRequest, "This model's maximum context length is 8192 tokens", ErrContextLengthExceeded},
+		{"content filter", http.StatusBadRequest, "The response was filtered due to content_filter", ErrContentFiltered},
+		{"unclassified", http.StatusBadRequest, "invalid request", nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			underlying := errors.New(tt.message)
+			err := fmt.Errorf("wrapped: %w", NewError("test", tt.statusCode, underlying))
+
+			assert.ErrorIs(t, err, underlying)
+			if tt.want == nil {
+				for _, kind := range []error{ErrRateLimited, ErrOverloaded, ErrContextLengthExceeded, ErrAuthentication, ErrContentFiltered} {
+					assert.NotErrorIs(t, err, kind)
+				}
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+			assert.Equal(t, tt.want == ErrRateLimited || tt.want == ErrOverloaded, IsRetryable(err))
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, time.Duration(0), ParseRetryAfter(http.Header{}))
+	assert.Equal(t, 2*time.Second, ParseRetryAfter(http.Header{"Retry-After": {"2"}}))
+	assert.Equal(t, 1500*time.Millisecond, ParseRetryAfter(http.Header{"Retry-After-Ms": {"1500"}, "Retry-After": {"2"}}))
+	assert.Equal(t, time.Duration(0), ParseRetryAfter(http.Header{"Retry-After": {"soon"}}))
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	d := ParseRetryAfter(http.Header{"Retry-After": {date}})
+	assert.Greater(t, d, 50*time.Second)
+	assert.LessOrEqual(t, d, time.Minute)
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	err := NewError("test", http.StatusTooManyRequests, errors.New("slow down"))
+	err.RetryAfter = time.Second
+	assert.Equal(t, time.Second, RetryAfter(fmt.Errorf("wrapped: %w", err)))
+	assert.Equal(t, time.Duration(0), RetryAfter(errors.New("other")))
+}
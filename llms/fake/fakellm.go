@@ -10,6 +10,7 @@ import (
 type LLM struct {
 	responses []string
 	index     int
+	usage     *llms.Usage
 }
 
 func NewFakeLLM(responses []string) *LLM {
@@ -29,9 +30,14 @@ func (f *LLM) GenerateContent(_ context.Context, _ []llms.MessageContent, _ ...l
 	}
 	response := f.responses[f.index]
 	f.index++
-	return &llms.ContentResponse{
+	resp := &llms.ContentResponse{
 		Choices: []*llms.ContentChoice{{Content: response}},
-	}, nil
+	}
+	if f.usage != nil {
+		usage := *f.usage
+		resp.Usage = &usage
+	}
+	return resp, nil
 }
 
 // Call  the model with a prompt.
@@ -55,3 +61,8 @@ func (f *LLM) Reset() {
 func (f *LLM) AddResponse(response string) {
 	f.responses = append(f.responses, response)
 }
+
+// SetUsage sets the token usage reported with every response.
+func (f *LLM) SetUsage(usage llms.Usage) {
+	f.usage = &usage
+}
@@ -106,6 +106,31 @@ func TestFakeLLM_AddResponseMethod(t *testing.T) {
 	}
 }
 
+func TestFakeLLM_SetUsageMethod(t *testing.T) {
+	t.Parallel()
+	fakeLLM := NewFakeLLM(setupResponses())
+	ctx := context.Background()
+	msg := llms.TextParts(llms.ChatMessageTypeHuman, "Teste")
+
+	resp, err := fakeLLM.GenerateContent(ctx, []llms.MessageContent{msg})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if resp.Usage != nil {
+		t.Errorf("Expected no usage, got %+v", resp.Usage)
+	}
+
+	usage := llms.Usage{InputTokens: 10, OutputTokens: 5, CachedInputTokens: 2, ReasoningTokens: 1}
+	fakeLLM.SetUsage(usage)
+	resp, err = fakeLLM.GenerateContent(ctx, []llms.MessageContent{msg})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if resp.Usage == nil || *resp.Usage != usage {
+		t.Errorf("Expected usage %+v, got %+v", usage, resp.Usage)
+	}
+}
+
 func TestFakeLLM_WithChain(t *testing.T) {
 	t.Parallel()
 	responses := setupResponses()
@@ -0,0 +1,347 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+)
+
+// ErrNoReply is returned by a ScriptedLLM called when no rule matches the call
+// and its queue of replies is empty.
+var ErrNoReply = errors.New("fake: no scripted reply for the call")
+
+// Reply is a scripted reply of a ScriptedLLM: the response it returns, or the
+// error it fails with.
+type Reply struct {
+	Response *llms.ContentResponse
+	Err      error
+}
+
+// TextReply returns a reply with a single choice of text content.
+func TextReply(text string) Reply {
+	return Reply{Response: &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: text, StopReason: "stop"}},
+	}}
+}
+
+// ToolCallReply returns a reply with a single choice asking to call tools,
+// like the ones of models with tool calling. The first call is also the
+// FuncCall of the choice.
+func ToolCallReply(toolCalls ...llms.ToolCall) Reply {
+	choice := &llms.ContentChoice{ToolCalls: toolCalls, StopReason: "tool_calls"}
+	if len(toolCalls) > 0 {
+		choice.FuncCall = toolCalls[0].FunctionCall
+	}
+	return Reply{Response: &llms.ContentResponse{Choices: []*llms.ContentChoice{choice}}}
+}
+
+// ToolCall returns a call of a function tool with the JSON arguments.
+func ToolCall(id, name, arguments string) llms.ToolCall {
+	return llms.ToolCall{
+		ID:           id,
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: name, Arguments: arguments},
+	}
+}
+
+// ErrorReply returns a reply failing with err.
+func ErrorReply(err error) Reply {
+	return Reply{Err: err}
+}
+
+// Matcher decides whether a rule of a ScriptedLLM applies to a call.
+type Matcher func(messages []llms.MessageContent, opts llms.CallOptions) bool
+
+// LastMessageContains matches calls whose last message has a text part
+// containing substr.
+func LastMessageContains(substr string) Matcher {
+	return func(messages []llms.MessageContent, _ llms.CallOptions) bool {
+		if len(messages) == 0 {
+			return false
+		}
+		for _, part := range messages[len(messages)-1].Parts {
+			if text, ok := part.(llms.TextContent); ok && strings.Contains(text.Text, substr) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// LastMessageRole matches calls whose last message has the role, e.g.
+// llms.ChatMessageTypeTool for calls following the results of tools.
+func LastMessageRole(role llms.ChatMessageType) Matcher {
+	return func(messages []llms.MessageContent, _ llms.CallOptions) bool {
+		return len(messages) > 0 && messages[len(messages)-1].Role == role
+	}
+}
+
+// HasTool matches calls offering the tool or function with the name.
+func HasTool(name string) Matcher {
+	return func(_ []llms.MessageContent, opts llms.CallOptions) bool {
+		for _, tool := range opts.Tools {
+			if tool.Function != nil && tool.Function.Name == name {
+				return true
+			}
+		}
+		for _, function := range opts.Functions {
+			if function.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Call is a call received by a ScriptedLLM.
+type Call struct {
+	Messages []llms.MessageContent
+	Options  llms.CallOptions
+}
+
+type rule struct {
+	match Matcher
+	reply Reply
+}
+
+// ScriptedLLM is a fake model whose replies are scripted, to test agents and
+// chains without network. Each call gets the reply of the first rule matching
+// it, or else the next reply of its queue:
+//
+//	llm := fake.NewScriptedLLM(
+//		fake.ToolCallReply(fake.ToolCall("call_1", "calculator", `{"__arg1":"3*3*3*3"}`)),
+//		fake.TextReply("3 to the power of 4 is 81."),
+//	)
+//	llm.On(fake.LastMessageContains("weather"), fake.ErrorReply(errUnavailable))
+//
+// With streaming options, the text and the reasoning of the replies are sent
+// in chunks of one word to StreamingFunc and StreamingReasoningFunc, and with
+// their tool calls as events to StreamingEventFunc.
+//
+// The calls are recorded for assertions. A ScriptedLLM is safe for concurrent
+// use.
+type ScriptedLLM struct {
+	// CallbacksHandler gets the events of the calls, like the handlers of the
+	// other models.
+	CallbacksHandler callbacks.Handler
+
+	mu      sync.Mutex
+	replies []Reply
+	rules   []rule
+	calls   []Call
+}
+
+var (
+	_ llms.Model          = (*ScriptedLLM)(nil)
+	_ llms.StreamingModel = (*ScriptedLLM)(nil)
+)
+
+// NewScriptedLLM creates a ScriptedLLM with a queue of replies.
+func NewScriptedLLM(replies ...Reply) *ScriptedLLM {
+	return &ScriptedLLM{replies: replies}
+}
+
+// Push appends replies to the queue.
+func (f *ScriptedLLM) Push(replies ...Reply) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.replies = append(f.replies, replies...)
+}
+
+// On adds a rule replying to the calls matching it. Rules are checked in the
+// order they were added, before the queue, and apply to any number of calls.
+func (f *ScriptedLLM) On(match Matcher, reply Reply) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = append(f.rules, rule{match: match, reply: reply})
+}
+
+// Calls returns the calls received by the model, in order.
+func (f *ScriptedLLM) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// LastCall returns the last call received by the model.
+func (f *ScriptedLLM) LastCall() (Call, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.calls) == 0 {
+		return Call{}, false
+	}
+	return f.calls[len(f.calls)-1], true
+}
+
+// Remaining returns the number of replies left in the queue.
+func (f *ScriptedLLM) Remaining() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.replies)
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (f *ScriptedLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+// GenerateContent records the call and returns its scripted reply.
+func (f *ScriptedLLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "fake")
+	if f.CallbacksHandler != nil {
+		f.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+	response, err := f.generateContent(ctx, messages, options...)
+	if err != nil {
+		if f.CallbacksHandler != nil {
+			f.CallbacksHandler.HandleLLMError(ctx, err)
+		}
+		return nil, err
+	}
+	if f.CallbacksHandler != nil {
+		f.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
+	}
+	return response, nil
+}
+
+func (f *ScriptedLLM) generateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	reply, err := f.next(messages, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if reply.Err != nil {
+		return nil, reply.Err
+	}
+	response := cloneResponse(reply.Response)
+	if err := stream(ctx, response, opts); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GenerateContentStream implements the llms.StreamingModel interface.
+func (f *ScriptedLLM) GenerateContentStream(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) llms.StreamSeq { //nolint:lll
+	return llms.StreamContentEvents(ctx, f, messages, options...)
+}
+
+// next records a call and returns its reply.
+func (f *ScriptedLLM) next(messages []llms.MessageContent, opts llms.CallOptions) (Reply, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{Messages: messages, Options: opts})
+	for _, r := range f.rules {
+		if r.match(messages, opts) {
+			return r.reply, nil
+		}
+	}
+	if len(f.replies) == 0 {
+		return Reply{}, fmt.Errorf("%w (call %d)", ErrNoReply, len(f.calls))
+	}
+	reply := f.replies[0]
+	f.replies = f.replies[1:]
+	return reply, nil
+}
+
+// stream sends the response to the streaming functions of the options.
+func stream(ctx context.Context, response *llms.ContentResponse, opts llms.CallOptions) error {
+	if response == nil {
+		return nil
+	}
+	for _, choice := range response.Choices {
+		for _, chunk := range chunks(choice.ReasoningContent) {
+			if opts.StreamingReasoningFunc != nil {
+				if err := opts.StreamingReasoningFunc(ctx, []byte(chunk), nil); err != nil {
+					return err
+				}
+			}
+			if opts.StreamingEventFunc != nil {
+				event := llms.StreamEvent{Type: llms.StreamEventReasoningDelta, Delta: chunk}
+				if err := opts.StreamingEventFunc(ctx, event); err != nil {
+					return err
+				}
+			}
+		}
+		for _, chunk := range chunks(choice.Content) {
+			if opts.StreamingFunc != nil {
+				if err := opts.StreamingFunc(ctx, []byte(chunk)); err != nil {
+					return err
+				}
+			}
+			if opts.StreamingEventFunc != nil {
+				event := llms.StreamEvent{Type: llms.StreamEventTextDelta, Delta: chunk}
+				if err := opts.StreamingEventFunc(ctx, event); err != nil {
+					return err
+				}
+			}
+		}
+		if opts.StreamingEventFunc == nil {
+			continue
+		}
+		for _, toolCall := range choice.ToolCalls {
+			if err := llms.SendToolCallEvents(ctx, opts.StreamingEventFunc, toolCall); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// chunks splits a text in chunks of a word followed by its spaces.
+func chunks(text string) []string {
+	var chunks []string
+	start := 0
+	inSpace := false
+	for i, r := range text {
+		space := unicode.IsSpace(r)
+		if inSpace && !space {
+			chunks = append(chunks, text[start:i])
+			start = i
+		}
+		inSpace = space
+	}
+	if start < len(text) {
+		chunks = append(chunks, text[start:])
+	}
+	return chunks
+}
+
+// cloneResponse copies a response and its choices, so that callers modifying
+// the response don't change the script.
+func cloneResponse(response *llms.ContentResponse) *llms.ContentResponse {
+	if response == nil {
+		return nil
+	}
+	clone := *response
+	clone.Choices = make([]*llms.ContentChoice, len(response.Choices))
+	for i, choice := range response.Choices {
+		c := *choice
+		c.ToolCalls = append([]llms.ToolCall(nil), choice.ToolCalls...)
+		clone.Choices[i] = &c
+	}
+	if response.Usage != nil {
+		usage := *response.Usage
+		clone.Usage = &usage
+	}
+	return &clone
+}
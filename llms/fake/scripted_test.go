@@ -0,0 +1,135 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptedLLM_Queue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	usage := &llms.Usage{InputTokens: 12, OutputTokens: 3}
+	llm := NewScriptedLLM(
+		Reply{Response: &llms.ContentResponse{
+			Choices: []*llms.ContentChoice{{
+				Content:          "Paris.",
+				ReasoningContent: "The capital of France is Paris.",
+				StopReason:       "stop",
+			}},
+			Usage: usage,
+		}},
+		ToolCallReply(ToolCall("call_1", "search", `{"query":"weather"}`)),
+	)
+
+	resp, err := llm.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What is the capital of France?"),
+	}, llms.WithTemperature(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, "Paris.", resp.Choices[0].Content)
+	assert.Equal(t, "The capital of France is Paris.", resp.Choices[0].ReasoningContent)
+	assert.Equal(t, usage, resp.Usage)
+
+	// responses are copies of the script.
+	resp.Choices[0].Content = "changed"
+
+	resp, err = llm.GenerateContent(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, resp.Choices[0].ToolCalls, 1)
+	assert.Equal(t, "search", resp.Choices[0].FuncCall.Name)
+	assert.Equal(t, "tool_calls", resp.Choices[0].StopReason)
+	assert.Zero(t, llm.Remaining())
+
+	_, err = llm.GenerateContent(ctx, nil)
+	require.ErrorIs(t, err, ErrNoReply)
+
+	calls := llm.Calls()
+	require.Len(t, calls, 3)
+	assert.InDelta(t, 0.5, calls[0].Options.Temperature, 0.001)
+	assert.Equal(t, "What is the capital of France?", calls[0].Messages[0].Parts[0].(llms.TextContent).Text)
+	last, ok := llm.LastCall()
+	require.True(t, ok)
+	assert.Equal(t, calls[2], last)
+}
+
+func TestScriptedLLM_Rules(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errUnavailable := errors.New("service unavailable")
+	llm := NewScriptedLLM(TextReply("from the queue"))
+	llm.On(LastMessageContains("weather"), ErrorReply(errUnavailable))
+	llm.On(HasTool("calculator"), TextReply("81"))
+
+	_, err := llm.Call(ctx, "What is the weather in Paris?")
+	require.ErrorIs(t, err, errUnavailable)
+
+	for i := 0; i < 2; i++ {
+		out, err := llm.Call(ctx, "What is 3^4?", llms.WithTools([]llms.Tool{{
+			Type:     "function",
+			Function: &llms.FunctionDefinition{Name: "calculator"},
+		}}))
+		require.NoError(t, err)
+		assert.Equal(t, "81", out)
+	}
+
+	out, err := llm.Call(ctx, "Hello")
+	require.NoError(t, err)
+	assert.Equal(t, "from the queue", out)
+	assert.Len(t, llm.Calls(), 4)
+}
+
+func TestScriptedLLM_Streaming(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	llm := NewScriptedLLM(Reply{Response: &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content:          "It is sunny.",
+			ReasoningContent: "Checking the weather.",
+			ToolCalls:        []llms.ToolCall{ToolCall("call_1", "weather", `{"city":"Paris"}`)},
+		}},
+	}})
+
+	var chunks, reasoning []string
+	_, err := llm.GenerateContent(ctx, nil,
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		}),
+		llms.WithStreamingReasoningFunc(func(_ context.Context, reasoningChunk, _ []byte) error {
+			reasoning = append(reasoning, string(reasoningChunk))
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"It ", "is ", "sunny."}, chunks)
+	assert.Equal(t, []string{"Checking ", "the ", "weather."}, reasoning)
+
+	llm.Push(TextReply("Hello world"))
+	var types []llms.StreamEventType
+	var text string
+	llms.GenerateContentStream(ctx, llm, nil)(func(event llms.StreamEvent, err error) bool {
+		require.NoError(t, err)
+		types = append(types, event.Type)
+		text += event.Delta
+		return true
+	})
+	assert.Equal(t, "Hello world", text)
+	assert.Equal(t, []llms.StreamEventType{
+		llms.StreamEventTextDelta, llms.StreamEventTextDelta, llms.StreamEventFinish,
+	}, types)
+
+	// errors of the streaming functions stop the call.
+	errStop := errors.New("stop")
+	llm.Push(TextReply("Hello world"))
+	_, err = llm.GenerateContent(ctx, nil, llms.WithStreamingFunc(func(context.Context, []byte) error {
+		return errStop
+	}))
+	require.ErrorIs(t, err, errStop)
+}
@@ -124,6 +124,45 @@ func (ToolCallResponse) isPart() {}
 type ContentResponse struct {
 	Choices  []*ContentChoice
 	Provider string
+
+	// Usage is the token usage reported by the model for the whole call. It
+	// is nil when the backend doesn't report usage: the local and huggingface
+	// backends never do, and cloudflare doesn't when streaming.
+	Usage *Usage
+}
+
+// Usage is the normalized token usage of a single GenerateContent call. All
+// backends report usage through this type in addition to the
+// provider-specific keys they may put in ContentChoice.GenerationInfo.
+type Usage struct {
+	// InputTokens is the number of tokens in the prompt, including any tokens
+	// that were read from the provider's prompt cache.
+	InputTokens int `json:"input_tokens"`
+	// OutputTokens is the number of generated tokens, including any reasoning
+	// tokens.
+	OutputTokens int `json:"output_tokens"`
+	// CachedInputTokens is the part of InputTokens that was served from the
+	// provider's prompt cache.
+	CachedInputTokens int `json:"cached_input_tokens,omitempty"`
+	// ReasoningTokens is the part of OutputTokens the model spent on
+	// reasoning before producing its answer.
+	ReasoningTokens int `json:"reasoning_tokens,omitempty"`
+}
+
+// TotalTokens returns the sum of input and output tokens.
+func (u Usage) TotalTokens() int {
+	return u.InputTokens + u.OutputTokens
+}
+
+// Add returns the sum of u and other. It's useful for aggregating the usage
+// of several calls, e.g. all the steps of an agent run.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		InputTokens:       u.InputTokens + other.InputTokens,
+		OutputTokens:      u.OutputTokens + other.OutputTokens,
+		CachedInputTokens: u.CachedInputTokens + other.CachedInputTokens,
+		ReasoningTokens:   u.ReasoningTokens + other.ReasoningTokens,
+	}
 }
 
 // ContentChoice is one of the response choices returned by GenerateContent
@@ -34,3 +34,17 @@ func TestTextParts(t *testing.T) {
 		})
 	}
 }
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+	a := Usage{InputTokens: 10, OutputTokens: 20, CachedInputTokens: 4, ReasoningTokens: 8}
+	b := Usage{InputTokens: 1, OutputTokens: 2}
+
+	if got := a.TotalTokens(); got != 30 {
+		t.Errorf("TotalTokens() = %v, want 30", got)
+	}
+	want := Usage{InputTokens: 11, OutputTokens: 22, CachedInputTokens: 4, ReasoningTokens: 8}
+	if got := a.Add(b); got != want {
+		t.Errorf("Add() = %+v, want %+v", got, want)
+	}
+}
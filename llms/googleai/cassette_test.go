@@ -0,0 +1,37 @@
+package googleai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/httputil"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateContentCassette(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	recorder, err := httputil.NewRecorder("testdata/generate_content.json", httputil.WithMode(httputil.ModeReplay))
+	require.NoError(t, err)
+	llm, err := New(ctx,
+		WithAPIKey("test-key"),
+		WithRest(),
+		WithDefaultModel("gemini-2.0-flash"),
+		WithHTTPClient(recorder.Client()),
+	)
+	require.NoError(t, err)
+
+	resp, err := llm.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What is the capital of France?"),
+	}, llms.WithTemperature(0))
+	require.NoError(t, err)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "The capital of France is Paris.", resp.Choices[0].Content)
+	assert.Equal(t, "FinishReasonStop", resp.Choices[0].StopReason)
+	require.NotNil(t, resp.Usage)
+	assert.Equal(t, 8, resp.Usage.InputTokens)
+	assert.Equal(t, 8, resp.Usage.OutputTokens)
+}
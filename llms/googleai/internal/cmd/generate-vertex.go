@@ -35,6 +35,10 @@ func main() {
 				rewriteReceiverName(x)
 			}
 			removeTokenCount(x)
+			removeCachedContentTokenCount(x)
+
+		case *ast.BasicLit:
+			renameRun(x)
 		}
 
 		return true
@@ -55,6 +59,13 @@ func rewriteImport(x *ast.ImportSpec) {
 	}
 }
 
+// renameRun renames the runs of the model, named after the provider.
+func renameRun(x *ast.BasicLit) {
+	if x.Kind == token.STRING && x.Value == `"googleai"` {
+		x.Value = `"vertex"`
+	}
+}
+
 func rewriteReceiverName(fun *ast.FuncDecl) {
 	recv := fun.Recv.List[0]
 	ty := recv.Type.(*ast.StarExpr)
@@ -104,6 +115,35 @@ func removeTokenCount(fun *ast.FuncDecl) {
 	})
 }
 
+// removeCachedContentTokenCount removes key-value pairs reading
+// CachedContentTokenCount, which the vertex UsageMetadata doesn't have.
+func removeCachedContentTokenCount(fun *ast.FuncDecl) {
+	ast.Inspect(fun, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.CompositeLit); ok {
+			elts := lit.Elts[:0]
+			for _, elt := range lit.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok && readsCachedContentTokenCount(kv.Value) {
+					continue
+				}
+				elts = append(elts, elt)
+			}
+			lit.Elts = elts
+		}
+		return true
+	})
+}
+
+func readsCachedContentTokenCount(x ast.Expr) bool {
+	found := false
+	ast.Inspect(x, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == "CachedContentTokenCount" {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
 // getIdentName returns the identifier name from ast.Ident expressions; for
 // other expressions, returns an empty string.
 func getIdentName(x ast.Expr) string {
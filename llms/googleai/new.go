@@ -17,6 +17,9 @@ import (
 	"google.golang.org/api/option"
 )
 
+// providerName is the provider name used in the errors returned by GoogleAI.
+const providerName = "googleai"
+
 // GoogleAI is a type that represents a Google AI API client.
 type GoogleAI struct {
 	CallbacksHandler callbacks.Handler
@@ -82,7 +85,12 @@ func (t *thinkingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	return t.base.RoundTrip(req)
 }
 
-var _ llms.Model = &GoogleAI{}
+var (
+	_ llms.Model          = &GoogleAI{}
+	_ llms.StreamingModel = &GoogleAI{}
+
+	_ llms.StructuredOutputModel = &GoogleAI{}
+)
 
 // New creates a new GoogleAI client.
 func New(ctx context.Context, opts ...Option) (*GoogleAI, error) {
@@ -0,0 +1,12 @@
+package googleai
+
+import (
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
+	"github.com/google/generative-ai-go/genai"
+)
+
+// setSchemaConstraints sets the constraints of the JSON schema supported by
+// the genai schema. The schema of the Gemini API has no constraints such as
+// patterns or bounds, so they are dropped. The vertex package, generated from
+// this one, has its own implementation.
+func setSchemaConstraints(*genai.Schema, jsonschema.Definition) {}
@@ -13,6 +13,9 @@ import (
 	"github.com/IT-Tech-Company/langchaingo/llms/googleai/internal/palmclient"
 )
 
+// providerName is the provider name used in the errors returned by Vertex.
+const providerName = "vertex"
+
 // Vertex is a type that represents a Vertex AI API client.
 //
 // Right now, the Vertex Gemini SDK doesn't support embeddings; therefore,
@@ -24,7 +27,12 @@ type Vertex struct {
 	palmClient       *palmclient.PaLMClient
 }
 
-var _ llms.Model = &Vertex{}
+var (
+	_ llms.Model          = &Vertex{}
+	_ llms.StreamingModel = &Vertex{}
+
+	_ llms.StructuredOutputModel = &Vertex{}
+)
 
 // New creates a new Vertex client.
 func New(ctx context.Context, opts ...googleai.Option) (*Vertex, error) {
@@ -0,0 +1,31 @@
+package vertex
+
+import (
+	"cloud.google.com/go/vertexai/genai"
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
+)
+
+// setSchemaConstraints sets the constraints of the JSON schema supported by
+// the Vertex AI schema: the pattern of strings, the bounds of numbers and the
+// lengths of strings and arrays.
+func setSchemaConstraints(schema *genai.Schema, def jsonschema.Definition) {
+	schema.Pattern = def.Pattern
+	if def.MinItems != nil {
+		schema.MinItems = int64(*def.MinItems)
+	}
+	if def.MaxItems != nil {
+		schema.MaxItems = int64(*def.MaxItems)
+	}
+	if def.MinLength != nil {
+		schema.MinLength = int64(*def.MinLength)
+	}
+	if def.MaxLength != nil {
+		schema.MaxLength = int64(*def.MaxLength)
+	}
+	if def.Minimum != nil {
+		schema.Minimum = *def.Minimum
+	}
+	if def.Maximum != nil {
+		schema.Maximum = *def.Maximum
+	}
+}
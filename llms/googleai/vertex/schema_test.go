@@ -0,0 +1,27 @@
+package vertex
+
+import (
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertGeminiSchemaConstraints(t *testing.T) {
+	t.Parallel()
+
+	minItems, maxLength, maximum := 1, 8, 10.5
+	schema := convertGeminiSchema(jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"tags": {Type: jsonschema.Array, MinItems: &minItems, Items: &jsonschema.Definition{Type: jsonschema.String}},
+			"code": {Type: jsonschema.String, Pattern: "^[A-Z]+$", MaxLength: &maxLength},
+			"cost": {Type: jsonschema.Number, Maximum: &maximum},
+		},
+	})
+
+	assert.Equal(t, int64(1), schema.Properties["tags"].MinItems)
+	assert.Equal(t, "^[A-Z]+$", schema.Properties["code"].Pattern)
+	assert.Equal(t, int64(8), schema.Properties["code"].MaxLength)
+	assert.InDelta(t, 10.5, schema.Properties["cost"].Maximum, 0)
+}
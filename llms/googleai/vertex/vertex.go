@@ -10,12 +10,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 
 	"cloud.google.com/go/vertexai/genai"
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
 	"github.com/IT-Tech-Company/langchaingo/internal/imageutil"
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
 	"github.com/IT-Tech-Company/langchaingo/llms"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -39,28 +44,45 @@ func (g *Vertex) Call(ctx context.Context, prompt string, options ...llms.CallOp
 	return llms.GenerateFromSinglePrompt(ctx, g, prompt, options...)
 }
 
+// SupportsResponseSchema implements the [llms.StructuredOutputModel]
+// interface.
+func (g *Vertex) SupportsResponseSchema() bool {
+	return true
+}
+
+// GenerateContentStream implements the [llms.StreamingModel] interface.
+func (g *Vertex) GenerateContentStream(
+	ctx context.Context,
+	messages []llms.MessageContent,
+	options ...llms.CallOption,
+) llms.StreamSeq {
+	return llms.StreamContentEvents(ctx, g, messages, options...)
+}
+
 // GenerateContent implements the [llms.Model] interface.
 func (g *Vertex) GenerateContent(
 	ctx context.Context,
 	messages []llms.MessageContent,
 	options ...llms.CallOption,
 ) (*llms.ContentResponse, error) {
-	if g.CallbacksHandler != nil {
-		g.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := llms.CallOptions{
-		Model:          g.opts.DefaultModel,
-		CandidateCount: g.opts.DefaultCandidateCount,
-		MaxTokens:      g.opts.DefaultMaxTokens,
-		Temperature:    g.opts.DefaultTemperature,
-		TopP:           g.opts.DefaultTopP,
-		TopK:           g.opts.DefaultTopK,
+		Model:           g.opts.DefaultModel,
+		CandidateCount:  g.opts.DefaultCandidateCount,
+		MaxTokens:       g.opts.DefaultMaxTokens,
+		Temperature:     g.opts.DefaultTemperature,
+		TopP:            g.opts.DefaultTopP,
+		TopK:            g.opts.DefaultTopK,
+		DynamicThinking: g.opts.DynamicThinking,
 	}
 	for _, opt := range options {
 		opt(&opts)
 	}
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "vertex", callbacks.WithRunModel(opts.Model))
+	if g.CallbacksHandler != nil {
+		g.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
 	model := g.client.GenerativeModel(opts.Model)
 	model.SetCandidateCount(int32(opts.CandidateCount))
 	model.SetMaxOutputTokens(int32(opts.MaxTokens))
@@ -101,6 +123,13 @@ func (g *Vertex) GenerateContent(
 		model.ResponseMIMEType = ResponseMIMETypeJson
 	}
 
+	if opts.ResponseSchema != nil {
+		if model.ResponseSchema, err = convertSchema(opts.ResponseSchema.Schema); err != nil {
+			return nil, err
+		}
+		model.ResponseMIMEType = ResponseMIMETypeJson
+	}
+
 	var response *llms.ContentResponse
 
 	if len(messages) == 1 {
@@ -113,7 +142,7 @@ func (g *Vertex) GenerateContent(
 		response, err = generateFromMessages(ctx, model, messages, &opts)
 	}
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	if g.CallbacksHandler != nil {
@@ -123,6 +152,35 @@ func (g *Vertex) GenerateContent(
 	return response, nil
 }
 
+// mapError classifies an error returned by the genai client.
+func mapError(err error) error {
+	var blocked *genai.BlockedError
+	if errors.As(err, &blocked) {
+		return &llms.Error{Provider: providerName, Kind: llms.ErrContentFiltered, Err: err}
+	}
+
+	var statusCode int
+	var httpErr interface{ HTTPCode() int }
+	if errors.As(err, &httpErr) && httpErr.HTTPCode() > 0 {
+		statusCode = httpErr.HTTPCode()
+	} else if st, ok := status.FromError(err); ok {
+		statusCode = grpcCodeToHTTPStatus[st.Code()]
+	}
+	return llms.NewError(providerName, statusCode, err)
+}
+
+// grpcCodeToHTTPStatus maps the gRPC codes relevant for error classification
+// to HTTP status codes.
+var grpcCodeToHTTPStatus = map[codes.Code]int{
+	codes.InvalidArgument:   http.StatusBadRequest,
+	codes.Unauthenticated:   http.StatusUnauthorized,
+	codes.PermissionDenied:  http.StatusForbidden,
+	codes.ResourceExhausted: http.StatusTooManyRequests,
+	codes.Internal:          http.StatusInternalServerError,
+	codes.Unavailable:       http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:  http.StatusGatewayTimeout,
+}
+
 // convertCandidates converts a sequence of genai.Candidate to a response.
 func convertCandidates(candidates []*genai.Candidate, usage *genai.UsageMetadata) (*llms.ContentResponse, error) {
 	var contentResponse llms.ContentResponse
@@ -175,9 +233,21 @@ func convertCandidates(candidates []*genai.Candidate, usage *genai.UsageMetadata
 				ToolCalls:      toolCalls,
 			})
 	}
+	contentResponse.Usage = convertUsage(usage)
 	return &contentResponse, nil
 }
 
+// convertUsage converts genai usage metadata to llms.Usage.
+func convertUsage(usage *genai.UsageMetadata) *llms.Usage {
+	if usage == nil {
+		return nil
+	}
+	return &llms.Usage{
+		InputTokens:  int(usage.PromptTokenCount),
+		OutputTokens: int(usage.CandidatesTokenCount),
+	}
+}
+
 // convertParts converts between a sequence of langchain parts and genai parts.
 func convertParts(parts []llms.ContentPart) ([]genai.Part, error) {
 	convertedParts := make([]genai.Part, 0, len(parts))
@@ -197,9 +267,11 @@ func convertParts(parts []llms.ContentPart) ([]genai.Part, error) {
 			out = genai.ImageData(typ, data)
 		case llms.ToolCall:
 			fc := p.FunctionCall
-			var argsMap map[string]any
-			if err := json.Unmarshal([]byte(fc.Arguments), &argsMap); err != nil {
-				return convertedParts, err
+			argsMap := map[string]any{}
+			if fc.Arguments != "" {
+				if err := json.Unmarshal([]byte(fc.Arguments), &argsMap); err != nil {
+					return convertedParts, err
+				}
 			}
 			out = genai.FunctionCall{
 				Name: fc.Name,
@@ -263,7 +335,7 @@ func generateFromSingleMessage(
 		return nil, err
 	}
 
-	if opts.StreamingFunc == nil {
+	if opts.StreamingFunc == nil && opts.StreamingEventFunc == nil {
 		// When no streaming is requested, just call GenerateContent and return
 		// the complete response with a list of candidates.
 		resp, err := model.GenerateContent(ctx, convertedParts...)
@@ -287,6 +359,7 @@ func generateFromMessages(
 	opts *llms.CallOptions,
 ) (*llms.ContentResponse, error) {
 	history := make([]*genai.Content, 0, len(messages))
+	var prevRole llms.ChatMessageType
 	for _, mc := range messages {
 		content, err := convertContent(mc)
 		if err != nil {
@@ -296,6 +369,14 @@ func generateFromMessages(
 			model.SystemInstruction = content
 			continue
 		}
+		// The responses to parallel function calls have to be sent in a
+		// single turn.
+		if mc.Role == llms.ChatMessageTypeTool && prevRole == llms.ChatMessageTypeTool {
+			last := history[len(history)-1]
+			last.Parts = append(last.Parts, content.Parts...)
+			continue
+		}
+		prevRole = mc.Role
 		history = append(history, content)
 	}
 
@@ -308,7 +389,7 @@ func generateFromMessages(
 	session := model.StartChat()
 	session.History = history
 
-	if opts.StreamingFunc == nil {
+	if opts.StreamingFunc == nil && opts.StreamingEventFunc == nil {
 		resp, err := session.SendMessage(ctx, reqContent.Parts...)
 		if err != nil {
 			return nil, err
@@ -361,17 +442,47 @@ DoStream:
 		candidate.CitationMetadata = respCandidate.CitationMetadata
 
 		for _, part := range respCandidate.Content.Parts {
-			if text, ok := part.(genai.Text); ok {
+			if text, ok := part.(genai.Text); ok && opts.StreamingFunc != nil {
 				if opts.StreamingFunc(ctx, []byte(text)) != nil {
 					break DoStream
 				}
 			}
+			if opts.StreamingEventFunc != nil {
+				if err := streamPartEvent(ctx, part, opts.StreamingEventFunc); err != nil {
+					return nil, err
+				}
+			}
 		}
 	}
 	mresp := iter.MergedResponse()
 	return convertCandidates([]*genai.Candidate{candidate}, mresp.UsageMetadata)
 }
 
+// streamPartEvent sends the streaming events for a part of a streamed
+// response. Function calls arrive in one piece, so all their events are sent
+// at once.
+func streamPartEvent(ctx context.Context, part genai.Part, fn func(context.Context, llms.StreamEvent) error) error {
+	switch v := part.(type) {
+	case genai.Text:
+		if v == "" {
+			return nil
+		}
+		return fn(ctx, llms.StreamEvent{Type: llms.StreamEventTextDelta, Delta: string(v)})
+	case genai.FunctionCall:
+		b, err := json.Marshal(v.Args)
+		if err != nil {
+			return err
+		}
+		return llms.SendToolCallEvents(ctx, fn, llms.ToolCall{
+			FunctionCall: &llms.FunctionCall{
+				Name:      v.Name,
+				Arguments: string(b),
+			},
+		})
+	}
+	return nil
+}
+
 // convertTools converts from a list of langchaingo tools to a list of genai
 // tools.
 func convertTools(tools []llms.Tool) ([]*genai.Tool, error) {
@@ -388,8 +499,22 @@ func convertTools(tools []llms.Tool) ([]*genai.Tool, error) {
 			Description: tool.Function.Description,
 		}
 
-		// Expect the Parameters field to be a map[string]any, from which we will
-		// extract properties to populate the schema.
+		// Schemas of the jsonschema package are converted to the OpenAPI subset
+		// supported by Gemini.
+		if def, ok := schemaDefinition(tool.Function.Parameters); ok {
+			schema, err := convertSchema(def)
+			if err != nil {
+				return nil, fmt.Errorf("tool [%d]: %w", i, err)
+			}
+			genaiFuncDecl.Parameters = schema
+			genaiTools = append(genaiTools, &genai.Tool{
+				FunctionDeclarations: []*genai.FunctionDeclaration{genaiFuncDecl},
+			})
+			continue
+		}
+
+		// Otherwise expect the Parameters field to be a map[string]any, from which
+		// we will extract properties to populate the schema.
 		params, ok := tool.Function.Parameters.(map[string]any)
 		if !ok {
 			return nil, fmt.Errorf("tool [%d]: unsupported type %T of Parameters", i, tool.Function.Parameters)
@@ -460,6 +585,51 @@ func convertTools(tools []llms.Tool) ([]*genai.Tool, error) {
 	return genaiTools, nil
 }
 
+// schemaDefinition returns the parameters of a tool if they are a JSON schema
+// of the jsonschema package.
+func schemaDefinition(parameters any) (jsonschema.Definition, bool) {
+	switch def := parameters.(type) {
+	case jsonschema.Definition:
+		return def, true
+	case *jsonschema.Definition:
+		return *def, true
+	}
+	return jsonschema.Definition{}, false
+}
+
+// convertSchema converts a JSON schema to a genai schema.
+func convertSchema(def jsonschema.Definition) (*genai.Schema, error) {
+	def, err := jsonschema.ToGemini(def)
+	if err != nil {
+		return nil, err
+	}
+	return convertGeminiSchema(def), nil
+}
+
+// convertGeminiSchema converts a JSON schema in the subset supported by Gemini
+// to a genai schema.
+func convertGeminiSchema(def jsonschema.Definition) *genai.Schema {
+	schema := &genai.Schema{
+		Type:        convertToolSchemaType(string(def.Type)),
+		Format:      def.Format,
+		Description: def.Description,
+		Nullable:    def.Nullable,
+		Enum:        def.Enum,
+		Required:    def.Required,
+	}
+	if def.Items != nil {
+		schema.Items = convertGeminiSchema(*def.Items)
+	}
+	if len(def.Properties) > 0 {
+		schema.Properties = make(map[string]*genai.Schema, len(def.Properties))
+		for name, prop := range def.Properties {
+			schema.Properties[name] = convertGeminiSchema(prop)
+		}
+	}
+	setSchemaConstraints(schema, def)
+	return schema
+}
+
 // convertToolSchemaType converts a tool's schema type from its langchaingo
 // representation (string) to a genai enum.
 func convertToolSchemaType(ty string) genai.Type {
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 type embeddingPayload struct {
@@ -42,7 +44,9 @@ func (c *Client) createEmbedding(ctx context.Context, model string, task string,
 	if r.StatusCode != http.StatusOK {
 		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
 
-		return nil, fmt.Errorf("%s: %s", msg, "unable to create embeddings") // nolint:goerr113
+		apiErr := llms.NewError("huggingface", r.StatusCode, fmt.Errorf("%s: %s", msg, "unable to create embeddings")) // nolint:goerr113,lll
+		apiErr.RetryAfter = llms.ParseRetryAfter(r.Header)
+		return nil, apiErr
 	}
 
 	var response [][]float32
@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/IT-Tech-Company/langchaingo/llms"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -49,6 +51,24 @@ func TestRunInference(t *testing.T) {
 	}
 }
 
+func TestRunInference_RateLimited(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New("token", "model", server.URL)
+	require.NoError(t, err)
+
+	_, err = client.RunInference(context.TODO(), &InferenceRequest{})
+	require.ErrorIs(t, err, ErrUnexpectedStatusCode)
+	require.ErrorIs(t, err, llms.ErrRateLimited)
+	assert.Equal(t, 2*time.Second, llms.RetryAfter(err))
+}
+
 func mockServer(t *testing.T) *httptest.Server {
 	t.Helper()
 
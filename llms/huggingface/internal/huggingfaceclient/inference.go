@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 var ErrUnexpectedStatusCode = errors.New("unexpected status code")
@@ -81,7 +83,9 @@ func (c *Client) runInference(ctx context.Context, payload *inferencePayload) (i
 		} else {
 			err = fmt.Errorf("%w: %d", ErrUnexpectedStatusCode, r.StatusCode)
 		}
-		return nil, err
+		apiErr := llms.NewError("huggingface", r.StatusCode, err)
+		apiErr.RetryAfter = llms.ParseRetryAfter(r.Header)
+		return nil, apiErr
 	}
 
 	// debug print the http response with httputil:
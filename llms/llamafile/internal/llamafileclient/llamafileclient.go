@@ -14,6 +14,8 @@ import (
 	"os"
 	"runtime"
 	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 const maxBufferSize = 512 * 1000
@@ -51,7 +53,7 @@ func checkError(resp *http.Response, body []byte) error {
 		apiError.ErrorMessage = string(body)
 	}
 
-	return apiError
+	return llms.NewError("llamafile", resp.StatusCode, apiError)
 }
 
 func NewClient(ourl *url.URL, ohttp *http.Client) (*Client, error) {
@@ -213,15 +215,15 @@ func processScan(bts []byte, response *http.Response, fn func([]byte) error) err
 	if err := json.Unmarshal(bts, &errorResponse); err != nil {
 		return err
 	}
-	if errorResponse.Error != "" {
-		return errors.New(errorResponse.Error)
-	}
 	if response.StatusCode >= http.StatusBadRequest {
-		return StatusError{
+		return llms.NewError("llamafile", response.StatusCode, StatusError{
 			StatusCode:   response.StatusCode,
 			Status:       response.Status,
 			ErrorMessage: errorResponse.Error,
-		}
+		})
+	}
+	if errorResponse.Error != "" {
+		return llms.NewError("llamafile", response.StatusCode, errors.New(errorResponse.Error)) //nolint:goerr113
 	}
 
 	return fn(bts)
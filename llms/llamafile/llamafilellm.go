@@ -46,6 +46,7 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 // GenerateContent implements the Model interface.
 // nolint: goerr113
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { // nolint: lll, cyclop, funlen
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "llamafile")
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
@@ -98,6 +99,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	req = makeLlamaOptionsFromOptions(req, opts)
 
 	streamedResponse := ""
+	var usage *llms.Usage
 	fn := func(response llamafileclient.ChatResponse) error {
 		if opts.StreamingFunc != nil && response.Content != "" {
 			if err := opts.StreamingFunc(ctx, []byte(response.Content)); err != nil {
@@ -107,6 +109,13 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		if response.Content != "" {
 			streamedResponse += response.Content
 		}
+		if response.Stop {
+			usage = &llms.Usage{
+				InputTokens:       response.TokensEvaluated,
+				OutputTokens:      response.TokensPredicted,
+				CachedInputTokens: response.TokensCached,
+			}
+		}
 
 		return nil
 	}
@@ -125,6 +134,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 				Content: streamedResponse,
 			},
 		},
+		Usage: usage,
 	}, nil
 }
 
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 const defaultURL = "https://chat.maritaca.ai/api"
@@ -66,15 +68,17 @@ func (c *Client) stream(ctx context.Context, method, path string, data any, fn f
 			Error string `json:"detail,omitempty"`
 		}
 
-		if err := json.NewDecoder(response.Body).Decode(&errorResponse); err != nil {
-			return err
-		}
+		// The body isn't always JSON, e.g. on rate limits: fall back to the
+		// status then.
+		_ = json.NewDecoder(response.Body).Decode(&errorResponse)
 
-		return StatusError{
+		apiErr := llms.NewError("maritaca", response.StatusCode, StatusError{
 			StatusCode:   response.StatusCode,
 			Status:       response.Status,
 			ErrorMessage: errorResponse.Error,
-		}
+		})
+		apiErr.RetryAfter = llms.ParseRetryAfter(response.Header)
+		return apiErr
 	}
 
 	scanner := bufio.NewScanner(response.Body)
@@ -51,10 +51,6 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 // GenerateContent implements the Model interface.
 // nolint: goerr113
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { // nolint: lll, cyclop, funlen
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := llms.CallOptions{}
 	for _, opt := range options {
 		opt(&opts)
@@ -66,6 +62,11 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		model = opts.Model
 	}
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "maritaca", callbacks.WithRunModel(model))
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
 	// Our input is a sequence of MessageContent, each of which potentially has
 	// a sequence of Part that could be text, images etc.
 	// We have to convert it to a format maritaca undestands: ChatRequest, which
@@ -129,6 +130,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 			streamedResponse += response.Text
 		case "end":
 			resp.Answer = streamedResponse
+			resp.Usage = response.Usage
 		case "nostream":
 			resp = response
 		}
@@ -147,6 +149,12 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	choices := createChoice(resp)
 
 	response := &llms.ContentResponse{Choices: choices}
+	if resp.Usage.TotalTokens > 0 {
+		response.Usage = &llms.Usage{
+			InputTokens:  resp.Usage.PromptTokens,
+			OutputTokens: resp.Usage.CompletionTokens,
+		}
+	}
 
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
@@ -223,29 +223,28 @@ func (tc ToolCall) MarshalJSON() ([]byte, error) {
 }
 
 func (tc *ToolCall) UnmarshalJSON(data []byte) error {
-	var m map[string]any
+	var m map[string]json.RawMessage
 	if err := json.Unmarshal(data, &m); err != nil {
 		return err
 	}
-	_, ok := m["type"].(string)
-	if !ok {
+	var typeField string
+	if err := json.Unmarshal(m["type"], &typeField); err != nil {
 		return fmt.Errorf(`missing "type" field in ToolCall`)
 	}
-	toolCall, ok := m["tool_call"].(map[string]any)
-	if !ok {
+	var toolCall map[string]json.RawMessage
+	if err := json.Unmarshal(m["tool_call"], &toolCall); err != nil || toolCall == nil {
 		return fmt.Errorf("invalid tool_call field in ToolCall")
 	}
-	id, ok := toolCall["id"].(string)
-	if !ok {
+	var id string
+	if err := json.Unmarshal(toolCall["id"], &id); err != nil {
 		return fmt.Errorf("invalid id field in ToolCall")
 	}
-	typ, ok := toolCall["type"].(string)
-	if !ok {
+	var typ string
+	if err := json.Unmarshal(toolCall["type"], &typ); err != nil {
 		return fmt.Errorf("invalid type field in ToolCall")
 	}
 	var fc FunctionCall
-	fcData, ok := toolCall["function"].(json.RawMessage)
-	if ok {
+	if fcData, ok := toolCall["function"]; ok {
 		if err := json.Unmarshal(fcData, &fc); err != nil {
 			return fmt.Errorf("error unmarshalling function call: %w", err)
 		}
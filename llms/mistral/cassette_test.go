@@ -0,0 +1,36 @@
+package mistral
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/httputil"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateContentCassette(t *testing.T) {
+	t.Parallel()
+
+	recorder, err := httputil.NewRecorder("testdata/chat_completion.json", httputil.WithMode(httputil.ModeReplay))
+	require.NoError(t, err)
+	// the Mistral SDK doesn't accept an HTTP client, so the recorder serves
+	// the requests from a local server.
+	server := httptest.NewServer(recorder.Handler("https://api.mistral.ai"))
+	t.Cleanup(server.Close)
+	llm, err := New(WithAPIKey("test-key"), WithModel("mistral-small-latest"), WithEndpoint(server.URL))
+	require.NoError(t, err)
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What is the capital of France?"),
+	}, llms.WithTemperature(0))
+	require.NoError(t, err)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "The capital of France is Paris.", resp.Choices[0].Content)
+	assert.Equal(t, "stop", resp.Choices[0].StopReason)
+	require.NotNil(t, resp.Usage)
+	assert.Equal(t, 10, resp.Usage.InputTokens)
+	assert.Equal(t, 8, resp.Usage.OutputTokens)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"os"
+	"regexp"
+	"strconv"
 
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
 	"github.com/IT-Tech-Company/langchaingo/llms"
@@ -54,6 +56,7 @@ func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOpt
 	})
 	res, err := m.client.Chat("", messages, &mistralChatParams)
 	if err != nil {
+		err = mapError(err)
 		m.CallbacksHandler.HandleLLMError(ctx, err)
 		return "", err
 	}
@@ -69,6 +72,7 @@ func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOpt
 func (m *Model) GenerateContent(ctx context.Context, langchainMessages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
 	callOptions := resolveDefaultOptions(sdk.DefaultChatRequestParams, m.clientOptions)
 	setCallOptions(options, callOptions)
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "mistral", callbacks.WithRunModel(callOptions.Model))
 	m.CallbacksHandler.HandleLLMGenerateContentStart(ctx, langchainMessages)
 
 	chatOpts := mistralChatParamsFromCallOptions(callOptions)
@@ -147,6 +151,7 @@ func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llm
 	res, err := m.client.Chat(callOptions.Model, messages, &chatOpts)
 	m.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, nil)
 	if err != nil {
+		err = mapError(err)
 		m.CallbacksHandler.HandleLLMError(ctx, err)
 		return nil, err
 	}
@@ -158,6 +163,7 @@ func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llm
 
 	langchainContentResponse := &llms.ContentResponse{
 		Choices: make([]*llms.ContentChoice, 0),
+		Usage:   convertUsage(res.Usage),
 	}
 	for idx, choice := range res.Choices {
 		langchainContentResponse.Choices = append(langchainContentResponse.Choices, &llms.ContentChoice{
@@ -192,6 +198,7 @@ func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llm
 func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.CallOptions, messages []sdk.ChatMessage, chatOpts sdk.ChatRequestParams) (*llms.ContentResponse, error) {
 	chatResChan, err := m.client.ChatStream(callOptions.Model, messages, &chatOpts)
 	if err != nil {
+		err = mapError(err)
 		m.CallbacksHandler.HandleLLMError(ctx, err)
 		return nil, err
 	}
@@ -208,6 +215,9 @@ func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.C
 		langchainContentResponse.Choices[0].GenerationInfo["created"] = chatResChunk.Created
 		langchainContentResponse.Choices[0].GenerationInfo["model"] = chatResChunk.Model
 		langchainContentResponse.Choices[0].GenerationInfo["usage"] = chatResChunk.Usage
+		if chatResChunk.Usage.TotalTokens > 0 {
+			langchainContentResponse.Usage = convertUsage(chatResChunk.Usage)
+		}
 		if chatResChunk.Error == nil {
 			for _, choice := range chatResChunk.Choices {
 				chunkStr += choice.Delta.Content
@@ -232,16 +242,42 @@ func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.C
 				return langchainContentResponse, err
 			}
 		} else {
-			return langchainContentResponse, chatResChunk.Error
+			return langchainContentResponse, mapError(chatResChunk.Error)
 		}
 	}
 
 	return langchainContentResponse, nil
 }
 
+// httpErrorRe matches the status code in the errors returned by the Mistral SDK.
+var httpErrorRe = regexp.MustCompile(`^\(HTTP Error (\d+)\)`)
+
+// mapError classifies an error returned by the Mistral SDK.
+func mapError(err error) error {
+	var statusCode int
+	if m := httpErrorRe.FindStringSubmatch(err.Error()); m != nil {
+		statusCode, _ = strconv.Atoi(m[1])
+	}
+	return llms.NewError("mistral", statusCode, err)
+}
+
+// convertUsage converts the usage reported by the Mistral SDK to llms.Usage.
+func convertUsage(usage sdk.UsageInfo) *llms.Usage {
+	return &llms.Usage{
+		InputTokens:  usage.PromptTokens,
+		OutputTokens: usage.CompletionTokens,
+	}
+}
+
 func convertToMistralChatMessages(langchainMessages []llms.MessageContent) ([]sdk.ChatMessage, error) {
 	messages := make([]sdk.ChatMessage, 0)
 	for _, msg := range langchainMessages {
+		if msg.Role == llms.ChatMessageTypeAI && hasToolCalls(msg) {
+			// Parallel tool calls have to be sent in a single assistant
+			// message.
+			messages = append(messages, convertAIToolCallMessage(msg))
+			continue
+		}
 		for _, part := range msg.Parts {
 			switch p := part.(type) {
 			case llms.TextContent:
@@ -251,6 +287,8 @@ func convertToMistralChatMessages(langchainMessages []llms.MessageContent) ([]sd
 					messages = append(messages, chatMsg)
 				}
 			case llms.ToolCallResponse:
+				// The SDK has no field for the tool call ID, so the results are
+				// matched to the calls by their order.
 				chatMsg := sdk.ChatMessage{Role: string(msg.Role), Content: p.Content}
 				setMistralChatMessageRole(&msg, &chatMsg) // #nosec G601
 				messages = append(messages, chatMsg)
@@ -266,6 +304,37 @@ func convertToMistralChatMessages(langchainMessages []llms.MessageContent) ([]sd
 	return messages, nil
 }
 
+func hasToolCalls(msg llms.MessageContent) bool {
+	for _, part := range msg.Parts {
+		if _, ok := part.(llms.ToolCall); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// convertAIToolCallMessage converts an AI message with tool calls, and
+// possibly text, into a single assistant message.
+func convertAIToolCallMessage(msg llms.MessageContent) sdk.ChatMessage {
+	chatMsg := sdk.ChatMessage{Role: "assistant"}
+	for _, part := range msg.Parts {
+		switch p := part.(type) {
+		case llms.TextContent:
+			chatMsg.Content += p.Text
+		case llms.ToolCall:
+			chatMsg.ToolCalls = append(chatMsg.ToolCalls, sdk.ToolCall{
+				Id:   p.ID,
+				Type: sdk.ToolTypeFunction,
+				Function: sdk.FunctionCall{
+					Name:      p.FunctionCall.Name,
+					Arguments: p.FunctionCall.Arguments,
+				},
+			})
+		}
+	}
+	return chatMsg
+}
+
 func setMistralChatMessageRole(msg *llms.MessageContent, chatMsg *sdk.ChatMessage) {
 	switch msg.Role {
 	case llms.ChatMessageTypeAI:
@@ -0,0 +1,227 @@
+package llms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
+)
+
+// ErrInvalidObject is returned by GenerateObject when the model doesn't
+// respond with a valid object, even after being asked to fix it.
+var ErrInvalidObject = errors.New("invalid object")
+
+// ResponseSchema describes the JSON object a model must respond with.
+type ResponseSchema struct {
+	// Name is the name of the object. It must only contain letters, digits,
+	// underscores and dashes.
+	Name string `json:"name"`
+	// Description describes the object.
+	Description string `json:"description,omitempty"`
+	// Schema is the JSON schema of the object.
+	Schema jsonschema.Definition `json:"schema"`
+}
+
+// StructuredOutputModel is a Model with a native mechanism to generate JSON
+// objects matching a schema, such as OpenAI's structured outputs.
+type StructuredOutputModel interface {
+	Model
+
+	// SupportsResponseSchema reports whether the model supports the
+	// WithResponseSchema option, in which case the content of its responses
+	// is a JSON object matching the schema.
+	SupportsResponseSchema() bool
+}
+
+const _defaultObjectMaxRetries = 2
+
+// ObjectOption is an option of GenerateObject.
+type ObjectOption func(*objectOptions)
+
+type objectOptions struct {
+	name        string
+	description string
+	maxRetries  int
+	callOptions []CallOption
+}
+
+// WithObjectName sets the name of the object given to the model. It defaults
+// to the name of the type of the object.
+func WithObjectName(name string) ObjectOption {
+	return func(o *objectOptions) {
+		o.name = name
+	}
+}
+
+// WithObjectDescription sets the description of the object given to the
+// model.
+func WithObjectDescription(description string) ObjectOption {
+	return func(o *objectOptions) {
+		o.description = description
+	}
+}
+
+// WithObjectMaxRetries sets how many times the model is asked to fix an
+// invalid object. The default is 2.
+func WithObjectMaxRetries(maxRetries int) ObjectOption {
+	return func(o *objectOptions) {
+		o.maxRetries = maxRetries
+	}
+}
+
+// WithObjectCallOptions sets the options of the calls to the model.
+func WithObjectCallOptions(options ...CallOption) ObjectOption {
+	return func(o *objectOptions) {
+		o.callOptions = append(o.callOptions, options...)
+	}
+}
+
+// GenerateObject asks the model for a JSON object of type T, usually a struct,
+// and returns the decoded object. The schema of the object is derived from T
+// with jsonschema.For.
+//
+// Models implementing StructuredOutputModel get the schema with
+// WithResponseSchema; the others get it in instructions added to the last
+// message. The response is validated against the schema, and the model is
+// asked to fix invalid responses up to the number of retries set with
+// WithObjectMaxRetries, after which ErrInvalidObject is returned.
+func GenerateObject[T any](ctx context.Context, model Model, messages []MessageContent, options ...ObjectOption) (T, error) { //nolint:lll
+	var zero T
+	opts := objectOptions{
+		name:       objectName(reflect.TypeOf((*T)(nil)).Elem()),
+		maxRetries: _defaultObjectMaxRetries,
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	schema, err := jsonschema.For[T]()
+	if err != nil {
+		return zero, err
+	}
+
+	messages = append([]MessageContent(nil), messages...)
+	callOptions := opts.callOptions[:len(opts.callOptions):len(opts.callOptions)]
+	if m, ok := model.(StructuredOutputModel); ok && m.SupportsResponseSchema() {
+		callOptions = append(callOptions, WithResponseSchema(ResponseSchema{
+			Name:        opts.name,
+			Description: opts.description,
+			Schema:      schema,
+		}))
+	} else {
+		instructions, err := objectInstructions(schema, opts.description)
+		if err != nil {
+			return zero, err
+		}
+		messages = appendHumanText(messages, instructions)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := model.GenerateContent(ctx, messages, callOptions...)
+		if err != nil {
+			return zero, err
+		}
+
+		content := responseText(resp)
+		var obj T
+		err = decodeObject(schema, content, &obj)
+		if err == nil {
+			return obj, nil
+		}
+		if attempt >= opts.maxRetries {
+			return zero, fmt.Errorf("%w: %w", ErrInvalidObject, err)
+		}
+
+		messages = append(messages,
+			TextParts(ChatMessageTypeAI, content),
+			TextParts(ChatMessageTypeHuman, fmt.Sprintf(_objectRetryPrompt, err)),
+		)
+	}
+}
+
+const _objectInstructions = `Respond only with a JSON object matching the following JSON schema, without any other text:
+%s`
+
+const _objectRetryPrompt = `Your response is not valid:
+%s
+
+Respond again with a JSON object fixing these errors, without any other text.`
+
+// objectInstructions returns the instructions asking models without native
+// structured outputs for an object.
+func objectInstructions(schema jsonschema.Definition, description string) (string, error) {
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	instructions := fmt.Sprintf(_objectInstructions, schemaJSON)
+	if description != "" {
+		instructions = fmt.Sprintf("%s\n\nThe object is %s", instructions, description)
+	}
+	return instructions, nil
+}
+
+// appendHumanText adds the text to the last message if it's a human one, or
+// in a new human message otherwise.
+func appendHumanText(messages []MessageContent, text string) []MessageContent {
+	if n := len(messages); n > 0 && messages[n-1].Role == ChatMessageTypeHuman {
+		last := messages[n-1]
+		last.Parts = append(last.Parts[:len(last.Parts):len(last.Parts)], TextContent{Text: text})
+		messages[n-1] = last
+		return messages
+	}
+	return append(messages, TextParts(ChatMessageTypeHuman, text))
+}
+
+// responseText returns the text of all the choices of a response, as some
+// models return each part of their response as a separate choice.
+func responseText(resp *ContentResponse) string {
+	var sb strings.Builder
+	for _, choice := range resp.Choices {
+		sb.WriteString(choice.Content)
+	}
+	return sb.String()
+}
+
+// decodeObject validates the JSON in content against the schema and decodes
+// it into obj.
+func decodeObject(schema jsonschema.Definition, content string, obj any) error {
+	data := []byte(extractJSON(content))
+	if err := jsonschema.Validate(schema, data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, obj)
+}
+
+// extractJSON returns the JSON value in a text, removing the code fences and
+// the text models sometimes add around it.
+func extractJSON(text string) string {
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return text
+	}
+	end := strings.LastIndexAny(text, "}]")
+	if end < start {
+		return text
+	}
+	return text[start : end+1]
+}
+
+//nolint:gochecknoglobals
+var objectNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// objectName returns the default name of objects of the type.
+func objectName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if name := t.Name(); objectNamePattern.MatchString(name) {
+		return name
+	}
+	return "response"
+}
@@ -0,0 +1,139 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// objectModel responds with the given contents in order and records the
+// calls it receives.
+type objectModel struct {
+	responses []string
+	native    bool
+
+	messages [][]MessageContent
+	options  []CallOptions
+}
+
+func (m *objectModel) Call(ctx context.Context, prompt string, options ...CallOption) (string, error) {
+	return GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *objectModel) GenerateContent(_ context.Context, messages []MessageContent, options ...CallOption) (*ContentResponse, error) { //nolint:lll
+	var opts CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	m.messages = append(m.messages, messages)
+	m.options = append(m.options, opts)
+	if len(m.responses) == 0 {
+		return nil, errors.New("no more responses")
+	}
+	content := m.responses[0]
+	m.responses = m.responses[1:]
+	return &ContentResponse{Choices: []*ContentChoice{{Content: content}}}, nil
+}
+
+func (m *objectModel) SupportsResponseSchema() bool {
+	return m.native
+}
+
+type weather struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+	Unit        string  `json:"unit" jsonschema:"enum=celsius,enum=fahrenheit"`
+}
+
+func TestGenerateObject_Native(t *testing.T) {
+	t.Parallel()
+
+	model := &objectModel{
+		native:    true,
+		responses: []string{`{"city":"Paris","temperature":21.5,"unit":"celsius"}`},
+	}
+	messages := []MessageContent{TextParts(ChatMessageTypeHuman, "What's the weather in Paris?")}
+
+	obj, err := GenerateObject[weather](context.Background(), model, messages,
+		WithObjectDescription("the current weather"),
+		WithObjectCallOptions(WithTemperature(0)),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, weather{City: "Paris", Temperature: 21.5, Unit: "celsius"}, obj)
+
+	require.Len(t, model.options, 1)
+	schema := model.options[0].ResponseSchema
+	require.NotNil(t, schema)
+	assert.Equal(t, "weather", schema.Name)
+	assert.Equal(t, "the current weather", schema.Description)
+	assert.ElementsMatch(t, []string{"city", "temperature", "unit"}, schema.Schema.Required)
+	assert.Equal(t, []string{"celsius", "fahrenheit"}, schema.Schema.Properties["unit"].Enum)
+	assert.InDelta(t, 0, model.options[0].Temperature, 0)
+	assert.Equal(t, messages, model.messages[0], "messages shouldn't be changed for native models")
+}
+
+func TestGenerateObject_Fallback(t *testing.T) {
+	t.Parallel()
+
+	model := &objectModel{
+		responses: []string{
+			`{"city":"Paris","temperature":"hot","unit":"celsius"}`,
+			"Here it is:\n```json\n{\"city\":\"Paris\",\"temperature\":30,\"unit\":\"celsius\"}\n```",
+		},
+	}
+	messages := []MessageContent{TextParts(ChatMessageTypeHuman, "What's the weather in Paris?")}
+
+	obj, err := GenerateObject[weather](context.Background(), model, messages)
+	require.NoError(t, err)
+	assert.Equal(t, weather{City: "Paris", Temperature: 30, Unit: "celsius"}, obj)
+	assert.Len(t, messages[0].Parts, 1, "the given messages shouldn't be modified")
+
+	require.Len(t, model.messages, 2)
+	assert.Nil(t, model.options[0].ResponseSchema)
+
+	first := model.messages[0]
+	require.Len(t, first, 1)
+	require.Len(t, first[0].Parts, 2)
+	instructions, ok := first[0].Parts[1].(TextContent)
+	require.True(t, ok)
+	assert.Contains(t, instructions.Text, "Respond only with a JSON object")
+	assert.Contains(t, instructions.Text, `"temperature":{"type":"number"`)
+
+	retry := model.messages[1]
+	require.Len(t, retry, 3)
+	assert.Equal(t, ChatMessageTypeAI, retry[1].Role)
+	assert.Equal(t, ChatMessageTypeHuman, retry[2].Role)
+	prompt, ok := retry[2].Parts[0].(TextContent)
+	require.True(t, ok)
+	assert.Contains(t, prompt.Text, "$.temperature: expected number, got string")
+}
+
+func TestGenerateObject_Invalid(t *testing.T) {
+	t.Parallel()
+
+	model := &objectModel{
+		native:    true,
+		responses: []string{"not json", `{"city":"Paris"}`},
+	}
+	_, err := GenerateObject[weather](context.Background(), model,
+		[]MessageContent{TextParts(ChatMessageTypeHuman, "What's the weather in Paris?")},
+		WithObjectMaxRetries(1),
+	)
+	require.ErrorIs(t, err, ErrInvalidObject)
+	assert.Contains(t, err.Error(), `missing required property "temperature"`)
+	assert.Len(t, model.messages, 2)
+}
+
+func TestGenerateObject_Name(t *testing.T) {
+	t.Parallel()
+
+	model := &objectModel{native: true, responses: []string{`["a","b"]`}}
+	obj, err := GenerateObject[[]string](context.Background(), model,
+		[]MessageContent{TextParts(ChatMessageTypeHuman, "Give me two letters")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, obj)
+	assert.Equal(t, "response", model.options[0].ResponseSchema.Name)
+}
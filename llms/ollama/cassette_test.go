@@ -0,0 +1,34 @@
+package ollama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/httputil"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateContentCassette(t *testing.T) {
+	t.Parallel()
+
+	recorder, err := httputil.NewRecorder("testdata/chat.json", httputil.WithMode(httputil.ModeReplay))
+	require.NoError(t, err)
+	llm, err := New(
+		WithModel("llama3.2"),
+		WithServerURL("http://localhost:11434"),
+		WithHTTPClient(recorder.Client()),
+	)
+	require.NoError(t, err)
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What is the capital of France?"),
+	}, llms.WithTemperature(0))
+	require.NoError(t, err)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "The capital of France is Paris.", resp.Choices[0].Content)
+	require.NotNil(t, resp.Usage)
+	assert.Equal(t, 33, resp.Usage.InputTokens)
+	assert.Equal(t, 8, resp.Usage.OutputTokens)
+}
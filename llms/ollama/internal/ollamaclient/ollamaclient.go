@@ -13,6 +13,8 @@ import (
 	"os"
 	"runtime"
 	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 type Client struct {
@@ -33,7 +35,7 @@ func checkError(resp *http.Response, body []byte) error {
 		apiError.ErrorMessage = string(body)
 	}
 
-	return apiError
+	return llms.NewError("ollama", resp.StatusCode, apiError)
 }
 
 func NewClient(ourl *url.URL, ohttp *http.Client) (*Client, error) {
@@ -163,16 +165,16 @@ func (c *Client) stream(ctx context.Context, method, path string, data any, fn f
 			return err
 		}
 
-		if errorResponse.Error != "" {
-			return fmt.Errorf(errorResponse.Error) //nolint
-		}
-
 		if response.StatusCode >= http.StatusBadRequest {
-			return StatusError{
+			return llms.NewError("ollama", response.StatusCode, StatusError{
 				StatusCode:   response.StatusCode,
 				Status:       response.Status,
 				ErrorMessage: errorResponse.Error,
-			}
+			})
+		}
+
+		if errorResponse.Error != "" {
+			return llms.NewError("ollama", response.StatusCode, fmt.Errorf(errorResponse.Error)) //nolint
 		}
 
 		if err := fn(bts); err != nil {
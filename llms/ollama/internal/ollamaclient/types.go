@@ -1,6 +1,7 @@
 package ollamaclient
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"time"
@@ -41,17 +42,48 @@ type GenerateRequest struct {
 type ImageData []byte
 
 type Message struct {
-	Role    string      `json:"role"` // one of ["system", "user", "assistant"]
-	Content string      `json:"content"`
-	Images  []ImageData `json:"images,omitempty"`
+	Role      string      `json:"role"` // one of ["system", "user", "assistant", "tool"]
+	Content   string      `json:"content"`
+	Images    []ImageData `json:"images,omitempty"`
+	ToolCalls []ToolCall  `json:"tool_calls,omitempty"`
+	// ToolName is the name of the tool whose result a "tool" message holds.
+	ToolName string `json:"tool_name,omitempty"`
+}
+
+// ToolCall is a call to a tool requested by the model.
+type ToolCall struct {
+	ID       string           `json:"id,omitempty"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction is the function called by a ToolCall.
+type ToolCallFunction struct {
+	Index     int            `json:"index,omitempty"`
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// Tool is a tool the model may call.
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction is the definition of a function tool.
+type ToolFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
 }
 
 type ChatRequest struct {
-	Model     string     `json:"model"`
-	Messages  []*Message `json:"messages"`
-	Stream    bool       `json:"stream,omitempty"`
-	Format    string     `json:"format"`
-	KeepAlive string     `json:"keep_alive,omitempty"`
+	Model    string     `json:"model"`
+	Messages []*Message `json:"messages"`
+	Stream   bool       `json:"stream,omitempty"`
+	// Format is either the string "json" or the JSON schema of the response.
+	Format    json.RawMessage `json:"format,omitempty"`
+	KeepAlive string          `json:"keep_alive,omitempty"`
+	Tools     []Tool          `json:"tools,omitempty"`
 
 	Options Options `json:"options"`
 }
@@ -2,7 +2,9 @@ package ollama
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
 	"github.com/IT-Tech-Company/langchaingo/llms"
@@ -21,7 +23,12 @@ type LLM struct {
 	options          options
 }
 
-var _ llms.Model = (*LLM)(nil)
+var (
+	_ llms.Model          = (*LLM)(nil)
+	_ llms.StreamingModel = (*LLM)(nil)
+
+	_ llms.StructuredOutputModel = (*LLM)(nil)
+)
 
 // New creates a new ollama LLM implementation.
 func New(opts ...Option) (*LLM, error) {
@@ -46,10 +53,6 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 // GenerateContent implements the Model interface.
 // nolint: goerr113
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { // nolint: lll, cyclop, funlen
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := llms.CallOptions{}
 	for _, opt := range options {
 		opt(&opts)
@@ -61,6 +64,11 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		model = opts.Model
 	}
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "ollama", callbacks.WithRunModel(model))
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
 	// Our input is a sequence of MessageContent, each of which potentially has
 	// a sequence of Part that could be text, images etc.
 	// We have to convert it to a format Ollama undestands: ChatRequest, which
@@ -75,6 +83,8 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		var text string
 		foundText := false
 		var images []ollamaclient.ImageData
+		var toolCalls []ollamaclient.ToolCall
+		var toolResults []*ollamaclient.Message
 
 		for _, p := range mc.Parts {
 			switch pt := p.(type) {
@@ -86,19 +96,39 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 				text = pt.Text
 			case llms.BinaryContent:
 				images = append(images, ollamaclient.ImageData(pt.Data))
+			case llms.ToolCall:
+				toolCall, err := convertToolCall(pt)
+				if err != nil {
+					return nil, err
+				}
+				toolCalls = append(toolCalls, toolCall)
+			case llms.ToolCallResponse:
+				// Ollama expects a separate message for each tool result.
+				toolResults = append(toolResults, &ollamaclient.Message{
+					Role:     "tool",
+					Content:  pt.Content,
+					ToolName: pt.Name,
+				})
 			default:
-				return nil, errors.New("only support Text and BinaryContent parts right now")
+				return nil, errors.New("only support Text, BinaryContent, ToolCall and ToolCallResponse parts right now")
 			}
 		}
 
+		if len(toolResults) > 0 && !foundText && len(images) == 0 {
+			chatMsgs = append(chatMsgs, toolResults...)
+			continue
+		}
+
 		msg.Content = text
 		msg.Images = images
+		msg.ToolCalls = toolCalls
 		chatMsgs = append(chatMsgs, msg)
+		chatMsgs = append(chatMsgs, toolResults...)
 	}
 
-	format := o.options.format
-	if opts.JSONMode {
-		format = "json"
+	format, err := responseFormat(o.options.format, &opts)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get our ollamaOptions from llms.CallOptions
@@ -108,7 +138,8 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		Format:   format,
 		Messages: chatMsgs,
 		Options:  ollamaOptions,
-		Stream:   opts.StreamingFunc != nil,
+		Stream:   opts.StreamingFunc != nil || opts.StreamingEventFunc != nil,
+		Tools:    convertTools(opts.Tools),
 	}
 
 	keepAlive := o.options.keepAlive
@@ -118,6 +149,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 
 	var fn ollamaclient.ChatResponseFunc
 	streamedResponse := ""
+	var toolCalls []ollamaclient.ToolCall
 	var resp ollamaclient.ChatResponse
 
 	fn = func(response ollamaclient.ChatResponse) error {
@@ -126,20 +158,42 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 				return err
 			}
 		}
+		if opts.StreamingEventFunc != nil && response.Message != nil && response.Message.Content != "" {
+			err := opts.StreamingEventFunc(ctx, llms.StreamEvent{
+				Type:  llms.StreamEventTextDelta,
+				Delta: response.Message.Content,
+			})
+			if err != nil {
+				return err
+			}
+		}
 		if response.Message != nil {
 			streamedResponse += response.Message.Content
+			toolCalls = append(toolCalls, response.Message.ToolCalls...)
+			if opts.StreamingEventFunc != nil {
+				for _, toolCall := range response.Message.ToolCalls {
+					llmToolCall, err := convertOllamaToolCall(toolCall)
+					if err != nil {
+						return err
+					}
+					if err := llms.SendToolCallEvents(ctx, opts.StreamingEventFunc, llmToolCall); err != nil {
+						return err
+					}
+				}
+			}
 		}
 		if !req.Stream || response.Done {
 			resp = response
 			resp.Message = &ollamaclient.Message{
-				Role:    "assistant",
-				Content: streamedResponse,
+				Role:      "assistant",
+				Content:   streamedResponse,
+				ToolCalls: toolCalls,
 			}
 		}
 		return nil
 	}
 
-	err := o.client.GenerateChat(ctx, req, fn)
+	err = o.client.GenerateChat(ctx, req, fn)
 	if err != nil {
 		if o.CallbacksHandler != nil {
 			o.CallbacksHandler.HandleLLMError(ctx, err)
@@ -157,8 +211,21 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 			},
 		},
 	}
+	for _, toolCall := range resp.Message.ToolCalls {
+		llmToolCall, err := convertOllamaToolCall(toolCall)
+		if err != nil {
+			return nil, err
+		}
+		choices[0].ToolCalls = append(choices[0].ToolCalls, llmToolCall)
+	}
 
-	response := &llms.ContentResponse{Choices: choices}
+	response := &llms.ContentResponse{
+		Choices: choices,
+		Usage: &llms.Usage{
+			InputTokens:  resp.PromptEvalCount,
+			OutputTokens: resp.EvalCount,
+		},
+	}
 
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
@@ -167,6 +234,11 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	return response, nil
 }
 
+// GenerateContentStream implements the llms.StreamingModel interface.
+func (o *LLM) GenerateContentStream(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) llms.StreamSeq {
+	return llms.StreamContentEvents(ctx, o, messages, options...)
+}
+
 func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float32, error) {
 	embeddings := [][]float32{}
 
@@ -198,6 +270,80 @@ func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]flo
 	return embeddings, nil
 }
 
+// responseFormat returns the format of the response: the JSON schema given
+// with llms.WithResponseSchema, "json" in JSON mode, or the format set with
+// WithFormat.
+func responseFormat(format string, opts *llms.CallOptions) (json.RawMessage, error) {
+	switch {
+	case opts.ResponseSchema != nil:
+		return json.Marshal(opts.ResponseSchema.Schema)
+	case opts.JSONMode:
+		format = "json"
+	case format == "":
+		return nil, nil
+	}
+	return json.Marshal(format)
+}
+
+// SupportsResponseSchema implements the llms.StructuredOutputModel interface.
+func (o *LLM) SupportsResponseSchema() bool {
+	return true
+}
+
+func convertTools(tools []llms.Tool) []ollamaclient.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	out := make([]ollamaclient.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if tool.Function == nil {
+			continue
+		}
+		out = append(out, ollamaclient.Tool{
+			Type: "function",
+			Function: ollamaclient.ToolFunction{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				Parameters:  tool.Function.Parameters,
+			},
+		})
+	}
+	return out
+}
+
+func convertToolCall(toolCall llms.ToolCall) (ollamaclient.ToolCall, error) {
+	out := ollamaclient.ToolCall{ID: toolCall.ID}
+	if toolCall.FunctionCall == nil {
+		return out, nil
+	}
+	out.Function.Name = toolCall.FunctionCall.Name
+	out.Function.Arguments = map[string]any{}
+	if toolCall.FunctionCall.Arguments != "" {
+		if err := json.Unmarshal([]byte(toolCall.FunctionCall.Arguments), &out.Function.Arguments); err != nil {
+			return out, fmt.Errorf("invalid tool call arguments: %w", err)
+		}
+	}
+	return out, nil
+}
+
+func convertOllamaToolCall(toolCall ollamaclient.ToolCall) (llms.ToolCall, error) {
+	if toolCall.Function.Arguments == nil {
+		toolCall.Function.Arguments = map[string]any{}
+	}
+	arguments, err := json.Marshal(toolCall.Function.Arguments)
+	if err != nil {
+		return llms.ToolCall{}, err
+	}
+	return llms.ToolCall{
+		ID:   toolCall.ID,
+		Type: "function",
+		FunctionCall: &llms.FunctionCall{
+			Name:      toolCall.Function.Name,
+			Arguments: string(arguments),
+		},
+	}, nil
+}
+
 func typeToRole(typ llms.ChatMessageType) string {
 	switch typ {
 	case llms.ChatMessageTypeSystem:
@@ -0,0 +1,31 @@
+package openai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/httputil"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateContentCassette(t *testing.T) {
+	t.Parallel()
+
+	recorder, err := httputil.NewRecorder("testdata/chat_completion.json", httputil.WithMode(httputil.ModeReplay))
+	require.NoError(t, err)
+	llm, err := New(WithToken("sk-test"), WithModel("gpt-4o-mini"), WithHTTPClient(recorder.Client()))
+	require.NoError(t, err)
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What is the capital of France?"),
+	}, llms.WithTemperature(0))
+	require.NoError(t, err)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "Paris is the capital of France.", resp.Choices[0].Content)
+	assert.Equal(t, "stop", resp.Choices[0].StopReason)
+	require.NotNil(t, resp.Usage)
+	assert.Equal(t, 14, resp.Usage.InputTokens)
+	assert.Equal(t, 7, resp.Usage.OutputTokens)
+}
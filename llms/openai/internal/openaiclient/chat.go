@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
 	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
@@ -71,6 +72,10 @@ type ChatRequest struct {
 	// Return an error to stop streaming early.
 	StreamingReasoningFunc func(ctx context.Context, reasoningChunk, chunk []byte) error `json:"-"`
 
+	// StreamingEventFunc is a function to be called for each structured event of a streaming response.
+	// Return an error to stop streaming early.
+	StreamingEventFunc func(ctx context.Context, event llms.StreamEvent) error `json:"-"`
+
 	// Deprecated: use Tools instead.
 	Functions []FunctionDefinition `json:"functions,omitempty"`
 	// Deprecated: use ToolChoice instead.
@@ -124,9 +129,26 @@ type ResponseFormatJSONSchemaProperty struct {
 }
 
 type ResponseFormatJSONSchema struct {
-	Name   string                            `json:"name"`
-	Strict bool                              `json:"strict"`
-	Schema *ResponseFormatJSONSchemaProperty `json:"schema"`
+	Name        string                            `json:"name"`
+	Description string                            `json:"description,omitempty"`
+	Strict      bool                              `json:"strict"`
+	Schema      *ResponseFormatJSONSchemaProperty `json:"schema"`
+	// Definition is sent as the schema instead of Schema when set.
+	Definition *jsonschema.Definition `json:"-"`
+}
+
+func (s ResponseFormatJSONSchema) MarshalJSON() ([]byte, error) {
+	type alias ResponseFormatJSONSchema
+	if s.Definition == nil {
+		return json.Marshal(alias(s))
+	}
+	return json.Marshal(struct {
+		alias
+		Schema *jsonschema.Definition `json:"schema"`
+	}{
+		alias:  alias(s),
+		Schema: s.Definition,
+	})
 }
 
 // ResponseFormat is the format of the response.
@@ -302,6 +324,9 @@ type ChatUsage struct {
 	CompletionTokensDetails struct {
 		ReasoningTokens int `json:"reasoning_tokens"`
 	} `json:"completion_tokens_details"`
+	PromptTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
 }
 
 // ChatCompletionResponse is a response to a chat request.
@@ -323,6 +348,9 @@ type Usage struct {
 	CompletionTokensDetails struct {
 		ReasoningTokens int `json:"reasoning_tokens"`
 	} `json:"completion_tokens_details"`
+	PromptTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
 }
 
 // StreamedChatResponsePayload is a chunk from the stream.
@@ -384,8 +412,12 @@ type FunctionCall struct {
 	Arguments string `json:"arguments"`
 }
 
+func (r *ChatRequest) isStreaming() bool {
+	return r.StreamingFunc != nil || r.StreamingReasoningFunc != nil || r.StreamingEventFunc != nil
+}
+
 func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCompletionResponse, error) {
-	if payload.StreamingFunc != nil || payload.StreamingReasoningFunc != nil {
+	if payload.isStreaming() {
 		payload.Stream = true
 		if payload.StreamOptions == nil {
 			payload.StreamOptions = &StreamOptions{IncludeUsage: true}
@@ -415,18 +447,9 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCom
 	defer r.Body.Close()
 
 	if r.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
-
-		// No need to check the error here: if it fails, we'll just return the
-		// status code.
-		var errResp errorMessage
-		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
-		}
-
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		return nil, decodeError(r)
 	}
-	if payload.StreamingFunc != nil || payload.StreamingReasoningFunc != nil {
+	if payload.isStreaming() {
 		return parseStreamingChatResponse(ctx, r, payload)
 	}
 	// Parse response
@@ -491,6 +514,7 @@ func combineStreamingChatResponse(
 			response.Usage.PromptTokens = streamResponse.Usage.PromptTokens
 			response.Usage.TotalTokens = streamResponse.Usage.TotalTokens
 			response.Usage.CompletionTokensDetails.ReasoningTokens = streamResponse.Usage.CompletionTokensDetails.ReasoningTokens
+			response.Usage.PromptTokensDetails.CachedTokens = streamResponse.Usage.PromptTokensDetails.CachedTokens
 		}
 
 		if len(streamResponse.Choices) == 0 {
@@ -508,8 +532,21 @@ func combineStreamingChatResponse(
 		}
 
 		if len(choice.Delta.ToolCalls) > 0 {
-			chunk, response.Choices[0].Message.ToolCalls = updateToolCalls(response.Choices[0].Message.ToolCalls,
-				choice.Delta.ToolCalls)
+			chunk, _ = json.Marshal(choice.Delta.ToolCalls) // nolint:errchkjson
+			// Apply the deltas one at a time, so that the events can tell new
+			// tool calls apart from argument chunks of the current one.
+			for _, delta := range choice.Delta.ToolCalls {
+				tools := response.Choices[0].Message.ToolCalls
+				_, response.Choices[0].Message.ToolCalls = updateToolCalls(tools, []*ToolCall{delta})
+				if err := sendToolCallEvents(ctx, payload, len(tools), response.Choices[0].Message.ToolCalls,
+					delta); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := sendTextEvents(ctx, payload, choice.Delta.ReasoningContent, choice.Delta.Content); err != nil {
+			return nil, err
 		}
 
 		if payload.StreamingFunc != nil {
@@ -525,9 +562,94 @@ func combineStreamingChatResponse(
 			}
 		}
 	}
+
+	if tools := response.Choices[0].Message.ToolCalls; payload.StreamingEventFunc != nil && len(tools) > 0 {
+		err := payload.StreamingEventFunc(ctx, llms.StreamEvent{
+			Type:     llms.StreamEventToolCallEnd,
+			ToolCall: tools[len(tools)-1].toLLMToolCall(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("streaming event func returned an error: %w", err)
+		}
+	}
 	return &response, nil
 }
 
+// sendTextEvents sends the reasoning and text deltas of a chunk to the
+// StreamingEventFunc of the request.
+func sendTextEvents(ctx context.Context, payload *ChatRequest, reasoning, content string) error {
+	if payload.StreamingEventFunc == nil {
+		return nil
+	}
+	if reasoning != "" {
+		err := payload.StreamingEventFunc(ctx, llms.StreamEvent{Type: llms.StreamEventReasoningDelta, Delta: reasoning})
+		if err != nil {
+			return fmt.Errorf("streaming event func returned an error: %w", err)
+		}
+	}
+	if content != "" {
+		err := payload.StreamingEventFunc(ctx, llms.StreamEvent{Type: llms.StreamEventTextDelta, Delta: content})
+		if err != nil {
+			return fmt.Errorf("streaming event func returned an error: %w", err)
+		}
+	}
+	return nil
+}
+
+// sendToolCallEvents sends the events for a tool call delta to the
+// StreamingEventFunc of the request. prev is the number of tool calls before
+// the delta was applied, tools the tool calls after.
+func sendToolCallEvents(ctx context.Context, payload *ChatRequest, prev int, tools []ToolCall, delta *ToolCall) error {
+	if payload.StreamingEventFunc == nil || len(tools) == 0 {
+		return nil
+	}
+
+	var events []llms.StreamEvent
+	current := tools[len(tools)-1]
+	if len(tools) > prev {
+		// A new tool call started, which ends the previous one.
+		if prev > 0 {
+			events = append(events, llms.StreamEvent{
+				Type:     llms.StreamEventToolCallEnd,
+				ToolCall: tools[prev-1].toLLMToolCall(),
+			})
+		}
+		events = append(events, llms.StreamEvent{
+			Type: llms.StreamEventToolCallStart,
+			ToolCall: &llms.ToolCall{
+				ID:           current.ID,
+				Type:         string(current.Type),
+				FunctionCall: &llms.FunctionCall{Name: current.Function.Name},
+			},
+		})
+	}
+	if delta.Function.Arguments != "" {
+		events = append(events, llms.StreamEvent{
+			Type:     llms.StreamEventToolCallDelta,
+			Delta:    delta.Function.Arguments,
+			ToolCall: &llms.ToolCall{ID: current.ID, Type: string(current.Type)},
+		})
+	}
+
+	for _, event := range events {
+		if err := payload.StreamingEventFunc(ctx, event); err != nil {
+			return fmt.Errorf("streaming event func returned an error: %w", err)
+		}
+	}
+	return nil
+}
+
+func (t ToolCall) toLLMToolCall() *llms.ToolCall {
+	return &llms.ToolCall{
+		ID:   t.ID,
+		Type: string(t.Type),
+		FunctionCall: &llms.FunctionCall{
+			Name:      t.Function.Name,
+			Arguments: t.Function.Arguments,
+		},
+	}
+}
+
 func updateFunctionCall(message ChatMessage, functionCall *FunctionCall) []byte {
 	if message.FunctionCall == nil {
 		message.FunctionCall = functionCall
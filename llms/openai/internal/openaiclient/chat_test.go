@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/IT-Tech-Company/langchaingo/llms"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -56,6 +57,33 @@ func TestParseStreamingChatResponse_ReasoningContent(t *testing.T) {
 	assert.Equal(t, FinishReason("stop"), resp.Choices[0].FinishReason)
 }
 
+func TestParseStreamingChatResponse_Usage(t *testing.T) {
+	t.Parallel()
+	mockBody := `data: {"choices":[{"index":0,"delta":{"role":"assistant","content":"hello"},"finish_reason":"stop"}]}
+
+data: {"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":7,"total_tokens":19,"prompt_tokens_details":{"cached_tokens":8},"completion_tokens_details":{"reasoning_tokens":3}}}
+
+data: [DONE]`
+	r := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(mockBody)),
+	}
+
+	req := &ChatRequest{
+		StreamingFunc: func(_ context.Context, _ []byte) error {
+			return nil
+		},
+	}
+
+	resp, err := parseStreamingChatResponse(context.Background(), r, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, 12, resp.Usage.PromptTokens)
+	assert.Equal(t, 7, resp.Usage.CompletionTokens)
+	assert.Equal(t, 8, resp.Usage.PromptTokensDetails.CachedTokens)
+	assert.Equal(t, 3, resp.Usage.CompletionTokensDetails.ReasoningTokens)
+}
+
 func TestParseStreamingChatResponse_ReasoningFunc(t *testing.T) {
 	t.Parallel()
 	mockBody := `
@@ -83,6 +111,59 @@ data: {"id":"fa7e4fc5-a05d-4e7b-9a66-a2dd89e91a4e","object":"chat.completion.chu
 	assert.Equal(t, FinishReason(""), resp.Choices[0].FinishReason)
 }
 
+func TestParseStreamingChatResponse_Events(t *testing.T) {
+	t.Parallel()
+	mockBody := `data: {"choices":[{"index":0,"delta":{"role":"assistant","content":"Let me check."}}]}
+
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"search","arguments":""}}]}}]}
+
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"q\":"}}]}}]}
+
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"go\"}"}}]}}]}
+
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"time","arguments":"{}"}}]},"finish_reason":"tool_calls"}]}
+
+data: [DONE]`
+	r := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(mockBody)),
+	}
+
+	var events []llms.StreamEvent
+	req := &ChatRequest{
+		StreamingEventFunc: func(_ context.Context, event llms.StreamEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	}
+
+	resp, err := parseStreamingChatResponse(context.Background(), r, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Choices[0].Message.ToolCalls, 2)
+
+	types := make([]llms.StreamEventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []llms.StreamEventType{
+		llms.StreamEventTextDelta,
+		llms.StreamEventToolCallStart,
+		llms.StreamEventToolCallDelta,
+		llms.StreamEventToolCallDelta,
+		llms.StreamEventToolCallEnd,
+		llms.StreamEventToolCallStart,
+		llms.StreamEventToolCallDelta,
+		llms.StreamEventToolCallEnd,
+	}, types)
+	assert.Equal(t, "Let me check.", events[0].Delta)
+	assert.Equal(t, "search", events[1].ToolCall.FunctionCall.Name)
+	assert.Equal(t, "call_1", events[2].ToolCall.ID)
+	assert.Equal(t, `{"q":`, events[2].Delta)
+	assert.Equal(t, `{"q":"go"}`, events[4].ToolCall.FunctionCall.Arguments)
+	assert.Equal(t, "call_2", events[7].ToolCall.ID)
+	assert.Equal(t, `{}`, events[7].ToolCall.FunctionCall.Arguments)
+}
+
 func TestChatMessage_MarshalUnmarshal(t *testing.T) {
 	t.Parallel()
 	msg := ChatMessage{
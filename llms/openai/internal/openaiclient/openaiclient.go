@@ -2,11 +2,13 @@ package openaiclient
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
 )
 
 const (
@@ -84,27 +86,10 @@ type Completion struct {
 	Text string `json:"text"`
 }
 
-// CreateCompletion creates a completion.
+// CreateCompletion creates a completion. Failed requests aren't retried, see
+// the llms/retry package.
 func (c *Client) CreateCompletion(ctx context.Context, r *CompletionRequest) (*Completion, error) {
-	var (
-		resp *ChatCompletionResponse
-		err  error
-	)
-	for i := 1; i < 5; i++ {
-		resp, err = c.createCompletion(ctx, r)
-		if err == nil {
-			break
-		}
-
-		if strings.Contains(err.Error(), "429") {
-			// too many requests. wait and retry
-			time.Sleep(time.Duration(5*i) * time.Second)
-			continue
-		}
-
-		return nil, err
-	}
-
+	resp, err := c.createCompletion(ctx, r)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +133,8 @@ func (c *Client) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) ([][]
 	return embeddings, nil
 }
 
-// CreateChat creates chat request.
+// CreateChat creates chat request. Failed requests aren't retried, see the
+// llms/retry package.
 func (c *Client) CreateChat(ctx context.Context, r *ChatRequest) (*ChatCompletionResponse, error) {
 	if r.Model == "" {
 		if c.Model == "" {
@@ -158,24 +144,7 @@ func (c *Client) CreateChat(ctx context.Context, r *ChatRequest) (*ChatCompletio
 		}
 	}
 
-	var (
-		resp *ChatCompletionResponse
-		err  error
-	)
-	for i := 1; i < 5; i++ {
-		resp, err = c.createChat(ctx, r)
-		if err == nil {
-			break
-		}
-
-		if strings.Contains(err.Error(), "429") {
-			// too many requests. wait and retry
-			time.Sleep(time.Duration(5*i) * time.Second)
-			continue
-		}
-
-		return nil, err
-	}
+	resp, err := c.createChat(ctx, r)
 	if err != nil {
 		return nil, err
 	}
@@ -186,6 +155,27 @@ func (c *Client) CreateChat(ctx context.Context, r *ChatRequest) (*ChatCompletio
 	return resp, nil
 }
 
+// decodeError creates an error from an unsuccessful API response. The error
+// is classified with the llms error kinds, so that callers can tell e.g.
+// rate limits apart from invalid requests.
+func decodeError(r *http.Response) error {
+	msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
+
+	// No need to check the error here: if it fails, we'll just return the
+	// status code.
+	var errResp errorMessage
+	var err error
+	if decodeErr := json.NewDecoder(r.Body).Decode(&errResp); decodeErr != nil {
+		err = errors.New(msg) // nolint:goerr113
+	} else {
+		err = fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+	}
+
+	apiErr := llms.NewError("openai", r.StatusCode, err)
+	apiErr.RetryAfter = llms.ParseRetryAfter(r.Header)
+	return apiErr
+}
+
 func IsAzure(apiType APIType) bool {
 	return apiType == APITypeAzure || apiType == APITypeAzureAD
 }
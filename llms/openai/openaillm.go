@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
 	"github.com/IT-Tech-Company/langchaingo/llms"
 	"github.com/IT-Tech-Company/langchaingo/llms/openai/internal/openaiclient"
 )
@@ -24,9 +25,15 @@ const (
 	RoleTool      = "tool"
 )
 
-var _ llms.Model = (*LLM)(nil)
+var (
+	_ llms.Model          = (*LLM)(nil)
+	_ llms.StreamingModel = (*LLM)(nil)
 
-// New returns a new OpenAI LLM.
+	_ llms.StructuredOutputModel = (*LLM)(nil)
+)
+
+// New returns a new OpenAI LLM. Rate limited requests aren't retried, wrap
+// the LLM with the llms/retry package to retry them.
 func New(opts ...Option) (*LLM, error) {
 	opt, c, err := newClient(opts...)
 	if err != nil {
@@ -45,15 +52,20 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 
 // GenerateContent implements the Model interface.
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint: lll, cyclop, goerr113, funlen
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := llms.CallOptions{}
 	for _, opt := range options {
 		opt(&opts)
 	}
 
+	model := opts.Model
+	if model == "" {
+		model = o.client.Model
+	}
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "openai", callbacks.WithRunModel(model))
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
 	chatMsgs := make([]*ChatMessage, 0, len(messages))
 	for _, mc := range messages {
 		msg := &ChatMessage{MultiContent: mc.Parts}
@@ -102,6 +114,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		Messages:               chatMsgs,
 		StreamingFunc:          opts.StreamingFunc,
 		StreamingReasoningFunc: opts.StreamingReasoningFunc,
+		StreamingEventFunc:     opts.StreamingEventFunc,
 		Temperature:            opts.Temperature,
 		N:                      opts.N,
 		FrequencyPenalty:       opts.FrequencyPenalty,
@@ -120,12 +133,16 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 
 	// since req.Functions is deprecated, we need to use the new Tools API.
 	for _, fn := range opts.Functions {
+		parameters, err := functionParameters(fn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert llms function to openai tool: %w", err)
+		}
 		req.Tools = append(req.Tools, openaiclient.Tool{
 			Type: "function",
 			Function: openaiclient.FunctionDefinition{
 				Name:        fn.Name,
 				Description: fn.Description,
-				Parameters:  fn.Parameters,
+				Parameters:  parameters,
 				Strict:      fn.Strict,
 			},
 		})
@@ -143,6 +160,9 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	if o.client.ResponseFormat != nil {
 		req.ResponseFormat = o.client.ResponseFormat
 	}
+	if opts.ResponseSchema != nil {
+		req.ResponseFormat = responseFormatFromSchema(opts.ResponseSchema)
+	}
 
 	result, err := o.client.CreateChat(ctx, req)
 	if err != nil {
@@ -187,13 +207,27 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 			choices[i].FuncCall = choices[i].ToolCalls[0].FunctionCall
 		}
 	}
-	response := &llms.ContentResponse{Choices: choices, Provider: result.Provider}
+	response := &llms.ContentResponse{
+		Choices:  choices,
+		Provider: result.Provider,
+		Usage: &llms.Usage{
+			InputTokens:       result.Usage.PromptTokens,
+			OutputTokens:      result.Usage.CompletionTokens,
+			CachedInputTokens: result.Usage.PromptTokensDetails.CachedTokens,
+			ReasoningTokens:   result.Usage.CompletionTokensDetails.ReasoningTokens,
+		},
+	}
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
 	}
 	return response, nil
 }
 
+// GenerateContentStream implements the llms.StreamingModel interface.
+func (o *LLM) GenerateContentStream(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) llms.StreamSeq {
+	return llms.StreamContentEvents(ctx, o, messages, options...)
+}
+
 // CreateEmbedding creates embeddings for the given input texts.
 func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float32, error) {
 	embeddings, err := o.client.CreateEmbedding(ctx, &openaiclient.EmbeddingRequest{
@@ -238,10 +272,14 @@ func toolFromTool(t llms.Tool) (openaiclient.Tool, error) {
 	}
 	switch t.Type {
 	case string(openaiclient.ToolTypeFunction):
+		parameters, err := functionParameters(*t.Function)
+		if err != nil {
+			return openaiclient.Tool{}, err
+		}
 		tool.Function = openaiclient.FunctionDefinition{
 			Name:        t.Function.Name,
 			Description: t.Function.Description,
-			Parameters:  t.Function.Parameters,
+			Parameters:  parameters,
 			Strict:      t.Function.Strict,
 		}
 	default:
@@ -250,6 +288,48 @@ func toolFromTool(t llms.Tool) (openaiclient.Tool, error) {
 	return tool, nil
 }
 
+// responseFormatFromSchema returns the structured output response format of a
+// response schema. The strict mode is used when the schema supports it.
+func responseFormatFromSchema(schema *llms.ResponseSchema) *ResponseFormat {
+	def := schema.Schema
+	strictDef, err := jsonschema.ToOpenAIStrict(def)
+	strict := err == nil
+	if strict {
+		def = strictDef
+	}
+	return &ResponseFormat{
+		Type: "json_schema",
+		JSONSchema: &ResponseFormatJSONSchema{
+			Name:        schema.Name,
+			Description: schema.Description,
+			Strict:      strict,
+			Definition:  &def,
+		},
+	}
+}
+
+// SupportsResponseSchema implements llms.StructuredOutputModel. The schemas are
+// sent as structured output response formats.
+func (o *LLM) SupportsResponseSchema() bool {
+	return true
+}
+
+// functionParameters returns the parameters of a function. The schemas of
+// strict functions are converted to the subset of JSON Schema supported by the
+// strict mode.
+func functionParameters(fn llms.FunctionDefinition) (any, error) {
+	if !fn.Strict {
+		return fn.Parameters, nil
+	}
+	switch def := fn.Parameters.(type) {
+	case jsonschema.Definition:
+		return jsonschema.ToOpenAIStrict(def)
+	case *jsonschema.Definition:
+		return jsonschema.ToOpenAIStrict(*def)
+	}
+	return fn.Parameters, nil
+}
+
 // toolCallsFromToolCalls converts a slice of llms.ToolCall to a slice of ToolCall.
 func toolCallsFromToolCalls(tcs []llms.ToolCall) []openaiclient.ToolCall {
 	toolCalls := make([]openaiclient.ToolCall, len(tcs))
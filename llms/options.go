@@ -24,6 +24,10 @@ type CallOptions struct {
 	// StreamingReasoningFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming early.
 	StreamingReasoningFunc func(ctx context.Context, reasoningChunk, chunk []byte) error `json:"-"`
+	// StreamingEventFunc is a function to be called for each structured event of a streaming response.
+	// Only models implementing StreamingModel call it; use GenerateContentStream to consume the events.
+	// Return an error to stop streaming early.
+	StreamingEventFunc func(ctx context.Context, event StreamEvent) error `json:"-"`
 	// TopK is the number of tokens to consider for top-k sampling.
 	TopK int `json:"top_k"`
 	// TopP is the cumulative probability for top-p sampling.
@@ -45,6 +49,9 @@ type CallOptions struct {
 
 	// JSONMode is a flag to enable JSON mode.
 	JSONMode bool `json:"json"`
+	// ResponseSchema is the schema of the JSON object to respond with. Only models
+	// implementing StructuredOutputModel support it; see GenerateObject.
+	ResponseSchema *ResponseSchema `json:"response_schema,omitempty"`
 
 	// Tools is a list of tools to use. Each tool can be a specific tool or a function.
 	Tools []Tool `json:"tools,omitempty"`
@@ -176,6 +183,13 @@ func WithStreamingReasoningFunc(streamingReasoningFunc func(ctx context.Context,
 	}
 }
 
+// WithStreamingEventFunc specifies the function receiving structured streaming events.
+func WithStreamingEventFunc(streamingEventFunc func(ctx context.Context, event StreamEvent) error) CallOption {
+	return func(o *CallOptions) {
+		o.StreamingEventFunc = streamingEventFunc
+	}
+}
+
 // WithTopK will add an option to use top-k sampling.
 func WithTopK(topK int) CallOption {
 	return func(o *CallOptions) {
@@ -279,6 +293,13 @@ func WithJSONMode() CallOption {
 	}
 }
 
+// WithResponseSchema will make the model respond with a JSON object matching the schema.
+func WithResponseSchema(schema ResponseSchema) CallOption {
+	return func(o *CallOptions) {
+		o.ResponseSchema = &schema
+	}
+}
+
 // WithMetadata will add an option to set metadata to include in the request.
 // The meaning of this field is specific to the backend in use.
 func WithMetadata(metadata map[string]interface{}) CallOption {
@@ -0,0 +1,17 @@
+// Package ratelimit provides wrappers that keep the calls to a `llms.Model` or an
+// `embeddings.Embedder` within the quotas of a provider: requests per minute, tokens per
+// minute and concurrent calls.
+//
+// The quotas are tracked per model name by a Limiter, which is shared by the wrappers of the
+// models of a provider account:
+//
+//	limiter := ratelimit.NewLimiter(
+//		ratelimit.WithLimits(ratelimit.Limits{RequestsPerMinute: 500, TokensPerMinute: 30000}),
+//		ratelimit.WithModelLimits("gpt-4o", ratelimit.Limits{RequestsPerMinute: 100, MaxInFlight: 4}),
+//	)
+//	llm = ratelimit.New(llm, limiter, ratelimit.WithModelName("gpt-4o-mini"))
+//	embedder = ratelimit.NewEmbedder(embedder, limiter, ratelimit.WithModelName("text-embedding-3-small"))
+//
+// The tokens of a call are estimated before the call with `llms.CountTokens`, and reconciled
+// with the usage reported by the model after it.
+package ratelimit
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/IT-Tech-Company/langchaingo/embeddings"
+)
+
+// Embedder is an embeddings.Embedder wrapper that waits for the limits of a
+// Limiter before each call. Each call counts as one request, so embedders
+// splitting texts in batches should be given limits accordingly.
+type Embedder struct {
+	embedder embeddings.Embedder
+	limiter  *Limiter
+	opts     options
+}
+
+var _ embeddings.Embedder = (*Embedder)(nil)
+
+// NewEmbedder wraps an Embedder, limiting its calls with the limiter. The
+// limits of the embedder are the ones of the name given with WithModelName.
+func NewEmbedder(embedder embeddings.Embedder, limiter *Limiter, opts ...Option) *Embedder {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Embedder{
+		embedder: embedder,
+		limiter:  limiter,
+		opts:     o,
+	}
+}
+
+// EmbedDocuments waits for the limits of the embedder, then embeds the texts.
+func (e *Embedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	reservation, err := e.limiter.Wait(ctx, e.opts.modelName, e.estimateTokens(texts...))
+	if err != nil {
+		return nil, err
+	}
+	// Embedders don't report their usage.
+	defer reservation.Done(-1)
+
+	return e.embedder.EmbedDocuments(ctx, texts)
+}
+
+// EmbedQuery waits for the limits of the embedder, then embeds the text.
+func (e *Embedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	reservation, err := e.limiter.Wait(ctx, e.opts.modelName, e.estimateTokens(text))
+	if err != nil {
+		return nil, err
+	}
+	defer reservation.Done(-1)
+
+	return e.embedder.EmbedQuery(ctx, text)
+}
+
+func (e *Embedder) estimateTokens(texts ...string) int {
+	tokens := 0
+	for _, text := range texts {
+		tokens += e.opts.countTokens(e.opts.modelName, text)
+	}
+	return tokens
+}
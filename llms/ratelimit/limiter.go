@@ -0,0 +1,202 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limits are the limits of the calls to a model. Zero values mean no limit.
+type Limits struct {
+	// RequestsPerMinute is the maximum number of calls per minute.
+	RequestsPerMinute int
+	// TokensPerMinute is the maximum number of tokens per minute, counting
+	// both the input and the output tokens.
+	TokensPerMinute int
+	// MaxInFlight is the maximum number of concurrent calls.
+	MaxInFlight int
+}
+
+// Limiter enforces Limits on the calls to models. The limits are tracked per
+// model name, so a Limiter can be shared by all the wrappers of a provider
+// account, including from several goroutines.
+//
+// The requests and tokens per minute are token buckets: up to a minute of
+// quota can be used at once, and the quota is then refilled continuously.
+type Limiter struct {
+	limits      Limits
+	modelLimits map[string]Limits
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+
+	mu     sync.Mutex
+	states map[string]*state
+}
+
+// NewLimiter creates a Limiter applying the limits given with WithLimits to
+// every model, unless other limits are given for the model with
+// WithModelLimits.
+func NewLimiter(opts ...LimiterOption) *Limiter {
+	l := &Limiter{
+		modelLimits: map[string]Limits{},
+		now:         time.Now,
+		sleep:       sleep,
+		states:      map[string]*state{},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// state holds the quotas of a model.
+type state struct {
+	mu       sync.Mutex
+	requests *bucket
+	tokens   *bucket
+	inFlight chan struct{}
+}
+
+func (l *Limiter) state(model string) *state {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if s, ok := l.states[model]; ok {
+		return s
+	}
+
+	limits, ok := l.modelLimits[model]
+	if !ok {
+		limits = l.limits
+	}
+	now := l.now()
+	s := &state{
+		requests: newBucket(limits.RequestsPerMinute, now),
+		tokens:   newBucket(limits.TokensPerMinute, now),
+	}
+	if limits.MaxInFlight > 0 {
+		s.inFlight = make(chan struct{}, limits.MaxInFlight)
+	}
+	l.states[model] = s
+	return s
+}
+
+// Reservation is the quota taken by a call. Done must be called once the
+// call is over.
+type Reservation struct {
+	limiter   *Limiter
+	state     *state
+	estimated int
+	once      sync.Once
+}
+
+// Wait blocks until a call to the model estimated to use the given number of
+// tokens is allowed by the limits, and takes its quota. It returns the error
+// of the context if it is done first.
+func (l *Limiter) Wait(ctx context.Context, model string, estimatedTokens int) (*Reservation, error) {
+	s := l.state(model)
+
+	if s.inFlight != nil {
+		select {
+		case s.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	for {
+		s.mu.Lock()
+		now := l.now()
+		wait := max(s.requests.wait(1, now), s.tokens.wait(estimatedTokens, now))
+		if wait == 0 {
+			s.requests.take(1, now)
+			s.tokens.take(estimatedTokens, now)
+			s.mu.Unlock()
+			return &Reservation{limiter: l, state: s, estimated: estimatedTokens}, nil
+		}
+		s.mu.Unlock()
+
+		if err := l.sleep(ctx, wait); err != nil {
+			if s.inFlight != nil {
+				<-s.inFlight
+			}
+			return nil, err
+		}
+	}
+}
+
+// Done releases the call from the in-flight calls and reconciles the tokens
+// taken with the tokens the call actually used: unused tokens are given back,
+// and the extra tokens are taken from the quota of the next calls. A negative
+// number means the usage is unknown and keeps the estimate.
+func (r *Reservation) Done(usedTokens int) {
+	r.once.Do(func() {
+		if usedTokens >= 0 && usedTokens != r.estimated {
+			r.state.mu.Lock()
+			r.state.tokens.take(usedTokens-r.estimated, r.limiter.now())
+			r.state.mu.Unlock()
+		}
+		if r.state.inFlight != nil {
+			<-r.state.inFlight
+		}
+	})
+}
+
+// bucket is a token bucket holding a quota per minute. A nil bucket has no
+// limit.
+type bucket struct {
+	capacity  float64
+	available float64
+	last      time.Time
+}
+
+func newBucket(perMinute int, now time.Time) *bucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &bucket{capacity: float64(perMinute), available: float64(perMinute), last: now}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.available = min(b.capacity, b.available+b.capacity*elapsed.Minutes())
+		b.last = now
+	}
+}
+
+// wait returns how long to wait for n units to be available. Requests of more
+// than the capacity wait for a full bucket.
+func (b *bucket) wait(n int, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.refill(now)
+	missing := min(float64(n), b.capacity) - b.available
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(missing / b.capacity * float64(time.Minute)))
+}
+
+// take removes n units from the bucket, which can go into debt; negative
+// values give units back.
+func (b *bucket) take(n int, now time.Time) {
+	if b == nil {
+		return
+	}
+	b.refill(now)
+	b.available = min(b.capacity, b.available-float64(n))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
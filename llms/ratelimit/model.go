@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+)
+
+// Model is an LLM wrapper that waits for the limits of a Limiter before each
+// call.
+type Model struct {
+	llm     llms.Model
+	limiter *Limiter
+	opts    options
+}
+
+// assert that `Model` implements the `llms.StreamingModel` and
+// `llms.StructuredOutputModel` interfaces.
+var (
+	_ llms.StreamingModel        = (*Model)(nil)
+	_ llms.StructuredOutputModel = (*Model)(nil)
+)
+
+// New wraps a Model, limiting its calls with the limiter.
+func New(llm llms.Model, limiter *Limiter, opts ...Option) *Model {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Model{
+		llm:     llm,
+		limiter: limiter,
+		opts:    o,
+	}
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// GenerateContent waits for the limits of the model, then asks the model to
+// generate content from a sequence of messages.
+func (m *Model) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	reservation, err := m.wait(ctx, messages, options)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := m.llm.GenerateContent(ctx, messages, options...)
+	used := -1
+	if response != nil && response.Usage != nil {
+		used = response.Usage.InputTokens + response.Usage.OutputTokens
+	}
+	reservation.Done(used)
+	return response, err
+}
+
+// GenerateContentStream waits for the limits of the model, then streams the
+// response of the model. The model is streamed as by
+// llms.GenerateContentStream, natively if it implements llms.StreamingModel.
+func (m *Model) GenerateContentStream(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) llms.StreamSeq { //nolint:lll
+	streamer, ok := m.llm.(llms.StreamingModel)
+	if !ok {
+		return llms.GenerateContentStream(ctx, contentModel{m}, messages, options...)
+	}
+
+	return func(yield func(llms.StreamEvent, error) bool) {
+		reservation, err := m.wait(ctx, messages, options)
+		if err != nil {
+			yield(llms.StreamEvent{}, err)
+			return
+		}
+
+		used := -1
+		defer func() { reservation.Done(used) }()
+		streamer.GenerateContentStream(ctx, messages, options...)(func(event llms.StreamEvent, err error) bool {
+			if event.Usage != nil {
+				used = event.Usage.InputTokens + event.Usage.OutputTokens
+			}
+			return yield(event, err)
+		})
+	}
+}
+
+// SupportsResponseSchema reports whether the model supports
+// llms.WithResponseSchema.
+func (m *Model) SupportsResponseSchema() bool {
+	s, ok := m.llm.(llms.StructuredOutputModel)
+	return ok && s.SupportsResponseSchema()
+}
+
+// contentModel hides the GenerateContentStream method of a Model, so that
+// llms.GenerateContentStream streams it with GenerateContent.
+type contentModel struct {
+	llms.Model
+}
+
+// wait waits for the limits of the model of the call.
+func (m *Model) wait(ctx context.Context, messages []llms.MessageContent, options []llms.CallOption) (*Reservation, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	model := opts.Model
+	if model == "" {
+		model = m.opts.modelName
+	}
+
+	return m.limiter.Wait(ctx, model, m.estimateTokens(model, messages, opts))
+}
+
+// estimateTokens estimates the number of tokens of a call, counting the text
+// of the messages and the maximum number of output tokens.
+func (m *Model) estimateTokens(model string, messages []llms.MessageContent, opts llms.CallOptions) int {
+	tokens := opts.MaxTokens
+	if tokens == 0 {
+		tokens = m.opts.outputTokens
+	}
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				tokens += m.opts.countTokens(model, text.Text)
+			}
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import "github.com/IT-Tech-Company/langchaingo/llms"
+
+// LimiterOption is a function that configures a Limiter.
+type LimiterOption func(*Limiter)
+
+// WithLimits sets the limits of the models without limits of their own.
+func WithLimits(limits Limits) LimiterOption {
+	return func(l *Limiter) {
+		l.limits = limits
+	}
+}
+
+// WithModelLimits sets the limits of a model, identified by the name given
+// with llms.WithModel or WithModelName.
+func WithModelLimits(model string, limits Limits) LimiterOption {
+	return func(l *Limiter) {
+		l.modelLimits[model] = limits
+	}
+}
+
+type options struct {
+	modelName    string
+	countTokens  func(model, text string) int
+	outputTokens int
+}
+
+func defaultOptions() options {
+	return options{
+		countTokens: llms.CountTokens,
+	}
+}
+
+// Option is a function that configures a Model or an Embedder.
+type Option func(*options)
+
+// WithModelName sets the name of the wrapped model, used to find its limits
+// and to count tokens when the calls don't set the model with llms.WithModel.
+func WithModelName(name string) Option {
+	return func(o *options) {
+		o.modelName = name
+	}
+}
+
+// WithTokenCounter sets the function estimating the number of tokens of a
+// text before a call. Defaults to llms.CountTokens.
+func WithTokenCounter(countTokens func(model, text string) int) Option {
+	return func(o *options) {
+		o.countTokens = countTokens
+	}
+}
+
+// WithOutputTokens sets the number of output tokens expected from calls
+// without llms.WithMaxTokens. The estimate of the tokens of a call counts
+// the tokens of its messages and its maximum number of output tokens; it is
+// reconciled with the usage reported by the model once the call is over.
+func WithOutputTokens(outputTokens int) Option {
+	return func(o *options) {
+		o.outputTokens = outputTokens
+	}
+}
@@ -0,0 +1,271 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/llms/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock whose sleeps advance the time instantly.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	slept time.Duration
+}
+
+func newFakeLimiter(opts ...LimiterOption) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewLimiter(opts...)
+	l.now = func() time.Time {
+		clock.mu.Lock()
+		defer clock.mu.Unlock()
+		return clock.now
+	}
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		clock.mu.Lock()
+		defer clock.mu.Unlock()
+		clock.now = clock.now.Add(d)
+		clock.slept += d
+		return nil
+	}
+	return l, clock
+}
+
+func TestLimiter_RequestsPerMinute(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l, clock := newFakeLimiter(WithLimits(Limits{RequestsPerMinute: 60}))
+
+	for i := 0; i < 60; i++ {
+		r, err := l.Wait(ctx, "model", 0)
+		require.NoError(t, err)
+		r.Done(-1)
+	}
+	assert.Zero(t, clock.slept, "a minute of quota should be available at once")
+
+	r, err := l.Wait(ctx, "model", 0)
+	require.NoError(t, err)
+	r.Done(-1)
+	assert.Equal(t, time.Second, clock.slept)
+}
+
+func TestLimiter_TokensPerMinute(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l, clock := newFakeLimiter(WithLimits(Limits{TokensPerMinute: 1000}))
+
+	r, err := l.Wait(ctx, "model", 600)
+	require.NoError(t, err)
+	// the call used more tokens than estimated, the next calls pay for it.
+	r.Done(900)
+
+	r, err = l.Wait(ctx, "model", 400)
+	require.NoError(t, err)
+	assert.InDelta(t, 18*time.Second, clock.slept, float64(time.Millisecond))
+	// the call used fewer tokens than estimated, they are given back.
+	r.Done(100)
+
+	r, err = l.Wait(ctx, "model", 300)
+	require.NoError(t, err)
+	r.Done(-1)
+	assert.InDelta(t, 18*time.Second, clock.slept, float64(time.Millisecond))
+
+	// calls above the capacity wait for a full bucket.
+	_, err = l.Wait(ctx, "model", 5000)
+	require.NoError(t, err)
+	assert.InDelta(t, 78*time.Second, clock.slept, float64(time.Millisecond))
+}
+
+func TestLimiter_ModelLimits(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l, clock := newFakeLimiter(
+		WithLimits(Limits{RequestsPerMinute: 1}),
+		WithModelLimits("fast", Limits{RequestsPerMinute: 600}),
+	)
+
+	for _, model := range []string{"slow", "other", "fast", "fast"} {
+		r, err := l.Wait(ctx, model, 0)
+		require.NoError(t, err)
+		r.Done(-1)
+	}
+	assert.Zero(t, clock.slept, "models should have separate quotas")
+
+	_, err := l.Wait(ctx, "slow", 0)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, clock.slept)
+}
+
+func TestLimiter_MaxInFlight(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := NewLimiter(WithLimits(Limits{MaxInFlight: 2}))
+
+	var inFlight, maxInFlight atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := l.Wait(ctx, "model", 0)
+			assert.NoError(t, err)
+			n := inFlight.Add(1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+			r.Done(-1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), maxInFlight.Load())
+
+	r, err := l.Wait(ctx, "model", 0)
+	require.NoError(t, err)
+	_, err = l.Wait(ctx, "model", 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = l.Wait(ctx, "model", 0)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	r.Done(-1)
+	r.Done(-1) // Done is idempotent.
+	_, err = l.Wait(context.Background(), "model", 0)
+	require.NoError(t, err)
+}
+
+type usageModel struct {
+	usage *llms.Usage
+	opts  llms.CallOptions
+}
+
+func (m *usageModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *usageModel) GenerateContent(_ context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	for _, opt := range options {
+		opt(&m.opts)
+	}
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: "ok"}},
+		Usage:   m.usage,
+	}, nil
+}
+
+func approximateTokens(_, text string) int {
+	return len(text) / 5
+}
+
+func TestModel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l, clock := newFakeLimiter(WithModelLimits("small", Limits{TokensPerMinute: 100}))
+	inner := &usageModel{usage: &llms.Usage{InputTokens: 20, OutputTokens: 40}}
+	llm := New(inner, l, WithModelName("small"), WithTokenCounter(approximateTokens), WithOutputTokens(30))
+
+	// 10 tokens of input and 30 of output are estimated, 60 are used.
+	_, err := llm.Call(ctx, "01234567890123456789012345678901234567890123456789")
+	require.NoError(t, err)
+	state := l.state("small")
+	assert.InDelta(t, 40, state.tokens.available, 0.001)
+
+	// 10 tokens of input and 50 of output are estimated.
+	_, err = llm.Call(ctx, "01234567890123456789012345678901234567890123456789", llms.WithMaxTokens(50))
+	require.NoError(t, err)
+	assert.Equal(t, 50, inner.opts.MaxTokens)
+	assert.InDelta(t, 12*time.Second, clock.slept, float64(time.Millisecond))
+
+	// other models aren't limited.
+	_, err = llm.Call(ctx, "hello", llms.WithModel("large"), llms.WithMaxTokens(1000))
+	require.NoError(t, err)
+	assert.InDelta(t, 12*time.Second, clock.slept, float64(time.Millisecond))
+}
+
+type countingEmbedder struct {
+	calls int
+}
+
+func (e *countingEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	return make([][]float32, len(texts)), nil
+}
+
+func (e *countingEmbedder) EmbedQuery(_ context.Context, _ string) ([]float32, error) {
+	e.calls++
+	return []float32{}, nil
+}
+
+func TestEmbedder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l, clock := newFakeLimiter(WithLimits(Limits{RequestsPerMinute: 2, TokensPerMinute: 10}))
+	inner := &countingEmbedder{}
+	embedder := NewEmbedder(inner, l, WithModelName("embed"), WithTokenCounter(approximateTokens))
+
+	_, err := embedder.EmbedDocuments(ctx, []string{"0123456789", "0123456789"})
+	require.NoError(t, err)
+	_, err = embedder.EmbedQuery(ctx, "0123456789")
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.calls)
+	assert.Zero(t, clock.slept)
+
+	_, err = embedder.EmbedQuery(ctx, "01234567890123456789")
+	require.NoError(t, err)
+	assert.InDelta(t, 30*time.Second, clock.slept, float64(time.Millisecond))
+}
+
+type schemaModel struct {
+	*fake.ScriptedLLM
+}
+
+func (schemaModel) SupportsResponseSchema() bool { return true }
+
+func TestModel_Capabilities(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := NewLimiter(WithModelLimits("small", Limits{RequestsPerMinute: 10}))
+	reply := fake.TextReply("hello world")
+	reply.Response.Usage = &llms.Usage{InputTokens: 3, OutputTokens: 2}
+	llm := New(schemaModel{fake.NewScriptedLLM(reply)}, l, WithModelName("small"))
+	assert.True(t, llm.SupportsResponseSchema())
+	assert.False(t, New(&usageModel{}, l).SupportsResponseSchema())
+
+	// the events of native streaming models are passed on.
+	var text string
+	var usage *llms.Usage
+	llms.GenerateContentStream(ctx, llm, nil)(func(event llms.StreamEvent, err error) bool {
+		require.NoError(t, err)
+		text += event.Delta
+		if event.Usage != nil {
+			usage = event.Usage
+		}
+		return true
+	})
+	assert.Equal(t, "hello world", text)
+	assert.Equal(t, &llms.Usage{InputTokens: 3, OutputTokens: 2}, usage)
+	assert.InDelta(t, 9, l.state("small").requests.available, 0.001)
+}
@@ -0,0 +1,10 @@
+// Package retry provides a wrapper that makes a `llms.Model` resilient to transient failures.
+// Failed calls are retried with jittered exponential backoff, honouring the Retry-After the
+// provider sent, and once the retries are exhausted the call falls back to an ordered list of
+// alternate models. Errors are classified with the error kinds defined in the llms package,
+// e.g. `llms.ErrRateLimited`.
+//
+// The models themselves don't retry failed calls. In particular the `openai` model no longer
+// retries rate limited requests on its own, as it used to do up to four times: wrap it in a
+// retrier to keep that behaviour.
+package retry
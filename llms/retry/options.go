@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+type options struct {
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	fallbacks      []llms.Model
+	retryIf        func(error) bool
+	fallbackIf     func(error) bool
+}
+
+// Option is a function that configures a Retrier.
+type Option func(*options)
+
+// WithMaxRetries sets how many times a failed call is retried on the same
+// model before falling back to the next one. Defaults to 3; 0 disables
+// retries.
+func WithMaxRetries(maxRetries int) Option {
+	return func(o *options) {
+		o.maxRetries = maxRetries
+	}
+}
+
+// WithBackoff sets the delay before the first retry and the maximum delay
+// between retries. The delay doubles with every attempt and is jittered.
+// Defaults to 500ms and 30s.
+func WithBackoff(initial, maxBackoff time.Duration) Option {
+	return func(o *options) {
+		o.initialBackoff = initial
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithFallbacks sets the models to fall back to, in order, when the wrapped
+// model keeps failing. Fallbacks receive the same messages and call options
+// as the wrapped model, so the model name should be configured on each
+// fallback rather than with llms.WithModel.
+func WithFallbacks(models ...llms.Model) Option {
+	return func(o *options) {
+		o.fallbacks = append(o.fallbacks, models...)
+	}
+}
+
+// WithRetryIf sets the function deciding whether an error is retried on the
+// same model. Defaults to llms.IsRetryable.
+func WithRetryIf(retryIf func(error) bool) Option {
+	return func(o *options) {
+		o.retryIf = retryIf
+	}
+}
+
+// WithFallbackIf sets the function deciding whether the next model is tried
+// after an error. Defaults to llms.IsRetryable.
+func WithFallbackIf(fallbackIf func(error) bool) Option {
+	return func(o *options) {
+		o.fallbackIf = fallbackIf
+	}
+}
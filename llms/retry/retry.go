@@ -0,0 +1,210 @@
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+)
+
+// Retrier is an LLM wrapper that retries failed calls and falls back to
+// alternate models.
+type Retrier struct {
+	models []llms.Model
+	opts   options
+}
+
+// assert that `Retrier` implements the `llms.Model`, `llms.StreamingModel`
+// and `llms.StructuredOutputModel` interfaces.
+var (
+	_ llms.Model                 = (*Retrier)(nil)
+	_ llms.StreamingModel        = (*Retrier)(nil)
+	_ llms.StructuredOutputModel = (*Retrier)(nil)
+)
+
+// New wraps a Model, retrying its transient failures and falling back to the
+// models given with WithFallbacks.
+func New(llm llms.Model, opts ...Option) *Retrier {
+	o := options{
+		maxRetries:     defaultMaxRetries,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		retryIf:        llms.IsRetryable,
+		fallbackIf:     llms.IsRetryable,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Retrier{
+		models: append([]llms.Model{llm}, o.fallbacks...),
+		opts:   o,
+	}
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (r *Retrier) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, r, prompt, options...)
+}
+
+// SupportsResponseSchema reports whether the model and all its fallbacks
+// support llms.WithResponseSchema.
+func (r *Retrier) SupportsResponseSchema() bool {
+	for _, model := range r.models {
+		if m, ok := model.(llms.StructuredOutputModel); !ok || !m.SupportsResponseSchema() {
+			return false
+		}
+	}
+	return true
+}
+
+// GenerateContent asks the model to generate content from a sequence of
+// messages, retrying and falling back as configured.
+//
+// When streaming, a failed call is neither retried nor passed to a fallback
+// once some of its output has been streamed, because the chunks already
+// delivered can't be taken back.
+func (r *Retrier) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	var streamed bool
+	if opts.StreamingFunc != nil {
+		streamingFunc := opts.StreamingFunc
+		options = append(options, llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			streamed = true
+			return streamingFunc(ctx, chunk)
+		}))
+	}
+	if opts.StreamingReasoningFunc != nil {
+		streamingReasoningFunc := opts.StreamingReasoningFunc
+		options = append(options, llms.WithStreamingReasoningFunc(func(ctx context.Context, reasoningChunk, chunk []byte) error { //nolint:lll
+			streamed = true
+			return streamingReasoningFunc(ctx, reasoningChunk, chunk)
+		}))
+	}
+	if opts.StreamingEventFunc != nil {
+		streamingEventFunc := opts.StreamingEventFunc
+		options = append(options, llms.WithStreamingEventFunc(func(ctx context.Context, event llms.StreamEvent) error {
+			streamed = true
+			return streamingEventFunc(ctx, event)
+		}))
+	}
+
+	var err error
+	for _, model := range r.models {
+		var response *llms.ContentResponse
+		for attempt := 0; ; attempt++ {
+			response, err = model.GenerateContent(ctx, messages, options...)
+			if err == nil {
+				return response, nil
+			}
+			if streamed || ctx.Err() != nil {
+				return nil, err
+			}
+			if attempt >= r.opts.maxRetries || !r.opts.retryIf(err) {
+				break
+			}
+			if sleepErr := sleep(ctx, r.backoff(attempt, err)); sleepErr != nil {
+				return nil, sleepErr
+			}
+		}
+
+		if !r.opts.fallbackIf(err) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// GenerateContentStream streams the response of the model, retrying and
+// falling back as configured. The models are streamed as by
+// llms.GenerateContentStream, natively if they implement llms.StreamingModel.
+//
+// Like with GenerateContent, a failed stream is neither retried nor passed to
+// a fallback once some of its events have been yielded.
+func (r *Retrier) GenerateContentStream(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) llms.StreamSeq { //nolint:lll
+	return func(yield func(llms.StreamEvent, error) bool) {
+		var err error
+		for _, model := range r.models {
+			for attempt := 0; ; attempt++ {
+				var streamed, stopped bool
+				err = nil
+				llms.GenerateContentStream(ctx, model, messages, options...)(func(event llms.StreamEvent, eventErr error) bool {
+					if eventErr != nil {
+						err = eventErr
+						return false
+					}
+					streamed = true
+					stopped = !yield(event, nil)
+					return !stopped
+				})
+				if stopped || err == nil {
+					return
+				}
+				if streamed || ctx.Err() != nil {
+					yield(llms.StreamEvent{}, err)
+					return
+				}
+				if attempt >= r.opts.maxRetries || !r.opts.retryIf(err) {
+					break
+				}
+				if sleepErr := sleep(ctx, r.backoff(attempt, err)); sleepErr != nil {
+					yield(llms.StreamEvent{}, sleepErr)
+					return
+				}
+			}
+
+			if !r.opts.fallbackIf(err) {
+				break
+			}
+		}
+		yield(llms.StreamEvent{}, err)
+	}
+}
+
+// backoff returns how long to wait before retrying after the given attempt
+// failed with err.
+func (r *Retrier) backoff(attempt int, err error) time.Duration {
+	backoff := r.opts.initialBackoff
+	for i := 0; i < attempt && backoff < r.opts.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > r.opts.maxBackoff {
+		backoff = r.opts.maxBackoff
+	}
+	// Jitter the backoff to somewhere between half and all of it, so that
+	// concurrent callers don't retry in lockstep.
+	if half := int64(backoff / 2); half > 0 {
+		backoff = time.Duration(half + rand.Int63n(half+1)) // nolint:gosec
+	}
+
+	if retryAfter := llms.RetryAfter(err); retryAfter > backoff {
+		backoff = retryAfter
+	}
+	return backoff
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
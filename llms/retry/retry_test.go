@@ -0,0 +1,251 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockLLM returns the given errors in turn, then the response.
+// not synchronized, don't use concurrently!
+type mockLLM struct {
+	called   int
+	errs     []error
+	response *llms.ContentResponse
+	stream   bool
+}
+
+func (m *mockLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *mockLLM) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	m.called++
+	if m.stream && opts.StreamingFunc != nil {
+		if err := opts.StreamingFunc(ctx, []byte("partial")); err != nil {
+			return nil, err
+		}
+	}
+	if m.stream && opts.StreamingReasoningFunc != nil {
+		if err := opts.StreamingReasoningFunc(ctx, []byte("thinking"), nil); err != nil {
+			return nil, err
+		}
+	}
+	if m.stream && opts.StreamingEventFunc != nil {
+		event := llms.StreamEvent{Type: llms.StreamEventTextDelta, Delta: "partial"}
+		if err := opts.StreamingEventFunc(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	if m.called <= len(m.errs) {
+		return nil, m.errs[m.called-1]
+	}
+	return m.response, nil
+}
+
+func response(content string) *llms.ContentResponse {
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: content}}}
+}
+
+func rateLimited() error {
+	return llms.NewError("test", http.StatusTooManyRequests, errors.New("slow down"))
+}
+
+func TestRetrier_RetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{errs: []error{rateLimited(), rateLimited()}, response: response("ok")}
+	r := New(llm, WithBackoff(time.Millisecond, time.Millisecond))
+
+	resp, err := r.GenerateContent(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Choices[0].Content)
+	assert.Equal(t, 3, llm.called)
+}
+
+func TestRetrier_DoesNotRetryPermanentErrors(t *testing.T) {
+	t.Parallel()
+
+	authErr := llms.NewError("test", http.StatusUnauthorized, errors.New("bad key"))
+	llm := &mockLLM{errs: []error{authErr}, response: response("ok")}
+	fallback := &mockLLM{response: response("fallback")}
+	r := New(llm, WithBackoff(time.Millisecond, time.Millisecond), WithFallbacks(fallback))
+
+	_, err := r.GenerateContent(context.Background(), nil)
+	require.ErrorIs(t, err, llms.ErrAuthentication)
+	assert.Equal(t, 1, llm.called)
+	assert.Equal(t, 0, fallback.called)
+}
+
+func TestRetrier_FallsBack(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{errs: []error{rateLimited(), rateLimited(), rateLimited()}}
+	fallback := &mockLLM{response: response("fallback")}
+	r := New(llm,
+		WithMaxRetries(1),
+		WithBackoff(time.Millisecond, time.Millisecond),
+		WithFallbacks(fallback),
+	)
+
+	resp, err := r.GenerateContent(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", resp.Choices[0].Content)
+	assert.Equal(t, 2, llm.called)
+	assert.Equal(t, 1, fallback.called)
+}
+
+func TestRetrier_AllModelsFail(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{errs: []error{rateLimited()}}
+	fallback := &mockLLM{errs: []error{llms.NewError("test", http.StatusServiceUnavailable, errors.New("down"))}}
+	r := New(llm, WithMaxRetries(0), WithFallbacks(fallback))
+
+	_, err := r.GenerateContent(context.Background(), nil)
+	require.ErrorIs(t, err, llms.ErrOverloaded)
+}
+
+func TestRetrier_CustomConditions(t *testing.T) {
+	t.Parallel()
+
+	errCustom := errors.New("custom")
+	llm := &mockLLM{errs: []error{errCustom, errCustom}}
+	fallback := &mockLLM{response: response("fallback")}
+	isCustom := func(err error) bool { return errors.Is(err, errCustom) }
+	r := New(llm,
+		WithMaxRetries(1),
+		WithBackoff(time.Millisecond, time.Millisecond),
+		WithRetryIf(isCustom),
+		WithFallbackIf(isCustom),
+		WithFallbacks(fallback),
+	)
+
+	resp, err := r.GenerateContent(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", resp.Choices[0].Content)
+	assert.Equal(t, 2, llm.called)
+}
+
+func TestRetrier_NoRetryAfterStreaming(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{errs: []error{rateLimited()}, response: response("ok"), stream: true}
+	fallback := &mockLLM{response: response("fallback")}
+	r := New(llm, WithBackoff(time.Millisecond, time.Millisecond), WithFallbacks(fallback))
+
+	var chunks []string
+	_, err := r.GenerateContent(context.Background(), nil,
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		}))
+	require.ErrorIs(t, err, llms.ErrRateLimited)
+	assert.Equal(t, []string{"partial"}, chunks)
+	assert.Equal(t, 1, llm.called)
+	assert.Equal(t, 0, fallback.called)
+}
+
+func TestRetrier_NoRetryAfterStreamingReasoningOrEvents(t *testing.T) {
+	t.Parallel()
+
+	options := map[string]llms.CallOption{
+		"reasoning": llms.WithStreamingReasoningFunc(func(context.Context, []byte, []byte) error { return nil }),
+		"events":    llms.WithStreamingEventFunc(func(context.Context, llms.StreamEvent) error { return nil }),
+	}
+	for name, option := range options {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			llm := &mockLLM{errs: []error{rateLimited()}, response: response("ok"), stream: true}
+			fallback := &mockLLM{response: response("fallback")}
+			r := New(llm, WithBackoff(time.Millisecond, time.Millisecond), WithFallbacks(fallback))
+
+			_, err := r.GenerateContent(context.Background(), nil, option)
+			require.ErrorIs(t, err, llms.ErrRateLimited)
+			assert.Equal(t, 1, llm.called)
+			assert.Equal(t, 0, fallback.called)
+		})
+	}
+}
+
+func TestRetrier_GenerateContentStream(t *testing.T) {
+	t.Parallel()
+
+	collect := func(seq llms.StreamSeq) (string, error) {
+		var (
+			text    string
+			lastErr error
+		)
+		seq(func(event llms.StreamEvent, err error) bool {
+			lastErr = err
+			if event.Type == llms.StreamEventTextDelta {
+				text += event.Delta
+			}
+			return err == nil
+		})
+		return text, lastErr
+	}
+
+	// failures before any event are retried, then passed to the fallback.
+	llm := &mockLLM{errs: []error{rateLimited(), rateLimited()}, response: response("ok")}
+	fallback := &mockLLM{response: response("fallback"), stream: true}
+	r := New(llm, WithMaxRetries(1), WithBackoff(time.Millisecond, time.Millisecond), WithFallbacks(fallback))
+	text, err := collect(r.GenerateContentStream(context.Background(), nil))
+	require.NoError(t, err)
+	assert.Equal(t, "partial", text)
+	assert.Equal(t, 2, llm.called)
+	assert.Equal(t, 1, fallback.called)
+
+	// failures after some events are returned.
+	llm = &mockLLM{errs: []error{rateLimited()}, response: response("ok"), stream: true}
+	fallback = &mockLLM{response: response("fallback")}
+	r = New(llm, WithBackoff(time.Millisecond, time.Millisecond), WithFallbacks(fallback))
+	text, err = collect(r.GenerateContentStream(context.Background(), nil))
+	require.ErrorIs(t, err, llms.ErrRateLimited)
+	assert.Equal(t, "partial", text)
+	assert.Equal(t, 1, llm.called)
+	assert.Equal(t, 0, fallback.called)
+}
+
+func TestRetrier_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{errs: []error{rateLimited()}, response: response("ok")}
+	r := New(llm, WithBackoff(time.Hour, time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := r.GenerateContent(ctx, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, llm.called)
+}
+
+func TestRetrier_Backoff(t *testing.T) {
+	t.Parallel()
+
+	r := New(&mockLLM{}, WithBackoff(100*time.Millisecond, time.Second))
+	for attempt, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		want *= time.Millisecond
+		got := r.backoff(attempt, errors.New("error"))
+		assert.GreaterOrEqual(t, got, want/2)
+		assert.LessOrEqual(t, got, want)
+	}
+
+	apiErr := rateLimited()
+	var e *llms.Error
+	require.ErrorAs(t, apiErr, &e)
+	e.RetryAfter = 5 * time.Second
+	assert.Equal(t, 5*time.Second, r.backoff(0, apiErr))
+}
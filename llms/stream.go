@@ -0,0 +1,245 @@
+package llms
+
+import (
+	"context"
+)
+
+// StreamEventType is the type of a StreamEvent.
+type StreamEventType string
+
+const (
+	// StreamEventTextDelta carries a chunk of the generated text in Delta.
+	StreamEventTextDelta StreamEventType = "text_delta"
+	// StreamEventReasoningDelta carries a chunk of the model's reasoning in
+	// Delta.
+	StreamEventReasoningDelta StreamEventType = "reasoning_delta"
+	// StreamEventToolCallStart announces a tool call. ToolCall has the ID and
+	// the function name, but no arguments yet.
+	StreamEventToolCallStart StreamEventType = "tool_call_start"
+	// StreamEventToolCallDelta carries a chunk of the JSON arguments of the
+	// tool call identified by ToolCall.ID in Delta.
+	StreamEventToolCallDelta StreamEventType = "tool_call_delta"
+	// StreamEventToolCallEnd is sent when a tool call is complete. ToolCall
+	// holds the whole call, including its arguments.
+	StreamEventToolCallEnd StreamEventType = "tool_call_end"
+	// StreamEventUsage carries the token usage of the call in Usage.
+	StreamEventUsage StreamEventType = "usage"
+	// StreamEventFinish is the last event of a stream. It carries the stop
+	// reason and the complete response.
+	StreamEventFinish StreamEventType = "finish"
+)
+
+// StreamEvent is a structured event of a streaming response.
+type StreamEvent struct {
+	// Type is the type of the event; it determines which other fields are set.
+	Type StreamEventType
+	// Delta is the text chunk of text, reasoning and tool call delta events.
+	Delta string
+	// ToolCall is the tool call of tool call events.
+	ToolCall *ToolCall
+	// Usage is the token usage of usage events.
+	Usage *Usage
+	// StopReason is the reason the model stopped generating, for finish
+	// events.
+	StopReason string
+	// Response is the complete response, for finish events.
+	Response *ContentResponse
+}
+
+// StreamSeq is a sequence of streaming events. It has the signature of an
+// iter.Seq2[StreamEvent, error], so with Go 1.23 or later it can be used in a
+// range loop:
+//
+//	for event, err := range llms.GenerateContentStream(ctx, llm, messages) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Print(event.Delta)
+//	}
+//
+// An error is always the last value of the sequence. Stopping the iteration
+// early cancels the underlying call.
+type StreamSeq func(yield func(StreamEvent, error) bool)
+
+// StreamingModel is a Model that natively reports structured streaming
+// events.
+type StreamingModel interface {
+	Model
+
+	// GenerateContentStream is like GenerateContent, but returns the response
+	// as a sequence of streaming events.
+	GenerateContentStream(ctx context.Context, messages []MessageContent, options ...CallOption) StreamSeq
+}
+
+// GenerateContentStream calls the model and returns its response as a
+// sequence of streaming events.
+//
+// Models implementing StreamingModel report text, reasoning and tool call
+// deltas as they arrive. For other models the text is streamed with
+// StreamingFunc, and the tool calls are reported once the response is
+// complete.
+func GenerateContentStream(ctx context.Context, model Model, messages []MessageContent, options ...CallOption) StreamSeq {
+	if m, ok := model.(StreamingModel); ok {
+		return m.GenerateContentStream(ctx, messages, options...)
+	}
+	return streamContent(ctx, model, messages, options, false)
+}
+
+// StreamContentEvents calls GenerateContent with a StreamingEventFunc and
+// returns the events the model reports. It is meant to implement
+// StreamingModel.GenerateContentStream for models that call
+// StreamingEventFunc; the usage and finish events are added from the
+// response, so models only need to report the deltas.
+func StreamContentEvents(ctx context.Context, model Model, messages []MessageContent, options ...CallOption) StreamSeq {
+	return streamContent(ctx, model, messages, options, true)
+}
+
+func streamContent(ctx context.Context, model Model, messages []MessageContent, options []CallOption, native bool) StreamSeq { //nolint:lll
+	return func(yield func(StreamEvent, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var opts CallOptions
+		for _, opt := range options {
+			opt(&opts)
+		}
+
+		events := make(chan StreamEvent)
+		send := func(ctx context.Context, event StreamEvent) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		options = append(options[:len(options):len(options)], streamingOptions(opts, send, native)...)
+
+		var (
+			response *ContentResponse
+			err      error
+		)
+		go func() {
+			defer close(events)
+			response, err = model.GenerateContent(ctx, messages, options...)
+		}()
+
+		for event := range events {
+			if !yield(event, nil) {
+				cancel()
+				for range events { //nolint:revive
+				}
+				return
+			}
+		}
+
+		if err != nil {
+			yield(StreamEvent{}, err)
+			return
+		}
+		for _, event := range responseEvents(response, native) {
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+}
+
+// streamingOptions returns the call options making a model send its
+// streaming events, keeping the streaming functions the caller set.
+func streamingOptions(opts CallOptions, send func(context.Context, StreamEvent) error, native bool) []CallOption {
+	if native {
+		return []CallOption{WithStreamingEventFunc(func(ctx context.Context, event StreamEvent) error {
+			if opts.StreamingEventFunc != nil {
+				if err := opts.StreamingEventFunc(ctx, event); err != nil {
+					return err
+				}
+			}
+			return send(ctx, event)
+		})}
+	}
+
+	return []CallOption{
+		WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			if opts.StreamingFunc != nil {
+				if err := opts.StreamingFunc(ctx, chunk); err != nil {
+					return err
+				}
+			}
+			if len(chunk) == 0 {
+				return nil
+			}
+			return send(ctx, StreamEvent{Type: StreamEventTextDelta, Delta: string(chunk)})
+		}),
+		WithStreamingReasoningFunc(func(ctx context.Context, reasoningChunk, chunk []byte) error {
+			if opts.StreamingReasoningFunc != nil {
+				if err := opts.StreamingReasoningFunc(ctx, reasoningChunk, chunk); err != nil {
+					return err
+				}
+			}
+			if len(reasoningChunk) == 0 {
+				return nil
+			}
+			return send(ctx, StreamEvent{Type: StreamEventReasoningDelta, Delta: string(reasoningChunk)})
+		}),
+	}
+}
+
+// responseEvents returns the events that follow the streamed deltas: the
+// tool calls if the model didn't stream them, the usage and the finish
+// event.
+func responseEvents(response *ContentResponse, native bool) []StreamEvent {
+	var events []StreamEvent
+	var stopReason string
+	if response != nil && len(response.Choices) > 0 {
+		stopReason = response.Choices[0].StopReason
+	}
+	if response != nil && !native {
+		// Some models return each part of the response as a separate choice,
+		// so look for tool calls in all of them.
+		for _, choice := range response.Choices {
+			for _, toolCall := range choice.ToolCalls {
+				events = append(events, toolCallEvents(toolCall)...)
+			}
+		}
+	}
+	if response != nil && response.Usage != nil {
+		events = append(events, StreamEvent{Type: StreamEventUsage, Usage: response.Usage})
+	}
+	return append(events, StreamEvent{Type: StreamEventFinish, StopReason: stopReason, Response: response})
+}
+
+// toolCallEvents returns the start, delta and end events of a tool call that
+// was received in one piece.
+func toolCallEvents(toolCall ToolCall) []StreamEvent {
+	start := ToolCall{ID: toolCall.ID, Type: toolCall.Type}
+	var arguments string
+	if toolCall.FunctionCall != nil {
+		start.FunctionCall = &FunctionCall{Name: toolCall.FunctionCall.Name}
+		arguments = toolCall.FunctionCall.Arguments
+	}
+
+	events := []StreamEvent{{Type: StreamEventToolCallStart, ToolCall: &start}}
+	if arguments != "" {
+		events = append(events, StreamEvent{
+			Type:     StreamEventToolCallDelta,
+			Delta:    arguments,
+			ToolCall: &ToolCall{ID: toolCall.ID, Type: toolCall.Type},
+		})
+	}
+	return append(events, StreamEvent{Type: StreamEventToolCallEnd, ToolCall: &toolCall})
+}
+
+// SendToolCallEvents sends the events of a tool call that was received in
+// one piece to streamingEventFunc.
+func SendToolCallEvents(ctx context.Context, streamingEventFunc func(context.Context, StreamEvent) error,
+	toolCall ToolCall,
+) error {
+	for _, event := range toolCallEvents(toolCall) {
+		if err := streamingEventFunc(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamingFuncModel streams its chunks with StreamingFunc only.
+type streamingFuncModel struct {
+	chunks   []string
+	response *ContentResponse
+	err      error
+}
+
+func (m *streamingFuncModel) Call(ctx context.Context, prompt string, options ...CallOption) (string, error) {
+	return GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *streamingFuncModel) GenerateContent(ctx context.Context, _ []MessageContent, options ...CallOption) (*ContentResponse, error) { //nolint:lll
+	var opts CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	for _, chunk := range m.chunks {
+		if opts.StreamingFunc == nil {
+			break
+		}
+		if err := opts.StreamingFunc(ctx, []byte(chunk)); err != nil {
+			return nil, err
+		}
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.response, nil
+}
+
+// eventModel reports its events natively.
+type eventModel struct {
+	events   []StreamEvent
+	response *ContentResponse
+}
+
+func (m *eventModel) Call(ctx context.Context, prompt string, options ...CallOption) (string, error) {
+	return GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *eventModel) GenerateContent(ctx context.Context, _ []MessageContent, options ...CallOption) (*ContentResponse, error) { //nolint:lll
+	var opts CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	for _, event := range m.events {
+		if err := opts.StreamingEventFunc(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	return m.response, nil
+}
+
+func (m *eventModel) GenerateContentStream(ctx context.Context, messages []MessageContent, options ...CallOption) StreamSeq { //nolint:lll
+	return StreamContentEvents(ctx, m, messages, options...)
+}
+
+func collectEvents(t *testing.T, seq StreamSeq) ([]StreamEvent, error) {
+	t.Helper()
+	var events []StreamEvent
+	var err error
+	seq(func(event StreamEvent, e error) bool {
+		if e != nil {
+			err = e
+			return false
+		}
+		events = append(events, event)
+		return true
+	})
+	return events, err
+}
+
+func eventTypes(events []StreamEvent) []StreamEventType {
+	types := make([]StreamEventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestGenerateContentStream_Adapter(t *testing.T) {
+	t.Parallel()
+
+	response := &ContentResponse{
+		Choices: []*ContentChoice{{
+			Content:    "Hello world",
+			StopReason: "stop",
+			ToolCalls: []ToolCall{{
+				ID:           "call_1",
+				Type:         "function",
+				FunctionCall: &FunctionCall{Name: "search", Arguments: `{"q":"go"}`},
+			}},
+		}},
+		Usage: &Usage{InputTokens: 3, OutputTokens: 2},
+	}
+	var streamed []string
+	model := &streamingFuncModel{chunks: []string{"Hello", " world"}, response: response}
+
+	events, err := collectEvents(t, GenerateContentStream(context.Background(), model, nil,
+		WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			streamed = append(streamed, string(chunk))
+			return nil
+		})))
+	require.NoError(t, err)
+
+	assert.Equal(t, []StreamEventType{
+		StreamEventTextDelta,
+		StreamEventTextDelta,
+		StreamEventToolCallStart,
+		StreamEventToolCallDelta,
+		StreamEventToolCallEnd,
+		StreamEventUsage,
+		StreamEventFinish,
+	}, eventTypes(events))
+	assert.Equal(t, "Hello", events[0].Delta)
+	assert.Equal(t, "search", events[2].ToolCall.FunctionCall.Name)
+	assert.Empty(t, events[2].ToolCall.FunctionCall.Arguments)
+	assert.Equal(t, `{"q":"go"}`, events[3].Delta)
+	assert.Equal(t, "call_1", events[3].ToolCall.ID)
+	assert.Equal(t, response.Choices[0].ToolCalls[0], *events[4].ToolCall)
+	assert.Equal(t, 5, events[5].Usage.TotalTokens())
+	assert.Equal(t, "stop", events[6].StopReason)
+	assert.Same(t, response, events[6].Response)
+
+	// The caller's streaming func keeps working.
+	assert.Equal(t, []string{"Hello", " world"}, streamed)
+}
+
+func TestGenerateContentStream_Error(t *testing.T) {
+	t.Parallel()
+
+	errModel := errors.New("model failed")
+	model := &streamingFuncModel{chunks: []string{"Hello"}, err: errModel}
+
+	events, err := collectEvents(t, GenerateContentStream(context.Background(), model, nil))
+	require.ErrorIs(t, err, errModel)
+	assert.Equal(t, []StreamEventType{StreamEventTextDelta}, eventTypes(events))
+}
+
+func TestGenerateContentStream_StopEarly(t *testing.T) {
+	t.Parallel()
+
+	model := &streamingFuncModel{
+		chunks:   []string{"a", "b", "c"},
+		response: &ContentResponse{Choices: []*ContentChoice{{Content: "abc"}}},
+	}
+
+	var events []StreamEvent
+	GenerateContentStream(context.Background(), model, nil)(func(event StreamEvent, err error) bool {
+		require.NoError(t, err)
+		events = append(events, event)
+		return false
+	})
+	assert.Len(t, events, 1)
+}
+
+func TestGenerateContentStream_Native(t *testing.T) {
+	t.Parallel()
+
+	toolCall := ToolCall{ID: "call_1", FunctionCall: &FunctionCall{Name: "search", Arguments: `{}`}}
+	model := &eventModel{
+		events: []StreamEvent{
+			{Type: StreamEventReasoningDelta, Delta: "thinking"},
+			{Type: StreamEventToolCallStart, ToolCall: &ToolCall{ID: "call_1", FunctionCall: &FunctionCall{Name: "search"}}},
+			{Type: StreamEventToolCallDelta, Delta: `{}`, ToolCall: &ToolCall{ID: "call_1"}},
+			{Type: StreamEventToolCallEnd, ToolCall: &toolCall},
+		},
+		response: &ContentResponse{Choices: []*ContentChoice{{ToolCalls: []ToolCall{toolCall}}}},
+	}
+
+	events, err := collectEvents(t, GenerateContentStream(context.Background(), model, nil))
+	require.NoError(t, err)
+
+	// Tool calls aren't reported twice for models streaming them natively.
+	assert.Equal(t, []StreamEventType{
+		StreamEventReasoningDelta,
+		StreamEventToolCallStart,
+		StreamEventToolCallDelta,
+		StreamEventToolCallEnd,
+		StreamEventFinish,
+	}, eventTypes(events))
+}
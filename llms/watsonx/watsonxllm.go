@@ -3,6 +3,8 @@ package watsonx
 import (
 	"context"
 	"errors"
+	"regexp"
+	"strconv"
 
 	wx "github.com/IBM/watsonx-go/pkg/models"
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
@@ -31,6 +33,7 @@ func (wx *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOpti
 // GenerateContent implements the Model interface.
 func (wx *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint: lll, cyclop, whitespace
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindLLM, "watsonx", callbacks.WithRunModel(wx.modelID))
 	if wx.CallbacksHandler != nil {
 		wx.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
@@ -46,6 +49,7 @@ func (wx *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConte
 		toWatsonxOptions(&options)...,
 	)
 	if err != nil {
+		err = mapError(err)
 		if wx.CallbacksHandler != nil {
 			wx.CallbacksHandler.HandleLLMError(ctx, err)
 		}
@@ -62,6 +66,10 @@ func (wx *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConte
 				Content: result.Text,
 			},
 		},
+		Usage: &llms.Usage{
+			InputTokens:  result.InputTokenCount,
+			OutputTokens: result.GeneratedTokenCount,
+		},
 	}
 	return resp, nil
 }
@@ -78,6 +86,19 @@ func New(modelID string, opts ...wx.ClientOption) (*LLM, error) {
 	}, nil
 }
 
+// statusCodeRe matches the status code in the errors returned by the watsonx
+// SDK.
+var statusCodeRe = regexp.MustCompile(`status code (\d+)`)
+
+// mapError classifies an error returned by the watsonx SDK.
+func mapError(err error) error {
+	var statusCode int
+	if m := statusCodeRe.FindStringSubmatch(err.Error()); m != nil {
+		statusCode, _ = strconv.Atoi(m[1])
+	}
+	return llms.NewError("watsonx", statusCode, err)
+}
+
 func getPrompt(messages []llms.MessageContent) (string, error) {
 	// Assume we get a single text message
 	msg0 := messages[0]
@@ -0,0 +1,202 @@
+package retrievers
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+const (
+	_defaultBM25K1           = 1.5
+	_defaultBM25B            = 0.75
+	_defaultBM25NumDocuments = 4
+)
+
+// BM25Option is a function that configures a BM25Retriever.
+type BM25Option func(*BM25Retriever)
+
+// WithBM25Parameters sets the k1 and b parameters of BM25, which control the
+// saturation of the term frequencies and the normalization by the length of
+// the documents. The defaults are 1.5 and 0.75.
+func WithBM25Parameters(k1, b float64) BM25Option {
+	return func(r *BM25Retriever) {
+		r.k1 = k1
+		r.b = b
+	}
+}
+
+// WithBM25NumDocuments sets the maximum number of documents returned. The
+// default is 4.
+func WithBM25NumDocuments(n int) BM25Option {
+	return func(r *BM25Retriever) {
+		r.numDocuments = n
+	}
+}
+
+// WithBM25Tokenizer sets the function splitting the documents and the queries
+// into terms. The default one is DefaultTokenizer.
+func WithBM25Tokenizer(tokenize func(text string) []string) BM25Option {
+	return func(r *BM25Retriever) {
+		r.tokenize = tokenize
+	}
+}
+
+// WithBM25Callback sets the callbacks handler of the retriever.
+func WithBM25Callback(handler callbacks.Handler) BM25Option {
+	return func(r *BM25Retriever) {
+		r.CallbacksHandler = handler
+	}
+}
+
+// DefaultTokenizer splits a text into lower-case terms made of letters,
+// digits, and inner dashes, dots and underscores, so identifiers such as
+// "ERR-404" or "v1.2" are kept whole.
+func DefaultTokenizer(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '.' && r != '_'
+	})
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if term := strings.Trim(field, "-._"); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// BM25Retriever is a retriever ranking documents held in memory by the
+// relevance of their terms to the terms of the query, with the Okapi BM25
+// function. Unlike a vector search, it finds the exact identifiers of a query,
+// such as error codes. The documents returned have their BM25 score, and
+// documents without any term of the query are never returned.
+//
+// A BM25Retriever is safe for concurrent use.
+type BM25Retriever struct {
+	CallbacksHandler callbacks.Handler
+
+	k1           float64
+	b            float64
+	numDocuments int
+	tokenize     func(text string) []string
+
+	mu sync.RWMutex
+	// docs are the indexed documents, with the frequencies of their terms and
+	// their numbers of terms.
+	docs      []schema.Document
+	termFreqs []map[string]int
+	lengths   []int
+	// docFreqs are the numbers of documents containing each term.
+	docFreqs    map[string]int
+	totalLength int
+}
+
+var _ schema.Retriever = (*BM25Retriever)(nil)
+
+// NewBM25Retriever creates a BM25Retriever indexing the documents.
+func NewBM25Retriever(docs []schema.Document, opts ...BM25Option) *BM25Retriever {
+	r := &BM25Retriever{
+		k1:           _defaultBM25K1,
+		b:            _defaultBM25B,
+		numDocuments: _defaultBM25NumDocuments,
+		tokenize:     DefaultTokenizer,
+		docFreqs:     make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.AddDocuments(docs)
+	return r
+}
+
+// AddDocuments indexes more documents.
+func (r *BM25Retriever) AddDocuments(docs []schema.Document) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, doc := range docs {
+		termFreqs := make(map[string]int)
+		terms := r.tokenize(doc.PageContent)
+		for _, term := range terms {
+			termFreqs[term]++
+		}
+		for term := range termFreqs {
+			r.docFreqs[term]++
+		}
+		r.docs = append(r.docs, doc)
+		r.termFreqs = append(r.termFreqs, termFreqs)
+		r.lengths = append(r.lengths, len(terms))
+		r.totalLength += len(terms)
+	}
+}
+
+// GetRelevantDocuments returns the documents with the highest BM25 scores for
+// the query.
+func (r *BM25Retriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindRetriever, "BM25Retriever")
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	docs := r.search(query)
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+	return docs, nil
+}
+
+func (r *BM25Retriever) search(query string) []schema.Document {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	docs := make([]schema.Document, 0)
+	if len(r.docs) == 0 {
+		return docs
+	}
+	numDocs := float64(len(r.docs))
+	avgLength := float64(r.totalLength) / numDocs
+
+	scores := make([]float64, len(r.docs))
+	seen := make(map[string]bool)
+	for _, term := range r.tokenize(query) {
+		if seen[term] || r.docFreqs[term] == 0 {
+			continue
+		}
+		seen[term] = true
+		docFreq := float64(r.docFreqs[term])
+		idf := math.Log(1 + (numDocs-docFreq+0.5)/(docFreq+0.5))
+		for i, termFreqs := range r.termFreqs {
+			tf := float64(termFreqs[term])
+			if tf == 0 {
+				continue
+			}
+			length := float64(r.lengths[i])
+			scores[i] += idf * tf * (r.k1 + 1) / (tf + r.k1*(1-r.b+r.b*length/avgLength))
+		}
+	}
+
+	indexes := make([]int, 0, len(r.docs))
+	for i, score := range scores {
+		if score > 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return scores[indexes[i]] > scores[indexes[j]]
+	})
+	if r.numDocuments > 0 && len(indexes) > r.numDocuments {
+		indexes = indexes[:r.numDocuments]
+	}
+	for _, i := range indexes {
+		doc := r.docs[i]
+		doc.Score = float32(scores[i])
+		docs = append(docs, doc)
+	}
+	return docs
+}
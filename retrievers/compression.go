@@ -0,0 +1,97 @@
+package retrievers
+
+import (
+	"context"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+// DocumentCompressor post-processes the documents retrieved for a query: it
+// can filter, shorten, reorder or score them.
+type DocumentCompressor interface {
+	CompressDocuments(ctx context.Context, docs []schema.Document, query string) ([]schema.Document, error)
+}
+
+// DocumentCompressorFunc is a function implementing DocumentCompressor.
+type DocumentCompressorFunc func(ctx context.Context, docs []schema.Document, query string) ([]schema.Document, error)
+
+var _ DocumentCompressor = DocumentCompressorFunc(nil)
+
+// CompressDocuments calls the function.
+func (f DocumentCompressorFunc) CompressDocuments(ctx context.Context, docs []schema.Document, query string) ([]schema.Document, error) { //nolint:lll
+	return f(ctx, docs, query)
+}
+
+// DocumentCompressorPipeline is a compressor running compressors one after
+// the other, each on the documents of the previous one.
+type DocumentCompressorPipeline []DocumentCompressor
+
+var _ DocumentCompressor = DocumentCompressorPipeline(nil)
+
+// CompressDocuments runs the compressors in order.
+func (p DocumentCompressorPipeline) CompressDocuments(ctx context.Context, docs []schema.Document, query string) ([]schema.Document, error) { //nolint:lll
+	var err error
+	for _, c := range p {
+		if len(docs) == 0 {
+			break
+		}
+		docs, err = c.CompressDocuments(ctx, docs, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// ContextualCompressionRetriever is a retriever passing the documents of
+// another retriever through a compressor, so only their parts relevant to the
+// query are given to a model. See LLMExtractor, EmbeddingsFilter and
+// RerankCompressor.
+type ContextualCompressionRetriever struct {
+	CallbacksHandler callbacks.Handler
+	Retriever        schema.Retriever
+	Compressor       DocumentCompressor
+}
+
+var _ schema.Retriever = (*ContextualCompressionRetriever)(nil)
+
+// NewContextualCompressionRetriever creates a ContextualCompressionRetriever.
+// Several compressors are run in order, as a DocumentCompressorPipeline.
+func NewContextualCompressionRetriever(
+	retriever schema.Retriever,
+	compressors ...DocumentCompressor,
+) *ContextualCompressionRetriever {
+	var compressor DocumentCompressor = DocumentCompressorPipeline(compressors)
+	if len(compressors) == 1 {
+		compressor = compressors[0]
+	}
+	return &ContextualCompressionRetriever{
+		Retriever:  retriever,
+		Compressor: compressor,
+	}
+}
+
+// GetRelevantDocuments returns the compressed documents of the retriever.
+func (r *ContextualCompressionRetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) { //nolint:lll
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindRetriever, "ContextualCompressionRetriever")
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	docs, err := r.Retriever.GetRelevantDocuments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) > 0 {
+		docs, err = r.Compressor.CompressDocuments(ctx, docs, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+	return docs, nil
+}
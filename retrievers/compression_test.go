@@ -0,0 +1,117 @@
+package retrievers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/llms/fake"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wordEmbedder embeds texts by counting the occurrences of a few words.
+type wordEmbedder struct{}
+
+func (wordEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = wordEmbedder{}.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (wordEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	words := []string{"gophers", "burrows", "google", "rust"}
+	vector := make([]float32, len(words))
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		for i, w := range words {
+			if strings.Trim(word, "?,.") == w {
+				vector[i]++
+			}
+		}
+	}
+	return vector, nil
+}
+
+// lengthReranker scores the documents by their length, and records the
+// queries.
+type lengthReranker struct {
+	queries []string
+}
+
+func (r *lengthReranker) Rerank(_ context.Context, query string, documents []string) ([]RerankResult, error) {
+	r.queries = append(r.queries, query)
+	results := make([]RerankResult, len(documents))
+	for i, doc := range documents {
+		results[i] = RerankResult{Index: i, Score: float32(len(doc))}
+	}
+	return results, nil
+}
+
+func TestContextualCompressionRetriever(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	base := newKeywordRetriever("Gophers live in burrows. They eat roots.", "Go gophers were drawn by Renee French.", "Rust has crabs.")
+	llm := fake.NewScriptedLLM()
+	llm.On(fake.LastMessageContains("burrows"), fake.TextReply("Gophers live in burrows."))
+	llm.On(fake.LastMessageContains("Renee"), fake.TextReply("NO_OUTPUT"))
+
+	r := NewContextualCompressionRetriever(base, NewLLMExtractor(llm))
+	docs, err := r.GetRelevantDocuments(ctx, "Where do gophers live?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gophers live in burrows."}, contents(docs))
+	assert.Len(t, llm.Calls(), 2)
+
+	// the compressors are run in order.
+	reranker := &lengthReranker{}
+	r = NewContextualCompressionRetriever(base,
+		NewEmbeddingsFilter(wordEmbedder{}, WithSimilarityThreshold(0.1)),
+		NewRerankCompressor(reranker, WithTopN(1)),
+	)
+	docs, err = r.GetRelevantDocuments(ctx, "gophers")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "Gophers live in burrows. They eat roots.", docs[0].PageContent)
+	assert.InDelta(t, 40, docs[0].Score, 0)
+	assert.Equal(t, []string{"gophers"}, reranker.queries)
+
+	_, err = NewContextualCompressionRetriever(base, NewLLMExtractor(llm)).GetRelevantDocuments(ctx, "rust")
+	require.ErrorIs(t, err, fake.ErrNoReply)
+}
+
+func TestEmbeddingsFilter(t *testing.T) {
+	t.Parallel()
+
+	docs := []schema.Document{
+		{PageContent: "Rust has crabs."},
+		{PageContent: "Gophers at Google."},
+		{PageContent: "Gophers live in burrows, gophers."},
+	}
+	filtered, err := NewEmbeddingsFilter(wordEmbedder{}).CompressDocuments(context.Background(), docs, "gophers")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gophers live in burrows, gophers.", "Gophers at Google.", "Rust has crabs."}, contents(filtered))
+	assert.InDelta(t, 0.894, filtered[0].Score, 0.001)
+
+	filtered, err = NewEmbeddingsFilter(wordEmbedder{}, WithSimilarityThreshold(0.1), WithTopK(1)).
+		CompressDocuments(context.Background(), docs, "gophers")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gophers live in burrows, gophers."}, contents(filtered))
+}
+
+func TestRerankCompressor(t *testing.T) {
+	t.Parallel()
+
+	docs := []schema.Document{{PageContent: "a"}, {PageContent: "abc"}, {PageContent: "ab"}, {PageContent: "abcd"}}
+	reranked, err := NewRerankCompressor(&lengthReranker{}).CompressDocuments(context.Background(), docs, "q")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abcd", "abc", "ab"}, contents(reranked))
+
+	reranked, err = NewRerankCompressor(&lengthReranker{}, WithTopN(0), WithMinScore(2)).
+		CompressDocuments(context.Background(), docs, "q")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abcd", "abc", "ab"}, contents(reranked))
+	assert.InDelta(t, 2, reranked[2].Score, 0)
+}
@@ -0,0 +1,19 @@
+// Package retrievers contains retrievers built on top of other retrievers,
+// such as the ones of vector stores, to improve the documents they find.
+//
+// The MultiQueryRetriever asks a model for several versions of the query, and
+// merges the documents retrieved for each of them. The HyDERetriever searches
+// with a hypothetical answer written by a model, which is often closer to the
+// relevant documents than the question. The ContextualCompressionRetriever
+// passes the documents through compressors, which keep only their relevant
+// parts, filter them by embedding similarity or rerank them.
+//
+// The BM25Retriever is a keyword index held in memory, finding the exact
+// terms vector searches miss, such as identifiers. The EnsembleRetriever
+// combines several retrievers by weighted reciprocal rank fusion, for example
+// a BM25Retriever and the retriever of a vector store. Stores with a native
+// hybrid search support vectorstores.WithHybridSearch instead.
+//
+// All of them implement schema.Retriever, so they can be used by chains such
+// as RetrievalQA.
+package retrievers
@@ -0,0 +1,87 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/IT-Tech-Company/langchaingo/embeddings"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+// EmbeddingsFilterOption is a function that configures an EmbeddingsFilter.
+type EmbeddingsFilterOption func(*EmbeddingsFilter)
+
+// WithSimilarityThreshold sets the minimum cosine similarity between the
+// embeddings of the query and of the documents kept.
+func WithSimilarityThreshold(threshold float32) EmbeddingsFilterOption {
+	return func(f *EmbeddingsFilter) {
+		f.SimilarityThreshold = threshold
+	}
+}
+
+// WithTopK sets the maximum number of documents kept.
+func WithTopK(k int) EmbeddingsFilterOption {
+	return func(f *EmbeddingsFilter) {
+		f.TopK = k
+	}
+}
+
+// EmbeddingsFilter is a compressor keeping the documents whose embeddings are
+// the most similar to the embedding of the query, by cosine similarity. The
+// documents kept are sorted by similarity, set as their score.
+type EmbeddingsFilter struct {
+	Embedder embeddings.Embedder
+	// SimilarityThreshold is the minimum similarity of the documents kept.
+	SimilarityThreshold float32
+	// TopK is the maximum number of documents kept, if positive.
+	TopK int
+}
+
+var _ DocumentCompressor = (*EmbeddingsFilter)(nil)
+
+// NewEmbeddingsFilter creates an EmbeddingsFilter. Without options, all the
+// documents are kept and sorted.
+func NewEmbeddingsFilter(embedder embeddings.Embedder, opts ...EmbeddingsFilterOption) *EmbeddingsFilter {
+	f := &EmbeddingsFilter{Embedder: embedder, SimilarityThreshold: -1}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// CompressDocuments returns the documents similar enough to the query.
+func (f *EmbeddingsFilter) CompressDocuments(ctx context.Context, docs []schema.Document, query string) ([]schema.Document, error) { //nolint:lll
+	queryVector, err := f.Embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+	vectors, err := f.Embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed documents: %w", err)
+	}
+	if len(vectors) != len(docs) {
+		return nil, fmt.Errorf("embed documents: got %d vectors for %d documents", len(vectors), len(docs))
+	}
+
+	filtered := make([]schema.Document, 0, len(docs))
+	for i, doc := range docs {
+		similarity := embeddings.CosineSimilarity(queryVector, vectors[i])
+		if similarity < f.SimilarityThreshold {
+			continue
+		}
+		doc.Score = similarity
+		filtered = append(filtered, doc)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Score > filtered[j].Score
+	})
+	if f.TopK > 0 && len(filtered) > f.TopK {
+		filtered = filtered[:f.TopK]
+	}
+	return filtered, nil
+}
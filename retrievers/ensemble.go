@@ -0,0 +1,169 @@
+package retrievers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+const _defaultRRFConstant = 60
+
+// ErrInvalidWeights is returned by NewEnsembleRetriever when the weights don't
+// match the retrievers.
+var ErrInvalidWeights = errors.New("invalid retriever weights")
+
+// EnsembleOption is a function that configures an EnsembleRetriever.
+type EnsembleOption func(*EnsembleRetriever)
+
+// WithWeights sets the weights of the retrievers, in order. By default, all
+// the retrievers have the same weight.
+func WithWeights(weights ...float64) EnsembleOption {
+	return func(r *EnsembleRetriever) {
+		r.Weights = weights
+	}
+}
+
+// WithRRFConstant sets the constant added to the ranks in the reciprocal rank
+// fusion, which reduces the advantage of the first ranks. The default is 60.
+func WithRRFConstant(c int) EnsembleOption {
+	return func(r *EnsembleRetriever) {
+		r.C = c
+	}
+}
+
+// WithEnsembleNumDocuments sets the maximum number of documents returned. By
+// default, all the documents found are returned.
+func WithEnsembleNumDocuments(n int) EnsembleOption {
+	return func(r *EnsembleRetriever) {
+		r.NumDocuments = n
+	}
+}
+
+// WithEnsembleCallback sets the callbacks handler of the retriever.
+func WithEnsembleCallback(handler callbacks.Handler) EnsembleOption {
+	return func(r *EnsembleRetriever) {
+		r.CallbacksHandler = handler
+	}
+}
+
+// EnsembleRetriever is a retriever combining the documents of several
+// retrievers, run in parallel, with weighted reciprocal rank fusion: each
+// document is scored with the sum over the retrievers of weight / (C + rank),
+// where rank starts at 1, and the documents are returned by decreasing score,
+// set as their score.
+//
+// Combining a BM25Retriever with the retriever of a vector store gives a
+// hybrid search, finding both exact terms and similar meanings.
+type EnsembleRetriever struct {
+	CallbacksHandler callbacks.Handler
+	Retrievers       []schema.Retriever
+	Weights          []float64
+	C                int
+	// NumDocuments is the maximum number of documents returned, if positive.
+	NumDocuments int
+}
+
+var _ schema.Retriever = (*EnsembleRetriever)(nil)
+
+// NewEnsembleRetriever creates an EnsembleRetriever.
+func NewEnsembleRetriever(retrievers []schema.Retriever, opts ...EnsembleOption) (*EnsembleRetriever, error) {
+	r := &EnsembleRetriever{
+		Retrievers: retrievers,
+		C:          _defaultRRFConstant,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if len(retrievers) == 0 {
+		return nil, errors.New("ensemble retriever without retrievers")
+	}
+	if r.Weights == nil {
+		r.Weights = make([]float64, len(retrievers))
+		for i := range r.Weights {
+			r.Weights[i] = 1
+		}
+	}
+	if len(r.Weights) != len(retrievers) {
+		return nil, fmt.Errorf("%w: %d weights for %d retrievers", ErrInvalidWeights, len(r.Weights), len(retrievers))
+	}
+	for _, w := range r.Weights {
+		if w < 0 {
+			return nil, fmt.Errorf("%w: negative weight %v", ErrInvalidWeights, w)
+		}
+	}
+	return r, nil
+}
+
+// GetRelevantDocuments returns the fused documents of the retrievers.
+func (r *EnsembleRetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindRetriever, "EnsembleRetriever")
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	results := make([][]schema.Document, len(r.Retrievers))
+	errs := make([]error, len(r.Retrievers))
+	var wg sync.WaitGroup
+	for i, retriever := range r.Retrievers {
+		wg.Add(1)
+		go func(i int, retriever schema.Retriever) {
+			defer wg.Done()
+			results[i], errs[i] = retriever.GetRelevantDocuments(ctx, query)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("retriever at index %d: %w", i, errs[i])
+			}
+		}(i, retriever)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	docs := r.fuse(results)
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+	return docs, nil
+}
+
+// fuse returns the documents of the retrievers by decreasing reciprocal rank
+// fusion score. Documents found by several retrievers are kept as returned by
+// the first one.
+func (r *EnsembleRetriever) fuse(results [][]schema.Document) []schema.Document {
+	docs := uniqueDocuments(results...)
+	indexes := make(map[string]int, len(docs))
+	for i, doc := range docs {
+		indexes[documentKey(doc)] = i
+	}
+
+	scores := make([]float64, len(docs))
+	for i, result := range results {
+		seen := make(map[int]bool, len(result))
+		for rank, doc := range result {
+			j := indexes[documentKey(doc)]
+			if seen[j] {
+				continue
+			}
+			seen[j] = true
+			scores[j] += r.Weights[i] / float64(r.C+rank+1)
+		}
+	}
+
+	for i := range docs {
+		docs[i].Score = float32(scores[i])
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].Score > docs[j].Score
+	})
+	if r.NumDocuments > 0 && len(docs) > r.NumDocuments {
+		docs = docs[:r.NumDocuments]
+	}
+	return docs
+}
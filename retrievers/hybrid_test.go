@@ -0,0 +1,101 @@
+package retrievers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func printerDocuments() []schema.Document {
+	return []schema.Document{
+		{PageContent: "The printer stops when the paper jams.", Metadata: map[string]any{"id": 1}},
+		{PageContent: "Error E-4012 means the printer tray is empty.", Metadata: map[string]any{"id": 2}},
+		{PageContent: "The router blinks when the network is down.", Metadata: map[string]any{"id": 3}},
+		{PageContent: "Printer, printer, printer: the printer manual.", Metadata: map[string]any{"id": 4}},
+	}
+}
+
+func TestDefaultTokenizer(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"error", "e-4012", "in", "v1.2", "my_var"}, DefaultTokenizer("Error E-4012 in v1.2: my_var."))
+	assert.Empty(t, DefaultTokenizer(" ...? "))
+}
+
+func TestBM25Retriever(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewBM25Retriever(printerDocuments())
+
+	docs, err := r.GetRelevantDocuments(ctx, "e-4012 code")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, 2, docs[0].Metadata["id"])
+	assert.Positive(t, docs[0].Score)
+
+	// the documents repeating the terms rank first, and the documents without
+	// any term are left out.
+	docs, err = r.GetRelevantDocuments(ctx, "printer")
+	require.NoError(t, err)
+	ids := make([]any, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.Metadata["id"]
+	}
+	assert.Equal(t, []any{4, 1, 2}, ids)
+
+	r = NewBM25Retriever(nil, WithBM25NumDocuments(1), WithBM25Parameters(1.2, 0.5))
+	docs, err = r.GetRelevantDocuments(ctx, "printer")
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+
+	r.AddDocuments(printerDocuments())
+	docs, err = r.GetRelevantDocuments(ctx, "printer")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, 4, docs[0].Metadata["id"])
+}
+
+// staticRetriever returns the same documents for any query.
+type staticRetriever []schema.Document
+
+func (r staticRetriever) GetRelevantDocuments(context.Context, string) ([]schema.Document, error) {
+	return r, nil
+}
+
+func TestEnsembleRetriever(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	all := printerDocuments()
+	keyword := NewBM25Retriever(all)
+	// a vector search would miss the identifier, and find similar documents.
+	vector := staticRetriever{all[0], all[3], all[1]}
+
+	r, err := NewEnsembleRetriever([]schema.Retriever{keyword, vector})
+	require.NoError(t, err)
+	docs, err := r.GetRelevantDocuments(ctx, "E-4012")
+	require.NoError(t, err)
+	assert.Equal(t, []string{all[1].PageContent, all[0].PageContent, all[3].PageContent}, contents(docs))
+	assert.InDelta(t, 1.0/61+1.0/63, docs[0].Score, 1e-6)
+	assert.InDelta(t, 1.0/61, docs[1].Score, 1e-6)
+
+	// the weights favor the vector search.
+	r, err = NewEnsembleRetriever([]schema.Retriever{keyword, vector},
+		WithWeights(0.2, 0.8), WithRRFConstant(1), WithEnsembleNumDocuments(2))
+	require.NoError(t, err)
+	docs, err = r.GetRelevantDocuments(ctx, "E-4012")
+	require.NoError(t, err)
+	assert.Equal(t, []string{all[0].PageContent, all[1].PageContent}, contents(docs))
+
+	_, err = NewEnsembleRetriever([]schema.Retriever{keyword, vector}, WithWeights(1))
+	require.ErrorIs(t, err, ErrInvalidWeights)
+
+	r, err = NewEnsembleRetriever([]schema.Retriever{keyword, newKeywordRetriever()})
+	require.NoError(t, err)
+	_, err = r.GetRelevantDocuments(ctx, "fail")
+	require.ErrorIs(t, err, errSearch)
+}
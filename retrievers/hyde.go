@@ -0,0 +1,102 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/prompts"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+const _defaultHyDETemplate = `Please write a passage to answer the question.
+Question: {{.question}}
+Passage:`
+
+// HyDEOption is a function that configures a HyDERetriever.
+type HyDEOption func(*HyDERetriever)
+
+// WithHyDEPrompt sets the prompt asking for the hypothetical answer, with the
+// input variable "question".
+func WithHyDEPrompt(prompt prompts.PromptTemplate) HyDEOption {
+	return func(r *HyDERetriever) {
+		r.Prompt = prompt
+	}
+}
+
+// WithHyDEQuery makes the retriever search with the query followed by the
+// hypothetical answer, instead of the answer alone.
+func WithHyDEQuery() HyDEOption {
+	return func(r *HyDERetriever) {
+		r.IncludeQuery = true
+	}
+}
+
+// WithHyDECallback sets the callbacks handler of the retriever.
+func WithHyDECallback(handler callbacks.Handler) HyDEOption {
+	return func(r *HyDERetriever) {
+		r.CallbacksHandler = handler
+	}
+}
+
+// HyDERetriever is a retriever implementing Hypothetical Document Embeddings:
+// it asks a model to answer the query, and searches with the answer, whose
+// embedding is usually closer to the ones of the relevant documents than the
+// embedding of the question. The retriever is typically the one of a vector
+// store, see vectorstores.ToRetriever.
+type HyDERetriever struct {
+	CallbacksHandler callbacks.Handler
+	Retriever        schema.Retriever
+	LLM              llms.Model
+	Prompt           prompts.PromptTemplate
+	// IncludeQuery makes the retriever search with the query followed by the
+	// hypothetical answer.
+	IncludeQuery bool
+}
+
+var _ schema.Retriever = (*HyDERetriever)(nil)
+
+// NewHyDERetriever creates a HyDERetriever over a retriever.
+func NewHyDERetriever(retriever schema.Retriever, llm llms.Model, opts ...HyDEOption) *HyDERetriever {
+	r := &HyDERetriever{
+		Retriever: retriever,
+		LLM:       llm,
+		Prompt:    prompts.NewPromptTemplate(_defaultHyDETemplate, []string{"question"}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// GetRelevantDocuments returns the documents retrieved for a hypothetical
+// answer to the query.
+func (r *HyDERetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindRetriever, "HyDERetriever")
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	prompt, err := r.Prompt.Format(map[string]any{"question": query})
+	if err != nil {
+		return nil, err
+	}
+	answer, err := llms.GenerateFromSinglePrompt(ctx, r.LLM, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("generate hypothetical answer: %w", err)
+	}
+	if r.IncludeQuery {
+		answer = query + "\n" + answer
+	}
+
+	docs, err := r.Retriever.GetRelevantDocuments(ctx, answer)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+	return docs, nil
+}
@@ -0,0 +1,107 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/prompts"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+// _extractorNoOutput is the answer of the model when no part of a document is
+// relevant.
+const _extractorNoOutput = "NO_OUTPUT"
+
+const _defaultExtractorTemplate = `Given the following question and context, extract any part of the context *AS IS* that is relevant to answer the question. If none of the context is relevant return ` + _extractorNoOutput + `.
+
+Remember, *DO NOT* edit the extracted parts of the context.
+
+> Question: {{.question}}
+> Context:
+>>>
+{{.context}}
+>>>
+Extracted relevant parts:`
+
+// LLMExtractorOption is a function that configures an LLMExtractor.
+type LLMExtractorOption func(*LLMExtractor)
+
+// WithExtractorPrompt sets the prompt asking for the relevant parts of a
+// document, with the input variables "question" and "context". The model must
+// answer NO_OUTPUT when no part is relevant.
+func WithExtractorPrompt(prompt prompts.PromptTemplate) LLMExtractorOption {
+	return func(e *LLMExtractor) {
+		e.Prompt = prompt
+	}
+}
+
+// LLMExtractor is a compressor asking a model for the parts of each document
+// relevant to the query, and dropping the documents without any. The
+// documents are processed concurrently.
+type LLMExtractor struct {
+	LLM    llms.Model
+	Prompt prompts.PromptTemplate
+}
+
+var _ DocumentCompressor = (*LLMExtractor)(nil)
+
+// NewLLMExtractor creates an LLMExtractor.
+func NewLLMExtractor(llm llms.Model, opts ...LLMExtractorOption) *LLMExtractor {
+	e := &LLMExtractor{
+		LLM:    llm,
+		Prompt: prompts.NewPromptTemplate(_defaultExtractorTemplate, []string{"question", "context"}),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// CompressDocuments replaces the content of the documents by their relevant
+// parts.
+func (e *LLMExtractor) CompressDocuments(ctx context.Context, docs []schema.Document, query string) ([]schema.Document, error) { //nolint:lll
+	extracts := make([]string, len(docs))
+	errs := make([]error, len(docs))
+	var wg sync.WaitGroup
+	for i, doc := range docs {
+		wg.Add(1)
+		go func(i int, doc schema.Document) {
+			defer wg.Done()
+			extracts[i], errs[i] = e.extract(ctx, doc, query)
+		}(i, doc)
+	}
+	wg.Wait()
+
+	compressed := make([]schema.Document, 0, len(docs))
+	for i, doc := range docs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("extract document %d: %w", i, errs[i])
+		}
+		if extracts[i] == "" {
+			continue
+		}
+		doc.PageContent = extracts[i]
+		compressed = append(compressed, doc)
+	}
+	return compressed, nil
+}
+
+// extract returns the relevant parts of a document, or an empty string.
+func (e *LLMExtractor) extract(ctx context.Context, doc schema.Document, query string) (string, error) {
+	prompt, err := e.Prompt.Format(map[string]any{"question": query, "context": doc.PageContent})
+	if err != nil {
+		return "", err
+	}
+	extract, err := llms.GenerateFromSinglePrompt(ctx, e.LLM, prompt)
+	if err != nil {
+		return "", err
+	}
+	extract = strings.TrimSpace(extract)
+	if extract == _extractorNoOutput {
+		return "", nil
+	}
+	return extract, nil
+}
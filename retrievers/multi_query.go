@@ -0,0 +1,183 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/prompts"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+const _defaultNumQueries = 3
+
+const _defaultMultiQueryTemplate = `You are an AI language model assistant. Your task is to generate {{.n}} different versions of the given user question to retrieve relevant documents from a vector database. By generating multiple perspectives on the user question, your goal is to help the user overcome some of the limitations of distance-based similarity search.
+
+Original question: {{.question}}`
+
+// MultiQueryOption is a function that configures a MultiQueryRetriever.
+type MultiQueryOption func(*MultiQueryRetriever)
+
+// WithNumQueries sets the number of versions of the query the model is asked
+// for. The default is 3.
+func WithNumQueries(n int) MultiQueryOption {
+	return func(r *MultiQueryRetriever) {
+		r.NumQueries = n
+	}
+}
+
+// WithoutOriginalQuery makes the retriever search only with the versions of
+// the query written by the model.
+func WithoutOriginalQuery() MultiQueryOption {
+	return func(r *MultiQueryRetriever) {
+		r.IncludeOriginal = false
+	}
+}
+
+// WithMultiQueryPrompt sets the prompt asking for the versions of the query,
+// with the input variables "question" and "n".
+func WithMultiQueryPrompt(prompt prompts.PromptTemplate) MultiQueryOption {
+	return func(r *MultiQueryRetriever) {
+		r.Prompt = prompt
+	}
+}
+
+// WithMultiQueryCallback sets the callbacks handler of the retriever.
+func WithMultiQueryCallback(handler callbacks.Handler) MultiQueryOption {
+	return func(r *MultiQueryRetriever) {
+		r.CallbacksHandler = handler
+	}
+}
+
+// MultiQueryRetriever is a retriever that asks a model for several versions
+// of the query, retrieves the documents of each version in parallel with
+// another retriever, and returns them without duplicates, in the order of the
+// queries.
+type MultiQueryRetriever struct {
+	CallbacksHandler callbacks.Handler
+	Retriever        schema.Retriever
+	LLM              llms.Model
+	Prompt           prompts.PromptTemplate
+	NumQueries       int
+	// IncludeOriginal makes the retriever also search with the query itself,
+	// first.
+	IncludeOriginal bool
+}
+
+var _ schema.Retriever = (*MultiQueryRetriever)(nil)
+
+// NewMultiQueryRetriever creates a MultiQueryRetriever over a retriever.
+func NewMultiQueryRetriever(retriever schema.Retriever, llm llms.Model, opts ...MultiQueryOption) *MultiQueryRetriever {
+	r := &MultiQueryRetriever{
+		Retriever:       retriever,
+		LLM:             llm,
+		Prompt:          prompts.NewPromptTemplate(_defaultMultiQueryTemplate, []string{"question", "n"}),
+		NumQueries:      _defaultNumQueries,
+		IncludeOriginal: true,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// queryVersions is the object the model responds with.
+type queryVersions struct {
+	Queries []string `json:"queries" jsonschema:"description=The versions of the question"`
+}
+
+// GetRelevantDocuments returns the documents retrieved for the query and its
+// versions.
+func (r *MultiQueryRetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindRetriever, "MultiQueryRetriever")
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	queries, err := r.Queries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]schema.Document, len(queries))
+	errs := make([]error, len(queries))
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func(i int, q string) {
+			defer wg.Done()
+			results[i], errs[i] = r.Retriever.GetRelevantDocuments(ctx, q)
+		}(i, q)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("retrieve documents for %q: %w", queries[i], err)
+		}
+	}
+	docs := uniqueDocuments(results...)
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+	return docs, nil
+}
+
+// Queries returns the queries the retriever searches with: the query, unless
+// IncludeOriginal is false, and its versions written by the model.
+func (r *MultiQueryRetriever) Queries(ctx context.Context, query string) ([]string, error) {
+	prompt, err := r.Prompt.Format(map[string]any{"question": query, "n": r.NumQueries})
+	if err != nil {
+		return nil, err
+	}
+	versions, err := llms.GenerateObject[queryVersions](ctx, r.LLM, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, prompt),
+	}, llms.WithObjectName("query_versions"))
+	if err != nil {
+		return nil, fmt.Errorf("generate queries: %w", err)
+	}
+
+	var queries []string
+	seen := make(map[string]bool)
+	add := func(q string) {
+		q = strings.TrimSpace(q)
+		if q != "" && !seen[q] {
+			seen[q] = true
+			queries = append(queries, q)
+		}
+	}
+	if r.IncludeOriginal {
+		add(query)
+	}
+	for _, q := range versions.Queries {
+		add(q)
+	}
+	return queries, nil
+}
+
+// uniqueDocuments concatenates lists of documents, keeping the first of the
+// documents with the same content and metadata.
+func uniqueDocuments(lists ...[]schema.Document) []schema.Document {
+	docs := make([]schema.Document, 0)
+	seen := make(map[string]bool)
+	for _, list := range lists {
+		for _, doc := range list {
+			key := documentKey(doc)
+			if !seen[key] {
+				seen[key] = true
+				docs = append(docs, doc)
+			}
+		}
+	}
+	return docs
+}
+
+// documentKey identifies the documents with the same content and metadata.
+// Scores, which depend on the query, are ignored.
+func documentKey(doc schema.Document) string {
+	return fmt.Sprintf("%s\x00%v", doc.PageContent, doc.Metadata)
+}
@@ -0,0 +1,101 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+const _defaultRerankTopN = 3
+
+// RerankResult is the relevance of a document to a query, given by a
+// Reranker.
+type RerankResult struct {
+	// Index is the index of the document in the documents given to the
+	// reranker.
+	Index int
+	// Score is the relevance of the document, higher for more relevant ones.
+	Score float32
+}
+
+// Reranker scores the relevance of documents to a query, typically with a
+// cross-encoder model. See the rerankers package for implementations using
+// hosted models.
+type Reranker interface {
+	// Rerank returns the results of the documents, sorted by decreasing
+	// relevance. It may leave out the least relevant documents.
+	Rerank(ctx context.Context, query string, documents []string) ([]RerankResult, error)
+}
+
+// RerankOption is a function that configures a RerankCompressor.
+type RerankOption func(*RerankCompressor)
+
+// WithTopN sets the number of documents kept. The default is 3.
+func WithTopN(n int) RerankOption {
+	return func(c *RerankCompressor) {
+		c.TopN = n
+	}
+}
+
+// WithMinScore sets the minimum score of the documents kept.
+func WithMinScore(score float32) RerankOption {
+	return func(c *RerankCompressor) {
+		c.MinScore = &score
+	}
+}
+
+// RerankCompressor is a compressor keeping the documents most relevant to the
+// query according to a Reranker. The documents kept are sorted by relevance,
+// set as their score.
+type RerankCompressor struct {
+	Reranker Reranker
+	// TopN is the maximum number of documents kept, if positive.
+	TopN int
+	// MinScore is the minimum score of the documents kept, if set.
+	MinScore *float32
+}
+
+var _ DocumentCompressor = (*RerankCompressor)(nil)
+
+// NewRerankCompressor creates a RerankCompressor.
+func NewRerankCompressor(reranker Reranker, opts ...RerankOption) *RerankCompressor {
+	c := &RerankCompressor{Reranker: reranker, TopN: _defaultRerankTopN}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CompressDocuments returns the most relevant documents.
+func (c *RerankCompressor) CompressDocuments(ctx context.Context, docs []schema.Document, query string) ([]schema.Document, error) { //nolint:lll
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+	results, err := c.Reranker.Rerank(ctx, query, texts)
+	if err != nil {
+		return nil, fmt.Errorf("rerank documents: %w", err)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	reranked := make([]schema.Document, 0, len(results))
+	for _, result := range results {
+		if c.TopN > 0 && len(reranked) == c.TopN {
+			break
+		}
+		if result.Index < 0 || result.Index >= len(docs) {
+			return nil, fmt.Errorf("rerank documents: invalid index %d for %d documents", result.Index, len(docs))
+		}
+		if c.MinScore != nil && result.Score < *c.MinScore {
+			continue
+		}
+		doc := docs[result.Index]
+		doc.Score = result.Score
+		reranked = append(reranked, doc)
+	}
+	return reranked, nil
+}
@@ -0,0 +1,12 @@
+// Package rerankers implements retrievers.Reranker with the rerank APIs of
+// Cohere, Jina and Voyage AI, which score documents with cross-encoder models.
+//
+//	reranker, err := rerankers.NewCohere()
+//	if err != nil {
+//		return err
+//	}
+//	retriever := retrievers.NewContextualCompressionRetriever(
+//		vectorstores.ToRetriever(store, 20),
+//		retrievers.NewRerankCompressor(reranker, retrievers.WithTopN(4)),
+//	)
+package rerankers
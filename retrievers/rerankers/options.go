@@ -0,0 +1,43 @@
+package rerankers
+
+import "net/http"
+
+// Option is a function that configures a Reranker.
+type Option func(*Reranker)
+
+// WithAPIKey sets the API key. By default, it is read from the environment
+// variable of the provider: COHERE_API_KEY, JINA_API_KEY or VOYAGEAI_API_KEY.
+func WithAPIKey(apiKey string) Option {
+	return func(r *Reranker) {
+		r.apiKey = apiKey
+	}
+}
+
+// WithModel sets the rerank model.
+func WithModel(model string) Option {
+	return func(r *Reranker) {
+		r.Model = model
+	}
+}
+
+// WithBaseURL sets the URL of the rerank endpoint.
+func WithBaseURL(baseURL string) Option {
+	return func(r *Reranker) {
+		r.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client. The default is http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *Reranker) {
+		r.client = client
+	}
+}
+
+// WithTopN sets the number of results the API returns. By default, all the
+// documents are returned.
+func WithTopN(n int) Option {
+	return func(r *Reranker) {
+		r.TopN = n
+	}
+}
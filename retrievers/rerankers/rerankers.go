@@ -0,0 +1,169 @@
+package rerankers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/IT-Tech-Company/langchaingo/retrievers"
+)
+
+// ErrMissingAPIKey is returned when no API key is set for a provider.
+var ErrMissingAPIKey = errors.New("missing API key")
+
+// provider describes the rerank API of a provider.
+type provider struct {
+	name         string
+	baseURL      string
+	model        string
+	apiKeyEnv    string
+	topNField    string
+	resultsField string
+}
+
+//nolint:gochecknoglobals
+var (
+	cohere = provider{
+		name:         "Cohere",
+		baseURL:      "https://api.cohere.com/v2/rerank",
+		model:        "rerank-v3.5",
+		apiKeyEnv:    "COHERE_API_KEY",
+		topNField:    "top_n",
+		resultsField: "results",
+	}
+	jina = provider{
+		name:         "Jina",
+		baseURL:      "https://api.jina.ai/v1/rerank",
+		model:        "jina-reranker-v2-base-multilingual",
+		apiKeyEnv:    "JINA_API_KEY",
+		topNField:    "top_n",
+		resultsField: "results",
+	}
+	voyageAI = provider{
+		name:         "Voyage AI",
+		baseURL:      "https://api.voyageai.com/v1/rerank",
+		model:        "rerank-2",
+		apiKeyEnv:    "VOYAGEAI_API_KEY",
+		topNField:    "top_k",
+		resultsField: "data",
+	}
+)
+
+// Reranker scores documents with the rerank API of a provider.
+type Reranker struct {
+	provider provider
+	baseURL  string
+	apiKey   string
+	client   *http.Client
+	Model    string
+	// TopN is the number of results the API returns, if positive.
+	TopN int
+}
+
+var _ retrievers.Reranker = (*Reranker)(nil)
+
+// NewCohere creates a reranker using the Cohere API. The default model is
+// "rerank-v3.5".
+func NewCohere(opts ...Option) (*Reranker, error) {
+	return newReranker(cohere, opts...)
+}
+
+// NewJina creates a reranker using the Jina API. The default model is
+// "jina-reranker-v2-base-multilingual".
+func NewJina(opts ...Option) (*Reranker, error) {
+	return newReranker(jina, opts...)
+}
+
+// NewVoyageAI creates a reranker using the Voyage AI API. The default model
+// is "rerank-2".
+func NewVoyageAI(opts ...Option) (*Reranker, error) {
+	return newReranker(voyageAI, opts...)
+}
+
+func newReranker(p provider, opts ...Option) (*Reranker, error) {
+	r := &Reranker{
+		provider: p,
+		baseURL:  p.baseURL,
+		apiKey:   os.Getenv(p.apiKeyEnv),
+		client:   http.DefaultClient,
+		Model:    p.model,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.apiKey == "" {
+		return nil, fmt.Errorf("%w: set the %s API key with WithAPIKey or %s", ErrMissingAPIKey, p.name, p.apiKeyEnv)
+	}
+	return r, nil
+}
+
+type rerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float32 `json:"relevance_score"`
+}
+
+// Rerank returns the results of the documents, sorted by decreasing relevance.
+func (r *Reranker) Rerank(ctx context.Context, query string, documents []string) ([]retrievers.RerankResult, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+	body := map[string]any{
+		"model":     r.Model,
+		"query":     query,
+		"documents": documents,
+	}
+	if r.TopN > 0 {
+		body[r.provider.topNField] = r.TopN
+	}
+	if r.provider.name == jina.name {
+		body["return_documents"] = false
+	}
+
+	var response map[string]json.RawMessage
+	if err := r.request(ctx, body, &response); err != nil {
+		return nil, err
+	}
+	var results []rerankResult
+	if err := json.Unmarshal(response[r.provider.resultsField], &results); err != nil {
+		return nil, fmt.Errorf("%s rerank: decode results: %w", r.provider.name, err)
+	}
+
+	reranked := make([]retrievers.RerankResult, len(results))
+	for i, result := range results {
+		reranked[i] = retrievers.RerankResult{Index: result.Index, Score: result.RelevanceScore}
+	}
+	return reranked, nil
+}
+
+func (r *Reranker) request(ctx context.Context, body any, response any) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s rerank request: %w", r.provider.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("%s rerank: unexpected status %s: %s", r.provider.name, resp.Status, bytes.TrimSpace(msg))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("%s rerank: decode response: %w", r.provider.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package rerankers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/retrievers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRerankServer returns a server answering rerank requests with the body,
+// and recording the requests.
+func newRerankServer(t *testing.T, status int, response string, requests chan<- map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var request map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		requests <- request
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRerankers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		new      func(...Option) (*Reranker, error)
+		response string
+		topN     string
+	}{
+		{"cohere", NewCohere, `{"id": "1", "results": [{"index": 1, "relevance_score": 0.9}, {"index": 0, "relevance_score": 0.2}]}`, "top_n"},
+		{"jina", NewJina, `{"model": "m", "results": [{"index": 1, "relevance_score": 0.9}, {"index": 0, "relevance_score": 0.2}]}`, "top_n"},
+		{"voyageai", NewVoyageAI, `{"object": "list", "data": [{"index": 1, "relevance_score": 0.9}, {"index": 0, "relevance_score": 0.2}]}`, "top_k"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			requests := make(chan map[string]any, 1)
+			server := newRerankServer(t, http.StatusOK, tt.response, requests)
+			r, err := tt.new(WithAPIKey("secret"), WithBaseURL(server.URL), WithModel("m"), WithTopN(2))
+			require.NoError(t, err)
+
+			results, err := r.Rerank(context.Background(), "gophers", []string{"Rust", "Gophers"})
+			require.NoError(t, err)
+			assert.Equal(t, []retrievers.RerankResult{{Index: 1, Score: 0.9}, {Index: 0, Score: 0.2}}, results)
+
+			request := <-requests
+			assert.Equal(t, "m", request["model"])
+			assert.Equal(t, "gophers", request["query"])
+			assert.Equal(t, []any{"Rust", "Gophers"}, request["documents"])
+			assert.InDelta(t, 2, request[tt.topN], 0)
+		})
+	}
+}
+
+func TestReranker_Errors(t *testing.T) { //nolint:paralleltest
+	t.Setenv("COHERE_API_KEY", "")
+	_, err := NewCohere()
+	require.ErrorIs(t, err, ErrMissingAPIKey)
+
+	requests := make(chan map[string]any, 1)
+	server := newRerankServer(t, http.StatusTooManyRequests, `{"message": "rate limited"}`, requests)
+	r, err := NewCohere(WithAPIKey("secret"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	_, err = r.Rerank(context.Background(), "gophers", []string{"Gophers"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "429")
+	assert.Contains(t, err.Error(), "rate limited")
+}
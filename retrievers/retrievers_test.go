@@ -0,0 +1,142 @@
+package retrievers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/chains"
+	"github.com/IT-Tech-Company/langchaingo/llms"
+	"github.com/IT-Tech-Company/langchaingo/llms/fake"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errSearch = errors.New("search failed")
+
+// keywordRetriever returns the documents containing a word of the query, and
+// records the queries.
+type keywordRetriever struct {
+	docs []schema.Document
+
+	mu      sync.Mutex
+	queries []string
+}
+
+func newKeywordRetriever(texts ...string) *keywordRetriever {
+	r := &keywordRetriever{}
+	for _, text := range texts {
+		r.docs = append(r.docs, schema.Document{PageContent: text})
+	}
+	return r
+}
+
+func (r *keywordRetriever) GetRelevantDocuments(_ context.Context, query string) ([]schema.Document, error) {
+	r.mu.Lock()
+	r.queries = append(r.queries, query)
+	r.mu.Unlock()
+
+	if strings.Contains(query, "fail") {
+		return nil, errSearch
+	}
+	var docs []schema.Document
+	for _, doc := range r.docs {
+		for _, word := range strings.Fields(strings.ToLower(query)) {
+			if strings.Contains(strings.ToLower(doc.PageContent), strings.Trim(word, "?,.")) {
+				docs = append(docs, doc)
+				break
+			}
+		}
+	}
+	return docs, nil
+}
+
+func (r *keywordRetriever) Queries() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.queries...)
+}
+
+func contents(docs []schema.Document) []string {
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+	return texts
+}
+
+func TestMultiQueryRetriever(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	base := newKeywordRetriever("Gophers live in burrows.", "Go was created at Google.", "Rust has a borrow checker.")
+	llm := fake.NewScriptedLLM(fake.TextReply(`{"queries": ["Where do gophers live?", "gophers burrows", "Google"]}`))
+	r := NewMultiQueryRetriever(base, llm, WithNumQueries(2))
+
+	docs, err := r.GetRelevantDocuments(ctx, "gophers")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gophers live in burrows.", "Go was created at Google."}, contents(docs))
+	assert.ElementsMatch(t, []string{"gophers", "Where do gophers live?", "gophers burrows", "Google"}, base.Queries())
+
+	call, ok := llm.LastCall()
+	require.True(t, ok)
+	prompt := call.Messages[0].Parts[0].(llms.TextContent).Text
+	assert.Contains(t, prompt, "generate 2 different versions")
+	assert.Contains(t, prompt, "Original question: gophers")
+
+	// the original query can be left out.
+	llm.Push(fake.TextReply(`{"queries": ["borrow checker", " borrow checker "]}`))
+	queries, err := NewMultiQueryRetriever(base, llm, WithoutOriginalQuery()).Queries(ctx, "rust")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"borrow checker"}, queries)
+
+	llm.Push(fake.TextReply(`{"queries": ["fail"]}`))
+	_, err = r.GetRelevantDocuments(ctx, "gophers")
+	require.ErrorIs(t, err, errSearch)
+}
+
+func TestHyDERetriever(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	base := newKeywordRetriever("Gophers live in burrows.", "Go was created at Google.")
+	llm := fake.NewScriptedLLM(fake.TextReply("Burrows."), fake.TextReply("Google."))
+
+	docs, err := NewHyDERetriever(base, llm).GetRelevantDocuments(ctx, "Where do gophers live?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gophers live in burrows."}, contents(docs))
+
+	call, ok := llm.LastCall()
+	require.True(t, ok)
+	assert.Contains(t, call.Messages[0].Parts[0].(llms.TextContent).Text, "Question: Where do gophers live?")
+
+	_, err = NewHyDERetriever(base, llm, WithHyDEQuery()).GetRelevantDocuments(ctx, "Who made Go?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Burrows.", "Who made Go?\nGoogle."}, base.Queries())
+
+	llm.Push(fake.ErrorReply(errSearch))
+	_, err = NewHyDERetriever(base, llm).GetRelevantDocuments(ctx, "Who made Go?")
+	require.ErrorIs(t, err, errSearch)
+}
+
+func TestRetrievalQA(t *testing.T) {
+	t.Parallel()
+
+	base := newKeywordRetriever("Gophers live in burrows.", "Go was created at Google.")
+	llm := fake.NewScriptedLLM(
+		fake.TextReply(`{"queries": ["burrows"]}`),
+		fake.TextReply("In burrows."),
+	)
+	qa := chains.NewRetrievalQAFromLLM(llm, NewMultiQueryRetriever(base, llm))
+
+	out, err := chains.Run(context.Background(), qa, "Where do gophers live?")
+	require.NoError(t, err)
+	assert.Equal(t, "In burrows.", out)
+
+	call, ok := llm.LastCall()
+	require.True(t, ok)
+	assert.Contains(t, call.Messages[0].Parts[0].(llms.TextContent).Text, "Gophers live in burrows.")
+}
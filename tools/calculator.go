@@ -2,9 +2,11 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
 	"go.starlark.net/lib/math"
 	"go.starlark.net/starlark"
 )
@@ -14,7 +16,7 @@ type Calculator struct {
 	CallbacksHandler callbacks.Handler
 }
 
-var _ Tool = Calculator{}
+var _ SchemaTool = Calculator{}
 
 // Description returns a string describing the calculator tool.
 func (c Calculator) Description() string {
@@ -31,6 +33,7 @@ func (c Calculator) Name() string {
 // string. If the evaluator errors the error is given in the result to give the
 // agent the ability to retry.
 func (c Calculator) Call(ctx context.Context, input string) (string, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindTool, c.Name())
 	if c.CallbacksHandler != nil {
 		c.CallbacksHandler.HandleToolStart(ctx, input)
 	}
@@ -47,3 +50,28 @@ func (c Calculator) Call(ctx context.Context, input string) (string, error) {
 
 	return result, nil
 }
+
+// Schema returns the schema of the arguments of the calculator tool.
+func (c Calculator) Schema() jsonschema.Definition {
+	return jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"expression": {
+				Type:        jsonschema.String,
+				Description: "The math expression to evaluate, e.g. 2 * (3 + 4).",
+			},
+		},
+		Required: []string{"expression"},
+	}
+}
+
+// CallWithArgs evaluates the expression given in the arguments.
+func (c Calculator) CallWithArgs(ctx context.Context, args json.RawMessage) (string, error) {
+	var a struct {
+		Expression string `json:"expression"`
+	}
+	if err := DecodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	return c.Call(ctx, a.Expression)
+}
@@ -43,6 +43,7 @@ func (t Tool) Description() string {
 
 // Call performs the search and return the result.
 func (t Tool) Call(ctx context.Context, input string) (string, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindTool, t.Name())
 	if t.CallbacksHandler != nil {
 		t.CallbacksHandler.HandleToolStart(ctx, input)
 	}
@@ -95,6 +95,7 @@ func (t *Tool) Description() string {
 
 // Call executes a query against the Perplexity AI model and returns the response.
 func (t *Tool) Call(ctx context.Context, input string) (string, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindTool, t.Name())
 	if t.CallbacksHandler != nil {
 		t.CallbacksHandler.HandleToolStart(ctx, input)
 	}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
+)
+
+// ErrInvalidArgs is returned when the arguments given to a SchemaTool don't
+// match its schema.
+var ErrInvalidArgs = errors.New("invalid tool arguments")
+
+// SchemaTool is a Tool with structured arguments. Agents using the tool
+// calling API of the models describe the arguments of the tool with Schema,
+// and call it with CallWithArgs instead of passing a single string input.
+type SchemaTool interface {
+	Tool
+
+	// Schema returns the JSON schema of the arguments of the tool. It is
+	// usually an object schema.
+	Schema() jsonschema.Definition
+	// CallWithArgs calls the tool with the JSON encoded arguments.
+	CallWithArgs(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// Func is a SchemaTool calling a function with arguments of type T. The schema
+// of the arguments is derived from T with jsonschema.For.
+type Func[T any] struct {
+	name        string
+	description string
+	schema      jsonschema.Definition
+	fn          func(ctx context.Context, args T) (string, error)
+}
+
+var _ SchemaTool = (*Func[struct{}])(nil)
+
+// NewFunc creates a new SchemaTool calling fn with the decoded arguments.
+func NewFunc[T any](name, description string, fn func(ctx context.Context, args T) (string, error)) (*Func[T], error) {
+	schema, err := jsonschema.For[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Func[T]{
+		name:        name,
+		description: description,
+		schema:      schema,
+		fn:          fn,
+	}, nil
+}
+
+// Name returns the name of the tool.
+func (f *Func[T]) Name() string {
+	return f.name
+}
+
+// Description returns the description of the tool.
+func (f *Func[T]) Description() string {
+	return f.description
+}
+
+// Schema returns the schema of the arguments of the tool.
+func (f *Func[T]) Schema() jsonschema.Definition {
+	return f.schema
+}
+
+// Call calls the tool with the arguments JSON encoded in the input.
+func (f *Func[T]) Call(ctx context.Context, input string) (string, error) {
+	return f.CallWithArgs(ctx, json.RawMessage(input))
+}
+
+// CallWithArgs decodes the arguments and calls the function of the tool.
+func (f *Func[T]) CallWithArgs(ctx context.Context, args json.RawMessage) (string, error) {
+	var v T
+	if err := DecodeArgs(args, &v); err != nil {
+		return "", err
+	}
+	return f.fn(ctx, v)
+}
+
+// DecodeArgs decodes the JSON encoded arguments of a SchemaTool into v.
+func DecodeArgs(args json.RawMessage, v any) error {
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	return nil
+}
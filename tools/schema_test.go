@@ -0,0 +1,47 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
+	"github.com/IT-Tech-Company/langchaingo/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunc(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Name string `json:"name" jsonschema:"description=The name to greet"`
+	}
+	tool, err := tools.NewFunc("greet", "Greets someone.", func(_ context.Context, a args) (string, error) {
+		return "Hello " + a.Name, nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"name": {Type: jsonschema.String, Description: "The name to greet"},
+		},
+		Required: []string{"name"},
+	}, tool.Schema())
+
+	result, err := tool.CallWithArgs(context.Background(), json.RawMessage(`{"name":"Gopher"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Gopher", result)
+
+	_, err = tool.Call(context.Background(), "Gopher")
+	require.ErrorIs(t, err, tools.ErrInvalidArgs)
+}
+
+func TestCalculatorCallWithArgs(t *testing.T) {
+	t.Parallel()
+
+	result, err := tools.Calculator{}.CallWithArgs(context.Background(), json.RawMessage(`{"expression":"2 * (3 + 4)"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "14", result)
+}
@@ -2,11 +2,13 @@ package serpapi
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"os"
 	"strings"
 
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
 	"github.com/IT-Tech-Company/langchaingo/tools"
 	"github.com/IT-Tech-Company/langchaingo/tools/serpapi/internal"
 )
@@ -18,7 +20,7 @@ type Tool struct {
 	client           *internal.Client
 }
 
-var _ tools.Tool = Tool{}
+var _ tools.SchemaTool = Tool{}
 
 // New creates a new serpapi tool to search on internet.
 func New(opts ...Option) (*Tool, error) {
@@ -52,6 +54,7 @@ func (t Tool) Description() string {
 }
 
 func (t Tool) Call(ctx context.Context, input string) (string, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindTool, t.Name())
 	if t.CallbacksHandler != nil {
 		t.CallbacksHandler.HandleToolStart(ctx, input)
 	}
@@ -75,3 +78,28 @@ func (t Tool) Call(ctx context.Context, input string) (string, error) {
 
 	return strings.Join(strings.Fields(result), " "), nil
 }
+
+// Schema returns the schema of the arguments of the tool.
+func (t Tool) Schema() jsonschema.Definition {
+	return jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"query": {
+				Type:        jsonschema.String,
+				Description: "The query to search on Google.",
+			},
+		},
+		Required: []string{"query"},
+	}
+}
+
+// CallWithArgs searches for the query given in the arguments.
+func (t Tool) CallWithArgs(ctx context.Context, args json.RawMessage) (string, error) {
+	var a struct {
+		Query string `json:"query"`
+	}
+	if err := tools.DecodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	return t.Call(ctx, a.Query)
+}
@@ -0,0 +1,88 @@
+package sqldatabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
+	"github.com/IT-Tech-Company/langchaingo/tools"
+)
+
+// QueryTool is a tool running SQL queries on a database.
+type QueryTool struct {
+	CallbacksHandler callbacks.Handler
+	DB               *SQLDatabase
+}
+
+var _ tools.SchemaTool = QueryTool{}
+
+// NewQueryTool creates a new tool running SQL queries on the database.
+func NewQueryTool(db *SQLDatabase) QueryTool {
+	return QueryTool{DB: db}
+}
+
+// Name returns the name of the tool.
+func (t QueryTool) Name() string {
+	return "sql_db_query"
+}
+
+// Description returns the description of the tool, with the dialect and the
+// tables of the database.
+func (t QueryTool) Description() string {
+	return fmt.Sprintf(`Useful for getting data from a %s database with the tables %s.
+	Input should be a detailed and correct SQL query, output is the columns and rows of the result.
+	If the query is not correct, an error message will be returned, rewrite the query and try again.`,
+		t.DB.Dialect(), strings.Join(t.DB.TableNames(), ", "))
+}
+
+// Call runs the query given as input. If the query fails the error is reported
+// to the callbacks handler and given in the result, to give the agent the
+// ability to fix the query.
+func (t QueryTool) Call(ctx context.Context, input string) (string, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindTool, t.Name())
+	if t.CallbacksHandler != nil {
+		t.CallbacksHandler.HandleToolStart(ctx, input)
+	}
+
+	result, err := t.DB.Query(ctx, input)
+	if err != nil {
+		if t.CallbacksHandler != nil {
+			t.CallbacksHandler.HandleToolError(ctx, err)
+		}
+		return fmt.Sprintf("error from database: %s", err.Error()), nil //nolint:nilerr
+	}
+
+	if t.CallbacksHandler != nil {
+		t.CallbacksHandler.HandleToolEnd(ctx, result)
+	}
+
+	return result, nil
+}
+
+// Schema returns the schema of the arguments of the tool.
+func (t QueryTool) Schema() jsonschema.Definition {
+	return jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"query": {
+				Type:        jsonschema.String,
+				Description: "The SQL query to run.",
+			},
+		},
+		Required: []string{"query"},
+	}
+}
+
+// CallWithArgs runs the query given in the arguments.
+func (t QueryTool) CallWithArgs(ctx context.Context, args json.RawMessage) (string, error) {
+	var a struct {
+		Query string `json:"query"`
+	}
+	if err := tools.DecodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	return t.Call(ctx, a.Query)
+}
@@ -0,0 +1,113 @@
+package sqldatabase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/tools/sqldatabase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNoSuchTable = errors.New("no such table: countries")
+
+// fakeEngine is a database engine with the cities table, whose queries
+// return its rows unless they use an other table.
+type fakeEngine struct {
+	sqldatabase.Engine
+}
+
+func (fakeEngine) Dialect() string {
+	return "sqlite3"
+}
+
+func (fakeEngine) TableNames(context.Context) ([]string, error) {
+	return []string{"cities"}, nil
+}
+
+func (fakeEngine) Query(_ context.Context, query string, _ ...any) ([]string, [][]string, error) {
+	if query != "SELECT name, country FROM cities" {
+		return nil, nil, errNoSuchTable
+	}
+	return []string{"name", "country"}, [][]string{{"Tokyo", "japan"}, {"Paris", "france"}}, nil
+}
+
+// toolHandler records the events of the tools.
+type toolHandler struct {
+	callbacks.SimpleHandler
+
+	starts []string
+	ends   []string
+	errs   []error
+}
+
+func (h *toolHandler) HandleToolStart(_ context.Context, input string) {
+	h.starts = append(h.starts, input)
+}
+
+func (h *toolHandler) HandleToolEnd(_ context.Context, output string) {
+	h.ends = append(h.ends, output)
+}
+
+func (h *toolHandler) HandleToolError(_ context.Context, err error) {
+	h.errs = append(h.errs, err)
+}
+
+func newQueryTool(t *testing.T) (sqldatabase.QueryTool, *toolHandler) {
+	t.Helper()
+
+	db, err := sqldatabase.NewSQLDatabase(fakeEngine{}, nil)
+	require.NoError(t, err)
+	handler := &toolHandler{}
+	tool := sqldatabase.NewQueryTool(db)
+	tool.CallbacksHandler = handler
+	return tool, handler
+}
+
+func TestQueryTool(t *testing.T) {
+	t.Parallel()
+
+	tool, handler := newQueryTool(t)
+	assert.Equal(t, "sql_db_query", tool.Name())
+	assert.Contains(t, tool.Description(), "sqlite3 database with the tables cities")
+
+	result, err := tool.Call(context.Background(), "SELECT name, country FROM cities")
+	require.NoError(t, err)
+	assert.Equal(t, "name\tcountry\nTokyo\tjapan\nParis\tfrance\n", result)
+	assert.Equal(t, []string{"SELECT name, country FROM cities"}, handler.starts)
+	assert.Equal(t, []string{result}, handler.ends)
+	assert.Empty(t, handler.errs)
+}
+
+func TestQueryToolError(t *testing.T) {
+	t.Parallel()
+
+	tool, handler := newQueryTool(t)
+
+	// the error is given to the agent, and reported to the handler.
+	result, err := tool.Call(context.Background(), "SELECT name FROM countries")
+	require.NoError(t, err)
+	assert.Equal(t, "error from database: no such table: countries", result)
+	require.Len(t, handler.errs, 1)
+	require.ErrorIs(t, handler.errs[0], errNoSuchTable)
+	assert.Empty(t, handler.ends)
+}
+
+func TestQueryToolCallWithArgs(t *testing.T) {
+	t.Parallel()
+
+	tool, handler := newQueryTool(t)
+	assert.Equal(t, []string{"query"}, tool.Schema().Required)
+
+	args := json.RawMessage(`{"query": "SELECT name, country FROM cities"}`)
+	result, err := tool.CallWithArgs(context.Background(), args)
+	require.NoError(t, err)
+	assert.Equal(t, "name\tcountry\nTokyo\tjapan\nParis\tfrance\n", result)
+	assert.Equal(t, []string{"SELECT name, country FROM cities"}, handler.starts)
+
+	_, err = tool.CallWithArgs(context.Background(), json.RawMessage(`{"query": 1}`))
+	require.Error(t, err)
+}
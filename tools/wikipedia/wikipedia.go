@@ -2,10 +2,12 @@ package wikipedia
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"strconv"
 
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
+	"github.com/IT-Tech-Company/langchaingo/jsonschema"
 	"github.com/IT-Tech-Company/langchaingo/tools"
 )
 
@@ -31,7 +33,7 @@ type Tool struct {
 	UserAgent string
 }
 
-var _ tools.Tool = Tool{}
+var _ tools.SchemaTool = Tool{}
 
 // New creates a new wikipedia tool to find wikipedia pages using the wikipedia api. TopK is set
 // to 2, DocMaxChars is set to 2000 and the language code is set to "en".
@@ -59,6 +61,7 @@ func (t Tool) Description() string {
 // Call uses the wikipedia api to find the top search results for the input and returns
 // the first part of the documents combined.
 func (t Tool) Call(ctx context.Context, input string) (string, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindTool, t.Name())
 	if t.CallbacksHandler != nil {
 		t.CallbacksHandler.HandleToolStart(ctx, input)
 	}
@@ -109,3 +112,28 @@ func (t Tool) searchWiKi(ctx context.Context, input string) (string, error) {
 
 	return result, nil
 }
+
+// Schema returns the schema of the arguments of the tool.
+func (t Tool) Schema() jsonschema.Definition {
+	return jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"query": {
+				Type:        jsonschema.String,
+				Description: "The query to search on Wikipedia.",
+			},
+		},
+		Required: []string{"query"},
+	}
+}
+
+// CallWithArgs searches for the query given in the arguments.
+func (t Tool) CallWithArgs(ctx context.Context, args json.RawMessage) (string, error) {
+	var a struct {
+		Query string `json:"query"`
+	}
+	if err := tools.DecodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	return t.Call(ctx, a.Query)
+}
@@ -2,10 +2,12 @@ package azureaisearch
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/IT-Tech-Company/langchaingo/embeddings"
 	"github.com/IT-Tech-Company/langchaingo/schema"
@@ -39,6 +41,8 @@ var (
 	ErrAssertingContent = errors.New(
 		"couldn't assert content to string",
 	)
+	// ErrInvalidFilter is returned when a filter isn't an OData filter string.
+	ErrInvalidFilter = errors.New("invalid filter")
 )
 
 // New creates a vectorstore for azure AI search
@@ -55,7 +59,10 @@ func New(opts ...Option) (Store, error) {
 	return s, nil
 }
 
-var _ vectorstores.VectorStore = &Store{}
+// maxBatchSize is the maximum number of documents of a request to azure AI search.
+const maxBatchSize = 1000
+
+var _ vectorstores.DocumentManager = &Store{}
 
 // AddDocuments adds the text and metadata from the documents to the Chroma collection associated with 'Store'.
 // and returns the ids of the added documents.
@@ -64,9 +71,134 @@ func (s *Store) AddDocuments(
 	docs []schema.Document,
 	options ...vectorstores.Option,
 ) ([]string, error) {
+	ids := make([]string, len(docs))
+	for i := range docs {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.uploadDocuments(ctx, s.getOptions(options...), ids, docs); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Upsert uploads the documents with the ids, replacing the documents with the
+// same ids. The ids which aren't valid azure AI search keys are encoded.
+func (s *Store) Upsert(
+	ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if len(ids) != len(docs) {
+		return vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = documentKey(id)
+	}
+	return s.uploadDocuments(ctx, s.getOptions(options...), keys, docs)
+}
+
+// Get returns the documents with the ids, by id.
+func (s *Store) Get(
+	ctx context.Context,
+	ids []string,
+	options ...vectorstores.Option,
+) (map[string]schema.Document, error) {
 	opts := s.getOptions(options...)
-	ids := []string{}
+	docs := make(map[string]schema.Document, len(ids))
+	for start := 0; start < len(ids); start += maxBatchSize {
+		batch := ids[start:min(start+maxBatchSize, len(ids))]
+		byKey := make(map[string]string, len(batch))
+		keys := make([]string, 0, len(batch))
+		for _, id := range batch {
+			key := documentKey(id)
+			byKey[key] = id
+			keys = append(keys, key)
+		}
 
+		searchResults := SearchDocumentsRequestOuput{}
+		payload := SearchDocumentsRequestInput{
+			Filter: fmt.Sprintf("search.in(id, '%s', ',')", strings.Join(keys, ",")),
+			Select: "id,content,metadata",
+			Top:    len(keys),
+		}
+		if err := s.SearchDocuments(ctx, opts.NameSpace, payload, &searchResults); err != nil {
+			return nil, err
+		}
+
+		for _, searchResult := range searchResults.Value {
+			doc, err := assertResultValues(searchResult)
+			if err != nil {
+				return nil, err
+			}
+			key, _ := searchResult["id"].(string)
+			if id, ok := byKey[key]; ok {
+				docs[id] = schema.Document{PageContent: doc.PageContent, Metadata: doc.Metadata}
+			}
+		}
+	}
+	return docs, nil
+}
+
+// Delete deletes the documents with the ids.
+func (s *Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = documentKey(id)
+	}
+	return s.deleteDocuments(ctx, s.getOptions(options...), keys)
+}
+
+// DeleteByFilter deletes the documents matching the filter, an OData filter
+// expression such as "category eq 'news'".
+func (s *Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	filterString, ok := filter.(string)
+	if !ok {
+		return fmt.Errorf("%w: filter must be an OData filter string", ErrInvalidFilter)
+	}
+
+	opts := s.getOptions(options...)
+	// the keys are collected before deleting, so that the pages of the search
+	// aren't shifted by the deletions.
+	keys := []string{}
+	for {
+		searchResults := SearchDocumentsRequestOuput{}
+		payload := SearchDocumentsRequestInput{
+			Filter: filterString,
+			Select: "id",
+			Skip:   len(keys),
+			Top:    maxBatchSize,
+		}
+		if err := s.SearchDocuments(ctx, opts.NameSpace, payload, &searchResults); err != nil {
+			return err
+		}
+		for _, searchResult := range searchResults.Value {
+			if key, ok := searchResult["id"].(string); ok {
+				keys = append(keys, key)
+			}
+		}
+		if len(searchResults.Value) < maxBatchSize {
+			break
+		}
+	}
+	return s.deleteDocuments(ctx, opts, keys)
+}
+
+// uploadDocuments embeds the documents and uploads them with the keys to the
+// index named by the name space of the options.
+func (s *Store) uploadDocuments(
+	ctx context.Context,
+	opts vectorstores.Options,
+	keys []string,
+	docs []schema.Document,
+) error {
 	texts := []string{}
 
 	for _, doc := range docs {
@@ -75,21 +207,53 @@ func (s *Store) AddDocuments(
 
 	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return ids, err
+		return err
 	}
 
 	if len(vectors) != len(docs) {
-		return ids, ErrNumberOfVectorDoesNotMatch
+		return ErrNumberOfVectorDoesNotMatch
 	}
 	for i, doc := range docs {
-		id := uuid.NewString()
-		if err = s.UploadDocument(ctx, id, opts.NameSpace, doc.PageContent, vectors[i], doc.Metadata); err != nil {
-			return ids, err
+		if err = s.UploadDocument(ctx, keys[i], opts.NameSpace, doc.PageContent, vectors[i], doc.Metadata); err != nil {
+			return err
 		}
-		ids = append(ids, id)
 	}
 
-	return ids, nil
+	return nil
+}
+
+// deleteDocuments deletes the documents with the keys from the index named by
+// the name space of the options, in batches.
+func (s *Store) deleteDocuments(ctx context.Context, opts vectorstores.Options, keys []string) error {
+	for start := 0; start < len(keys); start += maxBatchSize {
+		batch := keys[start:min(start+maxBatchSize, len(keys))]
+		if err := s.DeleteDocumentsAPIRequest(ctx, opts.NameSpace, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodedKeyPrefix is the prefix of the keys of the encoded ids.
+const encodedKeyPrefix = "b64-"
+
+// documentKey returns the id if it is a valid azure AI search key, made of
+// letters, digits, dashes, underscores and equal signs and not starting with
+// an underscore, or else its URL-safe base64 encoding prefixed with
+// encodedKeyPrefix. The ids starting with the prefix are encoded too, so that
+// different ids never have the same key.
+func documentKey(id string) string {
+	valid := id != "" && id[0] != '_' && !strings.HasPrefix(id, encodedKeyPrefix)
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '=') {
+			valid = false
+			break
+		}
+	}
+	if valid {
+		return id
+	}
+	return encodedKeyPrefix + base64.URLEncoding.EncodeToString([]byte(id))
 }
 
 // SimilaritySearch creates a vector embedding from the query using the embedder
@@ -101,18 +265,39 @@ func (s *Store) SimilaritySearch(
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
+	if opts.HybridSearch && (opts.HybridAlpha < 0 || opts.HybridAlpha > 1) {
+		return nil, vectorstores.ErrInvalidHybridAlpha
+	}
 
-	queryVector, err := s.embedder.EmbedQuery(ctx, query)
-	if err != nil {
-		return nil, err
+	fetchK := numDocuments
+	if opts.MMR {
+		var err error
+		if fetchK, err = vectorstores.MMRFetchK(opts, numDocuments); err != nil {
+			return nil, err
+		}
 	}
 
-	payload := SearchDocumentsRequestInput{
-		Vectors: []SearchDocumentsRequestInputVector{{
+	payload := SearchDocumentsRequestInput{}
+	// hybrid searches combine a full-text search of the query with the vector
+	// search, and azure fuses their results by rank.
+	if opts.HybridSearch && opts.HybridAlpha < 1 {
+		payload.Search = query
+		payload.Top = fetchK
+	}
+	var queryVector []float32
+	if !opts.HybridSearch || opts.HybridAlpha > 0 || opts.MMR {
+		var err error
+		queryVector, err = s.embedder.EmbedQuery(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !opts.HybridSearch || opts.HybridAlpha > 0 {
+		payload.Vectors = []SearchDocumentsRequestInputVector{{
 			Fields: "contentVector",
 			Value:  queryVector,
-			K:      numDocuments,
-		}},
+			K:      fetchK,
+		}}
 	}
 
 	if filter, ok := opts.Filters.(string); ok {
@@ -125,6 +310,7 @@ func (s *Store) SimilaritySearch(
 	}
 
 	output := []schema.Document{}
+	vectors := [][]float32{}
 	for _, searchResult := range searchResults.Value {
 		doc, err := assertResultValues(searchResult)
 		if err != nil {
@@ -136,11 +322,39 @@ func (s *Store) SimilaritySearch(
 		}
 
 		output = append(output, *doc)
+		if vectors != nil {
+			vectors = append(vectors, resultVector(searchResult))
+			if vectors[len(vectors)-1] == nil {
+				// the candidates are embedded again when a vector isn't retrievable.
+				vectors = nil
+			}
+		}
 	}
 
+	if opts.MMR {
+		return vectorstores.SelectByMMR(ctx, s.embedder, queryVector, output, vectors, numDocuments, opts.MMRLambda)
+	}
 	return output, nil
 }
 
+// resultVector returns the content vector of a search result, or nil if it
+// isn't returned.
+func resultVector(searchResult map[string]interface{}) []float32 {
+	values, ok := searchResult["contentVector"].([]interface{})
+	if !ok || len(values) == 0 {
+		return nil
+	}
+	vector := make([]float32, len(values))
+	for i, value := range values {
+		f, ok := value.(float64)
+		if !ok {
+			return nil
+		}
+		vector[i] = float32(f)
+	}
+	return vector
+}
+
 func assertResultValues(searchResult map[string]interface{}) (*schema.Document, error) {
 	var score float32
 	if scoreFloat64, ok := searchResult["@search.score"].(float64); ok {
@@ -139,6 +139,43 @@ func TestAzureaiSearchStoreRestWithScoreThreshold(t *testing.T) {
 	require.Len(t, docs, 6)
 }
 
+func TestAzureaiSearchStoreHybridSearch(t *testing.T) {
+	t.Parallel()
+	checkEnvVariables(t)
+	indexName := uuid.New().String()
+
+	llm := setLLM(t)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	storer, err := azureaisearch.New(
+		azureaisearch.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+
+	setIndex(t, storer, indexName)
+	defer removeIndex(t, storer, indexName)
+
+	_, err = storer.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "The printer reports error E4012 when its tray is empty"},
+		{PageContent: "The printer stops when the paper jams"},
+		{PageContent: "The router blinks when the network is down"},
+	}, vectorstores.WithNameSpace(indexName))
+	require.NoError(t, err)
+
+	docs, err := storer.SimilaritySearch(context.Background(), "E4012", 1,
+		vectorstores.WithHybridSearch(0.5),
+		vectorstores.WithNameSpace(indexName))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Contains(t, docs[0].PageContent, "E4012")
+
+	_, err = storer.SimilaritySearch(context.Background(), "E4012", 1,
+		vectorstores.WithHybridSearch(2),
+		vectorstores.WithNameSpace(indexName))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridAlpha)
+}
+
 func TestAzureaiSearchAsRetriever(t *testing.T) {
 	t.Parallel()
 	checkEnvVariables(t)
@@ -0,0 +1,41 @@
+package azureaisearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// DeleteDocumentsAPIRequest makes a request to azure AI search to delete the documents with the keys.
+func (s *Store) DeleteDocumentsAPIRequest(ctx context.Context, indexName string, keys []string) error {
+	URL := fmt.Sprintf("%s/indexes/%s/docs/index?api-version=2020-06-30", s.azureAISearchEndpoint, indexName)
+
+	documents := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		documents = append(documents, map[string]interface{}{
+			"@search.action": "delete",
+			"id":             key,
+		})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"value": documents,
+	})
+	if err != nil {
+		return fmt.Errorf("err marshalling body for azure ai search: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("err setting request for azure ai search delete documents: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	if s.azureAISearchAPIKey != "" {
+		req.Header.Add("api-key", s.azureAISearchAPIKey)
+	}
+
+	return s.httpDefaultSend(req, "azure ai search delete documents", nil)
+}
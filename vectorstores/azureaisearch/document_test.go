@@ -0,0 +1,185 @@
+package azureaisearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lengthEmbedder embeds texts as their length.
+type lengthEmbedder struct{}
+
+func (lengthEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text))}
+	}
+	return vectors, nil
+}
+
+func (lengthEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text))}, nil
+}
+
+// fakeIndex is an azure AI search index holding its documents in memory. Its
+// searches match the documents with the keys of a search.in filter on the id,
+// and all the documents for other filters.
+type fakeIndex struct {
+	mu      sync.Mutex
+	docs    map[string]map[string]any
+	filters []string
+}
+
+func newFakeStore(t *testing.T) (*Store, *fakeIndex) {
+	t.Helper()
+
+	index := &fakeIndex{docs: map[string]map[string]any{}}
+	server := httptest.NewServer(index)
+	t.Cleanup(server.Close)
+	return &Store{
+		azureAISearchEndpoint: server.URL,
+		embedder:              lengthEmbedder{},
+		client:                server.Client(),
+	}, index
+}
+
+func (f *fakeIndex) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/indexes/docs/docs/index":
+		var body struct {
+			Value []map[string]any `json:"value"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, doc := range body.Value {
+			key, _ := doc["id"].(string)
+			if doc["@search.action"] == "delete" {
+				delete(f.docs, key)
+				continue
+			}
+			f.docs[key] = doc
+		}
+	case "/indexes/docs/docs/search":
+		var input SearchDocumentsRequestInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.filters = append(f.filters, input.Filter)
+		keys := make([]string, 0, len(f.docs))
+		for key := range f.docs {
+			keys = append(keys, key)
+		}
+		if in, ok := strings.CutPrefix(input.Filter, "search.in(id, '"); ok {
+			in, _, _ = strings.Cut(in, "'")
+			keys = strings.Split(in, ",")
+		}
+		slices.Sort(keys)
+		output := SearchDocumentsRequestOuput{Value: []map[string]any{}}
+		for _, key := range keys[min(input.Skip, len(keys)):] {
+			if doc, ok := f.docs[key]; ok {
+				output.Value = append(output.Value, map[string]any{
+					"@search.score": 1.0,
+					"id":            key,
+					"content":       doc["content"],
+					"metadata":      doc["metadata"],
+				})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(output)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeIndex) keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := make([]string, 0, len(f.docs))
+	for key := range f.docs {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestDocumentManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, index := newFakeStore(t)
+	opt := vectorstores.WithNameSpace("docs")
+
+	err := store.Upsert(ctx, []string{"tokyo", "docs/osaka.md"}, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	}, opt)
+	require.NoError(t, err)
+
+	// upserted documents replace the documents with the same ids.
+	err = store.Upsert(ctx, []string{"tokyo"}, []schema.Document{
+		{PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+	}, opt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b64-ZG9jcy9vc2FrYS5tZA==", "tokyo"}, index.keys())
+	assert.Equal(t, []any{float64(len("Tokyo is a city"))}, index.docs["tokyo"]["contentVector"])
+
+	docs, err := store.Get(ctx, []string{"tokyo", "docs/osaka.md", "unknown"}, opt)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]schema.Document{
+		"tokyo":         {PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+		"docs/osaka.md": {PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	}, docs)
+
+	require.NoError(t, store.Delete(ctx, []string{"docs/osaka.md"}, opt))
+	assert.Equal(t, []string{"tokyo"}, index.keys())
+
+	require.NoError(t, store.DeleteByFilter(ctx, "country eq 'japan'", opt))
+	assert.Empty(t, index.keys())
+	assert.Equal(t, "country eq 'japan'", index.filters[len(index.filters)-1])
+
+	err = store.Upsert(ctx, []string{"a", "b"}, []schema.Document{{PageContent: "Tokyo"}}, opt)
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+	err = store.DeleteByFilter(ctx, nil, opt)
+	require.ErrorIs(t, err, vectorstores.ErrMissingFilter)
+	err = store.DeleteByFilter(ctx, map[string]any{"country": "japan"}, opt)
+	require.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestDocumentKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: "doc-1_a=", want: "doc-1_a="},
+		{id: "docs/a.md", want: "b64-ZG9jcy9hLm1k"},
+		{id: "_doc", want: "b64-X2RvYw=="},
+		{id: "", want: "b64-"},
+		// the ids starting with the prefix are encoded, so they don't collide
+		// with the keys of the encoded ids.
+		{id: "b64-ZG9jcy9hLm1k", want: "b64-YjY0LVpHOWpjeTloTG0xaw=="},
+		// the encoding of a first byte above 0xFB starts with an underscore.
+		{id: "\xfcdoc", want: "b64-_GRvYw=="},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, documentKey(tt.id), tt.id)
+	}
+}
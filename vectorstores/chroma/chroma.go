@@ -138,8 +138,16 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, stErr
 	}
 
+	limit := numDocuments
+	if opts.MMR {
+		var err error
+		if limit, err = vectorstores.MMRFetchK(opts, numDocuments); err != nil {
+			return nil, err
+		}
+	}
+
 	filter := s.getNamespacedFilter(opts)
-	qr, queryErr := s.collection.Query(ctx, []string{query}, safeIntToInt32(numDocuments), filter, nil, s.includes)
+	qr, queryErr := s.collection.Query(ctx, []string{query}, safeIntToInt32(limit), filter, nil, s.includes)
 	if queryErr != nil {
 		return nil, queryErr
 	}
@@ -149,6 +157,7 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 			ErrUnexpectedResponseLength, len(qr.Documents), len(qr.Metadatas), len(qr.Distances))
 	}
 	var sDocs []schema.Document
+	var ids []string
 	for docsI := range qr.Documents {
 		for docI := range qr.Documents[docsI] {
 			if score := 1.0 - qr.Distances[docsI][docI]; score >= scoreThreshold {
@@ -157,13 +166,73 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 					PageContent: qr.Documents[docsI][docI],
 					Score:       score,
 				})
+				ids = append(ids, qr.Ids[docsI][docI])
 			}
 		}
 	}
 
+	if opts.MMR {
+		return s.selectByMMR(ctx, query, sDocs, ids, numDocuments, opts.MMRLambda)
+	}
 	return sDocs, nil
 }
 
+// selectByMMR selects documents by maximal marginal relevance, with their
+// stored embeddings and the query embedded with the embedding function of the
+// collection. Chroma doesn't return the embeddings of the results of a query,
+// so they are got by the ids of the documents.
+func (s Store) selectByMMR(
+	ctx context.Context,
+	query string,
+	docs []schema.Document,
+	ids []string,
+	numDocuments int,
+	lambda float32,
+) ([]schema.Document, error) {
+	if len(docs) == 0 {
+		return docs, nil
+	}
+	embedder := embeddingFunction{s.collection.EmbeddingFunction}
+	queryVector, err := embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	vectors, err := s.embeddings(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return vectorstores.SelectByMMR(ctx, embedder, queryVector, docs, vectors, numDocuments, lambda)
+}
+
+// embeddings returns the stored embeddings of the documents with the ids, or
+// nil if some of them have none.
+func (s Store) embeddings(ctx context.Context, ids []string) ([][]float32, error) {
+	gr, err := s.collection.Get(ctx, nil, nil, ids, []chromatypes.QueryEnum{chromatypes.IEmbeddings})
+	if err != nil {
+		return nil, err
+	}
+	if len(gr.Ids) != len(gr.Embeddings) {
+		return nil, fmt.Errorf("%w: gr.Ids[%d], gr.Embeddings[%d]",
+			ErrUnexpectedResponseLength, len(gr.Ids), len(gr.Embeddings))
+	}
+
+	byID := make(map[string][]float32, len(gr.Ids))
+	for i, id := range gr.Ids {
+		if embedding := gr.Embeddings[i]; embedding != nil && embedding.ArrayOfFloat32 != nil {
+			byID[id] = *embedding.ArrayOfFloat32
+		}
+	}
+	vectors := make([][]float32, len(ids))
+	for i, id := range ids {
+		vector, ok := byID[id]
+		if !ok {
+			return nil, nil
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
 func (s Store) RemoveCollection() error {
 	if s.client == nil || s.collection == nil {
 		return fmt.Errorf("%w: no collection", ErrRemoveCollection)
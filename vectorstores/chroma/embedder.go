@@ -37,3 +37,37 @@ func (e chromaGoEmbedder) EmbedQuery(ctx context.Context, text string) (*chromat
 func (e chromaGoEmbedder) EmbedRecords(ctx context.Context, records []*chromatypes.Record, force bool) error {
 	return chromatypes.EmbedRecordsDefaultImpl(e, ctx, records, force)
 }
+
+var _ embeddings.Embedder = embeddingFunction{} // compile-time check
+
+// embeddingFunction adapts a 'chroma_go.EmbeddingFunction' to an 'embeddings.Embedder'.
+type embeddingFunction struct {
+	chromatypes.EmbeddingFunction
+}
+
+func (e embeddingFunction) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	_chrmembeddings, err := e.EmbeddingFunction.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	_embeddings := make([][]float32, len(_chrmembeddings))
+	for i, emb := range _chrmembeddings {
+		_embeddings[i] = float32Embedding(emb)
+	}
+	return _embeddings, nil
+}
+
+func (e embeddingFunction) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	_chrmembedding, err := e.EmbeddingFunction.EmbedQuery(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return float32Embedding(_chrmembedding), nil
+}
+
+func float32Embedding(emb *chromatypes.Embedding) []float32 {
+	if emb == nil || emb.GetFloat32() == nil {
+		return nil
+	}
+	return *emb.GetFloat32()
+}
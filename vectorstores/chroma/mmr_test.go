@@ -0,0 +1,107 @@
+package chroma_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores/chroma"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// axisEmbedder embeds the texts it knows as their vector, and records the
+// texts of the calls to EmbedDocuments.
+type axisEmbedder struct {
+	vectors map[string][]float32
+
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (e *axisEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	e.mu.Lock()
+	e.calls = append(e.calls, texts)
+	e.mu.Unlock()
+
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.vectors[text]
+	}
+	return vectors, nil
+}
+
+func (e *axisEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.EmbedDocuments(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+// newFakeChroma returns a server answering the requests of a chroma client
+// with the results of a query and the stored embeddings of the documents.
+func newFakeChroma(t *testing.T, query, get any) *httptest.Server {
+	t.Helper()
+
+	responses := map[string]any{
+		"/api/v1/version":              "0.4.14",
+		"/api/v1/heartbeat":            map[string]any{"nanosecond heartbeat": 1},
+		"/api/v1/collections":          map[string]any{"id": "id", "name": "cities", "metadata": map[string]any{}},
+		"/api/v1/collections/id/query": query,
+		"/api/v1/collections/id/get":   get,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b, err := json.Marshal(response)
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(b)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestChromaMMRUsesStoredEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeChroma(t,
+		map[string]any{
+			"ids":       [][]string{{"tokyo", "kyoto", "paris"}},
+			"documents": [][]string{{"Tokyo", "Kyoto", "Paris"}},
+			"metadatas": [][]map[string]any{{{}, {}, {}}},
+			"distances": [][]float32{{0.1, 0.1, 0.5}},
+		},
+		map[string]any{
+			"ids":        []string{"paris", "kyoto", "tokyo"},
+			"embeddings": [][]float32{{0.5, 0.5}, {1, 0.01}, {1, 0}},
+		},
+	)
+	embedder := &axisEmbedder{vectors: map[string][]float32{"japan": {1, 0}}}
+	s, err := chroma.New(
+		chroma.WithChromaURL(server.URL),
+		chroma.WithNameSpace("cities"),
+		chroma.WithEmbedder(embedder),
+	)
+	require.NoError(t, err)
+
+	docs, err := s.SimilaritySearch(context.Background(), "japan", 2, vectorstores.WithMMR(3, 0.3))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	// Kyoto is nearly the same as Tokyo, so the more diverse Paris is picked.
+	assert.Equal(t, "Tokyo", docs[0].PageContent)
+	assert.Equal(t, "Paris", docs[1].PageContent)
+
+	// only the query was embedded, by the query and by the MMR selection.
+	for _, texts := range embedder.calls {
+		assert.Equal(t, []string{"japan"}, texts)
+	}
+}
@@ -0,0 +1,9 @@
+// Package inmemory contains an implementation of the VectorStore interface
+// keeping the documents in memory, without external database, for tests and
+// small applications.
+//
+// The documents are searched by the cosine similarity of their embeddings,
+// with an exact index comparing the query with every document, or with an
+// approximate HNSW index, see WithHNSW. The store can be saved to a file and
+// loaded again, see Store.SaveFile and Store.LoadFile.
+package inmemory
@@ -0,0 +1,199 @@
+package inmemory
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+)
+
+const (
+	_defaultHNSWM              = 16
+	_defaultHNSWEfConstruction = 200
+	_defaultHNSWEfSearch       = 64
+)
+
+// hnsw is an approximate index, a hierarchical navigable small world graph:
+// the vectors are linked to their nearest neighbors on layers, each one
+// holding a sample of the vectors of the layer below, and searches go down
+// the layers from a single entry point, closer to the query on each layer.
+//
+// See https://arxiv.org/abs/1603.09320.
+type hnsw struct {
+	m              int
+	efConstruction int
+	efSearch       int
+	levelFactor    float64
+	rng            *rand.Rand
+
+	vectors [][]float32
+	// neighbors are the neighbors of each vector on the layers it is on.
+	neighbors [][][]int
+	entry     int
+	maxLevel  int
+}
+
+var _ index = (*hnsw)(nil)
+
+func newHNSW(m, efConstruction, efSearch int) *hnsw {
+	if m < 2 {
+		m = _defaultHNSWM
+	}
+	if efConstruction <= 0 {
+		efConstruction = _defaultHNSWEfConstruction
+	}
+	if efSearch <= 0 {
+		efSearch = _defaultHNSWEfSearch
+	}
+	return &hnsw{
+		m:              m,
+		efConstruction: max(efConstruction, m),
+		efSearch:       efSearch,
+		levelFactor:    1 / math.Log(float64(m)),
+		// the graph only depends on the vectors and their order.
+		rng:   rand.New(rand.NewSource(1)), //nolint:gosec
+		entry: -1,
+	}
+}
+
+// maxNeighbors is the maximum number of neighbors of a vector on a layer.
+func (h *hnsw) maxNeighbors(level int) int {
+	if level == 0 {
+		return 2 * h.m
+	}
+	return h.m
+}
+
+func (h *hnsw) add(vector []float32) {
+	id := len(h.vectors)
+	level := int(-math.Log(1-h.rng.Float64()) * h.levelFactor)
+	h.vectors = append(h.vectors, vector)
+	h.neighbors = append(h.neighbors, make([][]int, level+1))
+	if h.entry < 0 {
+		h.entry, h.maxLevel = id, level
+		return
+	}
+
+	entries := []candidate{{id: h.entry, dist: distance(vector, h.vectors[h.entry])}}
+	for l := h.maxLevel; l > level; l-- {
+		entries = h.searchLayer(vector, entries, 1, l, nil)
+	}
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		nearest := h.searchLayer(vector, entries, h.efConstruction, l, nil)
+		h.neighbors[id][l] = h.selectNeighbors(nearest, h.m)
+		for _, n := range h.neighbors[id][l] {
+			h.link(n, id, l)
+		}
+		entries = nearest
+	}
+	if level > h.maxLevel {
+		h.entry, h.maxLevel = id, level
+	}
+}
+
+// link adds a neighbor to a vector on a layer, and prunes its neighbors if it
+// has too many.
+func (h *hnsw) link(id, neighbor, level int) {
+	links := append(h.neighbors[id][level], neighbor)
+	if len(links) > h.maxNeighbors(level) {
+		linked := make([]candidate, len(links))
+		for i, n := range links {
+			linked[i] = candidate{id: n, dist: distance(h.vectors[id], h.vectors[n])}
+		}
+		sorted := &candidates{items: linked}
+		links = h.selectNeighbors(sorted.sorted(), h.maxNeighbors(level))
+	}
+	h.neighbors[id][level] = links
+}
+
+// selectNeighbors selects at most m of the candidates, sorted by distance,
+// as neighbors, preferring the ones closer to the vector than to the selected
+// ones, so that the neighbors lead to different regions of the graph.
+func (h *hnsw) selectNeighbors(sorted []candidate, m int) []int {
+	selected := make([]int, 0, m)
+	var pruned []int
+	for _, c := range sorted {
+		if len(selected) == m {
+			break
+		}
+		diverse := true
+		for _, s := range selected {
+			if distance(h.vectors[c.id], h.vectors[s]) < c.dist {
+				diverse = false
+				break
+			}
+		}
+		if diverse {
+			selected = append(selected, c.id)
+		} else {
+			pruned = append(pruned, c.id)
+		}
+	}
+	for _, id := range pruned {
+		if len(selected) == m {
+			break
+		}
+		selected = append(selected, id)
+	}
+	return selected
+}
+
+// searchLayer returns the ef accepted vectors nearest to the query found on a
+// layer from the entries, in order of distance. The vectors which aren't
+// accepted are still visited to find the others.
+func (h *hnsw) searchLayer(
+	query []float32,
+	entries []candidate,
+	ef, level int,
+	accept func(id int) bool,
+) []candidate {
+	visited := make(map[int]bool, ef*h.m)
+	toVisit := &candidates{}
+	nearest := &candidates{max: true}
+	for _, e := range entries {
+		visited[e.id] = true
+		heap.Push(toVisit, e)
+		if accept == nil || accept(e.id) {
+			heap.Push(nearest, e)
+		}
+	}
+	for nearest.Len() > ef {
+		heap.Pop(nearest)
+	}
+
+	for toVisit.Len() > 0 {
+		c := heap.Pop(toVisit).(candidate)
+		if nearest.Len() >= ef && c.dist > nearest.top().dist {
+			break
+		}
+		for _, n := range h.neighbors[c.id][level] {
+			if visited[n] {
+				continue
+			}
+			visited[n] = true
+			dist := distance(query, h.vectors[n])
+			if nearest.Len() >= ef && dist >= nearest.top().dist {
+				continue
+			}
+			heap.Push(toVisit, candidate{id: n, dist: dist})
+			if accept == nil || accept(n) {
+				heap.Push(nearest, candidate{id: n, dist: dist})
+				if nearest.Len() > ef {
+					heap.Pop(nearest)
+				}
+			}
+		}
+	}
+	return nearest.sorted()
+}
+
+func (h *hnsw) search(query []float32, k int, accept func(id int) bool) []candidate {
+	if h.entry < 0 || k <= 0 {
+		return nil
+	}
+	entries := []candidate{{id: h.entry, dist: distance(query, h.vectors[h.entry])}}
+	for l := h.maxLevel; l > 0; l-- {
+		entries = h.searchLayer(query, entries, 1, l, nil)
+	}
+	nearest := h.searchLayer(query, entries, max(h.efSearch, k), 0, accept)
+	return nearest[:min(k, len(nearest))]
+}
@@ -0,0 +1,48 @@
+package inmemory
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHNSW_Recall(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(42)) //nolint:gosec
+	randomVector := func() []float32 {
+		vector := make([]float32, 16)
+		for i := range vector {
+			vector[i] = float32(rng.NormFloat64())
+		}
+		return normalize(vector)
+	}
+
+	exact, approximate := &bruteForce{}, newHNSW(8, 64, 32)
+	for i := 0; i < 2000; i++ {
+		vector := randomVector()
+		exact.add(vector)
+		approximate.add(vector)
+	}
+
+	even := func(id int) bool { return id%2 == 0 }
+	for _, accept := range []func(int) bool{func(int) bool { return true }, even} {
+		found, total := 0, 0
+		for q := 0; q < 50; q++ {
+			query := randomVector()
+			want := make(map[int]bool)
+			for _, c := range exact.search(query, 10, accept) {
+				want[c.id] = true
+			}
+			for _, c := range approximate.search(query, 10, accept) {
+				assert.True(t, accept(c.id))
+				if want[c.id] {
+					found++
+				}
+			}
+			total += len(want)
+		}
+		assert.Greater(t, float64(found)/float64(total), 0.9)
+	}
+}
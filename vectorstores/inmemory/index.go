@@ -0,0 +1,108 @@
+package inmemory
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// index finds the nearest neighbors of a query among the vectors added to it,
+// identified by their order of addition. The vectors are normalized, and the
+// distance between two vectors is 1 minus their cosine similarity.
+type index interface {
+	add(vector []float32)
+	// search returns at most k of the accepted vectors nearest to the query,
+	// in order of distance.
+	search(query []float32, k int, accept func(id int) bool) []candidate
+}
+
+// candidate is a vector of an index, with its distance to a query.
+type candidate struct {
+	id   int
+	dist float32
+}
+
+// candidates is a heap of candidates, with the nearest one on top, or the
+// farthest one with max.
+type candidates struct {
+	items []candidate
+	max   bool
+}
+
+var _ heap.Interface = (*candidates)(nil)
+
+func (h *candidates) Len() int { return len(h.items) }
+
+func (h *candidates) Less(i, j int) bool {
+	if h.max {
+		return h.items[i].dist > h.items[j].dist
+	}
+	return h.items[i].dist < h.items[j].dist
+}
+
+func (h *candidates) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *candidates) Push(x any) { h.items = append(h.items, x.(candidate)) }
+
+func (h *candidates) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+func (h *candidates) top() candidate { return h.items[0] }
+
+// sorted returns the candidates in order of distance.
+func (h *candidates) sorted() []candidate {
+	items := slices.Clone(h.items)
+	slices.SortFunc(items, func(a, b candidate) int {
+		switch {
+		case a.dist < b.dist:
+			return -1
+		case a.dist > b.dist:
+			return 1
+		default:
+			return a.id - b.id
+		}
+	})
+	return items
+}
+
+// bruteForce is an exact index comparing the query with every vector.
+type bruteForce struct {
+	vectors [][]float32
+}
+
+var _ index = (*bruteForce)(nil)
+
+func (b *bruteForce) add(vector []float32) {
+	b.vectors = append(b.vectors, vector)
+}
+
+func (b *bruteForce) search(query []float32, k int, accept func(id int) bool) []candidate {
+	if k <= 0 {
+		return nil
+	}
+	nearest := &candidates{max: true}
+	for id, vector := range b.vectors {
+		if !accept(id) {
+			continue
+		}
+		dist := distance(query, vector)
+		if nearest.Len() < k {
+			heap.Push(nearest, candidate{id: id, dist: dist})
+		} else if dist < nearest.top().dist {
+			nearest.items[0] = candidate{id: id, dist: dist}
+			heap.Fix(nearest, 0)
+		}
+	}
+	return nearest.sorted()
+}
+
+// distance returns 1 minus the cosine similarity of two normalized vectors.
+func distance(a, b []float32) float32 {
+	var dot float32
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	return 1 - dot
+}
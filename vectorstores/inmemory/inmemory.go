@@ -0,0 +1,446 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/IT-Tech-Company/langchaingo/embeddings"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidScoreThreshold is returned when the score threshold isn't
+	// between 0 and 1.
+	ErrInvalidScoreThreshold = errors.New("score threshold must be between 0 and 1")
+	// ErrInvalidFilters is returned when the filters have an unsupported type.
+	ErrInvalidFilters = errors.New("invalid filters")
+	// ErrEmbedderWrongNumberVectors is returned when the embedder returns a
+	// number of vectors different from the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New("number of vectors from embedder does not match number of documents")
+	// ErrInvalidDimension is returned when a vector doesn't have the dimension
+	// of the vectors of the store.
+	ErrInvalidDimension = errors.New("vector dimension does not match the store")
+)
+
+// entry is a document of the store, at the id of its vector in the index.
+type entry struct {
+	id        string
+	nameSpace string
+	doc       schema.Document
+	vector    []float32
+	deleted   bool
+}
+
+// Store is a vector store keeping the documents and their embeddings in
+// memory. It is safe for concurrent use.
+type Store struct {
+	embedder embeddings.Embedder
+	newIndex func() index
+
+	mu      sync.RWMutex
+	index   index
+	entries []entry
+	// ids are the entries of the documents by id.
+	ids       map[string]int
+	deleted   int
+	dimension int
+}
+
+var _ vectorstores.DocumentManager = (*Store)(nil)
+
+// New creates an empty store.
+func New(opts ...Option) (*Store, error) {
+	return applyOptions(opts...)
+}
+
+// AddDocuments adds the documents to the store with new ids, and returns the
+// ids. The name space of the options is the one of the documents.
+func (s *Store) AddDocuments(
+	ctx context.Context,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	opts := s.getOptions(options...)
+	docs = deduplicate(ctx, opts, docs)
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	vectors, err := s.embedDocuments(ctx, opts, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(docs))
+	for i := range docs {
+		ids[i] = uuid.NewString()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.add(ids, opts.NameSpace, docs, vectors); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SimilaritySearch returns the documents of the name space of the options
+// most similar to the query, by the cosine similarity of their embeddings,
+// which is the score of the documents.
+//
+// Filters are either a map[string]any of metadata values the documents must
+// have, numbers of any type being equal to the same numbers of other types,
+// or a func(metadata map[string]any) bool accepting the documents. Hybrid
+// searches aren't supported.
+func (s *Store) SimilaritySearch(
+	ctx context.Context,
+	query string,
+	numDocuments int,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	match, err := metadataFilter(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+	limit := numDocuments
+	if opts.MMR {
+		if limit, err = vectorstores.MMRFetchK(opts, numDocuments); err != nil {
+			return nil, err
+		}
+	}
+
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	queryVector, err := embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	queryVector = normalize(queryVector)
+
+	docs, vectors, err := s.search(queryVector, limit, opts, match)
+	if err != nil || !opts.MMR {
+		return docs, err
+	}
+	return vectorstores.SelectByMMR(ctx, embedder, queryVector, docs, vectors, numDocuments, opts.MMRLambda)
+}
+
+func (s *Store) search(
+	queryVector []float32,
+	limit int,
+	opts vectorstores.Options,
+	match func(map[string]any) bool,
+) ([]schema.Document, [][]float32, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.dimension != 0 && len(queryVector) != s.dimension {
+		return nil, nil, fmt.Errorf("%w: query vector of dimension %d instead of %d",
+			ErrInvalidDimension, len(queryVector), s.dimension)
+	}
+
+	nearest := s.index.search(queryVector, limit, func(id int) bool {
+		e := &s.entries[id]
+		return !e.deleted && e.nameSpace == opts.NameSpace && match(e.doc.Metadata)
+	})
+
+	docs := make([]schema.Document, 0, len(nearest))
+	vectors := make([][]float32, 0, len(nearest))
+	for _, c := range nearest {
+		score := 1 - c.dist
+		if opts.ScoreThreshold > 0 && score < opts.ScoreThreshold {
+			break
+		}
+		e := &s.entries[c.id]
+		docs = append(docs, schema.Document{
+			PageContent: e.doc.PageContent,
+			Metadata:    maps.Clone(e.doc.Metadata),
+			Score:       score,
+		})
+		vectors = append(vectors, e.vector)
+	}
+	return docs, vectors, nil
+}
+
+// Upsert adds the documents with the ids, replacing the documents with the
+// same ids. The name space of the options is the one of the documents.
+func (s *Store) Upsert(
+	ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if len(ids) != len(docs) {
+		return vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+	vectors, err := s.embedDocuments(ctx, opts, docs)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.add(ids, opts.NameSpace, docs, vectors)
+}
+
+// Get returns the documents with the ids, by id, in any name space. Unknown
+// ids are ignored.
+func (s *Store) Get(_ context.Context, ids []string, _ ...vectorstores.Option) (map[string]schema.Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	docs := make(map[string]schema.Document, len(ids))
+	for _, id := range ids {
+		if i, ok := s.ids[id]; ok {
+			docs[id] = schema.Document{
+				PageContent: s.entries[i].doc.PageContent,
+				Metadata:    maps.Clone(s.entries[i].doc.Metadata),
+			}
+		}
+	}
+	return docs, nil
+}
+
+// Delete deletes the documents with the ids from the store, in any name
+// space. Unknown ids are ignored.
+func (s *Store) Delete(_ context.Context, ids []string, _ ...vectorstores.Option) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		s.remove(id)
+	}
+	s.compact()
+	return nil
+}
+
+// DeleteByFilter deletes the documents of the name space of the options
+// matching the filter, see SimilaritySearch for its format.
+func (s *Store) DeleteByFilter(_ context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+	match, err := metadataFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.entries {
+		if !e.deleted && e.nameSpace == opts.NameSpace && match(e.doc.Metadata) {
+			s.remove(e.id)
+		}
+	}
+	s.compact()
+	return nil
+}
+
+// Len returns the number of documents in the store.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.ids)
+}
+
+// add adds the documents with their ids and vectors, replacing the documents
+// with the same ids. It must be called with the lock held.
+func (s *Store) add(ids []string, nameSpace string, docs []schema.Document, vectors [][]float32) error {
+	dimension := s.dimension
+	for _, v := range vectors {
+		if dimension == 0 {
+			dimension = len(v)
+		}
+		if len(v) != dimension {
+			return fmt.Errorf("%w: vector of dimension %d instead of %d", ErrInvalidDimension, len(v), dimension)
+		}
+	}
+	s.dimension = dimension
+
+	for i, doc := range docs {
+		s.remove(ids[i])
+		vector := normalize(vectors[i])
+		s.ids[ids[i]] = len(s.entries)
+		s.entries = append(s.entries, entry{
+			id:        ids[i],
+			nameSpace: nameSpace,
+			doc:       schema.Document{PageContent: doc.PageContent, Metadata: maps.Clone(doc.Metadata)},
+			vector:    vector,
+		})
+		s.index.add(vector)
+	}
+	s.compact()
+	return nil
+}
+
+// remove marks the document with the id as deleted. The vectors of the index
+// can't be removed, so the deleted documents are skipped by the searches
+// until the store is compacted. It must be called with the lock held.
+func (s *Store) remove(id string) {
+	i, ok := s.ids[id]
+	if !ok {
+		return
+	}
+	delete(s.ids, id)
+	s.entries[i].deleted = true
+	s.entries[i].doc = schema.Document{}
+	s.deleted++
+}
+
+// compact rebuilds the index without the deleted documents once they are the
+// majority. It must be called with the lock held.
+func (s *Store) compact() {
+	if s.deleted*2 <= len(s.entries) {
+		return
+	}
+	entries := make([]entry, 0, len(s.entries)-s.deleted)
+	for _, e := range s.entries {
+		if !e.deleted {
+			entries = append(entries, e)
+		}
+	}
+	s.reset(entries)
+}
+
+// reset replaces the documents of the store. It must be called with the lock
+// held.
+func (s *Store) reset(entries []entry) {
+	s.entries = entries
+	s.ids = make(map[string]int, len(entries))
+	s.index = s.newIndex()
+	s.deleted = 0
+	s.dimension = 0
+	for i, e := range entries {
+		s.ids[e.id] = i
+		s.index.add(e.vector)
+		s.dimension = len(e.vector)
+	}
+}
+
+func (s *Store) embedDocuments(
+	ctx context.Context,
+	opts vectorstores.Options,
+	docs []schema.Document,
+) ([][]float32, error) {
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+	return vectors, nil
+}
+
+func (s *Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func deduplicate(ctx context.Context, opts vectorstores.Options, docs []schema.Document) []schema.Document {
+	if opts.Deduplicater == nil {
+		return docs
+	}
+
+	filtered := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if !opts.Deduplicater(ctx, doc) {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered
+}
+
+// normalize returns a copy of the vector with a norm of 1, or of 0 for a
+// zero vector.
+func normalize(vector []float32) []float32 {
+	var norm float64
+	for _, x := range vector {
+		norm += float64(x) * float64(x)
+	}
+	normalized := make([]float32, len(vector))
+	if norm == 0 {
+		return normalized
+	}
+	norm = math.Sqrt(norm)
+	for i, x := range vector {
+		normalized[i] = float32(float64(x) / norm)
+	}
+	return normalized
+}
+
+// metadataFilter returns the function matching the metadata of the documents
+// with the filters.
+func metadataFilter(filters any) (func(metadata map[string]any) bool, error) {
+	switch filters := filters.(type) {
+	case nil:
+		return func(map[string]any) bool { return true }, nil
+	case func(map[string]any) bool:
+		return filters, nil
+	case map[string]any:
+		return func(metadata map[string]any) bool {
+			for key, value := range filters {
+				if v, ok := metadata[key]; !ok || !equalValues(v, value) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %T", ErrInvalidFilters, filters)
+	}
+}
+
+// equalValues reports whether two metadata values are equal, comparing the
+// numbers by value, as the numbers of a loaded snapshot are float64.
+func equalValues(a, b any) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch {
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	case v.CanFloat():
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
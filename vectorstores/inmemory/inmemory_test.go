@@ -0,0 +1,238 @@
+package inmemory_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wordEmbedder embeds texts by counting the occurrences of a few words.
+type wordEmbedder struct{}
+
+func (wordEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = wordEmbedder{}.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (wordEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	words := []string{"japan", "capital", "city", "food", "potato"}
+	vector := make([]float32, len(words))
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		for i, w := range words {
+			if strings.Trim(word, "?,.") == w {
+				vector[i]++
+			}
+		}
+	}
+	return vector, nil
+}
+
+func newStore(t *testing.T, opts ...inmemory.Option) *inmemory.Store {
+	t.Helper()
+
+	store, err := inmemory.New(append([]inmemory.Option{inmemory.WithEmbedder(wordEmbedder{})}, opts...)...)
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "Tokyo is the capital city of Japan", Metadata: map[string]any{"country": "japan", "year": 1868}},
+		{PageContent: "Kyoto is a city of Japan", Metadata: map[string]any{"country": "japan", "year": 794}},
+		{PageContent: "Paris is the capital city", Metadata: map[string]any{"country": "france"}},
+		{PageContent: "Potato is food"},
+	})
+	require.NoError(t, err)
+	return store
+}
+
+func contents(docs []schema.Document) []string {
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+	return texts
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	for name, opts := range map[string][]inmemory.Option{
+		"brute force": nil,
+		"hnsw":        {inmemory.WithHNSW(4, 0, 0)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			store := newStore(t, opts...)
+			assert.Equal(t, 4, store.Len())
+
+			docs, err := store.SimilaritySearch(ctx, "capital of Japan", 2)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Tokyo is the capital city of Japan", "Kyoto is a city of Japan"}, contents(docs))
+			assert.InDelta(t, 0.8165, docs[0].Score, 1e-4)
+			assert.Equal(t, "japan", docs[0].Metadata["country"])
+
+			docs, err = store.SimilaritySearch(ctx, "capital of Japan", 4, vectorstores.WithScoreThreshold(0.5))
+			require.NoError(t, err)
+			assert.Len(t, docs, 3)
+
+			docs, err = store.SimilaritySearch(ctx, "capital city", 4,
+				vectorstores.WithFilters(map[string]any{"country": "japan", "year": int64(794)}))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Kyoto is a city of Japan"}, contents(docs))
+
+			docs, err = store.SimilaritySearch(ctx, "capital city", 4,
+				vectorstores.WithFilters(func(metadata map[string]any) bool { return metadata["country"] == nil }))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Potato is food"}, contents(docs))
+
+			// the documents of other name spaces aren't found.
+			_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "Osaka is a city of Japan"}},
+				vectorstores.WithNameSpace("other"))
+			require.NoError(t, err)
+			docs, err = store.SimilaritySearch(ctx, "city of Japan", 4, vectorstores.WithNameSpace("other"))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Osaka is a city of Japan"}, contents(docs))
+		})
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, err := inmemory.New(inmemory.WithEmbedder(wordEmbedder{}), inmemory.WithHNSW(0, 0, 0))
+	require.NoError(t, err)
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "Tokyo is the capital of Japan"},
+		{PageContent: "Kyoto is a city of Japan"},
+		{PageContent: "Potato is food"},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+
+	require.NoError(t, store.Delete(ctx, ids[:1]))
+	assert.Equal(t, 2, store.Len())
+	docs, err := store.SimilaritySearch(ctx, "capital of Japan", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Kyoto is a city of Japan", "Potato is food"}, contents(docs))
+
+	// the index is rebuilt once most documents are deleted.
+	require.NoError(t, store.Delete(ctx, []string{ids[1], "unknown"}))
+	docs, err = store.SimilaritySearch(ctx, "capital of Japan", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Potato is food"}, contents(docs))
+}
+
+func TestStore_DocumentManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var store vectorstores.DocumentManager = newStore(t)
+
+	err := store.Upsert(ctx, []string{"tokyo", "osaka"}, []schema.Document{
+		{PageContent: "Tokyo is the capital of Japan", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Osaka is a city of Japan", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	// upserted documents replace the documents with the same ids.
+	err = store.Upsert(ctx, []string{"tokyo"}, []schema.Document{
+		{PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+	docs, err := store.Get(ctx, []string{"tokyo", "osaka", "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]schema.Document{
+		"tokyo": {PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+		"osaka": {PageContent: "Osaka is a city of Japan", Metadata: map[string]any{"country": "japan"}},
+	}, docs)
+
+	require.NoError(t, store.DeleteByFilter(ctx, map[string]any{"country": "japan"}))
+	found, err := store.SimilaritySearch(ctx, "city of Japan", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Paris is the capital city", "Potato is food"}, contents(found))
+	docs, err = store.Get(ctx, []string{"tokyo", "osaka"})
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+
+	err = store.Upsert(ctx, []string{"a", "b"}, []schema.Document{{PageContent: "Potato"}})
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+	err = store.DeleteByFilter(ctx, nil)
+	require.ErrorIs(t, err, vectorstores.ErrMissingFilter)
+	err = store.DeleteByFilter(ctx, "country = japan")
+	require.ErrorIs(t, err, inmemory.ErrInvalidFilters)
+}
+
+func TestStore_MMR(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newStore(t)
+	_, err := store.AddDocuments(ctx, []schema.Document{{PageContent: "Tokyo, capital city of Japan"}})
+	require.NoError(t, err)
+
+	// the duplicate of the most relevant document is skipped.
+	docs, err := store.SimilaritySearch(ctx, "capital city of Japan", 2, vectorstores.WithMMR(4, 0.3))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.NotContains(t, docs[1].PageContent, "Tokyo")
+
+	_, err = store.SimilaritySearch(ctx, "capital city of Japan", 2, vectorstores.WithMMR(4, 2))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidMMRLambda)
+}
+
+func TestStore_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newStore(t)
+	path := filepath.Join(t.TempDir(), "store.json")
+	require.NoError(t, store.SaveFile(path))
+
+	loaded, err := inmemory.New(inmemory.WithEmbedder(wordEmbedder{}), inmemory.WithHNSW(0, 0, 0))
+	require.NoError(t, err)
+	require.NoError(t, loaded.LoadFile(path))
+	assert.Equal(t, 4, loaded.Len())
+
+	want, err := store.SimilaritySearch(ctx, "capital of Japan", 4)
+	require.NoError(t, err)
+	got, err := loaded.SimilaritySearch(ctx, "capital of Japan", 4)
+	require.NoError(t, err)
+	assert.Equal(t, contents(want), contents(got))
+
+	// the numbers of the metadata are float64 once loaded.
+	got, err = loaded.SimilaritySearch(ctx, "city", 4, vectorstores.WithFilters(map[string]any{"year": 1868}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Tokyo is the capital city of Japan"}, contents(got))
+	assert.InDelta(t, 1868, got[0].Metadata["year"], 0)
+
+	err = loaded.Load(bytes.NewBufferString(`{"version": 2, "documents": []}`))
+	require.ErrorIs(t, err, inmemory.ErrInvalidSnapshot)
+	assert.Equal(t, 4, loaded.Len())
+}
+
+func TestStore_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := inmemory.New()
+	require.ErrorIs(t, err, inmemory.ErrInvalidOptions)
+
+	ctx := context.Background()
+	store := newStore(t)
+	_, err = store.SimilaritySearch(ctx, "Japan", 1, vectorstores.WithScoreThreshold(1.5))
+	require.ErrorIs(t, err, inmemory.ErrInvalidScoreThreshold)
+	_, err = store.SimilaritySearch(ctx, "Japan", 1, vectorstores.WithFilters("country = japan"))
+	require.ErrorIs(t, err, inmemory.ErrInvalidFilters)
+}
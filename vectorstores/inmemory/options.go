@@ -0,0 +1,52 @@
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/IT-Tech-Company/langchaingo/embeddings"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function that configures a Store.
+type Option func(s *Store)
+
+// WithEmbedder returns an Option for setting the embedder used when adding
+// documents or doing similarity search. Required.
+func WithEmbedder(embedder embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = embedder
+	}
+}
+
+// WithHNSW returns an Option making the store use an approximate HNSW index,
+// faster than the default exact index on large stores. m is the number of
+// neighbors of the vectors in the graph, 16 by default, efConstruction the
+// number of candidates considered when adding a vector, 200 by default, and
+// efSearch the number of candidates considered when searching, 64 by default
+// and at least the number of documents returned. Zeros use the defaults.
+func WithHNSW(m, efConstruction, efSearch int) Option {
+	return func(s *Store) {
+		s.newIndex = func() index {
+			return newHNSW(m, efConstruction, efSearch)
+		}
+	}
+}
+
+func applyOptions(opts ...Option) (*Store, error) {
+	s := &Store{
+		newIndex: func() index { return &bruteForce{} },
+		ids:      make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.embedder == nil {
+		return nil, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	s.index = s.newIndex()
+	return s, nil
+}
@@ -0,0 +1,131 @@
+package inmemory
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const _snapshotVersion = 1
+
+// ErrInvalidSnapshot is returned when loading a snapshot which wasn't written
+// by Save.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// snapshot is the JSON document written by Save.
+type snapshot struct {
+	Version   int                `json:"version"`
+	Documents []snapshotDocument `json:"documents"`
+}
+
+type snapshotDocument struct {
+	ID          string         `json:"id"`
+	NameSpace   string         `json:"namespace,omitempty"`
+	PageContent string         `json:"page_content"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Vector      []float32      `json:"vector"`
+}
+
+// Save writes a snapshot of the documents of the store and their embeddings,
+// as JSON. The metadata of the documents must be encodable as JSON.
+func (s *Store) Save(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap := snapshot{
+		Version:   _snapshotVersion,
+		Documents: make([]snapshotDocument, 0, len(s.ids)),
+	}
+	for _, e := range s.entries {
+		if e.deleted {
+			continue
+		}
+		snap.Documents = append(snap.Documents, snapshotDocument{
+			ID:          e.id,
+			NameSpace:   e.nameSpace,
+			PageContent: e.doc.PageContent,
+			Metadata:    e.doc.Metadata,
+			Vector:      e.vector,
+		})
+	}
+	if err := json.NewEncoder(w).Encode(snap); err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	return nil
+}
+
+// SaveFile writes a snapshot of the store to a file, see Save. The file is
+// replaced at once, so it is never left partially written.
+func (s *Store) SaveFile(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	w := bufio.NewWriter(f)
+	if err := s.Save(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Load replaces the documents of the store with the ones of a snapshot
+// written by Save. The index of the store is rebuilt from their embeddings,
+// which aren't computed again.
+func (s *Store) Load(r io.Reader) error {
+	var snap snapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	if snap.Version != _snapshotVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, snap.Version)
+	}
+
+	entries := make([]entry, len(snap.Documents))
+	seen := make(map[string]bool, len(snap.Documents))
+	for i, d := range snap.Documents {
+		if seen[d.ID] {
+			return fmt.Errorf("%w: duplicate id %q", ErrInvalidSnapshot, d.ID)
+		}
+		seen[d.ID] = true
+		if len(d.Vector) != len(snap.Documents[0].Vector) {
+			return fmt.Errorf("%w: %w", ErrInvalidSnapshot, ErrInvalidDimension)
+		}
+		entries[i] = entry{
+			id:        d.ID,
+			nameSpace: d.NameSpace,
+			vector:    normalize(d.Vector),
+		}
+		entries[i].doc.PageContent = d.PageContent
+		entries[i].doc.Metadata = d.Metadata
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reset(entries)
+	return nil
+}
+
+// LoadFile loads a snapshot written by SaveFile, see Load.
+func (s *Store) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return s.Load(bufio.NewReader(f))
+}
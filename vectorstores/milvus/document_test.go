@@ -0,0 +1,74 @@
+package milvus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := &fakeClient{}
+	embedder := &axisEmbedder{vectors: map[string][]float32{
+		"Tokyo": {1, 0}, "Osaka": {0, 1}, "Tokyo is a city": {1, 1},
+	}}
+	var store vectorstores.DocumentManager = newFakeStore(t, c, embedder)
+
+	err := store.Upsert(ctx, []string{"tokyo", "osaka"}, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	// upserted documents replace the documents with the same ids.
+	err = store.Upsert(ctx, []string{"tokyo"}, []schema.Document{
+		{PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, c.rows, 2)
+	docs, err := store.Get(ctx, []string{"tokyo", "osaka", "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]schema.Document{
+		"tokyo": {PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+		"osaka": {PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	}, docs)
+
+	// the replaced documents are kept when the insertion fails.
+	c.insertErr = errors.New("insert failed")
+	err = store.Upsert(ctx, []string{"tokyo"}, []schema.Document{{PageContent: "Tokyo"}})
+	require.ErrorIs(t, err, c.insertErr)
+	c.insertErr = nil
+	docs, err = store.Get(ctx, []string{"tokyo"})
+	require.NoError(t, err)
+	assert.Equal(t, "Tokyo is a city", docs["tokyo"].PageContent)
+
+	require.NoError(t, store.Delete(ctx, []string{"tokyo"}))
+	docs, err = store.Get(ctx, []string{"tokyo", "osaka"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"osaka"}, keys(docs))
+
+	require.NoError(t, store.DeleteByFilter(ctx, `meta["country"] == "japan"`))
+	assert.Equal(t, `meta["country"] == "japan"`, c.deleteExprs[len(c.deleteExprs)-1])
+
+	err = store.Upsert(ctx, []string{"a", "b"}, []schema.Document{{PageContent: "Tokyo"}})
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+	err = store.DeleteByFilter(ctx, nil)
+	require.ErrorIs(t, err, vectorstores.ErrMissingFilter)
+	err = store.DeleteByFilter(ctx, map[string]any{"country": "japan"})
+	require.ErrorIs(t, err, ErrInvalidFilters)
+}
+
+func keys(docs map[string]schema.Document) []string {
+	ids := make([]string, 0, len(docs))
+	for id := range docs {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,217 @@
+package milvus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient is a milvus client holding the rows of a collection in memory.
+// The methods the store doesn't use panic.
+type fakeClient struct {
+	client.Client
+
+	rows   []fakeRow
+	nextPK int64
+
+	// insertErr is returned by InsertRows if set.
+	insertErr error
+	// searchFields are the output fields of the last search.
+	searchFields []string
+	// deleteExprs are the expressions of the calls to Delete.
+	deleteExprs []string
+}
+
+type fakeRow struct {
+	pk     int64
+	text   string
+	meta   []byte
+	vector []float32
+}
+
+// Search returns all the rows, with the output fields.
+func (c *fakeClient) Search(_ context.Context, _ string, _ []string, _ string, outputFields []string,
+	_ []entity.Vector, vectorField string, _ entity.MetricType, _ int, _ entity.SearchParam,
+	_ ...client.SearchQueryOptionFunc,
+) ([]client.SearchResult, error) {
+	c.searchFields = outputFields
+	return []client.SearchResult{{
+		ResultCount: len(c.rows),
+		Fields:      c.columns(c.rows, outputFields, vectorField),
+		Scores:      make([]float32, len(c.rows)),
+	}}, nil
+}
+
+func (c *fakeClient) HasCollection(_ context.Context, _ string) (bool, error) {
+	return true, nil
+}
+
+// Query returns the rows with the ids of an expression of Store.idsExpr.
+func (c *fakeClient) Query(_ context.Context, _ string, _ []string, expr string, outputFields []string,
+	_ ...client.SearchQueryOptionFunc,
+) (client.ResultSet, error) {
+	return c.columns(c.matching(expr), outputFields, _defaultVectorField), nil
+}
+
+func (c *fakeClient) InsertRows(_ context.Context, _ string, _ string, rows []interface{}) (entity.Column, error) {
+	if c.insertErr != nil {
+		return nil, c.insertErr
+	}
+	for _, row := range rows {
+		fields := row.(map[string]any)
+		meta, err := json.Marshal(fields[_defaultMetaField])
+		if err != nil {
+			return nil, err
+		}
+		c.nextPK++
+		c.rows = append(c.rows, fakeRow{
+			pk:     c.nextPK,
+			text:   fields[_defaultTextField].(string),
+			meta:   meta,
+			vector: fields[_defaultVectorField].([]float32),
+		})
+	}
+	return nil, nil
+}
+
+func (c *fakeClient) Flush(_ context.Context, _ string, _ bool, _ ...client.FlushOption) error {
+	return nil
+}
+
+// Delete records the expression, and deletes the rows with the ids of an
+// expression of Store.idsExpr.
+func (c *fakeClient) Delete(_ context.Context, _ string, _ string, expr string) error {
+	c.deleteExprs = append(c.deleteExprs, expr)
+	matching := c.matching(expr)
+	c.rows = slices.DeleteFunc(c.rows, func(row fakeRow) bool {
+		return slices.ContainsFunc(matching, func(m fakeRow) bool { return m.pk == row.pk })
+	})
+	return nil
+}
+
+func (c *fakeClient) DeleteByPks(_ context.Context, _ string, _ string, ids entity.Column) error {
+	pks := ids.(*entity.ColumnInt64).Data()
+	c.rows = slices.DeleteFunc(c.rows, func(row fakeRow) bool {
+		return slices.Contains(pks, row.pk)
+	})
+	return nil
+}
+
+// matching returns the rows with the ids of an expression of Store.idsExpr,
+// and no rows for other expressions.
+func (c *fakeClient) matching(expr string) []fakeRow {
+	prefix := fmt.Sprintf("%s[%q] in [", _defaultMetaField, documentIDKey)
+	if !strings.HasPrefix(expr, prefix) {
+		return nil
+	}
+	ids := []string{}
+	for _, quoted := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(expr, prefix), "]"), ", ") {
+		id, err := strconv.Unquote(quoted)
+		if err == nil {
+			ids = append(ids, id)
+		}
+	}
+	var rows []fakeRow
+	for _, row := range c.rows {
+		var meta map[string]any
+		if json.Unmarshal(row.meta, &meta) != nil {
+			continue
+		}
+		if id, ok := meta[documentIDKey].(string); ok && slices.Contains(ids, id) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// columns returns the fields of the rows.
+func (c *fakeClient) columns(rows []fakeRow, fields []string, vectorField string) client.ResultSet {
+	var (
+		pks     []int64
+		texts   []string
+		metas   [][]byte
+		vectors [][]float32
+	)
+	for _, row := range rows {
+		pks = append(pks, row.pk)
+		texts = append(texts, row.text)
+		metas = append(metas, row.meta)
+		vectors = append(vectors, row.vector)
+	}
+	set := client.ResultSet{}
+	for _, field := range fields {
+		switch field {
+		case _defaultPrimaryField:
+			set = append(set, entity.NewColumnInt64(field, pks))
+		case _defaultTextField:
+			set = append(set, entity.NewColumnVarChar(field, texts))
+		case _defaultMetaField:
+			set = append(set, entity.NewColumnJSONBytes(field, metas))
+		case vectorField:
+			if len(vectors) > 0 {
+				set = append(set, entity.NewColumnFloatVector(field, len(vectors[0]), vectors))
+			}
+		}
+	}
+	return set
+}
+
+// newFakeStore returns a loaded store of the client with the default fields.
+func newFakeStore(t *testing.T, c *fakeClient, embedder *axisEmbedder) Store {
+	t.Helper()
+
+	idx, err := entity.NewIndexHNSW(entity.L2, 8, 64)
+	require.NoError(t, err)
+	s, err := applyClientOptions(WithEmbedder(embedder), WithIndex(idx))
+	require.NoError(t, err)
+	s.client = c
+	s.loaded = true
+	s.schema = &entity.Schema{Fields: []*entity.Field{
+		{Name: s.primaryField, DataType: entity.FieldTypeInt64},
+		{Name: s.textField, DataType: entity.FieldTypeVarChar},
+		{Name: s.metaField, DataType: entity.FieldTypeJSON},
+		{Name: s.vectorField, DataType: entity.FieldTypeFloatVector},
+	}}
+	return s
+}
+
+// addRows adds the documents as rows of the client, with their vectors.
+func (c *fakeClient) addRows(t *testing.T, docs []schema.Document, vectors [][]float32) {
+	t.Helper()
+
+	for i, doc := range docs {
+		meta, err := json.Marshal(doc.Metadata)
+		require.NoError(t, err)
+		c.nextPK++
+		c.rows = append(c.rows, fakeRow{pk: c.nextPK, text: doc.PageContent, meta: meta, vector: vectors[i]})
+	}
+}
+
+// axisEmbedder embeds the texts it knows as their vector, and records the
+// texts of the calls to EmbedDocuments.
+type axisEmbedder struct {
+	vectors map[string][]float32
+	calls   [][]string
+}
+
+func (e *axisEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls = append(e.calls, texts)
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.vectors[text]
+	}
+	return vectors, nil
+}
+
+func (e *axisEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return e.vectors[text], nil
+}
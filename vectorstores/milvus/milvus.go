@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/IT-Tech-Company/langchaingo/embeddings"
 	"github.com/IT-Tech-Company/langchaingo/schema"
@@ -14,6 +15,10 @@ import (
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 )
 
+// documentIDKey is the metadata key of the ids of the documents given to
+// Upsert, as the primary keys of the collection are generated by Milvus.
+const documentIDKey = "document_id"
+
 // Store is a wrapper around the milvus client.
 type Store struct {
 	dropOld          bool
@@ -40,7 +45,7 @@ type Store struct {
 }
 
 var (
-	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.DocumentManager = Store{}
 
 	ErrEmbedderWrongNumberVectors = errors.New(
 		"number of vectors from embedder does not match number of documents",
@@ -196,6 +201,15 @@ func (s *Store) load(ctx context.Context) error {
 func (s Store) AddDocuments(ctx context.Context, docs []schema.Document,
 	_ ...vectorstores.Option,
 ) ([]string, error) {
+	vectors, err := s.embedDocuments(ctx, docs)
+	if err != nil {
+		return nil, err
+	}
+	return nil, s.insertDocuments(ctx, docs, vectors)
+}
+
+// embedDocuments returns the embeddings of the documents.
+func (s Store) embedDocuments(ctx context.Context, docs []schema.Document) ([][]float32, error) {
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
@@ -209,8 +223,14 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document,
 	if len(vectors) != len(docs) {
 		return nil, ErrEmbedderWrongNumberVectors
 	}
+	return vectors, nil
+}
+
+// insertDocuments inserts the documents with their embeddings, creating the
+// collection if needed.
+func (s Store) insertDocuments(ctx context.Context, docs []schema.Document, vectors [][]float32) error {
 	if err := s.init(ctx, len(vectors[0])); err != nil {
-		return nil, err
+		return err
 	}
 
 	colsData := make([]interface{}, 0, len(docs))
@@ -223,16 +243,170 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document,
 		colsData = append(colsData, docMap)
 	}
 
-	_, err = s.client.InsertRows(ctx, s.collectionName, s.partitionName, colsData)
+	_, err := s.client.InsertRows(ctx, s.collectionName, s.partitionName, colsData)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if !s.skipFlushOnWrite {
 		if err = s.client.Flush(ctx, s.collectionName, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Upsert adds the documents with the ids, replacing the documents with the
+// same ids. The ids are stored in the metadata of the documents, under
+// "document_id".
+//
+// The primary keys of the collection are generated by milvus, so the documents
+// are replaced by inserting them and deleting the previous documents with the
+// ids by primary key, which isn't atomic: if the insertion fails the previous
+// documents are kept, but if the deletion fails both are in the collection.
+func (s Store) Upsert(ctx context.Context, ids []string, docs []schema.Document,
+	_ ...vectorstores.Option,
+) error {
+	if len(ids) != len(docs) {
+		return vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	withIDs := make([]schema.Document, len(docs))
+	for i, doc := range docs {
+		metadata := make(map[string]any, len(doc.Metadata)+1)
+		for key, value := range doc.Metadata {
+			metadata[key] = value
+		}
+		metadata[documentIDKey] = ids[i]
+		withIDs[i] = schema.Document{PageContent: doc.PageContent, Metadata: metadata}
+	}
+
+	vectors, err := s.embedDocuments(ctx, withIDs)
+	if err != nil {
+		return err
+	}
+	replaced, err := s.primaryKeys(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if err := s.insertDocuments(ctx, withIDs, vectors); err != nil {
+		return err
+	}
+	if replaced == nil || replaced.Len() == 0 {
+		return nil
+	}
+	return s.client.DeleteByPks(ctx, s.collectionName, s.partitionName, replaced)
+}
+
+// primaryKeys returns the column of the primary keys of the documents added by
+// Upsert with the ids, or nil if the collection doesn't exist.
+func (s Store) primaryKeys(ctx context.Context, ids []string) (entity.Column, error) {
+	exists, err := s.client.HasCollection(ctx, s.collectionName)
+	if err != nil || !exists {
+		return nil, err
+	}
+	resultSet, err := s.client.Query(ctx, s.collectionName, s.partitions(), s.idsExpr(ids),
+		[]string{s.primaryField},
+		client.WithSearchQueryConsistencyLevel(s.consistencyLevel),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return resultSet.GetColumn(s.primaryField), nil
+}
+
+// partitions returns the partitions of the queries of the store.
+func (s Store) partitions() []string {
+	if s.partitionName == "" {
+		return []string{}
+	}
+	return []string{s.partitionName}
+}
+
+// Get returns the documents added by Upsert with the ids, by id. The ids
+// aren't part of the metadata of the returned documents.
+func (s Store) Get(ctx context.Context, ids []string,
+	_ ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	docs := make(map[string]schema.Document, len(ids))
+	exists, err := s.client.HasCollection(ctx, s.collectionName)
+	if err != nil || !exists || len(ids) == 0 {
+		return docs, err
+	}
+
+	resultSet, err := s.client.Query(ctx, s.collectionName, s.partitions(), s.idsExpr(ids),
+		[]string{s.textField, s.metaField},
+		client.WithSearchQueryConsistencyLevel(s.consistencyLevel),
+	)
+	if err != nil {
+		return nil, err
+	}
+	textcol, ok := resultSet.GetColumn(s.textField).(*entity.ColumnVarChar)
+	if !ok {
+		return nil, fmt.Errorf("%w: text column missing", ErrColumnNotFound)
+	}
+	metacol, ok := resultSet.GetColumn(s.metaField).(*entity.ColumnJSONBytes)
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata column missing", ErrColumnNotFound)
+	}
+	for i := 0; i < textcol.Len(); i++ {
+		doc := schema.Document{}
+		if doc.PageContent, err = textcol.ValueByIdx(i); err != nil {
+			return nil, err
+		}
+		metaStr, err := metacol.ValueByIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(metaStr, &doc.Metadata); err != nil {
 			return nil, err
 		}
+		if id, ok := doc.Metadata[documentIDKey].(string); ok {
+			delete(doc.Metadata, documentIDKey)
+			docs[id] = doc
+		}
+	}
+	return docs, nil
+}
+
+// Delete deletes the documents added by Upsert with the ids.
+func (s Store) Delete(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.deleteByExpr(ctx, s.idsExpr(ids))
+}
+
+// DeleteByFilter deletes the documents matching the filter, a boolean
+// expression like the filters of SimilaritySearch.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	expr, err := s.getFilters(vectorstores.Options{Filters: filter})
+	if err != nil {
+		return err
 	}
-	return nil, nil
+	return s.deleteByExpr(ctx, expr)
+}
+
+func (s Store) deleteByExpr(ctx context.Context, expr string) error {
+	exists, err := s.client.HasCollection(ctx, s.collectionName)
+	if err != nil || !exists {
+		return err
+	}
+	return s.client.Delete(ctx, s.collectionName, s.partitionName, expr)
+}
+
+// idsExpr returns the expression matching the documents with the ids.
+func (s Store) idsExpr(ids []string) string {
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = strconv.Quote(id)
+	}
+	return fmt.Sprintf("%s[%q] in [%s]", s.metaField, documentIDKey, strings.Join(quoted, ", "))
 }
 
 func (s *Store) getSearchFields() []string {
@@ -300,6 +474,12 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 	if err != nil {
 		return nil, err
 	}
+	limit := numDocuments
+	if opts.MMR {
+		if limit, err = vectorstores.MMRFetchK(opts, numDocuments); err != nil {
+			return nil, err
+		}
+	}
 	vector, err := s.embedder.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
@@ -310,23 +490,23 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 	vectors := []entity.Vector{
 		entity.FloatVector(vector),
 	}
-	partitions := []string{}
-	if s.partitionName != "" {
-		partitions = append(partitions, s.partitionName)
-	}
 	sp := s.searchParameters
 	if opts.ScoreThreshold > 0 {
 		sp.AddRadius(float64(opts.ScoreThreshold))
 	}
+	fields := s.getSearchFields()
+	if opts.MMR {
+		fields = append(fields, s.vectorField)
+	}
 	searchResult, err := s.client.Search(ctx,
 		s.collectionName,
-		partitions,
+		s.partitions(),
 		filter,
-		s.getSearchFields(),
+		fields,
 		vectors,
 		s.vectorField,
 		s.metricType,
-		numDocuments,
+		limit,
 		sp,
 		client.WithSearchQueryConsistencyLevel(s.consistencyLevel),
 	)
@@ -334,7 +514,32 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, err
 	}
 
-	return s.convertResultToDocument(searchResult)
+	docs, err := s.convertResultToDocument(searchResult)
+	if err != nil || !opts.MMR {
+		return docs, err
+	}
+	found, err := s.resultVectors(searchResult)
+	if err != nil {
+		return nil, err
+	}
+	return vectorstores.SelectByMMR(ctx, s.embedder, vector, docs, found, numDocuments, opts.MMRLambda)
+}
+
+// resultVectors returns the vectors of the results of a search outputting the
+// vector field, in the order of convertResultToDocument.
+func (s Store) resultVectors(searchResult []client.SearchResult) ([][]float32, error) {
+	vectors := [][]float32{}
+	for _, res := range searchResult {
+		if res.ResultCount == 0 {
+			continue
+		}
+		vectorcol, ok := res.Fields.GetColumn(s.vectorField).(*entity.ColumnFloatVector)
+		if !ok {
+			return nil, fmt.Errorf("%w: vector column missing", ErrColumnNotFound)
+		}
+		vectors = append(vectors, vectorcol.Data()[:res.ResultCount]...)
+	}
+	return vectors, nil
 }
 
 // getFilters return metadata filters.
@@ -0,0 +1,39 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMMRUsesStoredVectors(t *testing.T) {
+	t.Parallel()
+
+	c := &fakeClient{}
+	c.addRows(t, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{}},
+		{PageContent: "Kyoto", Metadata: map[string]any{}},
+		{PageContent: "Paris", Metadata: map[string]any{}},
+	}, [][]float32{{1, 0}, {1, 0.01}, {0.5, 0.5}})
+	embedder := &axisEmbedder{vectors: map[string][]float32{"japan": {1, 0}}}
+	s := newFakeStore(t, c, embedder)
+
+	docs, err := s.SimilaritySearch(context.Background(), "japan", 2, vectorstores.WithMMR(3, 0.3))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	// Kyoto is nearly the same as Tokyo, so the more diverse Paris is picked.
+	assert.Equal(t, "Tokyo", docs[0].PageContent)
+	assert.Equal(t, "Paris", docs[1].PageContent)
+
+	assert.Contains(t, c.searchFields, s.vectorField)
+	assert.Empty(t, embedder.calls, "the candidates were embedded")
+
+	// other searches don't output the vectors.
+	_, err = s.SimilaritySearch(context.Background(), "japan", 2)
+	require.NoError(t, err)
+	assert.NotContains(t, c.searchFields, s.vectorField)
+}
@@ -0,0 +1,108 @@
+package vectorstores
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IT-Tech-Company/langchaingo/embeddings"
+	"github.com/IT-Tech-Company/langchaingo/schema"
+)
+
+// MMRFetchK returns the number of candidates a store fetches for a maximal
+// marginal relevance search of numDocuments documents with the options, at
+// least numDocuments. It fails if the options of WithMMR are invalid.
+func MMRFetchK(opts Options, numDocuments int) (int, error) {
+	if opts.MMRLambda < 0 || opts.MMRLambda > 1 {
+		return 0, ErrInvalidMMRLambda
+	}
+	return max(opts.MMRFetchK, numDocuments), nil
+}
+
+// MaxMarginalRelevance returns the indexes of at most k of the vectors,
+// selected in order by maximal marginal relevance to the query vector: the
+// one maximizing lambda times its cosine similarity to the query minus
+// 1-lambda times its highest cosine similarity to the vectors already
+// selected.
+func MaxMarginalRelevance(queryVector []float32, vectors [][]float32, k int, lambda float32) []int {
+	k = min(k, len(vectors))
+	selected := make([]int, 0, k)
+	if k <= 0 {
+		return selected
+	}
+
+	relevance := make([]float32, len(vectors))
+	// redundancy is the highest similarity of each vector to the selected ones.
+	redundancy := make([]float32, len(vectors))
+	picked := make([]bool, len(vectors))
+	for i, v := range vectors {
+		relevance[i] = embeddings.CosineSimilarity(queryVector, v)
+	}
+
+	for len(selected) < k {
+		best := -1
+		var bestScore float32
+		for i := range vectors {
+			if picked[i] {
+				continue
+			}
+			score := lambda * relevance[i]
+			if len(selected) > 0 {
+				score -= (1 - lambda) * redundancy[i]
+			}
+			if best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		selected = append(selected, best)
+		picked[best] = true
+		for i, v := range vectors {
+			if picked[i] {
+				continue
+			}
+			if similarity := embeddings.CosineSimilarity(vectors[best], v); len(selected) == 1 || similarity > redundancy[i] {
+				redundancy[i] = similarity
+			}
+		}
+	}
+	return selected
+}
+
+// SelectByMMR returns at most numDocuments of the candidate documents of a
+// search, selected in order by maximal marginal relevance. The vectors are the
+// embeddings of the candidates, or nil for stores which don't return them, in
+// which case the candidates are embedded with the embedder.
+func SelectByMMR(
+	ctx context.Context,
+	embedder embeddings.Embedder,
+	queryVector []float32,
+	candidates []schema.Document,
+	vectors [][]float32,
+	numDocuments int,
+	lambda float32,
+) ([]schema.Document, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+	if vectors == nil {
+		texts := make([]string, len(candidates))
+		for i, doc := range candidates {
+			texts[i] = doc.PageContent
+		}
+		var err error
+		vectors, err = embedder.EmbedDocuments(ctx, texts)
+		if err != nil {
+			return nil, fmt.Errorf("embed candidates: %w", err)
+		}
+	}
+	if len(vectors) != len(candidates) {
+		return nil, fmt.Errorf("got %d vectors for %d candidates", len(vectors), len(candidates))
+	}
+
+	indexes := MaxMarginalRelevance(queryVector, vectors, numDocuments, lambda)
+	docs := make([]schema.Document, len(indexes))
+	for i, index := range indexes {
+		docs[i] = candidates[index]
+	}
+	return docs, nil
+}
@@ -0,0 +1,69 @@
+package vectorstores
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// axisEmbedder embeds "x" and "y" as the unit vectors of the axes, and any
+// other text between them.
+type axisEmbedder struct{}
+
+func (axisEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = axisEmbedder{}.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (axisEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	switch text[0] {
+	case 'x':
+		return []float32{1, 0}, nil
+	case 'y':
+		return []float32{0, 1}, nil
+	default:
+		return []float32{1, 1}, nil
+	}
+}
+
+func TestMaxMarginalRelevance(t *testing.T) {
+	t.Parallel()
+
+	query := []float32{1, 0.3}
+	vectors := [][]float32{{1, 0.1}, {0.98, 0.05}, {0.6, 0.8}, {0, 1}}
+
+	// a plain similarity search returns the near-duplicates first.
+	assert.Equal(t, []int{0, 1, 2}, MaxMarginalRelevance(query, vectors, 3, 1))
+	assert.Equal(t, []int{0, 2, 1}, MaxMarginalRelevance(query, vectors, 3, 0.6))
+	assert.Equal(t, []int{0, 3}, MaxMarginalRelevance(query, vectors, 2, 0))
+	assert.Len(t, MaxMarginalRelevance(query, vectors, 10, 0.5), 4)
+	assert.Empty(t, MaxMarginalRelevance(query, nil, 3, 0.5))
+}
+
+func TestSelectByMMR(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	candidates := []schema.Document{{PageContent: "x1"}, {PageContent: "x2"}, {PageContent: "y1"}, {PageContent: "xy"}}
+	docs, err := SelectByMMR(ctx, axisEmbedder{}, []float32{1, 0.1}, candidates, nil, 2, 0.5)
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{{PageContent: "x1"}, {PageContent: "y1"}}, docs)
+
+	_, err = SelectByMMR(ctx, nil, []float32{1, 0}, candidates, [][]float32{{1, 0}}, 2, 0.5)
+	require.Error(t, err)
+
+	fetchK, err := MMRFetchK(Options{MMRFetchK: 20, MMRLambda: 0.5}, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 20, fetchK)
+	fetchK, err = MMRFetchK(Options{MMRLambda: 0.5}, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, fetchK)
+	_, err = MMRFetchK(Options{MMRLambda: 2}, 4)
+	require.ErrorIs(t, err, ErrInvalidMMRLambda)
+}
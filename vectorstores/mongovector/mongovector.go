@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/IT-Tech-Company/langchaingo/embeddings"
 	"github.com/IT-Tech-Company/langchaingo/schema"
@@ -13,6 +14,7 @@ import (
 )
 
 const (
+	idName                     = "_id"
 	defaultIndex               = "vector_index"
 	pageContentName            = "pageContent"
 	defaultPath                = "plot_embedding"
@@ -37,7 +39,7 @@ type Store struct {
 	numCandidates int
 }
 
-var _ vectorstores.VectorStore = &Store{}
+var _ vectorstores.DocumentManager = &Store{}
 
 // New returns a Store that can read and write to the vector store.
 func New(coll *mongo.Collection, embedder embeddings.Embedder, opts ...Option) Store {
@@ -132,6 +134,145 @@ func (store *Store) AddDocuments(
 	return ids, nil
 }
 
+// Upsert creates embeddings for the documents using the user-specified
+// embedding model, then replaces the documents with the ids in the collection,
+// inserting the ones which don't exist yet. The ids are stored as the _id of
+// the documents.
+func (store *Store) Upsert(
+	ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	opts ...vectorstores.Option,
+) error {
+	if len(ids) != len(docs) {
+		return vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	cfg, err := mergeAddOpts(store, opts...)
+	if err != nil {
+		return err
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := cfg.Embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+
+	models := make([]mongo.WriteModel, 0, len(docs))
+	for i := range vectors {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: idName, Value: ids[i]}}).
+			SetReplacement(bson.D{
+				{Key: pageContentName, Value: docs[i].PageContent},
+				{Key: store.path, Value: vectors[i]},
+				{Key: metadataName, Value: docs[i].Metadata},
+			}).
+			SetUpsert(true))
+	}
+
+	_, err = store.coll.BulkWrite(ctx, models)
+	return err
+}
+
+// Get returns the documents with the ids, by id. The ids are either the ids
+// given to Upsert or the ids returned by AddDocuments. The options are unused.
+func (store *Store) Get(
+	ctx context.Context,
+	ids []string,
+	_ ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	docs := make(map[string]schema.Document, len(ids))
+	if len(ids) == 0 {
+		return docs, nil
+	}
+
+	values := idValues(ids)
+	cur, err := store.coll.Find(ctx, idsFilter(values))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var doc struct {
+			ID          any            `bson:"_id"`
+			PageContent string         `bson:"pageContent"`
+			Metadata    map[string]any `bson:"metadata"`
+		}
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		id, ok := values[doc.ID]
+		if !ok {
+			continue
+		}
+		docs[id] = schema.Document{PageContent: doc.PageContent, Metadata: doc.Metadata}
+	}
+
+	return docs, cur.Err()
+}
+
+// Delete deletes the documents with the ids, see Get. The options are unused.
+func (store *Store) Delete(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := store.coll.DeleteMany(ctx, idsFilter(idValues(ids)))
+	return err
+}
+
+// DeleteByFilter deletes the documents matching the filter, an MQL query such
+// as bson.D{{Key: "metadata.source", Value: "a"}}. The options are unused.
+func (store *Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+
+	_, err := store.coll.DeleteMany(ctx, filter)
+	return err
+}
+
+// idValues returns the ids by the values of the _id field of their documents:
+// the ids themselves, and the ObjectIDs of the ids returned by AddDocuments.
+func idValues(ids []string) map[any]string {
+	values := make(map[any]string, len(ids))
+	for _, id := range ids {
+		values[id] = id
+
+		hex := strings.TrimSuffix(strings.TrimPrefix(id, `ObjectID("`), `")`)
+		if oid, err := bson.ObjectIDFromHex(hex); err == nil {
+			values[oid] = id
+		}
+	}
+
+	return values
+}
+
+// idsFilter returns a filter matching the documents whose _id is one of the
+// values.
+func idsFilter(values map[any]string) bson.D {
+	in := make(bson.A, 0, len(values))
+	for value := range values {
+		in = append(in, value)
+	}
+
+	return bson.D{{Key: idName, Value: bson.D{{Key: "$in", Value: in}}}}
+}
+
 func mergeSearchOpts(store *Store, opts ...vectorstores.Option) (*vectorstores.Options, error) {
 	mopts := &vectorstores.Options{}
 	for _, set := range opts {
@@ -194,9 +335,16 @@ func (store *Store) SimilaritySearch(
 		return nil, err
 	}
 
-	numCandidates := defaultNumCandidatesScalar * numDocuments
+	limit := numDocuments
+	if cfg.MMR {
+		if limit, err = vectorstores.MMRFetchK(*cfg, numDocuments); err != nil {
+			return nil, err
+		}
+	}
+
+	numCandidates := defaultNumCandidatesScalar * limit
 	if store.numCandidates == 0 {
-		numCandidates = numDocuments
+		numCandidates = limit
 	}
 
 	// Create the pipeline for performing the similarity search.
@@ -212,7 +360,7 @@ func (store *Store) SimilaritySearch(
 		Path:          store.path,
 		QueryVector:   vector,
 		NumCandidates: numCandidates,
-		Limit:         numDocuments,
+		Limit:         limit,
 		Filter:        cfg.Filters,
 	}
 
@@ -232,6 +380,7 @@ func (store *Store) SimilaritySearch(
 	}
 
 	found := []schema.Document{}
+	var vectors [][]float32
 	for cur.Next(ctx) {
 		doc := schema.Document{}
 		err := cur.Decode(&doc)
@@ -244,7 +393,19 @@ func (store *Store) SimilaritySearch(
 		}
 
 		found = append(found, doc)
+
+		// The embeddings of the documents are kept for MMR.
+		if cfg.MMR {
+			var docVector []float32
+			if err := cur.Current.Lookup(store.path).Unmarshal(&docVector); err != nil {
+				return nil, err
+			}
+			vectors = append(vectors, docVector)
+		}
 	}
 
+	if cfg.MMR {
+		return vectorstores.SelectByMMR(ctx, cfg.Embedder, vector, found, vectors, numDocuments, cfg.MMRLambda)
+	}
 	return found, nil
 }
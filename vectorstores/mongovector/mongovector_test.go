@@ -268,6 +268,57 @@ func TestStore_AddDocuments(t *testing.T) {
 	}
 }
 
+//nolint:paralleltest
+func TestStore_DocumentManager(t *testing.T) {
+	store := setupTest(t, testIndexSize3, testIndexDP3)
+	resetVectorStore(t, store.coll)
+
+	ctx := context.Background()
+	err := store.Upsert(ctx, []string{"tokyo", "osaka"}, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	// upserted documents replace the documents with the same ids.
+	err = store.Upsert(ctx, []string{"tokyo"}, []schema.Document{
+		{PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	// the ids returned by AddDocuments are accepted too.
+	added, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "Kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, added, 1)
+
+	docs, err := store.Get(ctx, []string{"tokyo", "osaka", added[0], "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]schema.Document{
+		"tokyo":  {PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+		"osaka":  {PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+		added[0]: {PageContent: "Kyoto", Metadata: map[string]any{"country": "japan"}},
+	}, docs)
+
+	require.NoError(t, store.Delete(ctx, []string{"tokyo", added[0]}))
+	docs, err = store.Get(ctx, []string{"tokyo", "osaka", added[0]})
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "Osaka", docs["osaka"].PageContent)
+
+	filter := bson.D{{Key: "metadata.country", Value: "japan"}}
+	require.NoError(t, store.DeleteByFilter(ctx, filter))
+	docs, err = store.Get(ctx, []string{"osaka"})
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+
+	err = store.Upsert(ctx, []string{"a", "b"}, []schema.Document{{PageContent: "Tokyo"}})
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+	err = store.DeleteByFilter(ctx, nil)
+	require.ErrorIs(t, err, vectorstores.ErrMissingFilter)
+}
+
 type simSearchTest struct {
 	ctx          context.Context //nolint:containedctx
 	seed         []schema.Document
@@ -22,7 +22,7 @@ func (s *Store) documentIndexing(
 	text string,
 	vector []float32,
 	metadata map[string]any,
-) (*opensearchapi.Response, error) {
+) error {
 	document := document{
 		FieldsContent:       text,
 		FieldsContentVector: vector,
@@ -32,7 +32,7 @@ func (s *Store) documentIndexing(
 	buf := new(bytes.Buffer)
 
 	if err := json.NewEncoder(buf).Encode(document); err != nil {
-		return nil, fmt.Errorf("error encoding index schema to json buffer %w", err)
+		return fmt.Errorf("error encoding index schema to json buffer %w", err)
 	}
 
 	indice := opensearchapi.IndexRequest{
@@ -41,5 +41,8 @@ func (s *Store) documentIndexing(
 		Body:       buf,
 	}
 
-	return indice.Do(ctx, s.client)
+	if _, err := s.do(ctx, indice); err != nil {
+		return fmt.Errorf("indice.Do err: %w", err)
+	}
+	return nil
 }
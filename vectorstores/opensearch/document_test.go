@@ -0,0 +1,162 @@
+package opensearch_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores/opensearch"
+	opensearchgo "github.com/opensearch-project/opensearch-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lengthEmbedder embeds texts as their length.
+type lengthEmbedder struct{}
+
+func (lengthEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text))}
+	}
+	return vectors, nil
+}
+
+func (lengthEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text))}, nil
+}
+
+// fakeIndex is an opensearch index holding its documents in memory. Its
+// deletions by query delete the documents with the ids of an ids query, and
+// all the documents for other queries.
+type fakeIndex struct {
+	mu      sync.Mutex
+	docs    map[string]json.RawMessage
+	queries []string
+}
+
+func newFakeStore(t *testing.T) (opensearch.Store, *fakeIndex) {
+	t.Helper()
+
+	index := &fakeIndex{docs: map[string]json.RawMessage{}}
+	server := httptest.NewServer(index)
+	t.Cleanup(server.Close)
+	client, err := opensearchgo.NewClient(opensearchgo.Config{Addresses: []string{server.URL}})
+	require.NoError(t, err)
+	store, err := opensearch.New(client, opensearch.WithEmbedder(lengthEmbedder{}))
+	require.NoError(t, err)
+	return store, index
+}
+
+func (f *fakeIndex) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch path := r.URL.Path; {
+	case path == "/":
+		// the client checks the server before its first request.
+		_, _ = w.Write([]byte(`{"version": {"number": "2.11.0", "distribution": "opensearch"}}`))
+	case strings.HasPrefix(path, "/cities/_doc/"):
+		id := strings.TrimPrefix(path, "/cities/_doc/")
+		var doc json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&doc); err != nil || id == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"type": "mapper_parsing_exception"}, "status": 400}`))
+			return
+		}
+		f.docs[id] = doc
+		_, _ = w.Write([]byte(`{"_index": "cities", "_id": "` + id + `", "result": "created"}`))
+	case path == "/cities/_mget":
+		var body struct {
+			IDs []string `json:"ids"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		docs := []map[string]any{}
+		for _, id := range body.IDs {
+			doc, ok := f.docs[id]
+			result := map[string]any{"_index": "cities", "_id": id, "found": ok}
+			if ok {
+				result["_source"] = doc
+			}
+			docs = append(docs, result)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"docs": docs})
+	case path == "/cities/_delete_by_query":
+		var body struct {
+			Query json.RawMessage `json:"query"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.queries = append(f.queries, string(body.Query))
+		var ids struct {
+			IDs struct {
+				Values []string `json:"values"`
+			} `json:"ids"`
+		}
+		_ = json.Unmarshal(body.Query, &ids)
+		if ids.IDs.Values == nil {
+			f.docs = map[string]json.RawMessage{}
+		}
+		for _, id := range ids.IDs.Values {
+			delete(f.docs, id)
+		}
+		_, _ = w.Write([]byte(`{"deleted": 1}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestDocumentManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, index := newFakeStore(t)
+	ns := vectorstores.WithNameSpace("cities")
+
+	err := store.Upsert(ctx, []string{"tokyo", "osaka"}, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	}, ns)
+	require.NoError(t, err)
+
+	// upserted documents replace the documents with the same ids.
+	err = store.Upsert(ctx, []string{"tokyo"}, []schema.Document{
+		{PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+	}, ns)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"content": "Tokyo is a city", "contentVector": [15], "metadata": {"country": "japan"}}`,
+		string(index.docs["tokyo"]))
+
+	docs, err := store.Get(ctx, []string{"tokyo", "osaka", "unknown"}, ns)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]schema.Document{
+		"tokyo": {PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+		"osaka": {PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	}, docs)
+
+	require.NoError(t, store.Delete(ctx, []string{"tokyo"}, ns))
+	assert.JSONEq(t, `{"ids": {"values": ["tokyo"]}}`, index.queries[0])
+	assert.Len(t, index.docs, 1)
+
+	filter := map[string]any{"term": map[string]any{"metadata.country": "japan"}}
+	require.NoError(t, store.DeleteByFilter(ctx, filter, ns))
+	assert.JSONEq(t, `{"term": {"metadata.country": "japan"}}`, index.queries[1])
+	assert.Empty(t, index.docs)
+
+	// the errors of opensearch are returned.
+	err = store.Upsert(ctx, []string{"invalid"}, []schema.Document{{PageContent: "Tokyo"}}, ns)
+	require.ErrorIs(t, err, opensearch.ErrResponse)
+	_, err = store.Get(ctx, []string{"tokyo"}, vectorstores.WithNameSpace("unknown"))
+	require.ErrorIs(t, err, opensearch.ErrResponse)
+
+	err = store.Upsert(ctx, []string{"a", "b"}, []schema.Document{{PageContent: "Tokyo"}}, ns)
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+	err = store.DeleteByFilter(ctx, nil, ns)
+	require.ErrorIs(t, err, vectorstores.ErrMissingFilter)
+}
@@ -32,6 +32,8 @@ var (
 	ErrAssertingMetadata = errors.New(
 		"couldn't assert metadata to map",
 	)
+	// ErrResponse is returned when opensearch responds with an error status.
+	ErrResponse = errors.New("error response")
 )
 
 // New creates and returns a vectorstore object for Opensearch
@@ -48,7 +50,7 @@ func New(client *opensearchgo.Client, opts ...Option) (Store, error) {
 	return s, nil
 }
 
-var _ vectorstores.VectorStore = Store{}
+var _ vectorstores.DocumentManager = Store{}
 
 // AddDocuments adds the text and metadata from the documents to the Chroma collection associated with 'Store'.
 // and returns the ids of the added documents.
@@ -57,33 +59,157 @@ func (s Store) AddDocuments(
 	docs []schema.Document,
 	options ...vectorstores.Option,
 ) ([]string, error) {
-	opts := s.getOptions(options...)
-	ids := []string{}
-	texts := []string{}
+	ids := make([]string, len(docs))
+	for i := range docs {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.indexDocuments(ctx, s.getOptions(options...), ids, docs); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
 
+// Upsert indexes the documents with the ids, replacing the documents with the
+// same ids in the index.
+func (s Store) Upsert(
+	ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if len(ids) != len(docs) {
+		return vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return s.indexDocuments(ctx, s.getOptions(options...), ids, docs)
+}
+
+// Get returns the documents with the ids, by id.
+func (s Store) Get(
+	ctx context.Context,
+	ids []string,
+	options ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	docs := make(map[string]schema.Document, len(ids))
+	if len(ids) == 0 {
+		return docs, nil
+	}
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(map[string]interface{}{"ids": ids}); err != nil {
+		return nil, fmt.Errorf("error encoding ids to json buffer %w", err)
+	}
+	mget := opensearchapi.MgetRequest{
+		Index: s.getOptions(options...).NameSpace,
+		Body:  buf,
+	}
+	body, err := s.do(ctx, mget)
+	if err != nil {
+		return nil, fmt.Errorf("mget.Do err: %w", err)
+	}
+	results := mgetResults{}
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, fmt.Errorf("error unmarshalling mget response body: %w %s", err, body)
+	}
+	for _, result := range results.Docs {
+		if !result.Found {
+			continue
+		}
+		docs[result.ID] = schema.Document{
+			PageContent: result.Source.FieldsContent,
+			Metadata:    result.Source.FieldsMetadata,
+		}
+	}
+	return docs, nil
+}
+
+// Delete deletes the documents with the ids from the index.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.deleteByQuery(ctx, s.getOptions(options...), map[string]interface{}{
+		"ids": map[string]interface{}{"values": ids},
+	})
+}
+
+// DeleteByFilter deletes the documents matching the filter, an opensearch
+// query such as map[string]any{"term": map[string]any{"metadata.source": "a"}}.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	return s.deleteByQuery(ctx, s.getOptions(options...), filter)
+}
+
+// indexDocuments indexes the documents with the ids in the index named by the
+// name space of the options.
+func (s Store) indexDocuments(
+	ctx context.Context,
+	opts vectorstores.Options,
+	ids []string,
+	docs []schema.Document,
+) error {
+	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
 	}
 
 	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return ids, err
+		return err
 	}
 
 	if len(vectors) != len(docs) {
-		return ids, ErrNumberOfVectorDoesNotMatch
+		return ErrNumberOfVectorDoesNotMatch
 	}
 
 	for i, doc := range docs {
-		id := uuid.NewString()
-		_, err := s.documentIndexing(ctx, id, opts.NameSpace, doc.PageContent, vectors[i], doc.Metadata)
-		if err != nil {
-			return ids, err
+		if err := s.documentIndexing(ctx, ids[i], opts.NameSpace, doc.PageContent, vectors[i], doc.Metadata); err != nil {
+			return err
 		}
-		ids = append(ids, id)
 	}
+	return nil
+}
 
-	return ids, nil
+// deleteByQuery deletes the documents matching the query from the index named
+// by the name space of the options.
+func (s Store) deleteByQuery(ctx context.Context, opts vectorstores.Options, query any) error {
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(map[string]interface{}{"query": query}); err != nil {
+		return fmt.Errorf("error encoding query to json buffer %w", err)
+	}
+	refresh := true
+	deleteByQuery := opensearchapi.DeleteByQueryRequest{
+		Index:   []string{opts.NameSpace},
+		Body:    buf,
+		Refresh: &refresh,
+	}
+	if _, err := s.do(ctx, deleteByQuery); err != nil {
+		return fmt.Errorf("deleteByQuery.Do err: %w", err)
+	}
+	return nil
+}
+
+// do sends the request and returns the body of the response, or an error with
+// the body if the response has an error status.
+func (s Store) do(ctx context.Context, req opensearchapi.Request) ([]byte, error) {
+	res, err := req.Do(ctx, s.client)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("%w: %s %s", ErrResponse, res.Status(), body)
+	}
+	return body, nil
 }
 
 // SimilaritySearch creates a vector embedding from the query using the embedder
@@ -95,23 +221,37 @@ func (s Store) SimilaritySearch(
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
+	if opts.HybridSearch && (opts.HybridAlpha < 0 || opts.HybridAlpha > 1) {
+		return nil, vectorstores.ErrInvalidHybridAlpha
+	}
+	fetchK := numDocuments
+	if opts.MMR {
+		var err error
+		if fetchK, err = vectorstores.MMRFetchK(opts, numDocuments); err != nil {
+			return nil, err
+		}
+	}
 
 	queryVector, err := s.embedder.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	searchPayload := map[string]interface{}{
-		"size": numDocuments,
-		"query": map[string]interface{}{
-			"knn": map[string]interface{}{
-				"contentVector": map[string]interface{}{
-					"vector": queryVector,
-					"k":      numDocuments,
-				},
+	knnQuery := map[string]interface{}{
+		"knn": map[string]interface{}{
+			"contentVector": map[string]interface{}{
+				"vector": queryVector,
+				"k":      fetchK,
 			},
 		},
 	}
+	searchPayload := map[string]interface{}{
+		"size":  fetchK,
+		"query": knnQuery,
+	}
+	if opts.HybridSearch {
+		searchPayload["query"] = hybridQuery(query, queryVector, fetchK, opts.HybridAlpha)
+	}
 
 	buf := new(bytes.Buffer)
 	if err := json.NewEncoder(buf).Encode(searchPayload); err != nil {
@@ -137,6 +277,7 @@ func (s Store) SimilaritySearch(
 		return output, fmt.Errorf("error unmarshalling search response body: %w %s", err, body)
 	}
 
+	vectors := [][]float32{}
 	for _, hit := range searchResults.Hits.Hits {
 		if opts.ScoreThreshold > 0 && opts.ScoreThreshold > hit.Score {
 			continue
@@ -147,7 +288,44 @@ func (s Store) SimilaritySearch(
 			Metadata:    hit.Source.FieldsMetadata,
 			Score:       hit.Score,
 		})
+		vectors = append(vectors, hit.Source.FieldsContentVector)
 	}
 
+	if opts.MMR {
+		return vectorstores.SelectByMMR(ctx, s.embedder, queryVector, output, vectors, numDocuments, opts.MMRLambda)
+	}
 	return output, nil
 }
+
+// hybridQuery returns a query matching the documents by the k-NN search of the
+// vector or by the full-text search of the query, whose scores are boosted by
+// alpha and 1-alpha and summed.
+func hybridQuery(query string, queryVector []float32, numDocuments int, alpha float32) map[string]interface{} {
+	should := make([]map[string]interface{}, 0, 2)
+	if alpha > 0 {
+		should = append(should, map[string]interface{}{
+			"knn": map[string]interface{}{
+				"contentVector": map[string]interface{}{
+					"vector": queryVector,
+					"k":      numDocuments,
+					"boost":  alpha,
+				},
+			},
+		})
+	}
+	if alpha < 1 {
+		should = append(should, map[string]interface{}{
+			"match": map[string]interface{}{
+				"content": map[string]interface{}{
+					"query": query,
+					"boost": 1 - alpha,
+				},
+			},
+		})
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"should": should,
+		},
+	}
+}
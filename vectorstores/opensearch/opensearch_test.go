@@ -196,6 +196,45 @@ func TestOpensearchStoreRestWithScoreThreshold(t *testing.T) {
 	require.Len(t, docs, 6)
 }
 
+func TestOpensearchStoreHybridSearch(t *testing.T) {
+	t.Parallel()
+	opensearchEndpoint, opensearchUser, opensearchPassword := getEnvVariables(t)
+	indexName := uuid.New().String()
+
+	llm := setLLM(t)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	storer, err := opensearch.New(
+		setOpensearchClient(t, opensearchEndpoint, opensearchUser, opensearchPassword),
+		opensearch.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+
+	setIndex(t, storer, indexName)
+	defer removeIndex(t, storer, indexName)
+
+	_, err = storer.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "The printer reports error E4012 when its tray is empty"},
+		{PageContent: "The printer stops when the paper jams"},
+		{PageContent: "The router blinks when the network is down"},
+	}, vectorstores.WithNameSpace(indexName))
+	require.NoError(t, err)
+	time.Sleep(time.Second)
+
+	docs, err := storer.SimilaritySearch(context.Background(), "E4012", 1,
+		vectorstores.WithHybridSearch(0.25),
+		vectorstores.WithNameSpace(indexName))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Contains(t, docs[0].PageContent, "E4012")
+
+	_, err = storer.SimilaritySearch(context.Background(), "E4012", 1,
+		vectorstores.WithHybridSearch(-1),
+		vectorstores.WithNameSpace(indexName))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridAlpha)
+}
+
 func TestOpensearchAsRetriever(t *testing.T) {
 	t.Parallel()
 	opensearchEndpoint, opensearchUser, opensearchPassword := getEnvVariables(t)
@@ -25,3 +25,12 @@ type searchResultsHit struct {
 	Score  float32  `json:"_score"`
 	Source document `json:"_source"`
 }
+
+type mgetResults struct {
+	Docs []struct {
+		Index  string   `json:"_index"`
+		ID     string   `json:"_id"`
+		Found  bool     `json:"found"`
+		Source document `json:"_source"`
+	} `json:"docs"`
+}
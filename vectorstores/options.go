@@ -17,6 +17,21 @@ type Options struct {
 	Filters        any
 	Embedder       embeddings.Embedder
 	Deduplicater   func(context.Context, schema.Document) bool
+	// HybridSearch makes the stores supporting it combine the similarity
+	// search with a keyword search of the query, see WithHybridSearch.
+	HybridSearch bool
+	// HybridAlpha is the weight of the similarity search in a hybrid search,
+	// between 0 and 1.
+	HybridAlpha float32
+	// MMR makes the stores select the documents by maximal marginal
+	// relevance, see WithMMR.
+	MMR bool
+	// MMRFetchK is the number of candidates of a maximal marginal relevance
+	// search.
+	MMRFetchK int
+	// MMRLambda is the weight of the relevance in a maximal marginal relevance
+	// search, between 0 and 1.
+	MMRLambda float32
 }
 
 // WithNameSpace returns an Option for setting the name space.
@@ -59,3 +74,34 @@ func WithDeduplicater(fn func(ctx context.Context, doc schema.Document) bool) Op
 		o.Deduplicater = fn
 	}
 }
+
+// WithHybridSearch returns an Option making the similarity search of the stores
+// supporting it a hybrid search: the results of a keyword search of the query
+// are combined with the results of the vector search. Alpha is the weight of
+// the vector search, between 0 for a pure keyword search and 1 for a pure
+// vector search. Stores fusing the results by rank, without weights, run both
+// searches for any alpha strictly between 0 and 1.
+//
+// Hybrid search is supported by the opensearch, weaviate, qdrant and
+// azureaisearch stores. The other stores ignore this option.
+func WithHybridSearch(alpha float32) Option {
+	return func(o *Options) {
+		o.HybridSearch = true
+		o.HybridAlpha = alpha
+	}
+}
+
+// WithMMR returns an Option making the similarity search select the documents
+// by maximal marginal relevance, for results both relevant to the query and
+// diverse: fetchK candidates are found by similarity, commonly 4 times more
+// than the documents returned, and the documents are selected one by one to
+// maximize lambda times their similarity to the query minus 1-lambda times
+// their highest similarity to the documents already selected. Lambda is
+// between 0 for the most diverse results and 1 for a plain similarity search.
+func WithMMR(fetchK int, lambda float32) Option {
+	return func(o *Options) {
+		o.MMR = true
+		o.MMRFetchK = fetchK
+		o.MMRLambda = lambda
+	}
+}
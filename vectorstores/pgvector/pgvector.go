@@ -34,6 +34,7 @@ var (
 	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
 	ErrInvalidFilters             = errors.New("invalid filters")
 	ErrUnsupportedOptions         = errors.New("unsupported options")
+	ErrDocumentConflict           = errors.New("document id is used by another collection")
 )
 
 // PGXConn represents both a pgx.Conn and pgxpool.Pool conn.
@@ -71,7 +72,7 @@ type HNSWIndex struct {
 	distanceFunction string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var _ vectorstores.DocumentManager = Store{}
 
 // New creates a new Store with options.
 func New(ctx context.Context, opts ...Option) (Store, error) {
@@ -237,6 +238,169 @@ func (s Store) AddDocuments(
 
 	docs = s.deduplicate(ctx, opts, docs)
 
+	vectors, err := s.embedDocuments(ctx, opts, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &pgx.Batch{}
+	sql := fmt.Sprintf(`INSERT INTO %s (uuid, document, embedding, cmetadata, collection_id)
+		VALUES($1, $2, $3, $4, $5)`, s.embeddingTableName)
+
+	ids := make([]string, len(docs))
+	for docIdx, doc := range docs {
+		id := uuid.New().String()
+		ids[docIdx] = id
+		b.Queue(sql, id, doc.PageContent, pgvector.NewVector(vectors[docIdx]), doc.Metadata, s.collectionUUID)
+	}
+	return ids, s.conn.SendBatch(ctx, b).Close()
+}
+
+// Upsert adds documents with the ids to the Postgres collection associated
+// with 'Store', replacing the documents with the same ids. The ids which
+// aren't UUIDs are stored as UUIDs derived from the collection and the ids.
+// It fails with ErrDocumentConflict if an id is a UUID used by a document of
+// another collection.
+func (s Store) Upsert(
+	ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if len(ids) != len(docs) {
+		return vectorstores.ErrMismatchedIDs
+	}
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil || opts.NameSpace != "" {
+		return ErrUnsupportedOptions
+	}
+
+	vectors, err := s.embedDocuments(ctx, opts, docs)
+	if err != nil {
+		return err
+	}
+
+	b := &pgx.Batch{}
+	sql := fmt.Sprintf(`INSERT INTO %s (uuid, document, embedding, cmetadata, collection_id)
+		VALUES($1, $2, $3, $4, $5) ON CONFLICT (uuid) DO
+		UPDATE SET document = $2, embedding = $3, cmetadata = $4
+		WHERE %s.collection_id = EXCLUDED.collection_id`, s.embeddingTableName, s.embeddingTableName)
+	for docIdx, doc := range docs {
+		id := s.documentUUID(ids[docIdx])
+		b.Queue(sql, id, doc.PageContent, pgvector.NewVector(vectors[docIdx]), doc.Metadata, s.collectionUUID)
+	}
+
+	br := s.conn.SendBatch(ctx, b)
+	defer br.Close()
+	for _, id := range ids {
+		tag, err := br.Exec()
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("%w: %s", ErrDocumentConflict, id)
+		}
+	}
+	return br.Close()
+}
+
+// Get returns the documents of the collection with the ids, by id.
+func (s Store) Get(
+	ctx context.Context,
+	ids []string,
+	options ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	if opts := s.getOptions(options...); opts.NameSpace != "" {
+		return nil, ErrUnsupportedOptions
+	}
+	byUUID := make(map[string]string, len(ids))
+	uuids := make([]string, len(ids))
+	for i, id := range ids {
+		uuids[i] = s.documentUUID(id)
+		byUUID[uuids[i]] = id
+	}
+
+	sql := fmt.Sprintf(`SELECT uuid, document, cmetadata FROM %s
+WHERE collection_id = $1 AND uuid = ANY($2::uuid[])`, s.embeddingTableName)
+	rows, err := s.conn.Query(ctx, sql, s.collectionUUID, uuids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docs := make(map[string]schema.Document, len(ids))
+	for rows.Next() {
+		var id string
+		doc := schema.Document{}
+		if err := rows.Scan(&id, &doc.PageContent, &doc.Metadata); err != nil {
+			return nil, err
+		}
+		docs[byUUID[id]] = doc
+	}
+	return docs, rows.Err()
+}
+
+// Delete deletes the documents of the collection with the ids.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if opts := s.getOptions(options...); opts.NameSpace != "" {
+		return ErrUnsupportedOptions
+	}
+	uuids := make([]string, len(ids))
+	for i, id := range ids {
+		uuids[i] = s.documentUUID(id)
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE collection_id = $1 AND uuid = ANY($2::uuid[])`, s.embeddingTableName)
+	_, err := s.conn.Exec(ctx, sql, s.collectionUUID, uuids)
+	return err
+}
+
+// DeleteByFilter deletes the documents of the collection whose metadata have
+// the values of the filter, a map[string]any.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+	if opts.NameSpace != "" {
+		return ErrUnsupportedOptions
+	}
+	opts.Filters = filter
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return err
+	}
+
+	args := []any{s.collectionUUID}
+	whereQuerys := []string{"TRUE"}
+	for k, v := range filters {
+		args = append(args, k, fmt.Sprint(v))
+		whereQuerys = append(whereQuerys, fmt.Sprintf("(cmetadata ->> $%d) = $%d", len(args)-1, len(args)))
+	}
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE collection_id = $1 AND %s`,
+		s.embeddingTableName, strings.Join(whereQuerys, " AND "))
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+// documentUUID returns the id if it is a UUID, or else a UUID derived from the
+// collection and the id, so that the same id can be used in every collection.
+func (s Store) documentUUID(id string) string {
+	if u, err := uuid.Parse(id); err == nil {
+		return u.String()
+	}
+	space, err := uuid.Parse(s.collectionUUID)
+	if err != nil {
+		space = uuid.NameSpaceURL
+	}
+	return uuid.NewSHA1(space, []byte(id)).String()
+}
+
+func (s Store) embedDocuments(
+	ctx context.Context,
+	opts vectorstores.Options,
+	docs []schema.Document,
+) ([][]float32, error) {
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
@@ -254,18 +418,7 @@ func (s Store) AddDocuments(
 	if len(vectors) != len(docs) {
 		return nil, ErrEmbedderWrongNumberVectors
 	}
-
-	b := &pgx.Batch{}
-	sql := fmt.Sprintf(`INSERT INTO %s (uuid, document, embedding, cmetadata, collection_id)
-		VALUES($1, $2, $3, $4, $5)`, s.embeddingTableName)
-
-	ids := make([]string, len(docs))
-	for docIdx, doc := range docs {
-		id := uuid.New().String()
-		ids[docIdx] = id
-		b.Queue(sql, id, doc.PageContent, pgvector.NewVector(vectors[docIdx]), doc.Metadata, s.collectionUUID)
-	}
-	return ids, s.conn.SendBatch(ctx, b).Close()
+	return vectors, nil
 }
 
 //nolint:cyclop
@@ -289,6 +442,16 @@ func (s Store) SimilaritySearch(
 	if opts.Embedder != nil {
 		embedder = opts.Embedder
 	}
+	limit := numDocuments
+	// maximal marginal relevance searches select the documents with their
+	// embeddings.
+	selectEmbedding := ""
+	if opts.MMR {
+		if limit, err = vectorstores.MMRFetchK(opts, numDocuments); err != nil {
+			return nil, err
+		}
+		selectEmbedding = ",\n\tdata.embedding"
+	}
 	embedderData, err := embedder.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
@@ -318,7 +481,7 @@ func (s Store) SimilaritySearch(
 SELECT
 	data.document,
 	data.cmetadata,
-	(1 - data.distance) AS score
+	(1 - data.distance) AS score%s
 FROM (
 	SELECT
 		filtered_embedding_dims.*,
@@ -329,24 +492,37 @@ FROM (
 WHERE %s
 ORDER BY
 	data.distance
-LIMIT $3`, s.embeddingTableName,
+LIMIT $3`, s.embeddingTableName, selectEmbedding,
 		s.collectionTableName, s.collectionTableName, s.collectionTableName, collectionName,
 		whereQuery)
-	rows, err := s.conn.Query(ctx, sql, dims, pgvector.NewVector(embedderData), numDocuments)
+	rows, err := s.conn.Query(ctx, sql, dims, pgvector.NewVector(embedderData), limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	docs := make([]schema.Document, 0)
+	vectors := make([][]float32, 0)
 	for rows.Next() {
 		doc := schema.Document{}
-		if err := rows.Scan(&doc.PageContent, &doc.Metadata, &doc.Score); err != nil {
+		dest := []any{&doc.PageContent, &doc.Metadata, &doc.Score}
+		var vector pgvector.Vector
+		if opts.MMR {
+			dest = append(dest, &vector)
+		}
+		if err := rows.Scan(dest...); err != nil {
 			return nil, err
 		}
 		docs = append(docs, doc)
+		vectors = append(vectors, vector.Slice())
 	}
-	return docs, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if opts.MMR {
+		return vectorstores.SelectByMMR(ctx, embedder, embedderData, docs, vectors, numDocuments, opts.MMRLambda)
+	}
+	return docs, nil
 }
 
 //nolint:cyclop
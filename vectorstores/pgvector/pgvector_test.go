@@ -119,6 +119,124 @@ func TestPgvectorStoreRest(t *testing.T) {
 	require.Equal(t, "japan", docs[0].Metadata["country"])
 }
 
+func TestPgvectorStoreMMR(t *testing.T) {
+	t.Parallel()
+	pgvectorURL := preCheckEnvSetting(t)
+	ctx := context.Background()
+
+	llm, err := openai.New(
+		openai.WithEmbeddingModel("text-embedding-ada-002"),
+	)
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	conn, err := pgx.Connect(ctx, pgvectorURL)
+	require.NoError(t, err)
+
+	store, err := pgvector.New(
+		ctx,
+		pgvector.WithConn(conn),
+		pgvector.WithEmbedder(e),
+		pgvector.WithPreDeleteCollection(true),
+		pgvector.WithCollectionName(makeNewCollectionName()),
+	)
+	require.NoError(t, err)
+
+	defer cleanupTestArtifacts(ctx, t, store, pgvectorURL)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "Tokyo is the capital of Japan"},
+		{PageContent: "The capital of Japan is Tokyo"},
+		{PageContent: "Kyoto was the capital of Japan for a thousand years"},
+	})
+	require.NoError(t, err)
+
+	// the duplicate of the most relevant document is skipped.
+	docs, err := store.SimilaritySearch(ctx, "capital of Japan", 2,
+		vectorstores.WithMMR(3, 0.3))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Contains(t, docs[1].PageContent, "Kyoto")
+
+	_, err = store.SimilaritySearch(ctx, "capital of Japan", 2,
+		vectorstores.WithMMR(3, 1.5))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidMMRLambda)
+}
+
+func TestPgvectorStoreDocumentManager(t *testing.T) {
+	t.Parallel()
+	pgvectorURL := preCheckEnvSetting(t)
+	ctx := context.Background()
+
+	llm, err := openai.New(
+		openai.WithEmbeddingModel("text-embedding-ada-002"),
+	)
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	conn, err := pgx.Connect(ctx, pgvectorURL)
+	require.NoError(t, err)
+
+	store, err := pgvector.New(
+		ctx,
+		pgvector.WithConn(conn),
+		pgvector.WithEmbedder(e),
+		pgvector.WithPreDeleteCollection(true),
+		pgvector.WithCollectionName(makeNewCollectionName()),
+	)
+	require.NoError(t, err)
+
+	defer cleanupTestArtifacts(ctx, t, store, pgvectorURL)
+
+	err = store.Upsert(ctx, []string{"tokyo", "kyoto"}, []schema.Document{
+		{PageContent: "Tokyo is the capital of Japan", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Kyoto is a city of Japan", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	// upserted documents replace the documents with the same ids.
+	err = store.Upsert(ctx, []string{"tokyo"}, []schema.Document{
+		{PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+	docs, err := store.Get(ctx, []string{"tokyo", "kyoto", "unknown"})
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "Tokyo is a city", docs["tokyo"].PageContent)
+
+	// the same ids are different documents in other collections.
+	other, err := pgvector.New(
+		ctx,
+		pgvector.WithConn(conn),
+		pgvector.WithEmbedder(e),
+		pgvector.WithPreDeleteCollection(true),
+		pgvector.WithCollectionName(makeNewCollectionName()),
+	)
+	require.NoError(t, err)
+	defer cleanupTestArtifacts(ctx, t, other, pgvectorURL)
+	err = other.Upsert(ctx, []string{"tokyo"}, []schema.Document{{PageContent: "Tokyo is big"}})
+	require.NoError(t, err)
+	docs, err = store.Get(ctx, []string{"tokyo"})
+	require.NoError(t, err)
+	require.Equal(t, "Tokyo is a city", docs["tokyo"].PageContent)
+	docs, err = other.Get(ctx, []string{"tokyo"})
+	require.NoError(t, err)
+	require.Equal(t, "Tokyo is big", docs["tokyo"].PageContent)
+
+	require.NoError(t, store.Delete(ctx, []string{"kyoto"}))
+	require.NoError(t, store.DeleteByFilter(ctx, map[string]any{"country": "japan"}))
+	docs, err = store.Get(ctx, []string{"tokyo", "kyoto"})
+	require.NoError(t, err)
+	require.Empty(t, docs)
+
+	err = store.DeleteByFilter(ctx, nil)
+	require.ErrorIs(t, err, vectorstores.ErrMissingFilter)
+	_, err = store.Get(ctx, []string{"tokyo"}, vectorstores.WithNameSpace("other"))
+	require.ErrorIs(t, err, pgvector.ErrUnsupportedOptions)
+}
+
 func TestPgvectorStoreRestWithScoreThreshold(t *testing.T) {
 	t.Parallel()
 	pgvectorURL := preCheckEnvSetting(t)
@@ -0,0 +1,143 @@
+package pinecone
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lengthEmbedder embeds texts as their length.
+type lengthEmbedder struct{}
+
+func (lengthEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text))}
+	}
+	return vectors, nil
+}
+
+func (lengthEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text))}, nil
+}
+
+// fakeIndex holds the vectors of an index by name space and id. Its
+// connections record the filters of the deletions, and the methods the store
+// doesn't use panic.
+type fakeIndex struct {
+	vectors map[string]map[string]*pinecone.Vector
+	filters []map[string]any
+	closed  int
+}
+
+type fakeConnection struct {
+	indexConnection
+
+	index     *fakeIndex
+	nameSpace string
+}
+
+func newFakeStore(t *testing.T) (Store, *fakeIndex) {
+	t.Helper()
+
+	index := &fakeIndex{vectors: map[string]map[string]*pinecone.Vector{}}
+	s, err := applyClientOptions(WithHost("index.pinecone.io"), WithAPIKey("key"), WithEmbedder(lengthEmbedder{}))
+	require.NoError(t, err)
+	s.connect = func(nameSpace string) (indexConnection, error) {
+		if index.vectors[nameSpace] == nil {
+			index.vectors[nameSpace] = map[string]*pinecone.Vector{}
+		}
+		return &fakeConnection{index: index, nameSpace: nameSpace}, nil
+	}
+	return s, index
+}
+
+func (c *fakeConnection) UpsertVectors(_ *context.Context, in []*pinecone.Vector) (uint32, error) {
+	for _, vector := range in {
+		c.index.vectors[c.nameSpace][vector.Id] = vector
+	}
+	return uint32(len(in)), nil
+}
+
+func (c *fakeConnection) FetchVectors(_ *context.Context, ids []string) (*pinecone.FetchVectorsResponse, error) {
+	res := &pinecone.FetchVectorsResponse{Vectors: map[string]*pinecone.Vector{}}
+	for _, id := range ids {
+		if vector, ok := c.index.vectors[c.nameSpace][id]; ok {
+			res.Vectors[id] = vector
+		}
+	}
+	return res, nil
+}
+
+func (c *fakeConnection) DeleteVectorsById(_ *context.Context, ids []string) error {
+	for _, id := range ids {
+		delete(c.index.vectors[c.nameSpace], id)
+	}
+	return nil
+}
+
+// DeleteVectorsByFilter deletes all the vectors of the name space.
+func (c *fakeConnection) DeleteVectorsByFilter(_ *context.Context, filter *pinecone.Filter) error {
+	c.index.filters = append(c.index.filters, filter.AsMap())
+	c.index.vectors[c.nameSpace] = map[string]*pinecone.Vector{}
+	return nil
+}
+
+func (c *fakeConnection) Close() error {
+	c.index.closed++
+	return nil
+}
+
+func TestDocumentManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var store vectorstores.DocumentManager
+	store, index := newFakeStore(t)
+	ns := vectorstores.WithNameSpace("cities")
+
+	err := store.Upsert(ctx, []string{"tokyo", "osaka"}, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	}, ns)
+	require.NoError(t, err)
+
+	// upserted documents replace the documents with the same ids.
+	err = store.Upsert(ctx, []string{"tokyo"}, []schema.Document{
+		{PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+	}, ns)
+	require.NoError(t, err)
+	tokyo := index.vectors["cities"]["tokyo"]
+	assert.Equal(t, []float32{15}, tokyo.Values)
+	assert.Equal(t, map[string]any{"text": "Tokyo is a city", "country": "japan"}, tokyo.Metadata.AsMap())
+
+	docs, err := store.Get(ctx, []string{"tokyo", "osaka", "unknown"}, ns)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]schema.Document{
+		"tokyo": {PageContent: "Tokyo is a city", Metadata: map[string]any{"country": "japan"}},
+		"osaka": {PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	}, docs)
+
+	// the documents are in the name space of the options.
+	docs, err = store.Get(ctx, []string{"tokyo"})
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+
+	require.NoError(t, store.Delete(ctx, []string{"tokyo"}, ns))
+	assert.Len(t, index.vectors["cities"], 1)
+
+	require.NoError(t, store.DeleteByFilter(ctx, map[string]any{"country": "japan"}, ns))
+	assert.Equal(t, []map[string]any{{"country": "japan"}}, index.filters)
+	assert.Empty(t, index.vectors["cities"])
+	assert.Equal(t, 6, index.closed, "the connections weren't closed")
+
+	err = store.Upsert(ctx, []string{"a", "b"}, []schema.Document{{PageContent: "Tokyo"}})
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+	err = store.DeleteByFilter(ctx, nil)
+	require.ErrorIs(t, err, vectorstores.ErrMissingFilter)
+}
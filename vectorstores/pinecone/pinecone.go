@@ -33,6 +33,9 @@ var (
 type Store struct {
 	embedder embeddings.Embedder
 	client   *pinecone.Client
+	// connect returns a connection to the index in the name space, which is
+	// replaced in tests.
+	connect func(nameSpace string) (indexConnection, error)
 
 	host      string
 	apiKey    string
@@ -40,6 +43,18 @@ type Store struct {
 	nameSpace string
 }
 
+// indexConnection is the part of *pinecone.IndexConnection used by the store.
+type indexConnection interface {
+	UpsertVectors(ctx *context.Context, in []*pinecone.Vector) (uint32, error)
+	FetchVectors(ctx *context.Context, ids []string) (*pinecone.FetchVectorsResponse, error)
+	QueryByVectorValues(ctx *context.Context, in *pinecone.QueryByVectorValuesRequest) (*pinecone.QueryVectorsResponse, error)
+	DeleteVectorsById(ctx *context.Context, ids []string) error
+	DeleteVectorsByFilter(ctx *context.Context, filter *pinecone.Filter) error
+	Close() error
+}
+
+var _ vectorstores.DocumentManager = Store{}
+
 // New creates a new Store with options. Options for WithAPIKey, WithHost and WithEmbedder must be set.
 func New(opts ...Option) (Store, error) {
 	s, err := applyClientOptions(opts...)
@@ -51,6 +66,13 @@ func New(opts ...Option) (Store, error) {
 	if err != nil {
 		return Store{}, err
 	}
+	s.connect = func(nameSpace string) (indexConnection, error) {
+		indexConn, err := s.client.IndexWithNamespace(s.host, nameSpace)
+		if err != nil {
+			return nil, err
+		}
+		return indexConn, nil
+	}
 
 	return s, nil
 }
@@ -61,16 +83,110 @@ func (s Store) AddDocuments(ctx context.Context,
 	docs []schema.Document,
 	options ...vectorstores.Option,
 ) ([]string, error) {
-	opts := s.getOptions(options...)
+	ids := make([]string, len(docs))
+	for i := range docs {
+		ids[i] = uuid.New().String()
+	}
+	if err := s.upsertVectors(ctx, s.getOptions(options...), ids, docs); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
 
-	nameSpace := s.getNameSpace(opts)
+// Upsert creates vector embeddings from the documents using the embedder and
+// upserts the vectors with the ids to the pinecone index, replacing the vectors
+// with the same ids.
+func (s Store) Upsert(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if len(ids) != len(docs) {
+		return vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return s.upsertVectors(ctx, s.getOptions(options...), ids, docs)
+}
 
-	indexConn, err := s.client.IndexWithNamespace(s.host, nameSpace)
+// Get returns the documents of the vectors with the ids, by id.
+func (s Store) Get(ctx context.Context,
+	ids []string,
+	options ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	indexConn, err := s.connect(s.getNameSpace(s.getOptions(options...)))
 	if err != nil {
 		return nil, err
 	}
 	defer indexConn.Close()
 
+	docs := make(map[string]schema.Document, len(ids))
+	if len(ids) == 0 {
+		return docs, nil
+	}
+	res, err := indexConn.FetchVectors(&ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for id, vector := range res.Vectors {
+		metadata := vector.Metadata.AsMap()
+		pageContent, ok := metadata[s.textKey].(string)
+		if !ok {
+			return nil, ErrMissingTextKey
+		}
+		delete(metadata, s.textKey)
+		docs[id] = schema.Document{PageContent: pageContent, Metadata: metadata}
+	}
+	return docs, nil
+}
+
+// Delete deletes the vectors with the ids.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	indexConn, err := s.connect(s.getNameSpace(s.getOptions(options...)))
+	if err != nil {
+		return err
+	}
+	defer indexConn.Close()
+
+	return indexConn.DeleteVectorsById(&ctx, ids)
+}
+
+// DeleteByFilter deletes the vectors matching the filter, a pinecone metadata
+// filter. Serverless indexes don't support it.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	protoFilterStruct, err := s.createProtoStructFilter(filter)
+	if err != nil {
+		return err
+	}
+	indexConn, err := s.connect(s.getNameSpace(s.getOptions(options...)))
+	if err != nil {
+		return err
+	}
+	defer indexConn.Close()
+
+	return indexConn.DeleteVectorsByFilter(&ctx, protoFilterStruct)
+}
+
+// upsertVectors upserts the vectors of the documents with the ids to the name
+// space of the options.
+func (s Store) upsertVectors(ctx context.Context,
+	opts vectorstores.Options,
+	ids []string,
+	docs []schema.Document,
+) error {
+	indexConn, err := s.connect(s.getNameSpace(opts))
+	if err != nil {
+		return err
+	}
+	defer indexConn.Close()
+
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
@@ -78,11 +194,11 @@ func (s Store) AddDocuments(ctx context.Context,
 
 	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if len(vectors) != len(docs) {
-		return nil, ErrEmbedderWrongNumberVectors
+		return ErrEmbedderWrongNumberVectors
 	}
 
 	metadatas := make([]map[string]any, 0, len(docs))
@@ -97,20 +213,16 @@ func (s Store) AddDocuments(ctx context.Context,
 	}
 
 	pineconeVectors := make([]*pinecone.Vector, 0, len(vectors))
-
-	ids := make([]string, len(vectors))
 	for i := 0; i < len(vectors); i++ {
 		metadataStruct, err := structpb.NewStruct(metadatas[i])
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		id := uuid.New().String()
-		ids[i] = id
 		pineconeVectors = append(
 			pineconeVectors,
 			&pinecone.Vector{
-				Id:       id,
+				Id:       ids[i],
 				Values:   vectors[i],
 				Metadata: metadataStruct,
 			},
@@ -118,11 +230,7 @@ func (s Store) AddDocuments(ctx context.Context,
 	}
 
 	_, err = indexConn.UpsertVectors(&ctx, pineconeVectors)
-	if err != nil {
-		return nil, err
-	}
-
-	return ids, nil
+	return err
 }
 
 // SimilaritySearch creates a vector embedding from the query using the embedder
@@ -131,7 +239,7 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 	opts := s.getOptions(options...)
 
 	nameSpace := s.getNameSpace(opts)
-	indexConn, err := s.client.IndexWithNamespace(s.host, nameSpace)
+	indexConn, err := s.connect(nameSpace)
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +259,13 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, err
 	}
 
+	topK := numDocuments
+	if opts.MMR {
+		if topK, err = vectorstores.MMRFetchK(opts, numDocuments); err != nil {
+			return nil, err
+		}
+	}
+
 	vector, err := s.embedder.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, err
@@ -160,7 +275,7 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		&ctx,
 		&pinecone.QueryByVectorValuesRequest{
 			Vector:          vector,
-			TopK:            uint32(numDocuments),
+			TopK:            uint32(topK),
 			Filter:          protoFilterStruct,
 			IncludeMetadata: true,
 			IncludeValues:   true,
@@ -174,16 +289,23 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return []schema.Document{}, nil
 	}
 
-	return s.getDocumentsFromMatches(queryResult, scoreThreshold)
+	docs, vectors, err := s.getDocumentsFromMatches(queryResult, scoreThreshold)
+	if err != nil || !opts.MMR {
+		return docs, err
+	}
+	return vectorstores.SelectByMMR(ctx, s.embedder, vector, docs, vectors, numDocuments, opts.MMRLambda)
 }
 
-func (s Store) getDocumentsFromMatches(queryResult *pinecone.QueryVectorsResponse, scoreThreshold float32) ([]schema.Document, error) {
+// getDocumentsFromMatches returns the documents of the matches above the score
+// threshold, and their vectors.
+func (s Store) getDocumentsFromMatches(queryResult *pinecone.QueryVectorsResponse, scoreThreshold float32) ([]schema.Document, [][]float32, error) { //nolint:lll
 	resultDocuments := make([]schema.Document, 0)
+	resultVectors := make([][]float32, 0)
 	for _, match := range queryResult.Matches {
 		metadata := match.Vector.Metadata.AsMap()
 		pageContent, ok := metadata[s.textKey].(string)
 		if !ok {
-			return nil, ErrMissingTextKey
+			return nil, nil, ErrMissingTextKey
 		}
 		delete(metadata, s.textKey)
 
@@ -196,11 +318,13 @@ func (s Store) getDocumentsFromMatches(queryResult *pinecone.QueryVectorsRespons
 		// If scoreThreshold is not 0, we only return matches with a score above the threshold.
 		if scoreThreshold != 0 && match.Score >= scoreThreshold {
 			resultDocuments = append(resultDocuments, doc)
+			resultVectors = append(resultVectors, match.Vector.Values)
 		} else if scoreThreshold == 0 { // If scoreThreshold is 0, we return all matches.
 			resultDocuments = append(resultDocuments, doc)
+			resultVectors = append(resultVectors, match.Vector.Values)
 		}
 	}
-	return resultDocuments, nil
+	return resultDocuments, resultVectors, nil
 }
 
 func (s Store) getNameSpace(opts vectorstores.Options) string {
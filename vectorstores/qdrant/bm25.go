@@ -0,0 +1,63 @@
+package qdrant
+
+import (
+	"hash/fnv"
+
+	"github.com/IT-Tech-Company/langchaingo/retrievers"
+)
+
+// The parameters of the BM25 weights of the terms of the points. Qdrant
+// doesn't know the average length of the contents, so it is estimated.
+const (
+	bm25K1        = 1.2
+	bm25B         = 0.75
+	bm25AvgLength = 256
+)
+
+// bm25Vector returns the sparse vector of the terms of a content, weighted by
+// their saturated and length normalized frequency. Qdrant multiplies them by
+// the inverse document frequency of the terms when the sparse vector of the
+// collection has the IDF modifier, so that the score of a query is its BM25
+// score.
+func bm25Vector(text string) sparseVector {
+	terms := retrievers.DefaultTokenizer(text)
+	frequencies := make(map[uint32]float32, len(terms))
+	for _, term := range terms {
+		frequencies[termIndex(term)]++
+	}
+
+	norm := bm25K1 * (1 - bm25B + bm25B*float32(len(terms))/bm25AvgLength)
+	vector := sparseVector{
+		Indices: make([]uint32, 0, len(frequencies)),
+		Values:  make([]float32, 0, len(frequencies)),
+	}
+	for index, tf := range frequencies {
+		vector.Indices = append(vector.Indices, index)
+		vector.Values = append(vector.Values, tf*(bm25K1+1)/(tf+norm))
+	}
+	return vector
+}
+
+// bm25QueryVector returns the sparse vector of the terms of a query, which
+// all weigh 1.
+func bm25QueryVector(query string) sparseVector {
+	var vector sparseVector
+	seen := make(map[uint32]bool)
+	for _, term := range retrievers.DefaultTokenizer(query) {
+		index := termIndex(term)
+		if seen[index] {
+			continue
+		}
+		seen[index] = true
+		vector.Indices = append(vector.Indices, index)
+		vector.Values = append(vector.Values, 1)
+	}
+	return vector
+}
+
+// termIndex returns the index of a term in the sparse vectors.
+func termIndex(term string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(term))
+	return h.Sum32()
+}
@@ -0,0 +1,85 @@
+package qdrant
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBM25Vector(t *testing.T) {
+	t.Parallel()
+
+	vector := bm25Vector("Error E4012: the printer reports an error")
+	require.Len(t, vector.Indices, 6)
+	weights := make(map[uint32]float32, len(vector.Indices))
+	for i, index := range vector.Indices {
+		weights[index] = vector.Values[i]
+	}
+	// repeated terms weigh more, but less than twice as much.
+	assert.Greater(t, weights[termIndex("error")], weights[termIndex("e4012")])
+	assert.Less(t, weights[termIndex("error")], 2*weights[termIndex("e4012")])
+
+	// longer contents weigh their terms less.
+	longer := bm25Vector("E4012" + strings.Repeat(" the printer reports an error", 100))
+	for i, index := range longer.Indices {
+		if index == termIndex("e4012") {
+			assert.Less(t, longer.Values[i], weights[termIndex("e4012")])
+		}
+	}
+
+	query := bm25QueryVector("error E4012 error")
+	assert.Equal(t, []uint32{termIndex("error"), termIndex("e4012")}, query.Indices)
+	assert.Equal(t, []float32{1, 1}, query.Values)
+}
+
+func TestHybridQueryBody(t *testing.T) {
+	t.Parallel()
+
+	s := Store{contentKey: defaultContentKey, sparseVector: "text"}
+	filter := map[string]any{"must": []any{map[string]any{"key": "kind", "match": map[string]any{"value": "manual"}}}}
+	body, err := s.newQueryBody("E4012", []float32{1, 0}, 3, 0.5, filter, vectorstores.Options{
+		HybridSearch: true,
+		HybridAlpha:  0.5,
+	})
+	require.NoError(t, err)
+
+	b, err := json.Marshal(body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"prefetch": [
+			{"query": [1, 0], "filter": `+mustJSON(t, filter)+`, "limit": 3, "score_threshold": 0.5},
+			{"query": {"indices": [`+mustJSON(t, termIndex("e4012"))+`], "values": [1]},
+			 "using": "text", "filter": `+mustJSON(t, filter)+`, "limit": 3}
+		],
+		"query": {"fusion": "rrf"},
+		"limit": 3,
+		"with_payload": false
+	}`, string(b))
+
+	// a pure keyword search only has the keyword search.
+	body, err = s.newQueryBody("E4012", []float32{1, 0}, 3, 0, nil, vectorstores.Options{HybridSearch: true})
+	require.NoError(t, err)
+	require.Len(t, body.Prefetch, 1)
+	assert.Equal(t, "text", body.Prefetch[0].Using)
+}
+
+func TestHybridSearchWithoutSparseVector(t *testing.T) {
+	t.Parallel()
+
+	_, err := Store{contentKey: defaultContentKey}.SimilaritySearch(context.Background(), "E4012", 3,
+		vectorstores.WithHybridSearch(0.5))
+	require.ErrorIs(t, err, ErrMissingSparseVector)
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(b)
+}
@@ -1,3 +1,11 @@
 // Package qdrant contains an implementation of the VectorStore
 // interface using Qdrant.
+//
+// Hybrid searches, see vectorstores.WithHybridSearch, need Qdrant 1.10 or later
+// and a sparse vector, see WithSparseVector. Their keyword search ranks the
+// points by the BM25 score of their content: the store stores the weights of
+// the terms of the content in the sparse vector, and Qdrant weighs them by
+// their inverse document frequency.
+// Maximal marginal relevance searches, see vectorstores.WithMMR, use the native
+// MMR of Qdrant 1.15 and later.
 package qdrant
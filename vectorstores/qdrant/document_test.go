@@ -0,0 +1,179 @@
+package qdrant
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lengthEmbedder embeds texts as their length.
+type lengthEmbedder struct{}
+
+func (lengthEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text))}
+	}
+	return vectors, nil
+}
+
+func (lengthEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text))}, nil
+}
+
+// fakeRequest is a request received by a fakeServer.
+type fakeRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// fakeServer is a Qdrant server which records the requests it receives, and
+// answers them with the response of their path.
+type fakeServer struct {
+	mu        sync.Mutex
+	requests  []fakeRequest
+	responses map[string]string
+}
+
+func newFakeStore(t *testing.T, responses map[string]string, opts ...Option) (Store, *fakeServer) {
+	t.Helper()
+
+	f := &fakeServer{responses: responses}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	s, err := New(append([]Option{
+		WithURL(*u),
+		WithCollectionName("docs"),
+		WithEmbedder(lengthEmbedder{}),
+	}, opts...)...)
+	require.NoError(t, err)
+	return s, f
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.requests = append(f.requests, fakeRequest{Method: r.Method, Path: r.URL.Path, Body: string(body)})
+	response, ok := f.responses[r.URL.Path]
+	if !ok {
+		response = `{"result": {"status": "completed"}, "status": "ok"}`
+	}
+	_, _ = w.Write([]byte(response))
+}
+
+func (f *fakeServer) last() fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests[len(f.requests)-1]
+}
+
+func TestDocumentManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tokyo, osaka := vectorstores.DocumentUUID("tokyo"), vectorstores.DocumentUUID("docs/osaka.md")
+	store, server := newFakeStore(t, map[string]string{
+		// the retrieved points are in any order, with UUIDs in canonical form.
+		"/collections/docs/points": `{"result": [
+			{"id": "` + osaka + `", "payload": {"content": "Osaka", "country": "japan"}},
+			{"id": "` + tokyo + `", "payload": {"content": "Tokyo", "country": "japan"}}
+		], "status": "ok"}`,
+	})
+
+	err := store.Upsert(ctx, []string{"tokyo", "docs/osaka.md"}, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+	req := server.last()
+	assert.Equal(t, http.MethodPut, req.Method)
+	assert.Equal(t, "/collections/docs/points", req.Path)
+	assert.JSONEq(t, `{"batch": {
+		"ids": ["`+tokyo+`", "`+osaka+`"],
+		"vectors": [[5], [5]],
+		"payloads": [{"content": "Tokyo", "country": "japan"}, {"content": "Osaka", "country": "japan"}]
+	}}`, req.Body)
+
+	docs, err := store.Get(ctx, []string{"tokyo", "docs/osaka.md", "unknown"})
+	require.NoError(t, err)
+	req = server.last()
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.JSONEq(t, `{
+		"ids": ["`+tokyo+`", "`+osaka+`", "`+vectorstores.DocumentUUID("unknown")+`"],
+		"with_payload": true
+	}`, req.Body)
+	assert.Equal(t, map[string]schema.Document{
+		"tokyo":         {PageContent: "Tokyo", Metadata: map[string]any{"country": "japan"}},
+		"docs/osaka.md": {PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	}, docs)
+
+	require.NoError(t, store.Delete(ctx, []string{"tokyo"}))
+	req = server.last()
+	assert.Equal(t, "/collections/docs/points/delete", req.Path)
+	assert.JSONEq(t, `{"points": ["`+tokyo+`"]}`, req.Body)
+
+	filter := map[string]any{"must": []any{map[string]any{"key": "country", "match": map[string]any{"value": "japan"}}}}
+	require.NoError(t, store.DeleteByFilter(ctx, filter))
+	req = server.last()
+	assert.Equal(t, "/collections/docs/points/delete", req.Path)
+	assert.JSONEq(t, `{"filter": `+mustJSON(t, filter)+`}`, req.Body)
+
+	err = store.Upsert(ctx, []string{"a", "b"}, []schema.Document{{PageContent: "Tokyo"}})
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+	err = store.DeleteByFilter(ctx, nil)
+	require.ErrorIs(t, err, vectorstores.ErrMissingFilter)
+}
+
+func TestUpsertWithSparseVector(t *testing.T) {
+	t.Parallel()
+
+	store, server := newFakeStore(t, nil, WithSparseVector("text"))
+	err := store.Upsert(context.Background(), []string{"tokyo"}, []schema.Document{{PageContent: "Tokyo"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"batch": {
+		"ids": ["`+vectorstores.DocumentUUID("tokyo")+`"],
+		"vectors": {"": [[5]], "text": [`+mustJSON(t, bm25Vector("Tokyo"))+`]},
+		"payloads": [{"content": "Tokyo"}]
+	}}`, server.last().Body)
+}
+
+func TestDocumentManagerErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status": {"error": "Not found: Collection docs doesn't exist!"}}`))
+	}))
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	store, err := New(WithURL(*u), WithCollectionName("docs"), WithEmbedder(lengthEmbedder{}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = store.Upsert(ctx, []string{"tokyo"}, []schema.Document{{PageContent: "Tokyo"}})
+	require.ErrorContains(t, err, "doesn't exist")
+	_, err = store.Get(ctx, []string{"tokyo"})
+	require.ErrorContains(t, err, "doesn't exist")
+	err = store.Delete(ctx, []string{"tokyo"})
+	require.ErrorContains(t, err, "doesn't exist")
+}
@@ -12,8 +12,13 @@ const (
 	defaultContentKey = "content"
 )
 
-// ErrInvalidOptions is returned when the options given are invalid.
-var ErrInvalidOptions = errors.New("invalid options")
+var (
+	// ErrInvalidOptions is returned when the options given are invalid.
+	ErrInvalidOptions = errors.New("invalid options")
+	// ErrMissingSparseVector is returned by the hybrid searches of a store
+	// without a sparse vector, see WithSparseVector.
+	ErrMissingSparseVector = errors.New("hybrid search needs a sparse vector")
+)
 
 // Option is a function that configures an Options.
 type Option func(p *Store)
@@ -56,6 +61,18 @@ func WithContentKey(contentKey string) Option {
 	}
 }
 
+// WithSparseVector returns an Option for setting the name of the sparse vector
+// of the collection holding the BM25 weights of the terms of the content,
+// which hybrid searches use for their keyword search. The collection must
+// have a sparse vector with that name and the IDF modifier, e.g.
+// {"sparse_vectors": {"text": {"modifier": "idf"}}}, besides its unnamed dense
+// vector. Optional, but required by hybrid searches.
+func WithSparseVector(name string) Option {
+	return func(p *Store) {
+		p.sparseVector = name
+	}
+}
+
 func applyClientOptions(opts ...Option) (Store, error) {
 	o := &Store{
 		contentKey: defaultContentKey,
@@ -3,11 +3,13 @@ package qdrant
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
 
 	"github.com/IT-Tech-Company/langchaingo/embeddings"
 	"github.com/IT-Tech-Company/langchaingo/schema"
 	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/google/uuid"
 )
 
 type Store struct {
@@ -16,9 +18,10 @@ type Store struct {
 	qdrantURL      url.URL
 	apiKey         string
 	contentKey     string
+	sparseVector   string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var _ vectorstores.DocumentManager = Store{}
 
 func New(opts ...Option) (Store, error) {
 	s, err := applyClientOptions(opts...)
@@ -32,6 +35,93 @@ func (s Store) AddDocuments(ctx context.Context,
 	docs []schema.Document,
 	_ ...vectorstores.Option,
 ) ([]string, error) {
+	vectors, payloads, err := s.points(ctx, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	if err := s.upsertPoints(ctx, &s.qdrantURL, ids, vectors, payloads); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Upsert adds the documents as points with the ids, replacing the points with
+// the same ids. The ids which aren't UUIDs are stored as UUIDs derived from
+// them, see vectorstores.DocumentUUID.
+func (s Store) Upsert(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	_ ...vectorstores.Option,
+) error {
+	if len(ids) != len(docs) {
+		return vectorstores.ErrMismatchedIDs
+	}
+	vectors, payloads, err := s.points(ctx, docs)
+	if err != nil {
+		return err
+	}
+
+	pointIDs := make([]string, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = vectorstores.DocumentUUID(id)
+	}
+	return s.upsertPoints(ctx, &s.qdrantURL, pointIDs, vectors, payloads)
+}
+
+// Get returns the documents of the points with the ids, by id.
+func (s Store) Get(ctx context.Context,
+	ids []string,
+	_ ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	byPointID := make(map[string]string, len(ids))
+	pointIDs := make([]string, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = vectorstores.DocumentUUID(id)
+		byPointID[pointIDs[i]] = id
+	}
+
+	results, err := s.retrievePoints(ctx, &s.qdrantURL, pointIDs)
+	if err != nil {
+		return nil, err
+	}
+	found, err := s.resultsToDocuments(results)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]schema.Document, len(found))
+	for i, doc := range found {
+		docs[byPointID[fmt.Sprint(results[i].ID)]] = doc
+	}
+	return docs, nil
+}
+
+// Delete deletes the points with the ids.
+func (s Store) Delete(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	pointIDs := make([]string, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = vectorstores.DocumentUUID(id)
+	}
+	return s.deletePoints(ctx, &s.qdrantURL, deleteBody{Points: pointIDs})
+}
+
+// DeleteByFilter deletes the points matching the filter, a Qdrant filter.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	return s.deletePoints(ctx, &s.qdrantURL, deleteBody{Filter: filter})
+}
+
+// points returns the vectors and the payloads of the points of the documents.
+// The vectors are the dense vectors, or the dense and the BM25 sparse vectors
+// by name if the store has a sparse vector.
+func (s Store) points(ctx context.Context, docs []schema.Document) (any, []map[string]interface{}, error) {
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
@@ -40,11 +130,11 @@ func (s Store) AddDocuments(ctx context.Context,
 	vectors,
 		err := s.embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if len(vectors) != len(docs) {
-		return nil, errors.New("number of vectors from embedder does not match number of documents")
+		return nil, nil, errors.New("number of vectors from embedder does not match number of documents")
 	}
 
 	metadatas := make([]map[string]interface{}, 0, len(docs))
@@ -58,7 +148,14 @@ func (s Store) AddDocuments(ctx context.Context,
 		metadatas = append(metadatas, metadata)
 	}
 
-	return s.upsertPoints(ctx, &s.qdrantURL, vectors, metadatas)
+	if s.sparseVector == "" {
+		return vectors, metadatas, nil
+	}
+	sparseVectors := make([]sparseVector, len(texts))
+	for i, text := range texts {
+		sparseVectors[i] = bm25Vector(text)
+	}
+	return map[string]any{"": vectors, s.sparseVector: sparseVectors}, metadatas, nil
 }
 
 func (s Store) SimilaritySearch(ctx context.Context,
@@ -74,6 +171,12 @@ func (s Store) SimilaritySearch(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if opts.HybridSearch && (opts.HybridAlpha < 0 || opts.HybridAlpha > 1) {
+		return nil, vectorstores.ErrInvalidHybridAlpha
+	}
+	if opts.HybridSearch && opts.HybridAlpha < 1 && s.sparseVector == "" {
+		return nil, ErrMissingSparseVector
+	}
 
 	vector,
 		err := s.embedder.EmbedQuery(ctx, query)
@@ -81,6 +184,13 @@ func (s Store) SimilaritySearch(ctx context.Context,
 		return nil, err
 	}
 
+	if opts.MMR || (opts.HybridSearch && opts.HybridAlpha < 1) {
+		body, err := s.newQueryBody(query, vector, numDocuments, scoreThreshold, filters, opts)
+		if err != nil {
+			return nil, err
+		}
+		return s.queryPoints(ctx, &s.qdrantURL, body)
+	}
 	return s.searchPoints(ctx, &s.qdrantURL, vector, numDocuments, scoreThreshold, filters)
 }
 
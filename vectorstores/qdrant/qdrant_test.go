@@ -113,6 +113,103 @@ func TestQdrantStoreWithScoreThreshold(t *testing.T) {
 	require.Len(t, docs, 10)
 }
 
+func TestQdrantStoreHybridSearch(t *testing.T) {
+	t.Parallel()
+
+	qdrantURL, apiKey, dimension, distance := getValues(t)
+	collectionName := setupCollection(t, qdrantURL, apiKey, dimension, distance)
+
+	opts := []openai.Option{
+		openai.WithModel("gpt-3.5-turbo-0125"),
+		openai.WithEmbeddingModel("text-embedding-ada-002"),
+	}
+
+	llm, err := openai.New(opts...)
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	url, err := url.Parse(qdrantURL)
+	require.NoError(t, err)
+	store, err := qdrant.New(
+		qdrant.WithURL(*url),
+		qdrant.WithAPIKey(apiKey),
+		qdrant.WithCollectionName(collectionName),
+		qdrant.WithEmbedder(e),
+		qdrant.WithSparseVector("text"),
+	)
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "The printer reports error E4012 when its tray is empty"},
+		{PageContent: "The printer stops when the paper jams"},
+		{PageContent: "The router blinks when the network is down"},
+	})
+	require.NoError(t, err)
+
+	// a pure keyword search only finds the points containing the query.
+	docs, err := store.SimilaritySearch(context.Background(), "E4012", 3,
+		vectorstores.WithHybridSearch(0))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Contains(t, docs[0].PageContent, "E4012")
+
+	docs, err = store.SimilaritySearch(context.Background(), "E4012", 3,
+		vectorstores.WithHybridSearch(0.5))
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	require.Contains(t, docs[0].PageContent, "E4012")
+
+	_, err = store.SimilaritySearch(context.Background(), "E4012", 1,
+		vectorstores.WithHybridSearch(1.1))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridAlpha)
+}
+
+func TestQdrantStoreMMR(t *testing.T) {
+	t.Parallel()
+
+	qdrantURL, apiKey, dimension, distance := getValues(t)
+	collectionName := setupCollection(t, qdrantURL, apiKey, dimension, distance)
+
+	opts := []openai.Option{
+		openai.WithModel("gpt-3.5-turbo-0125"),
+		openai.WithEmbeddingModel("text-embedding-ada-002"),
+	}
+
+	llm, err := openai.New(opts...)
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	url, err := url.Parse(qdrantURL)
+	require.NoError(t, err)
+	store, err := qdrant.New(
+		qdrant.WithURL(*url),
+		qdrant.WithAPIKey(apiKey),
+		qdrant.WithCollectionName(collectionName),
+		qdrant.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "Tokyo is the capital of Japan"},
+		{PageContent: "The capital of Japan is Tokyo"},
+		{PageContent: "Kyoto was the capital of Japan for a thousand years"},
+	})
+	require.NoError(t, err)
+
+	// the duplicate of the most relevant point is skipped.
+	docs, err := store.SimilaritySearch(context.Background(), "capital of Japan", 2,
+		vectorstores.WithMMR(3, 0.3))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Contains(t, docs[1].PageContent, "Kyoto")
+
+	_, err = store.SimilaritySearch(context.Background(), "capital of Japan", 2,
+		vectorstores.WithMMR(3, -0.5))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidMMRLambda)
+}
+
 func TestSimilaritySearchWithInvalidScoreThreshold(t *testing.T) {
 	t.Parallel()
 
@@ -365,7 +462,7 @@ func getValues(t *testing.T) (string, string, int, string) {
 
 	qdrantURL := os.Getenv("QDRANT_URL")
 	if qdrantURL == "" {
-		qdrantContainer, err := tcqdrant.RunContainer(context.Background(), testcontainers.WithImage("qdrant/qdrant:v1.7.4"))
+		qdrantContainer, err := tcqdrant.RunContainer(context.Background(), testcontainers.WithImage("qdrant/qdrant:v1.15.0"))
 		if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
 			t.Skip("Docker not available")
 		}
@@ -404,6 +501,9 @@ func setupCollection(t *testing.T, qdrantURL, apiKey string, dimension int, dist
 			"size":     dimension,
 			"distance": distance,
 		},
+		"sparse_vectors": map[string]interface{}{
+			"text": map[string]interface{}{"modifier": "idf"},
+		},
 	}
 
 	url, err := url.Parse(qdrantURL)
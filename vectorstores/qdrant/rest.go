@@ -10,21 +10,17 @@ import (
 	"net/url"
 
 	"github.com/IT-Tech-Company/langchaingo/schema"
-	"github.com/google/uuid"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
 )
 
 // upsertPoints updates or inserts points into the Qdrant collection.
 func (s Store) upsertPoints(
 	ctx context.Context,
 	baseURL *url.URL,
-	vectors [][]float32,
+	ids []string,
+	vectors any,
 	payloads []map[string]interface{},
-) ([]string, error) {
-	ids := make([]string, len(vectors))
-	for i := range ids {
-		ids[i] = uuid.NewString()
-	}
-
+) error {
 	payload := upsertBody{
 		Batch: upsertBatch{
 			IDs:      ids,
@@ -43,16 +39,54 @@ func (s Store) upsertPoints(
 		payload,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer body.Close()
 
 	if status == http.StatusOK {
-		return ids, nil
+		return nil
+	}
+
+	return newAPIError("upserting vectors", body)
+}
+
+// retrievePoints returns the points of the Qdrant collection with the ids.
+func (s Store) retrievePoints(ctx context.Context, baseURL *url.URL, ids []string) ([]result, error) {
+	url := baseURL.JoinPath("collections", s.collectionName, "points")
+	body, statusCode, err := DoRequest(ctx, *url, s.apiKey, http.MethodPost, retrieveBody{
+		IDs:         ids,
+		WithPayload: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode != http.StatusOK {
+		return nil, newAPIError("retrieving points", body)
+	}
+
+	var response retrieveResponse
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return nil, err
 	}
+	return response.Result, nil
+}
+
+// deletePoints deletes the points of the Qdrant collection with the ids or
+// matching the filter of the payload.
+func (s Store) deletePoints(ctx context.Context, baseURL *url.URL, payload deleteBody) error {
+	url := baseURL.JoinPath("collections", s.collectionName, "points", "delete")
+	body, statusCode, err := DoRequest(ctx, *url, s.apiKey, http.MethodPost, payload)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
 
-	return nil,
-		newAPIError("upserting vectors", body)
+	if statusCode != http.StatusOK {
+		return newAPIError("deleting points", body)
+	}
+	return nil
 }
 
 // searchPoints queries the Qdrant collection for points based on the provided parameters.
@@ -101,8 +135,124 @@ func (s Store) searchPoints(
 	if err != nil {
 		return nil, err
 	}
-	docs := make([]schema.Document, len(response.Result))
-	for i, match := range response.Result {
+	return s.resultsToDocuments(response.Result)
+}
+
+// queryPoints queries the Qdrant collection for points with the query API of
+// Qdrant 1.10 and later.
+func (s Store) queryPoints(ctx context.Context, baseURL *url.URL, payload queryBody) ([]schema.Document, error) {
+	payload.WithPayload = true
+
+	url := baseURL.JoinPath("collections", s.collectionName, "points", "query")
+	body,
+		statusCode,
+		err := DoRequest(
+		ctx, *url,
+		s.apiKey,
+		http.MethodPost,
+		payload,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode != http.StatusOK {
+		return nil, newAPIError("querying collection", body)
+	}
+
+	var response queryResponse
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return s.resultsToDocuments(response.Result.Points)
+}
+
+// newQueryBody returns the body of a query of the points for a hybrid or a
+// maximal marginal relevance search.
+//
+// A hybrid search fuses by rank the points found by a vector search and by a
+// BM25 keyword search of the sparse vectors. A maximal marginal relevance search uses the native MMR of Qdrant
+// 1.15 and later, on the points of the hybrid search if any.
+func (s Store) newQueryBody(
+	query string,
+	vector []float32,
+	numVectors int,
+	scoreThreshold float32,
+	filter any,
+	opts vectorstores.Options,
+) (queryBody, error) {
+	limit := numVectors
+	if opts.MMR {
+		var err error
+		if limit, err = vectorstores.MMRFetchK(opts, numVectors); err != nil {
+			return queryBody{}, err
+		}
+	}
+
+	payload := queryBody{
+		Query:          vector,
+		Filter:         filter,
+		Limit:          limit,
+		ScoreThreshold: scoreThreshold,
+	}
+	if opts.HybridSearch && opts.HybridAlpha < 1 {
+		payload = queryBody{
+			Prefetch: s.hybridPrefetches(query, vector, limit, scoreThreshold, filter, opts.HybridAlpha),
+			Query:    map[string]any{"fusion": "rrf"},
+			Limit:    limit,
+		}
+	}
+	if !opts.MMR {
+		return payload, nil
+	}
+
+	mmr := map[string]any{
+		"nearest": vector,
+		"mmr": map[string]any{
+			"diversity":        1 - opts.MMRLambda,
+			"candidates_limit": limit,
+		},
+	}
+	if payload.Prefetch == nil {
+		payload.Query = mmr
+		payload.Limit = numVectors
+		return payload, nil
+	}
+	return queryBody{
+		Prefetch: []prefetch{{Prefetch: payload.Prefetch, Query: payload.Query, Limit: limit}},
+		Query:    mmr,
+		Limit:    numVectors,
+	}, nil
+}
+
+// hybridPrefetches returns the searches of a hybrid search: a vector search
+// unless alpha is 0, and a keyword search of the sparse vectors with the BM25
+// weights of the terms of the query. The scores of the keyword search aren't
+// bounded, so the score threshold only applies to the vector search.
+func (s Store) hybridPrefetches(
+	query string,
+	vector []float32,
+	limit int,
+	scoreThreshold float32,
+	filter any,
+	alpha float32,
+) []prefetch {
+	prefetches := make([]prefetch, 0, 2)
+	if alpha > 0 {
+		prefetches = append(prefetches, prefetch{
+			Query: vector, Filter: filter, Limit: limit, ScoreThreshold: scoreThreshold,
+		})
+	}
+	return append(prefetches, prefetch{
+		Query: bm25QueryVector(query), Using: s.sparseVector, Filter: filter, Limit: limit,
+	})
+}
+
+// resultsToDocuments converts the points found by a search to documents.
+func (s Store) resultsToDocuments(results []result) ([]schema.Document, error) {
+	docs := make([]schema.Document, len(results))
+	for i, match := range results {
 		pageContent, ok := match.Payload[s.contentKey].(string)
 		if !ok {
 			return nil, fmt.Errorf("payload does not contain content key '%s'", s.contentKey)
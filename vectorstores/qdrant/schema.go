@@ -8,7 +8,14 @@ package qdrant
 type upsertBatch struct {
 	IDs      []string                 `json:"ids"`
 	Payloads []map[string]interface{} `json:"payloads"`
-	Vectors  [][]float32              `json:"vectors"`
+	// Vectors are the dense vectors of the points, or the dense and the
+	// sparse vectors by name, the dense ones having the default name "".
+	Vectors any `json:"vectors"`
+}
+
+type sparseVector struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
 }
 
 type upsertBody struct {
@@ -16,6 +23,7 @@ type upsertBody struct {
 }
 
 type result struct {
+	ID      any                    `json:"id"`
 	Score   float32                `json:"score"`
 	Payload map[string]interface{} `json:"payload"`
 }
@@ -24,6 +32,20 @@ type searchResponse struct {
 	Result []result `json:"result"`
 }
 
+type retrieveBody struct {
+	IDs         []string `json:"ids"`
+	WithPayload bool     `json:"with_payload"`
+}
+
+type retrieveResponse struct {
+	Result []result `json:"result"`
+}
+
+type deleteBody struct {
+	Points []string `json:"points,omitempty"`
+	Filter any      `json:"filter,omitempty"`
+}
+
 type searchBody struct {
 	Vector         []float32 `json:"vector"`
 	Filter         any       `json:"filter"`
@@ -32,3 +54,27 @@ type searchBody struct {
 	WithVector     bool      `json:"with_vector"`
 	WithPayload    bool      `json:"with_payload"`
 }
+
+type prefetch struct {
+	Prefetch       []prefetch `json:"prefetch,omitempty"`
+	Query          any        `json:"query"`
+	Using          string     `json:"using,omitempty"`
+	Filter         any        `json:"filter,omitempty"`
+	Limit          int        `json:"limit"`
+	ScoreThreshold float32    `json:"score_threshold,omitempty"`
+}
+
+type queryBody struct {
+	Prefetch       []prefetch `json:"prefetch,omitempty"`
+	Query          any        `json:"query"`
+	Filter         any        `json:"filter,omitempty"`
+	Limit          int        `json:"limit"`
+	ScoreThreshold float32    `json:"score_threshold,omitempty"`
+	WithPayload    bool       `json:"with_payload"`
+}
+
+type queryResponse struct {
+	Result struct {
+		Points []result `json:"points"`
+	} `json:"result"`
+}
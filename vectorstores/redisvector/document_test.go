@@ -0,0 +1,106 @@
+package redisvector
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// documentClient is a redis client holding the documents by key, whose
+// searches match all the documents of the index.
+type documentClient struct {
+	RedisClient
+
+	docs    map[string]schema.Document
+	queries []string
+}
+
+func (c *documentClient) CheckIndexExists(context.Context, string) bool {
+	return true
+}
+
+func (c *documentClient) SetDocsWithHash(_ context.Context, keys []string, docs []schema.Document) error {
+	for i, key := range keys {
+		c.docs[key] = docs[i]
+	}
+	return nil
+}
+
+func (c *documentClient) GetDocsWithHash(_ context.Context, keys []string) (map[string]schema.Document, error) {
+	docs := map[string]schema.Document{}
+	for _, key := range keys {
+		if doc, ok := c.docs[key]; ok {
+			docs[key] = doc
+		}
+	}
+	return docs, nil
+}
+
+func (c *documentClient) DeleteDocs(_ context.Context, keys []string) error {
+	for _, key := range keys {
+		delete(c.docs, key)
+	}
+	return nil
+}
+
+func (c *documentClient) SearchKeys(_ context.Context, _, query string, limit int) ([]string, error) {
+	c.queries = append(c.queries, query)
+	keys := make([]string, 0, len(c.docs))
+	for key := range c.docs {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys[:min(limit, len(keys))], nil
+}
+
+func TestStoreDocumentKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := &documentClient{docs: map[string]schema.Document{}}
+	s := &Store{embedder: &axisEmbedder{}, indexName: "cities", client: client}
+
+	err := s.Upsert(ctx, []string{"tokyo", "doc:cities:osaka"}, []schema.Document{
+		{PageContent: "Tokyo"},
+		{PageContent: "Osaka"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, client.docs, "doc:cities:tokyo")
+	assert.Contains(t, client.docs, "doc:cities:osaka")
+
+	// the documents are returned by the ids they were requested with.
+	docs, err := s.Get(ctx, []string{"osaka", "doc:cities:tokyo", "unknown"})
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "Osaka", docs["osaka"].PageContent)
+	assert.Equal(t, "Tokyo", docs["doc:cities:tokyo"].PageContent)
+
+	require.NoError(t, s.Delete(ctx, []string{"osaka"}))
+	assert.NotContains(t, client.docs, "doc:cities:osaka")
+
+	// the matching documents are searched until none is left.
+	require.NoError(t, s.DeleteByFilter(ctx, "@country:japan"))
+	assert.Empty(t, client.docs)
+	assert.Equal(t, []string{"@country:japan", "@country:japan"}, client.queries)
+}
+
+func TestStoreUnsupportedClient(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := &Store{embedder: &axisEmbedder{}, indexName: "cities", client: vectorSearchClient{}}
+
+	err := s.Upsert(ctx, []string{"tokyo"}, []schema.Document{{PageContent: "Tokyo"}})
+	require.ErrorIs(t, err, ErrUnsupportedClient)
+	_, err = s.Get(ctx, []string{"tokyo"})
+	require.ErrorIs(t, err, ErrUnsupportedClient)
+	err = s.Delete(ctx, []string{"tokyo"})
+	require.ErrorIs(t, err, ErrUnsupportedClient)
+	err = s.DeleteByFilter(ctx, "@country:japan", vectorstores.WithNameSpace("cities"))
+	require.ErrorIs(t, err, ErrUnsupportedClient)
+}
@@ -190,3 +190,11 @@ func TestIndexSearchAsCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestParseVectorString32(t *testing.T) {
+	t.Parallel()
+
+	vector := []float32{0.25, -1.5, 3}
+	assert.Equal(t, vector, parseVectorString32(VectorString32(vector)))
+	assert.Empty(t, parseVectorString32(""))
+}
@@ -152,6 +152,15 @@ func VectorString32(v []float32) string {
 	return unsafe.String(unsafe.SliceData(b), len(b))
 }
 
+// parseVectorString32 converts a string of VectorString32 into []float32.
+func parseVectorString32(s string) []float32 {
+	v := make([]float32, len(s)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32([]byte(s[i*4 : i*4+4])))
+	}
+	return v
+}
+
 // convert []float64 into string.
 func VectorString64(v []float64) string {
 	b := make([]byte, len(v)*8)
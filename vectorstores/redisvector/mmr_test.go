@@ -0,0 +1,66 @@
+package redisvector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vectorSearchClient is a redis client whose searches return the documents
+// and their vectors.
+type vectorSearchClient struct {
+	RedisClient
+
+	docs    []schema.Document
+	vectors [][]float32
+}
+
+func (c vectorSearchClient) SearchWithVectors(context.Context, IndexVectorSearch) ([]schema.Document, [][]float32, error) { //nolint:lll
+	return c.docs, c.vectors, nil
+}
+
+// axisEmbedder embeds the texts it knows as their vector, and records the
+// texts of the calls to EmbedDocuments.
+type axisEmbedder struct {
+	vectors map[string][]float32
+	calls   [][]string
+}
+
+func (e *axisEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls = append(e.calls, texts)
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.vectors[text]
+	}
+	return vectors, nil
+}
+
+func (e *axisEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return e.vectors[text], nil
+}
+
+func TestSimilaritySearchMMRUsesStoredVectors(t *testing.T) {
+	t.Parallel()
+
+	embedder := &axisEmbedder{vectors: map[string][]float32{"japan": {1, 0}}}
+	s := &Store{
+		embedder:  embedder,
+		indexName: "cities",
+		client: vectorSearchClient{
+			docs:    []schema.Document{{PageContent: "Tokyo"}, {PageContent: "Kyoto"}, {PageContent: "Paris"}},
+			vectors: [][]float32{{1, 0}, {1, 0.01}, {0.5, 0.5}},
+		},
+	}
+
+	docs, err := s.SimilaritySearch(context.Background(), "japan", 2, vectorstores.WithMMR(3, 0.3))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	// Kyoto is nearly the same as Tokyo, so the more diverse Paris is picked.
+	assert.Equal(t, "Tokyo", docs[0].PageContent)
+	assert.Equal(t, "Paris", docs[1].PageContent)
+	assert.Empty(t, embedder.calls, "the candidates were embedded")
+}
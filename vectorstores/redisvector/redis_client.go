@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
+	"slices"
 	"strconv"
 
 	"github.com/IT-Tech-Company/langchaingo/schema"
@@ -26,11 +27,32 @@ type RedisClient interface {
 	Search(ctx context.Context, search IndexVectorSearch) (int64, []schema.Document, error)
 }
 
+// DocumentClient is implemented by the redis clients that can manage the
+// documents by key, which the Store needs to implement
+// vectorstores.DocumentManager.
+type DocumentClient interface {
+	SetDocsWithHash(ctx context.Context, keys []string, docs []schema.Document) error
+	GetDocsWithHash(ctx context.Context, keys []string) (map[string]schema.Document, error)
+	DeleteDocs(ctx context.Context, keys []string) error
+	SearchKeys(ctx context.Context, index, query string, limit int) ([]string, error)
+}
+
+// VectorSearchClient is implemented by the redis clients that can return the
+// vectors of the documents found by a search, which the maximal marginal
+// relevance searches of the Store use instead of embedding the documents.
+type VectorSearchClient interface {
+	SearchWithVectors(ctx context.Context, search IndexVectorSearch) ([]schema.Document, [][]float32, error)
+}
+
 type RueidisClient struct {
 	client rueidis.Client
 }
 
-var _ RedisClient = RueidisClient{}
+var (
+	_ RedisClient        = RueidisClient{}
+	_ DocumentClient     = RueidisClient{}
+	_ VectorSearchClient = RueidisClient{}
+)
 
 // NewRueidisClient create rueidis redist client.
 func NewRueidisClient(url string) (*RueidisClient, error) {
@@ -99,6 +121,65 @@ func (c RueidisClient) AddDocsWithHash(ctx context.Context, prefix string, docs
 	return docIDs, errors.Join(errs...)
 }
 
+// SetDocsWithHash saves the documents at the keys, replacing the hashes of the
+// keys.
+func (c RueidisClient) SetDocsWithHash(ctx context.Context, keys []string, docs []schema.Document) error {
+	cmds := make([]rueidis.Completed, 0, 2*len(docs))
+	for i, doc := range docs {
+		cmds = append(cmds, c.client.B().Del().Key(keys[i]).Build(), c.generateHSetCMDWithKey(keys[i], doc))
+	}
+	errs := make([]error, 0, len(docs))
+	for _, res := range c.client.DoMulti(ctx, cmds...) {
+		if res.Error() != nil {
+			errs = append(errs, res.Error())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetDocsWithHash returns the documents saved at the keys, by key.
+func (c RueidisClient) GetDocsWithHash(ctx context.Context, keys []string) (map[string]schema.Document, error) {
+	cmds := make([]rueidis.Completed, 0, len(keys))
+	for _, key := range keys {
+		cmds = append(cmds, c.client.B().Hgetall().Key(key).Build())
+	}
+	docs := make(map[string]schema.Document, len(keys))
+	for i, res := range c.client.DoMulti(ctx, cmds...) {
+		fields, err := res.AsStrMap()
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			docs[keys[i]] = convertHashIntoDocSchema(keys[i], fields)
+		}
+	}
+	return docs, nil
+}
+
+// DeleteDocs deletes the documents saved at the keys.
+func (c RueidisClient) DeleteDocs(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Do(ctx, c.client.B().Del().Key(keys...).Build()).Error()
+}
+
+// SearchKeys returns the keys of at most limit documents of the index matching
+// the query.
+func (c RueidisClient) SearchKeys(ctx context.Context, index, query string, limit int) ([]string, error) {
+	cmd := c.client.B().Arbitrary("FT.SEARCH").Keys(index).
+		Args(query, "RETURN", "1", defaultContentFieldKey, "LIMIT", "0", strconv.Itoa(limit)).Build()
+	_, docs, err := c.client.Do(ctx, cmd).AsFtSearch()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(docs))
+	for i, doc := range docs {
+		keys[i] = doc.Key
+	}
+	return keys, nil
+}
+
 func (c RueidisClient) Search(ctx context.Context, search IndexVectorSearch) (int64, []schema.Document, error) {
 	cmds := search.AsCommand()
 	// fmt.Println(strings.Join(cmds, " "))
@@ -110,7 +191,35 @@ func (c RueidisClient) Search(ctx context.Context, search IndexVectorSearch) (in
 	return total, convertFTSearchResIntoDocSchema(docs), nil
 }
 
+// SearchWithVectors searches like Search, and returns the vectors of the
+// documents found too, or nil if some of them have no vector.
+func (c RueidisClient) SearchWithVectors(ctx context.Context, search IndexVectorSearch) ([]schema.Document, [][]float32, error) { //nolint:lll
+	if len(search.returns) > 0 {
+		search.returns = append(slices.Clip(search.returns), defaultContentVectorFieldKey)
+	}
+	cmds := search.AsCommand()
+	_, docs, err := c.client.Do(ctx, c.client.B().Arbitrary(cmds[0]).Keys(cmds[1]).Args(cmds[2:]...).Build()).AsFtSearch()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vectors := make([][]float32, len(docs))
+	for i, doc := range docs {
+		vector, ok := doc.Doc[defaultContentVectorFieldKey]
+		if !ok {
+			return convertFTSearchResIntoDocSchema(docs), nil, nil
+		}
+		vectors[i] = parseVectorString32(vector)
+	}
+	return convertFTSearchResIntoDocSchema(docs), vectors, nil
+}
+
 func (c RueidisClient) generateHSetCMD(prefix string, doc schema.Document) (string, rueidis.Completed) {
+	docID := getDocIDWithMetaData(prefix, doc.Metadata)
+	return docID, c.generateHSetCMDWithKey(docID, doc)
+}
+
+func (c RueidisClient) generateHSetCMDWithKey(key string, doc schema.Document) rueidis.Completed {
 	kvs := make([]string, 0, len(maps.Keys(doc.Metadata))*2)
 	for k, v := range doc.Metadata {
 		kvs = append(kvs, k)
@@ -127,8 +236,7 @@ func (c RueidisClient) generateHSetCMD(prefix string, doc schema.Document) (stri
 			kvs = append(kvs, fmt.Sprintf("%v", v))
 		}
 	}
-	docID := getDocIDWithMetaData(prefix, doc.Metadata)
-	return docID, c.client.B().Arbitrary("Hmset").Keys(docID).Args(kvs...).Build()
+	return c.client.B().Arbitrary("Hmset").Keys(key).Args(kvs...).Build()
 }
 
 // getPrefix get prefix with index name.
@@ -154,24 +262,28 @@ func getDocIDWithMetaData(prefix string, meta map[string]any) string {
 func convertFTSearchResIntoDocSchema(docs []rueidis.FtSearchDoc) []schema.Document {
 	res := make([]schema.Document, 0, len(docs))
 	for _, doc := range docs {
-		_doc := schema.Document{}
-		metadata := make(map[string]any, len(doc.Doc))
-		//nolint: gocritic
-		for k, v := range doc.Doc {
-			if k == defaultContentFieldKey {
-				_doc.PageContent = v
-			} else if k == defaultDistanceFieldKey {
-				score, _ := strconv.ParseFloat(v, 32)
-				_doc.Score = float32(score)
-			} else if k != defaultContentVectorFieldKey {
-				metadata[k] = v
-			}
-		}
-		if _, ok := metadata["id"]; !ok {
-			metadata["id"] = doc.Key
-		}
-		_doc.Metadata = metadata
-		res = append(res, _doc)
+		res = append(res, convertHashIntoDocSchema(doc.Key, doc.Doc))
 	}
 	return res
 }
+
+func convertHashIntoDocSchema(key string, fields map[string]string) schema.Document {
+	doc := schema.Document{}
+	metadata := make(map[string]any, len(fields))
+	//nolint: gocritic
+	for k, v := range fields {
+		if k == defaultContentFieldKey {
+			doc.PageContent = v
+		} else if k == defaultDistanceFieldKey {
+			score, _ := strconv.ParseFloat(v, 32)
+			doc.Score = float32(score)
+		} else if k != defaultContentVectorFieldKey {
+			metadata[k] = v
+		}
+	}
+	if _, ok := metadata["id"]; !ok {
+		metadata["id"] = key
+	}
+	doc.Metadata = metadata
+	return doc
+}
@@ -3,6 +3,8 @@ package redisvector
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/IT-Tech-Company/langchaingo/embeddings"
 	"github.com/IT-Tech-Company/langchaingo/schema"
@@ -15,6 +17,9 @@ const (
 	defaultContentFieldKey       = "content"        // page_content
 	defaultContentVectorFieldKey = "content_vector" // vector
 	defaultDistanceFieldKey      = "distance"       // distance
+
+	// deleteBatchSize is the number of documents DeleteByFilter deletes at once.
+	deleteBatchSize = 1000
 )
 
 var (
@@ -23,6 +28,7 @@ var (
 	ErrInvalidEmbeddingVector = errors.New("embedding vector error")
 	ErrInvalidScoreThreshold  = errors.New("score threshold must be between 0 and 1")
 	ErrInvalidFilters         = errors.New("invalid filters")
+	ErrUnsupportedClient      = errors.New("redis client does not implement DocumentClient")
 )
 
 // Store is a wrapper around the redis client.
@@ -36,7 +42,7 @@ type Store struct {
 	schemaGenerator        *schemaGenerator
 }
 
-var _ vectorstores.VectorStore = &Store{}
+var _ vectorstores.DocumentManager = &Store{}
 
 // New creates a new Store with options.
 func New(ctx context.Context, opts ...Option) (*Store, error) {
@@ -82,10 +88,115 @@ func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, _ ...v
 		return nil, err
 	}
 
-	indexSchema, err := generateSchemaWithMetadata(docs[0].Metadata)
+	if err := s.createIndexWithMetadata(ctx, docs[0].Metadata); err != nil {
+		return nil, err
+	}
+
+	docIDs, err := s.client.AddDocsWithHash(ctx, getPrefix(s.indexName), docs)
+	if err != nil {
+		return nil, err
+	}
+
+	return docIDs, nil
+}
+
+// Upsert adds the documents with the ids, replacing the documents with the
+// same ids. The ids are the keys of the documents, prefixed with
+// `doc:{index_name}:` unless they already are, like the ids returned by
+// AddDocuments.
+func (s *Store) Upsert(ctx context.Context, ids []string, docs []schema.Document, _ ...vectorstores.Option) error {
+	if len(ids) != len(docs) {
+		return vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	client, err := s.documentClient()
+	if err != nil {
+		return err
+	}
+	if err := s.appendDocumentsWithVectors(ctx, docs); err != nil {
+		return err
+	}
+	if err := s.createIndexWithMetadata(ctx, docs[0].Metadata); err != nil {
+		return err
+	}
+	return client.SetDocsWithHash(ctx, s.docKeys(ids), docs)
+}
+
+// Get returns the documents with the ids, by id.
+func (s *Store) Get(ctx context.Context, ids []string, _ ...vectorstores.Option) (map[string]schema.Document, error) {
+	client, err := s.documentClient()
 	if err != nil {
 		return nil, err
 	}
+	keys := s.docKeys(ids)
+	found, err := client.GetDocsWithHash(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	docs := make(map[string]schema.Document, len(found))
+	for i, key := range keys {
+		if doc, ok := found[key]; ok {
+			docs[ids[i]] = doc
+		}
+	}
+	return docs, nil
+}
+
+// Delete deletes the documents with the ids.
+func (s *Store) Delete(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	client, err := s.documentClient()
+	if err != nil {
+		return err
+	}
+	return client.DeleteDocs(ctx, s.docKeys(ids))
+}
+
+// DeleteByFilter deletes the documents of the index matching the filter, a
+// redis search query like the filters of SimilaritySearch.
+func (s *Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	query, ok := filter.(string)
+	if !ok {
+		return ErrInvalidFilters
+	}
+	client, err := s.documentClient()
+	if err != nil {
+		return err
+	}
+	for {
+		keys, err := client.SearchKeys(ctx, s.indexName, query, deleteBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		if err := client.DeleteDocs(ctx, keys); err != nil {
+			return err
+		}
+	}
+}
+
+// documentClient returns the client of the store as a DocumentClient.
+func (s *Store) documentClient() (DocumentClient, error) {
+	client, ok := s.client.(DocumentClient)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedClient, s.client)
+	}
+	return client, nil
+}
+
+// createIndexWithMetadata creates the index if needed, with the schema of the
+// metadata unless the schema is set.
+func (s *Store) createIndexWithMetadata(ctx context.Context, metadata map[string]any) error {
+	indexSchema, err := generateSchemaWithMetadata(metadata)
+	if err != nil {
+		return err
+	}
 
 	if s.indexSchema == nil {
 		s.indexSchema = indexSchema
@@ -93,16 +204,23 @@ func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, _ ...v
 
 	if s.createIndexIfNotExists && !s.client.CheckIndexExists(ctx, s.indexName) {
 		if err := s.client.CreateIndexIfNotExists(ctx, s.indexName, indexSchema); err != nil {
-			return nil, err
+			return err
 		}
 	}
+	return nil
+}
 
-	docIDs, err := s.client.AddDocsWithHash(ctx, getPrefix(s.indexName), docs)
-	if err != nil {
-		return nil, err
+// docKeys returns the keys of the documents with the ids.
+func (s *Store) docKeys(ids []string) []string {
+	prefix := getPrefix(s.indexName) + ":"
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = id
+		if !strings.HasPrefix(id, prefix) {
+			keys[i] = prefix + id
+		}
 	}
-
-	return docIDs, nil
+	return keys
 }
 
 // SimilaritySearch similarity search docs with `ScoreThreshold` `Filters` `Embedder`
@@ -112,6 +230,7 @@ func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, _ ...v
 //	WithFilters: filter string should match redis search pre-filter query pattern.(eg: @title:Dune)
 //		ref: https://redis.io/docs/latest/develop/interact/search-and-query/advanced-concepts/vectors/#pre-filter-query-attributes-hybrid-approach
 //	WithEmbedder: if set, it will embed query string with this embedder; otherwise embed with vector's embedder
+//	WithMMR: the candidates are embedded again to select them by maximal marginal relevance
 //
 // ref: https://redis.io/docs/latest/develop/interact/search-and-query/advanced-concepts/vectors/#pre-filter-query-attributes-hybrid-approach
 func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) {
@@ -133,7 +252,14 @@ func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, err
 	}
 
-	searchOpts := []SearchOption{WithScoreThreshold(scoreThreshold), WithOffsetLimit(0, numDocuments), WithPreFilters(filter)}
+	limit := numDocuments
+	if opts.MMR {
+		if limit, err = vectorstores.MMRFetchK(opts, numDocuments); err != nil {
+			return nil, err
+		}
+	}
+
+	searchOpts := []SearchOption{WithScoreThreshold(scoreThreshold), WithOffsetLimit(0, limit), WithPreFilters(filter)}
 	if s.indexSchema != nil {
 		searchOpts = append(searchOpts, WithReturns(maps.Keys(s.indexSchema.MetadataKeys())))
 	}
@@ -147,11 +273,21 @@ func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, err
 	}
 
-	_, docs, err := s.client.Search(ctx, *search)
-	if err != nil {
-		return nil, err
+	if opts.MMR {
+		var docs []schema.Document
+		var vectors [][]float32
+		if client, ok := s.client.(VectorSearchClient); ok {
+			docs, vectors, err = client.SearchWithVectors(ctx, *search)
+		} else {
+			_, docs, err = s.client.Search(ctx, *search)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return vectorstores.SelectByMMR(ctx, embedder, embedderData, docs, vectors, numDocuments, opts.MMRLambda)
 	}
-	return docs, nil
+	_, docs, err := s.client.Search(ctx, *search)
+	return docs, err
 }
 
 func (s *Store) DropIndex(ctx context.Context, index string, deleteDocuments bool) error {
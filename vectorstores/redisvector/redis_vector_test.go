@@ -33,6 +33,12 @@ func getValues(t *testing.T) (string, string) {
 		t.Skip("OLLAMA_HOST not set")
 	}
 
+	return getRedisURL(t), ollamaURL
+}
+
+func getRedisURL(t *testing.T) string {
+	t.Helper()
+
 	uri := os.Getenv("REDIS_URL")
 	if uri == "" {
 		ctx := context.Background()
@@ -64,7 +70,7 @@ func getValues(t *testing.T) (string, string) {
 		uri = url
 	}
 
-	return uri, ollamaURL
+	return uri
 }
 
 //go:embed testdata/schema.json
@@ -252,6 +258,81 @@ func TestAddDocuments(t *testing.T) {
 	})
 }
 
+// lengthEmbedder embeds texts as their length.
+type lengthEmbedder struct{}
+
+func (lengthEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text)), 1}
+	}
+	return vectors, nil
+}
+
+func (lengthEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text)), 1}, nil
+}
+
+func TestDocumentManager(t *testing.T) {
+	t.Parallel()
+
+	redisURL := getRedisURL(t)
+	ctx := context.Background()
+	index := "test_document_manager"
+
+	store, err := redisvector.New(ctx,
+		redisvector.WithConnectionURL(redisURL),
+		redisvector.WithIndexName(index, true),
+		redisvector.WithEmbedder(lengthEmbedder{}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, store.DropIndex(context.Background(), index, true))
+	})
+
+	err = store.Upsert(ctx, []string{"tokyo", "osaka"}, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	// upserted documents replace the documents with the same ids.
+	err = store.Upsert(ctx, []string{"tokyo"}, []schema.Document{
+		{PageContent: "Tokyo is a city", Metadata: map[string]any{"region": "kanto"}},
+	})
+	require.NoError(t, err)
+
+	// the keys of the documents are accepted as ids too.
+	key := "doc:" + index + ":osaka"
+	docs, err := store.Get(ctx, []string{"tokyo", key, "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]schema.Document{
+		"tokyo": {
+			PageContent: "Tokyo is a city",
+			Metadata:    map[string]any{"region": "kanto", "id": "doc:" + index + ":tokyo"},
+		},
+		key: {PageContent: "Osaka", Metadata: map[string]any{"country": "japan", "id": key}},
+	}, docs)
+
+	require.NoError(t, store.Delete(ctx, []string{"tokyo"}))
+	docs, err = store.Get(ctx, []string{"tokyo", "osaka"})
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "Osaka", docs["osaka"].PageContent)
+
+	require.NoError(t, store.DeleteByFilter(ctx, "@country:japan"))
+	docs, err = store.Get(ctx, []string{"osaka"})
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+
+	err = store.Upsert(ctx, []string{"a", "b"}, []schema.Document{{PageContent: "Tokyo"}})
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+	err = store.DeleteByFilter(ctx, nil)
+	require.ErrorIs(t, err, vectorstores.ErrMissingFilter)
+	err = store.DeleteByFilter(ctx, map[string]any{"country": "japan"})
+	require.ErrorIs(t, err, redisvector.ErrInvalidFilters)
+}
+
 func TestSimilaritySearch(t *testing.T) {
 	t.Parallel()
 
@@ -2,11 +2,31 @@ package vectorstores
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/IT-Tech-Company/langchaingo/callbacks"
 	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/google/uuid"
 )
 
+// ErrInvalidHybridAlpha is returned by the stores supporting hybrid search when
+// the alpha of WithHybridSearch isn't between 0 and 1.
+var ErrInvalidHybridAlpha = errors.New("hybrid search alpha must be between 0 and 1")
+
+// ErrInvalidMMRLambda is returned when the lambda of WithMMR isn't between 0
+// and 1.
+var ErrInvalidMMRLambda = errors.New("maximal marginal relevance lambda must be between 0 and 1")
+
+// ErrMismatchedIDs is returned by DocumentManager.Upsert when the numbers of
+// ids and of documents differ.
+var ErrMismatchedIDs = errors.New("number of ids does not match number of documents")
+
+// ErrMissingFilter is returned by DocumentManager.DeleteByFilter when the
+// filter is nil.
+var ErrMissingFilter = errors.New("missing filter")
+
 // VectorStore is the interface for saving and querying documents in the
 // form of vector embeddings.
 type VectorStore interface {
@@ -14,6 +34,35 @@ type VectorStore interface {
 	SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...Option) ([]schema.Document, error) //nolint:lll
 }
 
+// DocumentManager is the interface of the vector stores which can also delete,
+// replace and get their documents by id. The stores implementing it can be
+// kept in sync with the sources of their documents.
+type DocumentManager interface {
+	VectorStore
+	// Delete deletes the documents with the ids. Unknown ids are ignored.
+	Delete(ctx context.Context, ids []string, options ...Option) error
+	// DeleteByFilter deletes the documents matching the filter, which has the
+	// format of the filters of the store, see WithFilters. It fails with
+	// ErrMissingFilter if the filter is nil, rather than deleting everything.
+	DeleteByFilter(ctx context.Context, filter any, options ...Option) error
+	// Upsert adds the documents with the ids, replacing the documents with the
+	// same ids. It fails with ErrMismatchedIDs if the numbers of ids and of
+	// documents differ.
+	Upsert(ctx context.Context, ids []string, docs []schema.Document, options ...Option) error
+	// Get returns the documents with the ids, by id. Unknown ids are ignored.
+	Get(ctx context.Context, ids []string, options ...Option) (map[string]schema.Document, error)
+}
+
+// DocumentUUID returns the id if it is a UUID, or else a UUID derived from it,
+// for the stores whose document ids must be UUIDs. The same id always gives
+// the same UUID.
+func DocumentUUID(id string) string {
+	if u, err := uuid.Parse(id); err == nil {
+		return u.String()
+	}
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(id)).String()
+}
+
 // Retriever is a retriever for vector stores.
 type Retriever struct {
 	CallbacksHandler callbacks.Handler
@@ -26,6 +75,7 @@ var _ schema.Retriever = Retriever{}
 
 // GetRelevantDocuments returns documents using the vector store.
 func (r Retriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunKindRetriever, strings.TrimPrefix(fmt.Sprintf("%T", r.v), "*"))
 	if r.CallbacksHandler != nil {
 		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
 	}
@@ -0,0 +1,22 @@
+package vectorstores
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentUUID(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.NewString()
+	assert.Equal(t, id, DocumentUUID(id))
+	assert.Equal(t, id, DocumentUUID("{"+id+"}"))
+
+	// other ids give a stable UUID.
+	assert.Equal(t, DocumentUUID("doc-1"), DocumentUUID("doc-1"))
+	assert.NotEqual(t, DocumentUUID("doc-1"), DocumentUUID("doc-2"))
+	_, err := uuid.Parse(DocumentUUID("doc-1"))
+	assert.NoError(t, err)
+}
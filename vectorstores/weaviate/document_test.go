@@ -0,0 +1,160 @@
+package weaviate
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/IT-Tech-Company/langchaingo/schema"
+	"github.com/IT-Tech-Company/langchaingo/vectorstores"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
+)
+
+// lengthEmbedder embeds texts as their length.
+type lengthEmbedder struct{}
+
+func (lengthEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text))}
+	}
+	return vectors, nil
+}
+
+func (lengthEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text))}, nil
+}
+
+// fakeRequest is a request received by a fakeServer.
+type fakeRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// fakeServer is a weaviate server which records the requests it receives, and
+// answers them with the response of their method and path.
+type fakeServer struct {
+	mu        sync.Mutex
+	requests  []fakeRequest
+	responses map[string]string
+}
+
+func newFakeStore(t *testing.T, responses map[string]string) (Store, *fakeServer) {
+	t.Helper()
+
+	f := &fakeServer{responses: responses}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	s, err := New(
+		WithScheme("http"),
+		WithHost(strings.TrimPrefix(server.URL, "http://")),
+		WithIndexName("Docs"),
+		WithEmbedder(lengthEmbedder{}),
+	)
+	require.NoError(t, err)
+	return s, f
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.requests = append(f.requests, fakeRequest{Method: r.Method, Path: r.URL.Path, Body: string(body)})
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(f.responses[r.Method+" "+r.URL.Path]))
+}
+
+func (f *fakeServer) last() fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests[len(f.requests)-1]
+}
+
+func TestDocumentManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tokyo, osaka := vectorstores.DocumentUUID("tokyo"), vectorstores.DocumentUUID("docs/osaka.md")
+	store, server := newFakeStore(t, map[string]string{
+		"POST /v1/batch/objects": `[]`,
+		"POST /v1/graphql": `{"data": {"Get": {"Docs": [
+			{"text": "Osaka", "nameSpace": "default", "_additional": {"id": "` + osaka + `", "certainty": null}},
+			{"text": "Tokyo", "nameSpace": "default", "_additional": {"id": "` + tokyo + `", "certainty": null}}
+		]}}}`,
+		"DELETE /v1/batch/objects": `{"results": {"matches": 1, "successful": 1}}`,
+	})
+
+	err := store.Upsert(ctx, []string{"tokyo", "docs/osaka.md"}, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "Osaka", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+	req := server.last()
+	assert.Equal(t, "/v1/batch/objects", req.Path)
+	var batch struct {
+		Objects []struct {
+			Class      string         `json:"class"`
+			ID         string         `json:"id"`
+			Vector     []float32      `json:"vector"`
+			Properties map[string]any `json:"properties"`
+		} `json:"objects"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(req.Body), &batch))
+	require.Len(t, batch.Objects, 2)
+	assert.Equal(t, "Docs", batch.Objects[0].Class)
+	assert.Equal(t, tokyo, batch.Objects[0].ID)
+	assert.Equal(t, osaka, batch.Objects[1].ID)
+	assert.Equal(t, []float32{5}, batch.Objects[0].Vector)
+	assert.Equal(t, map[string]any{"text": "Tokyo", "nameSpace": "default", "country": "japan"},
+		batch.Objects[0].Properties)
+
+	docs, err := store.Get(ctx, []string{"tokyo", "docs/osaka.md", "unknown"})
+	require.NoError(t, err)
+	req = server.last()
+	assert.Equal(t, "/v1/graphql", req.Path)
+	assert.Contains(t, req.Body, "ContainsAny")
+	assert.Contains(t, req.Body, tokyo)
+	assert.Contains(t, req.Body, vectorstores.DocumentUUID("unknown"))
+	require.Len(t, docs, 2)
+	assert.Equal(t, "Tokyo", docs["tokyo"].PageContent)
+	assert.Equal(t, "Osaka", docs["docs/osaka.md"].PageContent)
+	assert.NotContains(t, docs["tokyo"].Metadata["_additional"], "id")
+
+	require.NoError(t, store.Delete(ctx, []string{"tokyo"}))
+	req = server.last()
+	assert.Equal(t, http.MethodDelete, req.Method)
+	assert.Equal(t, "/v1/batch/objects", req.Path)
+	assert.JSONEq(t, `{"match": {"class": "Docs", "where": {
+		"operator": "ContainsAny", "path": ["id"], "valueTextArray": ["`+tokyo+`"], "operands": null
+	}}}`, req.Body)
+
+	filter := filters.Where().WithPath([]string{"country"}).WithOperator(filters.Equal).WithValueString("japan")
+	require.NoError(t, store.DeleteByFilter(ctx, filter))
+	req = server.last()
+	assert.Equal(t, http.MethodDelete, req.Method)
+	assert.JSONEq(t, `{"match": {"class": "Docs", "where": {"operator": "And", "path": null, "operands": [
+		{"operator": "Equal", "path": ["nameSpace"], "valueString": "default", "operands": null},
+		{"operator": "Equal", "path": ["country"], "valueString": "japan", "operands": null}
+	]}}}`, req.Body)
+
+	err = store.Upsert(ctx, []string{"a", "b"}, []schema.Document{{PageContent: "Tokyo"}})
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+	err = store.DeleteByFilter(ctx, nil)
+	require.ErrorIs(t, err, vectorstores.ErrMissingFilter)
+	err = store.DeleteByFilter(ctx, map[string]any{"country": "japan"})
+	require.ErrorIs(t, err, ErrInvalidFilter)
+}
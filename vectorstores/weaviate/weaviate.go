@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/IT-Tech-Company/langchaingo/embeddings"
@@ -61,7 +62,7 @@ type Store struct {
 	additionalFields []string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var _ vectorstores.DocumentManager = Store{}
 
 // New creates a new Store with options.
 // When using weaviate,
@@ -94,8 +95,6 @@ func (s Store) AddDocuments(ctx context.Context,
 	options ...vectorstores.Option,
 ) ([]string, error) {
 	opts := s.getOptions(options...)
-	nameSpace := s.getNameSpace(opts)
-
 	docs = s.deduplicate(ctx, opts, docs)
 
 	if len(docs) == 0 {
@@ -104,6 +103,127 @@ func (s Store) AddDocuments(ctx context.Context,
 		return nil, nil
 	}
 
+	ids := make([]string, len(docs))
+	for i := range docs {
+		ids[i] = uuid.New().String()
+	}
+	if err := s.batchObjects(ctx, opts, ids, docs); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Upsert adds the documents with the ids, replacing the objects with the same
+// ids. The ids which aren't UUIDs are stored as UUIDs derived from them, see
+// vectorstores.DocumentUUID.
+func (s Store) Upsert(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if len(ids) != len(docs) {
+		return vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	objectIDs := make([]string, len(ids))
+	for i, id := range ids {
+		objectIDs[i] = vectorstores.DocumentUUID(id)
+	}
+	return s.batchObjects(ctx, s.getOptions(options...), objectIDs, docs)
+}
+
+// Get returns the documents with the ids, by id.
+func (s Store) Get(ctx context.Context,
+	ids []string,
+	_ ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	docs := make(map[string]schema.Document, len(ids))
+	if len(ids) == 0 {
+		return docs, nil
+	}
+	byObjectID := make(map[string]string, len(ids))
+	for _, id := range ids {
+		byObjectID[vectorstores.DocumentUUID(id)] = id
+	}
+
+	fields := s.createFields()
+	additional := &fields[len(fields)-1]
+	additional.Fields = append(additional.Fields, graphql.Field{Name: "id"})
+	res, err := s.client.GraphQL().
+		Get().
+		WithWhere(s.idsWhereBuilder(ids)).
+		WithClassName(s.indexName).
+		WithLimit(len(ids)).
+		WithFields(fields...).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	found, _, err := s.parseDocumentsByGraphQLResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range found {
+		additional, _ := doc.Metadata["_additional"].(map[string]any)
+		objectID, _ := additional["id"].(string)
+		delete(additional, "id")
+		if id, ok := byObjectID[objectID]; ok {
+			docs[id] = doc
+		}
+	}
+	return docs, nil
+}
+
+// Delete deletes the objects with the ids.
+func (s Store) Delete(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.client.Batch().ObjectsBatchDeleter().
+		WithClassName(s.indexName).
+		WithWhere(s.idsWhereBuilder(ids)).
+		Do(ctx)
+	return err
+}
+
+// DeleteByFilter deletes the objects of the name space matching the filter, a
+// *filters.WhereBuilder.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+	whereBuilder, err := s.createWhereBuilder(s.getNameSpace(opts), filter)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Batch().ObjectsBatchDeleter().
+		WithClassName(s.indexName).
+		WithWhere(whereBuilder).
+		Do(ctx)
+	return err
+}
+
+// idsWhereBuilder returns the condition of the objects with the ids.
+func (s Store) idsWhereBuilder(ids []string) *filters.WhereBuilder {
+	objectIDs := make([]string, len(ids))
+	for i, id := range ids {
+		objectIDs[i] = vectorstores.DocumentUUID(id)
+	}
+	return filters.Where().WithPath([]string{"id"}).WithOperator(filters.ContainsAny).WithValueText(objectIDs...)
+}
+
+// batchObjects upserts the documents as objects with the ids, in the name
+// space of the options.
+func (s Store) batchObjects(ctx context.Context,
+	opts vectorstores.Options,
+	ids []string,
+	docs []schema.Document,
+) error {
+	nameSpace := s.getNameSpace(opts)
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
@@ -111,11 +231,11 @@ func (s Store) AddDocuments(ctx context.Context,
 
 	vectors, err := opts.Embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if len(vectors) != len(docs) {
-		return nil, ErrEmbedderWrongNumberVectors
+		return ErrEmbedderWrongNumberVectors
 	}
 
 	metadatas := make([]map[string]any, 0, len(docs))
@@ -131,21 +251,16 @@ func (s Store) AddDocuments(ctx context.Context,
 	}
 
 	objects := make([]*models.Object, 0, len(docs))
-	ids := make([]string, len(docs))
 	for i := range docs {
-		id := strfmt.UUID(uuid.New().String())
-		ids[i] = id.String()
 		objects = append(objects, &models.Object{
 			Class:      s.indexName,
-			ID:         id,
+			ID:         strfmt.UUID(ids[i]),
 			Vector:     vectors[i],
 			Properties: metadatas[i],
 		})
 	}
-	if _, err := s.client.Batch().ObjectsBatcher().WithObjects(objects...).Do(ctx); err != nil {
-		return nil, err
-	}
-	return ids, nil
+	_, err = s.client.Batch().ObjectsBatcher().WithObjects(objects...).Do(ctx)
+	return err
 }
 
 func (s Store) SimilaritySearch(
@@ -160,6 +275,15 @@ func (s Store) SimilaritySearch(
 	if err != nil {
 		return nil, err
 	}
+	if opts.HybridSearch && (opts.HybridAlpha < 0 || opts.HybridAlpha > 1) {
+		return nil, vectorstores.ErrInvalidHybridAlpha
+	}
+	limit := numDocuments
+	if opts.MMR {
+		if limit, err = vectorstores.MMRFetchK(opts, numDocuments); err != nil {
+			return nil, err
+		}
+	}
 	filter := s.getFilters(opts)
 	whereBuilder, err := s.createWhereBuilder(nameSpace, filter)
 	if err != nil {
@@ -171,21 +295,64 @@ func (s Store) SimilaritySearch(
 		return nil, err
 	}
 
-	res, err := s.client.GraphQL().
+	get := s.client.GraphQL().
 		Get().
-		WithNearVector(s.client.GraphQL().
+		WithWhere(whereBuilder).
+		WithClassName(s.indexName).
+		WithLimit(limit)
+	var fields []graphql.Field
+	if opts.HybridSearch {
+		// hybrid queries fuse the results of a BM25 search of the query and of
+		// a vector search, and their score replaces the certainty.
+		get = get.WithHybrid(s.client.GraphQL().
+			HybridArgumentBuilder().
+			WithQuery(query).
+			WithVector(vector).
+			WithAlpha(opts.HybridAlpha),
+		)
+		fields = s.createHybridFields()
+	} else {
+		get = get.WithNearVector(s.client.GraphQL().
 			NearVectorArgBuilder().
 			WithVector(vector).
 			WithCertainty(scoreThreshold),
-		).
-		WithWhere(whereBuilder).
-		WithClassName(s.indexName).
-		WithLimit(numDocuments).
-		WithFields(s.createFields()...).Do(ctx)
+		)
+		fields = s.createFields()
+	}
+	if opts.MMR {
+		additional := &fields[len(fields)-1]
+		additional.Fields = append(additional.Fields, graphql.Field{Name: "vector"})
+	}
+
+	res, err := get.WithFields(fields...).Do(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return s.parseDocumentsByGraphQLResponse(res)
+	docs, vectors, err := s.parseDocumentsByGraphQLResponse(res)
+	if err != nil {
+		return nil, err
+	}
+	if opts.HybridSearch {
+		docs, vectors = filterByScore(docs, vectors, scoreThreshold)
+	}
+	if opts.MMR {
+		return vectorstores.SelectByMMR(ctx, opts.Embedder, vector, docs, vectors, numDocuments, opts.MMRLambda)
+	}
+	return docs, nil
+}
+
+// filterByScore returns the documents whose score is at least the threshold,
+// and their vectors.
+func filterByScore(docs []schema.Document, vectors [][]float32, threshold float32) ([]schema.Document, [][]float32) {
+	filteredDocs := make([]schema.Document, 0, len(docs))
+	filteredVectors := make([][]float32, 0, len(docs))
+	for i, doc := range docs {
+		if doc.Score >= threshold {
+			filteredDocs = append(filteredDocs, doc)
+			filteredVectors = append(filteredVectors, vectors[i])
+		}
+	}
+	return filteredDocs, filteredVectors
 }
 
 // MetadataSearch searches weaviate based on metadata rather than based on similarity.
@@ -214,41 +381,60 @@ func (s Store) MetadataSearch(
 		return nil, err
 	}
 
-	return s.parseDocumentsByGraphQLResponse(res)
+	docs, _, err := s.parseDocumentsByGraphQLResponse(res)
+	return docs, err
 }
 
+// parseDocumentsByGraphQLResponse returns the documents of a response, and
+// their vectors if they were queried.
+//
 //nolint:cyclop
-func (s Store) parseDocumentsByGraphQLResponse(res *models.GraphQLResponse) ([]schema.Document, error) {
+func (s Store) parseDocumentsByGraphQLResponse(res *models.GraphQLResponse) ([]schema.Document, [][]float32, error) {
 	if len(res.Errors) > 0 {
 		messages := make([]string, 0, len(res.Errors))
 		for _, e := range res.Errors {
 			messages = append(messages, e.Message)
 		}
-		return nil, fmt.Errorf("%w: %s", ErrInvalidResponse, strings.Join(messages, ", "))
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidResponse, strings.Join(messages, ", "))
 	}
 
 	data, ok := res.Data["Get"].(map[string]any)[s.indexName]
 	if !ok || data == nil {
-		return nil, ErrEmptyResponse
+		return nil, nil, ErrEmptyResponse
 	}
 	items, ok := data.([]any)
 
 	docs := make([]schema.Document, 0, len(items))
+	vectors := make([][]float32, 0, len(items))
 	if !ok || len(items) == 0 {
-		return docs, nil
+		return docs, vectors, nil
 	}
 	for _, item := range items {
 		itemMap, ok := item.(map[string]any)
 		if !ok {
-			return nil, ErrInvalidResponse
+			return nil, nil, ErrInvalidResponse
 		}
 		pageContent, ok := itemMap[s.textKey].(string)
 		if !ok {
-			return nil, ErrMissingTextKey
+			return nil, nil, ErrMissingTextKey
 		}
 		var score float64
+		var vector []float32
 		if additional, ok := itemMap["_additional"].(map[string]any); ok {
-			score, _ = additional["certainty"].(float64)
+			score, ok = additional["certainty"].(float64)
+			if !ok {
+				// hybrid queries return their score as a string.
+				hybridScore, _ := additional["score"].(string)
+				score, _ = strconv.ParseFloat(hybridScore, 32)
+			}
+			if values, ok := additional["vector"].([]any); ok {
+				vector = make([]float32, len(values))
+				for i, value := range values {
+					f, _ := value.(float64)
+					vector[i] = float32(f)
+				}
+				delete(additional, "vector")
+			}
 		}
 		delete(itemMap, s.textKey)
 		doc := schema.Document{
@@ -257,8 +443,9 @@ func (s Store) parseDocumentsByGraphQLResponse(res *models.GraphQLResponse) ([]s
 			Score:       float32(score),
 		}
 		docs = append(docs, doc)
+		vectors = append(vectors, vector)
 	}
-	return docs, nil
+	return docs, vectors, nil
 }
 
 func (s Store) deduplicate(ctx context.Context,
@@ -349,3 +536,18 @@ func (s Store) createFields() []graphql.Field {
 
 	return fields
 }
+
+// createHybridFields returns the fields of a hybrid query, whose score
+// replaces the certainty of a vector search.
+func (s Store) createHybridFields() []graphql.Field {
+	fields := s.createFields()
+	additional := &fields[len(fields)-1]
+	additionalFields := make([]graphql.Field, 0, len(additional.Fields)+1)
+	for _, field := range additional.Fields {
+		if field.Name != "certainty" && field.Name != "score" {
+			additionalFields = append(additionalFields, field)
+		}
+	}
+	additional.Fields = append(additionalFields, graphql.Field{Name: "score"})
+	return fields
+}
@@ -295,6 +295,46 @@ func TestSimilaritySearchWithInvalidScoreThreshold(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestWeaviateStoreHybridSearch(t *testing.T) {
+	t.Parallel()
+
+	scheme, host := getValues(t)
+	llm, err := openai.New()
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	store, err := New(
+		WithScheme(scheme),
+		WithHost(host),
+		WithEmbedder(e),
+		WithNameSpace(uuid.New().String()),
+		WithIndexName(randomizedCamelCaseClass()),
+	)
+	require.NoError(t, err)
+
+	err = createTestClass(context.Background(), store)
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "The printer reports error E4012 when its tray is empty"},
+		{PageContent: "The printer stops when the paper jams"},
+		{PageContent: "The router blinks when the network is down"},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(context.Background(), "E4012", 1,
+		vectorstores.WithHybridSearch(0.25))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Contains(t, docs[0].PageContent, "E4012")
+	require.Positive(t, docs[0].Score)
+
+	_, err = store.SimilaritySearch(context.Background(), "E4012", 1,
+		vectorstores.WithHybridSearch(1.5))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidHybridAlpha)
+}
+
 func TestWeaviateAsRetriever(t *testing.T) {
 	t.Parallel()
 